@@ -0,0 +1,90 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log/slog"
+	"os"
+
+	"github.com/iconidentify/xgrabba/internal/blobstore"
+	"github.com/iconidentify/xgrabba/internal/config"
+)
+
+var (
+	Version   = "dev"
+	BuildTime = "unknown"
+)
+
+// blobstore-migrate moves media from existing archives into the
+// content-addressed blob store. Stop the server before running it.
+func main() {
+	// Parse flags
+	configPath := flag.String("config", "", "Path to config file")
+	storagePath := flag.String("path", "", "Storage base path (overrides config)")
+	dryRun := flag.Bool("dry-run", false, "Report what would change without modifying files")
+	showVersion := flag.Bool("version", false, "Show version and exit")
+	flag.Parse()
+
+	if *showVersion {
+		fmt.Printf("xgrabba-blobstore-migrate %s (built %s)\n", Version, BuildTime)
+		os.Exit(0)
+	}
+
+	// Setup logger
+	logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{
+		Level: slog.LevelInfo,
+	}))
+	slog.SetDefault(logger)
+
+	basePath := *storagePath
+	if basePath == "" {
+		cfg, err := config.Load(*configPath)
+		if err != nil {
+			logger.Error("failed to load config", "error", err)
+			os.Exit(1)
+		}
+		basePath = cfg.Storage.BasePath
+	}
+
+	if _, err := os.Stat(basePath); os.IsNotExist(err) {
+		logger.Error("storage path does not exist", "path", basePath)
+		os.Exit(1)
+	}
+
+	store, err := blobstore.Open(basePath)
+	if err != nil {
+		logger.Error("failed to open blob store", "error", err)
+		os.Exit(1)
+	}
+
+	logger.Info("migrating archives into blob store", "path", basePath, "dry_run", *dryRun)
+
+	result, err := blobstore.Migrate(basePath, store, *dryRun, logger)
+	if err != nil {
+		logger.Error("migration failed", "error", err)
+		os.Exit(1)
+	}
+
+	// Print summary
+	stats := store.Stats()
+	fmt.Println()
+	if *dryRun {
+		fmt.Println("Migration Dry Run")
+	} else {
+		fmt.Println("Migration Complete!")
+	}
+	fmt.Println("-------------------")
+	fmt.Printf("Archives scanned:  %d\n", result.Archives)
+	fmt.Printf("Media files:       %d\n", result.MediaFiles)
+	fmt.Printf("Ingested:          %d\n", result.Ingested)
+	fmt.Printf("Already stored:    %d\n", result.AlreadyStored)
+	fmt.Printf("Bytes saved:       %d\n", result.BytesSaved)
+	fmt.Printf("Unique blobs:      %d (%d bytes)\n", stats.Blobs, stats.StoredBytes)
+	if len(result.Errors) > 0 {
+		fmt.Printf("Errors:            %d\n", len(result.Errors))
+		for _, e := range result.Errors {
+			fmt.Printf("  - %s\n", e)
+		}
+		os.Exit(1)
+	}
+}
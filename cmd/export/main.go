@@ -25,6 +25,7 @@ func main() {
 	dest := flag.String("dest", "", "Destination path for export (required)")
 	viewerDir := flag.String("viewers", "", "Directory containing viewer binaries to include")
 	configPath := flag.String("config", "", "Path to config file")
+	excludeCredentialed := flag.Bool("exclude-credentialed", false, "Skip tweets fetched with browser credentials (protected or sensitive)")
 	showVersion := flag.Bool("version", false, "Show version and exit")
 	flag.Parse()
 
@@ -66,7 +67,7 @@ func main() {
 
 	// Initialize minimal dependencies for TweetService
 	// We need TweetService to load existing tweets from disk
-	grokClient := grok.NewClient(cfg.Grok)
+	grokClient := grok.NewRouterFromConfig(cfg.Grok, cfg.AI)
 	dl := downloader.NewHTTPDownloader(cfg.Download)
 
 	tweetSvc := service.NewTweetService(
@@ -102,6 +103,8 @@ func main() {
 		DestPath:       *dest,
 		IncludeViewers: *viewerDir != "",
 		ViewerBinDir:   *viewerDir,
+
+		ExcludeCredentialed: *excludeCredentialed,
 	}
 
 	result, err := exportSvc.ExportToUSB(ctx, opts)
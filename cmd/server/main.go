@@ -14,13 +14,16 @@ import (
 
 	"github.com/iconidentify/xgrabba/internal/api"
 	"github.com/iconidentify/xgrabba/internal/api/handler"
+	"github.com/iconidentify/xgrabba/internal/blobstore"
 	"github.com/iconidentify/xgrabba/internal/bookmarks"
 	"github.com/iconidentify/xgrabba/internal/config"
 	"github.com/iconidentify/xgrabba/internal/downloader"
 	"github.com/iconidentify/xgrabba/internal/repository"
 	"github.com/iconidentify/xgrabba/internal/service"
+	"github.com/iconidentify/xgrabba/internal/storage"
 	"github.com/iconidentify/xgrabba/internal/worker"
 	"github.com/iconidentify/xgrabba/pkg/grok"
+	"github.com/iconidentify/xgrabba/pkg/ocr"
 	"github.com/iconidentify/xgrabba/pkg/twitter"
 	"github.com/iconidentify/xgrabba/pkg/usbclient"
 	"github.com/iconidentify/xgrabba/pkg/whisper"
@@ -75,7 +78,26 @@ func main() {
 	videoRepo := repository.NewFilesystemVideoRepository(cfg.Storage)
 	jobRepo := repository.NewInMemoryJobRepository()
 	playlistRepo := repository.NewFilesystemPlaylistRepository(cfg.Storage.BasePath)
-	grokClient := grok.NewClient(cfg.Grok)
+	grokClient := grok.NewRouterFromConfig(cfg.Grok, cfg.AI)
+	logger.Info("AI providers configured",
+		"filename", cfg.AI.ProviderFor(config.AITaskFilename),
+		"analysis", cfg.AI.ProviderFor(config.AITaskAnalysis),
+		"vision", cfg.AI.ProviderFor(config.AITaskVision),
+		"essay", cfg.AI.ProviderFor(config.AITaskEssay),
+		"translation", cfg.AI.ProviderFor(config.AITaskTranslation),
+		"ask", cfg.AI.ProviderFor(config.AITaskAsk),
+		"document", cfg.AI.ProviderFor(config.AITaskDocument),
+		"chapters", cfg.AI.ProviderFor(config.AITaskChapters),
+		"translation_languages", cfg.AI.TranslationTargets(),
+		"fallback", cfg.AI.FallbackProvider,
+	)
+	prompts, err := grok.LoadPrompts(cfg.AI.PromptsDir)
+	if err != nil {
+		logger.Error("failed to load prompt templates", "dir", cfg.AI.PromptsDir, "error", err)
+		os.Exit(1)
+	}
+	grokClient.SetPrompts(prompts)
+	logger.Info("prompt templates loaded", "dir", cfg.AI.PromptsDir, "version", prompts.Version())
 	dl := downloader.NewHTTPDownloader(cfg.Download)
 
 	// Initialize Whisper client for audio transcription
@@ -108,6 +130,28 @@ func main() {
 	defer eventSvc.Close()
 	logger.Info("event service initialized with SQLite persistence", "db_path", eventsDBPath)
 
+	// AI usage ledger; records every AI and transcription call and enforces budgets
+	aiUsageSvc := service.NewAIUsageService(cfg.Storage.BasePath, cfg.AI, logger, eventSvc)
+	grokClient.SetUsageRecorder(aiUsageSvc)
+	if cfg.AI.DailyBudgetUSD > 0 || cfg.AI.MonthlyBudgetUSD > 0 {
+		logger.Info("AI budgets enabled", "daily_usd", cfg.AI.DailyBudgetUSD, "monthly_usd", cfg.AI.MonthlyBudgetUSD)
+	}
+
+	// AI response cache so backfills and regenerations don't pay twice for identical requests
+	var aiCache *grok.ResponseCache
+	if cfg.AI.CacheEnabled {
+		aiCache = grok.NewResponseCache(filepath.Join(cfg.Storage.BasePath, ".ai_cache"), cfg.AI.CacheTTL)
+		grokClient.SetCache(aiCache)
+		go func() {
+			if n, err := aiCache.Prune(); err != nil {
+				logger.Warn("failed to prune AI response cache", "error", err)
+			} else if n > 0 {
+				logger.Info("pruned expired AI responses", "removed", n)
+			}
+		}()
+		logger.Info("AI response cache enabled", "ttl", cfg.AI.CacheTTL)
+	}
+
 	// Initialize services
 	videoSvc := service.NewVideoService(
 		videoRepo,
@@ -131,12 +175,90 @@ func main() {
 		eventSvc,
 	)
 
+	// OCR engine for text in screenshots and video keyframes (optional)
+	if cfg.OCR.Enabled {
+		ocrEngine, err := ocr.New(ocr.Config{
+			Engine:        cfg.OCR.Engine,
+			TesseractPath: cfg.OCR.TesseractPath,
+			Languages:     cfg.OCR.Languages,
+			APIKey:        cfg.OCR.APIKey,
+			BaseURL:       cfg.OCR.BaseURL,
+			Model:         cfg.OCR.Model,
+			Timeout:       cfg.OCR.Timeout,
+		})
+		if err != nil {
+			logger.Warn("OCR disabled", "error", err)
+		} else if ocrEngine != nil {
+			tweetSvc.SetOCREngine(ocrEngine)
+			logger.Info("OCR enabled", "engine", ocrEngine.Name())
+		} else {
+			logger.Info("OCR disabled (no tesseract binary or OCR_API_KEY)")
+		}
+	}
+
+	tweetSvc.SetAIUsage(aiUsageSvc)
+	tweetSvc.SetPrompts(prompts)
+	tweetSvc.SetTagRegistry(service.NewTagRegistryService(cfg.Storage.BasePath, logger))
+	if aiCache != nil {
+		tweetSvc.SetAICache(aiCache)
+	}
+
+	// Default media quality policy (archive requests may override it)
+	tweetSvc.SetMediaQuality(cfg.Media)
+	if cfg.Media != (config.MediaConfig{}) {
+		logger.Info("media quality policy",
+			"image_size", cfg.Media.ImageSize,
+			"max_video_resolution", cfg.Media.MaxVideoResolution,
+			"max_video_bitrate", cfg.Media.MaxVideoBitrate,
+			"keep_all_variants", cfg.Media.KeepAllVariants,
+		)
+	}
+
+	// Remote archive storage backend (optional, defaults to local disk)
+	if cfg.Storage.Backend != "" && cfg.Storage.Backend != storage.BackendLocal {
+		archiveStore, err := storage.New(cfg.Storage)
+		if err != nil {
+			logger.Error("failed to initialize storage backend", "backend", cfg.Storage.Backend, "error", err)
+			os.Exit(1)
+		}
+		tweetSvc.SetStorage(archiveStore)
+		restored, err := tweetSvc.RestoreFromStorage(context.Background())
+		if err != nil {
+			logger.Warn("failed to restore archives from storage", "error", err)
+		}
+		logger.Info("storage backend enabled",
+			"backend", archiveStore.Name(),
+			"presign_media", cfg.Storage.PresignMedia,
+			"evict_local_media", cfg.Storage.EvictLocalMedia,
+			"restored_archives", restored,
+		)
+	}
+
+	// Content-addressed media store (optional)
+	if cfg.Storage.ContentAddressed {
+		blobStore, err := blobstore.Open(cfg.Storage.BasePath)
+		if err != nil {
+			logger.Warn("content-addressed storage disabled", "error", err)
+		} else {
+			tweetSvc.SetBlobStore(blobStore)
+			stats := blobStore.Stats()
+			logger.Info("content-addressed storage enabled", "blobs", stats.Blobs, "references", stats.References)
+		}
+	}
+
 	// Initialize playlist service (needs tweetSvc for smart playlist search)
 	playlistSvc := service.NewPlaylistService(playlistRepo, tweetSvc, logger)
+	tweetSvc.SetPlaylistService(playlistSvc)
 
 	// Initialize export service with storage path for state persistence
 	exportSvc := service.NewExportService(tweetSvc, playlistSvc, logger, eventSvc, cfg.Storage.BasePath)
 
+	// Duplicate media detection (perceptual hashes) and hardlink dedupe
+	duplicateSvc := service.NewDuplicateService(tweetSvc, logger, eventSvc)
+
+	// Archive integrity scrubbing (checksum manifests, Resync repair)
+	integritySvc := service.NewIntegrityService(tweetSvc, cfg.Integrity, logger, eventSvc)
+
 	// Start AI metadata backfill in background for legacy tweets
 	backfillCtx, cancelBackfill := context.WithCancel(context.Background())
 	go tweetSvc.BackfillAIMetadata(backfillCtx)
@@ -147,9 +269,32 @@ func main() {
 	// Resume incomplete archives (tweets saved mid-processing before restart)
 	go tweetSvc.ResumeIncompleteArchives(context.Background())
 
+	// Compute content/perceptual hashes for archives created before hashing existed
+	go tweetSvc.BackfillMediaHashes(backfillCtx)
+
+	// Periodically re-verify archive checksums
+	go integritySvc.Start(backfillCtx)
+
+	// Purge archives whose trash retention has expired
+	go tweetSvc.RunTrashRetention(backfillCtx)
+
+	// Run AI phases skipped while an AI budget was exceeded once it resets
+	go tweetSvc.RunPausedAnalysis(backfillCtx)
+
+	// Normalize and curate AI tags of archives saved before the current tag registry
+	tweetSvc.StartTagRegistryApply()
+
 	// Twitter client (shared). Used for extension credential storage and optional GraphQL access.
 	twitterClient := twitter.NewClient(logger)
 
+	// Re-poll engagement metrics of recent archives on a decaying schedule
+	metricsSvc := service.NewMetricsService(tweetSvc, twitterClient, cfg.Metrics, logger)
+	go metricsSvc.Start(backfillCtx)
+
+	// Periodically check whether archived tweets still exist on X
+	upstreamSvc := service.NewUpstreamService(tweetSvc, twitterClient, cfg.Upstream, logger, eventSvc)
+	go upstreamSvc.Start(backfillCtx)
+
 	// Start bookmarks monitor (optional) to auto-archive newly bookmarked tweets (mobile-friendly).
 	bookmarksCtx, cancelBookmarks := context.WithCancel(context.Background())
 	var bookmarksOAuthHandler *handler.BookmarksOAuthHandler // Declared here so watching goroutine can access it
@@ -319,8 +464,38 @@ handlers:
 	// Playlist handler (service initialized earlier for export integration)
 	playlistHandler := handler.NewPlaylistHandler(playlistSvc, logger)
 
+	// Duplicate media handler
+	duplicateHandler := handler.NewDuplicateHandler(duplicateSvc, logger)
+
+	// Integrity scrub handler
+	integrityHandler := handler.NewIntegrityHandler(integritySvc, logger)
+
+	// Trash handler (restore/purge soft-deleted archives)
+	trashHandler := handler.NewTrashHandler(tweetSvc, logger)
+
+	// Engagement metrics history handler
+	metricsHandler := handler.NewMetricsHandler(metricsSvc, logger)
+
+	// AI usage and budget report handler
+	aiUsageHandler := handler.NewAIUsageHandler(aiUsageSvc, logger)
+
+	// AI response cache handler (stats and invalidation)
+	var aiCacheHandler *handler.AICacheHandler
+	if aiCache != nil {
+		aiCacheHandler = handler.NewAICacheHandler(aiCache, logger)
+	}
+
+	// Prompt template handler (version, preview and rerun)
+	aiPromptsHandler := handler.NewAIPromptsHandler(tweetSvc, logger)
+
+	// Ask-your-archive question answering
+	askHandler := handler.NewAskHandler(tweetSvc, playlistSvc, logger)
+
+	// AI tag counts and curation
+	tagHandler := handler.NewTagHandler(tweetSvc, logger)
+
 	// Setup router
-	router := api.NewRouter(videoHandler, tweetHandler, healthHandler, uiHandler, exportHandler, bookmarksOAuthHandler, usbHandler, eventHandler, extensionHandler, playlistHandler, cfg.Server.APIKey)
+	router := api.NewRouter(videoHandler, tweetHandler, healthHandler, uiHandler, exportHandler, bookmarksOAuthHandler, usbHandler, eventHandler, extensionHandler, playlistHandler, duplicateHandler, integrityHandler, trashHandler, metricsHandler, aiUsageHandler, aiCacheHandler, aiPromptsHandler, askHandler, tagHandler, cfg.Server.APIKey)
 
 	// Initialize worker pool
 	pool := worker.NewPool(
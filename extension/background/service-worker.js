@@ -312,6 +312,11 @@ async function archiveVideo(payload) {
       requestBody.author_username = payload.authorUsername;
     }
 
+    // Optional archive options (skip_ai, text_only, tags, playlist_ids, priority, quality...)
+    if (payload.options) {
+      Object.assign(requestBody, payload.options);
+    }
+
     const response = await fetch(`${backendUrl}/api/v1/tweets`, {
       method: 'POST',
       headers: {
@@ -0,0 +1,71 @@
+package handler
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/iconidentify/xgrabba/pkg/grok"
+)
+
+// AICacheHandler handles AI response cache HTTP requests.
+type AICacheHandler struct {
+	cache  *grok.ResponseCache
+	logger *slog.Logger
+}
+
+// NewAICacheHandler creates a new AI response cache handler.
+func NewAICacheHandler(cache *grok.ResponseCache, logger *slog.Logger) *AICacheHandler {
+	return &AICacheHandler{
+		cache:  cache,
+		logger: logger,
+	}
+}
+
+// Stats handles GET /api/v1/ai/cache
+func (h *AICacheHandler) Stats(w http.ResponseWriter, r *http.Request) {
+	h.writeJSON(w, http.StatusOK, h.cache.Stats())
+}
+
+// Invalidate handles DELETE /api/v1/ai/cache
+// Optional task, model and older_than (a duration such as 24h) query parameters
+// narrow which entries are removed; without them the whole cache is cleared.
+func (h *AICacheHandler) Invalidate(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	filter := grok.CacheFilter{
+		Task:  q.Get("task"),
+		Model: q.Get("model"),
+	}
+	if v := q.Get("older_than"); v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil || d <= 0 {
+			h.writeError(w, http.StatusBadRequest, "older_than must be a positive duration like 24h")
+			return
+		}
+		filter.OlderThan = d
+	}
+
+	removed, err := h.cache.Invalidate(filter)
+	if err != nil {
+		h.logger.Error("AI cache invalidation failed", "error", err)
+		h.writeError(w, http.StatusInternalServerError, "failed to invalidate AI cache")
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, map[string]interface{}{
+		"removed": removed,
+	})
+}
+
+func (h *AICacheHandler) writeJSON(w http.ResponseWriter, status int, data interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(data)
+}
+
+func (h *AICacheHandler) writeError(w http.ResponseWriter, status int, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(map[string]string{"error": message})
+}
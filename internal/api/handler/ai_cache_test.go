@@ -0,0 +1,37 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/iconidentify/xgrabba/pkg/grok"
+)
+
+func TestAICacheHandler(t *testing.T) {
+	h := NewAICacheHandler(grok.NewResponseCache(t.TempDir(), time.Hour), testLogger())
+
+	w := httptest.NewRecorder()
+	h.Stats(w, httptest.NewRequest(http.MethodGet, "/api/v1/ai/cache", nil))
+	var stats grok.CacheStats
+	if err := json.NewDecoder(w.Body).Decode(&stats); err != nil {
+		t.Fatal(err)
+	}
+	if w.Code != http.StatusOK || stats.Entries != 0 || stats.TTL != "1h0m0s" {
+		t.Errorf("stats = %d %+v", w.Code, stats)
+	}
+
+	w = httptest.NewRecorder()
+	h.Invalidate(w, httptest.NewRequest(http.MethodDelete, "/api/v1/ai/cache?older_than=soon", nil))
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("invalid older_than: status = %d, want %d", w.Code, http.StatusBadRequest)
+	}
+
+	w = httptest.NewRecorder()
+	h.Invalidate(w, httptest.NewRequest(http.MethodDelete, "/api/v1/ai/cache?task=vision&older_than=24h", nil))
+	if w.Code != http.StatusOK {
+		t.Errorf("invalidate: status = %d, want %d", w.Code, http.StatusOK)
+	}
+}
@@ -0,0 +1,104 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/iconidentify/xgrabba/internal/domain"
+	"github.com/iconidentify/xgrabba/internal/service"
+)
+
+// AIPromptsHandler handles prompt template HTTP requests.
+type AIPromptsHandler struct {
+	svc    *service.TweetService
+	logger *slog.Logger
+}
+
+// NewAIPromptsHandler creates a new prompt template handler.
+func NewAIPromptsHandler(svc *service.TweetService, logger *slog.Logger) *AIPromptsHandler {
+	return &AIPromptsHandler{
+		svc:    svc,
+		logger: logger,
+	}
+}
+
+// List handles GET /api/v1/ai/prompts
+// Returns the template version, which templates are overridden and how many
+// tweets were analyzed with a different version.
+func (h *AIPromptsHandler) List(w http.ResponseWriter, r *http.Request) {
+	prompts := h.svc.Prompts()
+	h.writeJSON(w, http.StatusOK, map[string]interface{}{
+		"version":  prompts.Version(),
+		"prompts":  prompts.List(),
+		"outdated": len(h.svc.OutdatedPromptTweets()),
+	})
+}
+
+// Preview handles GET /api/v1/ai/prompts/preview?tweet_id=...&task=...
+// Renders the prompt a task would send for a tweet without calling the AI
+// provider. task is one of filename, analysis, vision or essay.
+func (h *AIPromptsHandler) Preview(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	tweetID := q.Get("tweet_id")
+	task := q.Get("task")
+	if tweetID == "" || task == "" {
+		h.writeError(w, http.StatusBadRequest, "tweet_id and task are required")
+		return
+	}
+
+	rendered, err := h.svc.PreviewPrompt(r.Context(), domain.TweetID(tweetID), task)
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrVideoNotFound):
+			h.writeError(w, http.StatusNotFound, "tweet not found")
+		case errors.Is(err, service.ErrUnknownPromptTask):
+			h.writeError(w, http.StatusBadRequest, "task must be filename, analysis, vision or essay")
+		case errors.Is(err, domain.ErrMediaNotFound), errors.Is(err, service.ErrNoTranscript):
+			h.writeError(w, http.StatusUnprocessableEntity, err.Error())
+		default:
+			h.logger.Error("prompt preview failed", "tweet_id", tweetID, "task", task, "error", err)
+			h.writeError(w, http.StatusInternalServerError, "failed to render prompt")
+		}
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, rendered)
+}
+
+// Rerun handles POST /api/v1/ai/prompts/rerun
+// Re-runs AI analysis in the background for tweets analyzed with an older
+// prompt template version.
+func (h *AIPromptsHandler) Rerun(w http.ResponseWriter, r *http.Request) {
+	queued, err := h.svc.StartRerunOutdatedPrompts()
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrPromptRerunInProgress):
+			h.writeError(w, http.StatusConflict, err.Error())
+		case errors.Is(err, domain.ErrAIBudgetExceeded):
+			h.writeError(w, http.StatusTooManyRequests, err.Error())
+		default:
+			h.logger.Error("prompt rerun failed to start", "error", err)
+			h.writeError(w, http.StatusInternalServerError, "failed to start prompt rerun")
+		}
+		return
+	}
+
+	h.writeJSON(w, http.StatusAccepted, map[string]interface{}{
+		"queued":  queued,
+		"version": h.svc.Prompts().Version(),
+	})
+}
+
+func (h *AIPromptsHandler) writeJSON(w http.ResponseWriter, status int, data interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(data)
+}
+
+func (h *AIPromptsHandler) writeError(w http.ResponseWriter, status int, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(map[string]string{"error": message})
+}
@@ -0,0 +1,52 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/iconidentify/xgrabba/internal/config"
+	"github.com/iconidentify/xgrabba/internal/service"
+	"github.com/iconidentify/xgrabba/pkg/grok"
+)
+
+func TestAIPromptsHandler(t *testing.T) {
+	svc := service.NewTweetService(grok.NewRouter(), nil, nil, config.StorageConfig{BasePath: t.TempDir()}, config.AIConfig{}, false, testLogger(), nil)
+	h := NewAIPromptsHandler(svc, testLogger())
+
+	w := httptest.NewRecorder()
+	h.List(w, httptest.NewRequest(http.MethodGet, "/api/v1/ai/prompts", nil))
+	var list struct {
+		Version  string            `json:"version"`
+		Prompts  []grok.PromptInfo `json:"prompts"`
+		Outdated int               `json:"outdated"`
+	}
+	if err := json.NewDecoder(w.Body).Decode(&list); err != nil {
+		t.Fatal(err)
+	}
+	if w.Code != http.StatusOK || list.Version != grok.DefaultPrompts().Version() || len(list.Prompts) == 0 {
+		t.Errorf("list = %d %+v", w.Code, list)
+	}
+
+	tests := []struct {
+		query string
+		want  int
+	}{
+		{"task=analysis", http.StatusBadRequest},
+		{"tweet_id=1&task=analysis", http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		w = httptest.NewRecorder()
+		h.Preview(w, httptest.NewRequest(http.MethodGet, "/api/v1/ai/prompts/preview?"+tt.query, nil))
+		if w.Code != tt.want {
+			t.Errorf("preview %s: status = %d, want %d", tt.query, w.Code, tt.want)
+		}
+	}
+
+	w = httptest.NewRecorder()
+	h.Rerun(w, httptest.NewRequest(http.MethodPost, "/api/v1/ai/prompts/rerun", nil))
+	if w.Code != http.StatusAccepted {
+		t.Errorf("rerun: status = %d, want %d", w.Code, http.StatusAccepted)
+	}
+}
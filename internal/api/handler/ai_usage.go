@@ -0,0 +1,68 @@
+package handler
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/iconidentify/xgrabba/internal/service"
+)
+
+// AIUsageHandler handles AI usage and budget HTTP requests.
+type AIUsageHandler struct {
+	svc    *service.AIUsageService
+	logger *slog.Logger
+}
+
+// NewAIUsageHandler creates a new AI usage handler.
+func NewAIUsageHandler(svc *service.AIUsageService, logger *slog.Logger) *AIUsageHandler {
+	return &AIUsageHandler{
+		svc:    svc,
+		logger: logger,
+	}
+}
+
+// Usage handles GET /api/v1/ai/usage
+// Optional from and to query parameters are UTC days (YYYY-MM-DD); the default
+// is the current month to date.
+func (h *AIUsageHandler) Usage(w http.ResponseWriter, r *http.Request) {
+	now := time.Now().UTC()
+	from := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+	to := now
+
+	if v := r.URL.Query().Get("from"); v != "" {
+		t, err := time.Parse("2006-01-02", v)
+		if err != nil {
+			h.writeError(w, http.StatusBadRequest, "from must be a date like 2006-01-02")
+			return
+		}
+		from = t
+	}
+	if v := r.URL.Query().Get("to"); v != "" {
+		t, err := time.Parse("2006-01-02", v)
+		if err != nil {
+			h.writeError(w, http.StatusBadRequest, "to must be a date like 2006-01-02")
+			return
+		}
+		to = t
+	}
+	if to.Before(from) {
+		h.writeError(w, http.StatusBadRequest, "to must not be before from")
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, h.svc.Report(from, to))
+}
+
+func (h *AIUsageHandler) writeJSON(w http.ResponseWriter, status int, data interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(data)
+}
+
+func (h *AIUsageHandler) writeError(w http.ResponseWriter, status int, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(map[string]string{"error": message})
+}
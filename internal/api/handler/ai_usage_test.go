@@ -0,0 +1,45 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/iconidentify/xgrabba/internal/config"
+	"github.com/iconidentify/xgrabba/internal/service"
+)
+
+func TestAIUsageHandler_Usage(t *testing.T) {
+	svc := service.NewAIUsageService(t.TempDir(), config.AIConfig{DailyBudgetUSD: 5}, testLogger(), nil)
+	h := NewAIUsageHandler(svc, testLogger())
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/ai/usage?from=2024-03-01&to=2024-03-31", nil)
+	w := httptest.NewRecorder()
+	h.Usage(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d", w.Code, http.StatusOK)
+	}
+	var resp service.AIUsageReport
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatal(err)
+	}
+	if resp.From != "2024-03-01" || resp.To != "2024-03-31" || resp.Budget.DailyLimitUSD != 5 {
+		t.Errorf("resp = %+v", resp)
+	}
+}
+
+func TestAIUsageHandler_InvalidRange(t *testing.T) {
+	svc := service.NewAIUsageService(t.TempDir(), config.AIConfig{}, testLogger(), nil)
+	h := NewAIUsageHandler(svc, testLogger())
+
+	for _, query := range []string{"from=yesterday", "from=2024-03-10&to=2024-03-01"} {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/ai/usage?"+query, nil)
+		w := httptest.NewRecorder()
+		h.Usage(w, req)
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("%s: status = %d, want %d", query, w.Code, http.StatusBadRequest)
+		}
+	}
+}
@@ -0,0 +1,177 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/iconidentify/xgrabba/internal/domain"
+	"github.com/iconidentify/xgrabba/internal/service"
+)
+
+// AskHandler handles questions about the archive.
+type AskHandler struct {
+	tweetSvc    *service.TweetService
+	playlistSvc *service.PlaylistService
+	logger      *slog.Logger
+}
+
+// NewAskHandler creates a new ask handler. playlistSvc may be nil, which
+// disables scoping questions to a playlist.
+func NewAskHandler(tweetSvc *service.TweetService, playlistSvc *service.PlaylistService, logger *slog.Logger) *AskHandler {
+	return &AskHandler{
+		tweetSvc:    tweetSvc,
+		playlistSvc: playlistSvc,
+		logger:      logger,
+	}
+}
+
+// AskRequest is the body of POST /api/v1/ask. GET takes the same fields as
+// query parameters, with the question as q.
+type AskRequest struct {
+	Question   string `json:"question"`
+	PlaylistID string `json:"playlist_id,omitempty"`
+	From       string `json:"from,omitempty"` // YYYY-MM-DD, inclusive
+	To         string `json:"to,omitempty"`   // YYYY-MM-DD, inclusive
+	MaxSources int    `json:"max_sources,omitempty"`
+	Stream     bool   `json:"stream,omitempty"`
+}
+
+// Ask handles GET and POST /api/v1/ask
+// Answers a question from the most relevant archived tweets, citing tweet IDs
+// and transcript timestamps. With stream set, or an Accept header of
+// text/event-stream, the answer is sent as Server-Sent Events: "delta"
+// events carry pieces of the answer as it is generated and a final "done"
+// event carries the full answer with its citations and sources.
+func (h *AskHandler) Ask(w http.ResponseWriter, r *http.Request) {
+	var req AskRequest
+	if r.Method == http.MethodGet {
+		q := r.URL.Query()
+		req.Question = q.Get("q")
+		req.PlaylistID = q.Get("playlist_id")
+		req.From = q.Get("from")
+		req.To = q.Get("to")
+		req.MaxSources, _ = strconv.Atoi(q.Get("max_sources"))
+		req.Stream, _ = strconv.ParseBool(q.Get("stream"))
+	} else if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.writeError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	opts := service.AskOptions{Question: req.Question, MaxSources: req.MaxSources}
+	if req.From != "" {
+		t, err := time.Parse("2006-01-02", req.From)
+		if err != nil {
+			h.writeError(w, http.StatusBadRequest, "from must be a date like 2006-01-02")
+			return
+		}
+		opts.Since = t
+	}
+	if req.To != "" {
+		t, err := time.Parse("2006-01-02", req.To)
+		if err != nil {
+			h.writeError(w, http.StatusBadRequest, "to must be a date like 2006-01-02")
+			return
+		}
+		opts.Until = t.AddDate(0, 0, 1)
+	}
+	if req.PlaylistID != "" {
+		if h.playlistSvc == nil {
+			h.writeError(w, http.StatusNotFound, "playlist not found")
+			return
+		}
+		playlist, err := h.playlistSvc.Get(r.Context(), domain.PlaylistID(req.PlaylistID))
+		if err != nil {
+			h.writeAskError(w, err)
+			return
+		}
+		opts.TweetIDs = append([]string{}, playlist.Items...)
+	}
+
+	if req.Stream || strings.Contains(r.Header.Get("Accept"), "text/event-stream") {
+		h.stream(w, r, opts)
+		return
+	}
+
+	answer, err := h.tweetSvc.Ask(r.Context(), opts, nil)
+	if err != nil {
+		h.writeAskError(w, err)
+		return
+	}
+	h.writeJSON(w, http.StatusOK, answer)
+}
+
+// stream answers a question over Server-Sent Events. The event stream starts
+// with the first piece of the answer, so errors found before then, such as
+// no matching tweets, still get a normal JSON error response.
+func (h *AskHandler) stream(w http.ResponseWriter, r *http.Request, opts service.AskOptions) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		h.writeError(w, http.StatusInternalServerError, "streaming not supported")
+		return
+	}
+
+	started := false
+	send := func(event string, data interface{}) {
+		if !started {
+			w.Header().Set("Content-Type", "text/event-stream")
+			w.Header().Set("Cache-Control", "no-cache")
+			w.Header().Set("Connection", "keep-alive")
+			w.WriteHeader(http.StatusOK)
+			started = true
+		}
+		payload, err := json.Marshal(data)
+		if err != nil {
+			h.logger.Warn("failed to serialize ask event", "event", event, "error", err)
+			return
+		}
+		fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event, payload)
+		flusher.Flush()
+	}
+
+	answer, err := h.tweetSvc.Ask(r.Context(), opts, func(text string) {
+		send("delta", map[string]string{"text": text})
+	})
+	if err != nil {
+		if !started {
+			h.writeAskError(w, err)
+			return
+		}
+		h.logger.Error("streamed answer failed", "error", err)
+		send("error", map[string]string{"error": "failed to answer question"})
+		return
+	}
+	send("done", answer)
+}
+
+// writeAskError maps ask errors to HTTP responses.
+func (h *AskHandler) writeAskError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, service.ErrEmptyQuestion):
+		h.writeError(w, http.StatusBadRequest, err.Error())
+	case errors.Is(err, service.ErrNoAskSources), errors.Is(err, domain.ErrPlaylistNotFound):
+		h.writeError(w, http.StatusNotFound, err.Error())
+	case errors.Is(err, domain.ErrAIBudgetExceeded):
+		h.writeError(w, http.StatusTooManyRequests, err.Error())
+	default:
+		h.logger.Error("ask failed", "error", err)
+		h.writeError(w, http.StatusInternalServerError, "failed to answer question")
+	}
+}
+
+func (h *AskHandler) writeJSON(w http.ResponseWriter, status int, data interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(data)
+}
+
+func (h *AskHandler) writeError(w http.ResponseWriter, status int, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(map[string]string{"error": message})
+}
@@ -0,0 +1,124 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/iconidentify/xgrabba/internal/config"
+	"github.com/iconidentify/xgrabba/internal/domain"
+	"github.com/iconidentify/xgrabba/internal/repository"
+	"github.com/iconidentify/xgrabba/internal/service"
+	"github.com/iconidentify/xgrabba/pkg/grok"
+)
+
+// newAskTestHandler archives one tweet about cats and answers every question
+// from a fake OpenAI-compatible server, streaming when asked to.
+func newAskTestHandler(t *testing.T) *AskHandler {
+	t.Helper()
+	ai := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Stream bool `json:"stream"`
+		}
+		json.NewDecoder(r.Body).Decode(&req)
+		if !req.Stream {
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"choices": []map[string]interface{}{{"message": map[string]string{"content": "Cats purr [tweet:1]."}}},
+			})
+			return
+		}
+		for _, piece := range []string{"Cats purr ", "[tweet:1]."} {
+			fmt.Fprintf(w, "data: {\"choices\":[{\"delta\":{\"content\":%q}}]}\n\n", piece)
+		}
+		fmt.Fprint(w, "data: [DONE]\n\n")
+	}))
+	t.Cleanup(ai.Close)
+
+	base := t.TempDir()
+	dir := filepath.Join(base, "2024", "01", "alice_1")
+	os.MkdirAll(dir, 0755)
+	stored := domain.StoredTweet{TweetID: "1", Text: "Why cats purr", PostedAt: time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)}
+	stored.Author.Username = "alice"
+	data, _ := json.Marshal(stored)
+	os.WriteFile(filepath.Join(dir, "tweet.json"), data, 0644)
+
+	router := grok.NewRouterFromConfig(config.GrokConfig{}, config.AIConfig{
+		Provider: "openai",
+		OpenAI:   config.OpenAIConfig{BaseURL: ai.URL, Model: "llama3.2", Timeout: 5 * time.Second},
+	})
+	svc := service.NewTweetService(router, nil, nil, config.StorageConfig{BasePath: base}, config.AIConfig{}, false, testLogger(), nil)
+	playlists := service.NewPlaylistService(repository.NewFilesystemPlaylistRepository(t.TempDir()), svc, testLogger())
+	return NewAskHandler(svc, playlists, testLogger())
+}
+
+func TestAskHandler_JSON(t *testing.T) {
+	h := newAskTestHandler(t)
+
+	w := httptest.NewRecorder()
+	h.Ask(w, httptest.NewRequest(http.MethodPost, "/api/v1/ask", strings.NewReader(`{"question":"why do cats purr?"}`)))
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d: %s", w.Code, w.Body.String())
+	}
+	var answer service.AskAnswer
+	if err := json.NewDecoder(w.Body).Decode(&answer); err != nil {
+		t.Fatal(err)
+	}
+	if answer.Answer != "Cats purr [tweet:1]." || len(answer.Citations) != 1 || len(answer.Sources) != 1 {
+		t.Errorf("answer = %+v", answer)
+	}
+}
+
+func TestAskHandler_Stream(t *testing.T) {
+	h := newAskTestHandler(t)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/ask?q=cats+purr&from=2024-01-01&to=2024-01-02", nil)
+	req.Header.Set("Accept", "text/event-stream")
+	w := httptest.NewRecorder()
+	h.Ask(w, req)
+
+	if ct := w.Header().Get("Content-Type"); ct != "text/event-stream" {
+		t.Fatalf("content type = %q: %s", ct, w.Body.String())
+	}
+	body := w.Body.String()
+	for _, want := range []string{
+		"event: delta\ndata: {\"text\":\"Cats purr \"}\n\n",
+		"event: delta\ndata: {\"text\":\"[tweet:1].\"}\n\n",
+		"event: done\ndata: {\"question\":\"cats purr\"",
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("stream missing %q:\n%s", want, body)
+		}
+	}
+}
+
+func TestAskHandler_Errors(t *testing.T) {
+	h := newAskTestHandler(t)
+
+	tests := []struct {
+		name string
+		body string
+		want int
+	}{
+		{"invalid body", `{`, http.StatusBadRequest},
+		{"empty question", `{"question":" "}`, http.StatusBadRequest},
+		{"bad date", `{"question":"cats","from":"yesterday"}`, http.StatusBadRequest},
+		{"no matches", `{"question":"dogs bark"}`, http.StatusNotFound},
+		{"outside date range", `{"question":"cats","to":"2023-12-31","stream":true}`, http.StatusNotFound},
+		{"unknown playlist", `{"question":"cats","playlist_id":"nope"}`, http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			h.Ask(w, httptest.NewRequest(http.MethodPost, "/api/v1/ask", strings.NewReader(tt.body)))
+			if w.Code != tt.want {
+				t.Errorf("status = %d, want %d: %s", w.Code, tt.want, w.Body.String())
+			}
+		})
+	}
+}
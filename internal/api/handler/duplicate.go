@@ -0,0 +1,104 @@
+package handler
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/iconidentify/xgrabba/internal/service"
+)
+
+// DuplicateHandler handles duplicate media detection HTTP requests.
+type DuplicateHandler struct {
+	svc    *service.DuplicateService
+	logger *slog.Logger
+}
+
+// NewDuplicateHandler creates a new duplicate handler.
+func NewDuplicateHandler(svc *service.DuplicateService, logger *slog.Logger) *DuplicateHandler {
+	return &DuplicateHandler{
+		svc:    svc,
+		logger: logger,
+	}
+}
+
+// DuplicateListResponse contains duplicate clusters.
+type DuplicateListResponse struct {
+	Threshold        int                        `json:"threshold"`
+	Clusters         []service.DuplicateCluster `json:"clusters"`
+	Total            int                        `json:"total"`
+	ReclaimableBytes int64                      `json:"reclaimable_bytes"`
+}
+
+// DedupeRequest is the JSON request body for hardlink deduplication.
+type DedupeRequest struct {
+	DryRun bool `json:"dry_run"`
+}
+
+// List handles GET /api/v1/duplicates
+// Query parameters:
+//   - threshold: max pHash Hamming distance for near-duplicates (0-32, default 8; 0 = identical files only)
+func (h *DuplicateHandler) List(w http.ResponseWriter, r *http.Request) {
+	threshold := service.DefaultDuplicateThreshold
+	if t := r.URL.Query().Get("threshold"); t != "" {
+		parsed, err := strconv.Atoi(t)
+		if err != nil || parsed < 0 || parsed > 32 {
+			h.writeError(w, http.StatusBadRequest, "threshold must be 0-32")
+			return
+		}
+		threshold = parsed
+	}
+
+	clusters, err := h.svc.FindDuplicates(r.Context(), threshold)
+	if err != nil {
+		h.logger.Error("find duplicates failed", "error", err)
+		h.writeError(w, http.StatusInternalServerError, "failed to find duplicates")
+		return
+	}
+	if clusters == nil {
+		clusters = []service.DuplicateCluster{}
+	}
+
+	resp := DuplicateListResponse{
+		Threshold: threshold,
+		Clusters:  clusters,
+		Total:     len(clusters),
+	}
+	for _, c := range clusters {
+		resp.ReclaimableBytes += c.ReclaimableBytes
+	}
+	h.writeJSON(w, http.StatusOK, resp)
+}
+
+// Dedupe handles POST /api/v1/duplicates/dedupe
+// Replaces byte-identical media files across archives with hardlinks.
+func (h *DuplicateHandler) Dedupe(w http.ResponseWriter, r *http.Request) {
+	var req DedupeRequest
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			h.writeError(w, http.StatusBadRequest, "invalid request body")
+			return
+		}
+	}
+
+	result, err := h.svc.Dedupe(r.Context(), req.DryRun)
+	if err != nil {
+		h.logger.Error("dedupe failed", "error", err)
+		h.writeError(w, http.StatusInternalServerError, "failed to deduplicate media")
+		return
+	}
+	h.writeJSON(w, http.StatusOK, result)
+}
+
+func (h *DuplicateHandler) writeJSON(w http.ResponseWriter, status int, data interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(data)
+}
+
+func (h *DuplicateHandler) writeError(w http.ResponseWriter, status int, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(map[string]string{"error": message})
+}
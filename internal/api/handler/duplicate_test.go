@@ -0,0 +1,22 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestDuplicateHandler_List_InvalidThreshold(t *testing.T) {
+	h := NewDuplicateHandler(nil, testLogger())
+
+	for _, threshold := range []string{"abc", "-1", "33"} {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/duplicates?threshold="+threshold, nil)
+		w := httptest.NewRecorder()
+
+		h.List(w, req)
+
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("threshold=%s: status = %d, want %d", threshold, w.Code, http.StatusBadRequest)
+		}
+	}
+}
@@ -35,6 +35,8 @@ type ExportStartRequest struct {
 	Download       bool   `json:"download"` // If true, creates a downloadable zip instead of writing to dest_path
 	Encrypt        bool   `json:"encrypt"`  // If true, encrypts the archive with the given password
 	Password       string `json:"password"` // Password for encryption (required if encrypt is true)
+	// ExcludeCredentialed skips tweets fetched with the user's browser session
+	ExcludeCredentialed bool `json:"exclude_credentialed"`
 }
 
 // ExportStartResponse is the response for starting an export.
@@ -96,6 +98,8 @@ func (h *ExportHandler) Start(w http.ResponseWriter, r *http.Request) {
 		ViewerBinDir:   "bin", // Default viewer binary location
 		Encrypt:        req.Encrypt,
 		Password:       req.Password,
+
+		ExcludeCredentialed: req.ExcludeCredentialed,
 	}
 
 	var exportID string
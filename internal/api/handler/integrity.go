@@ -0,0 +1,71 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/iconidentify/xgrabba/internal/service"
+)
+
+// IntegrityHandler handles archive integrity scrub HTTP requests.
+type IntegrityHandler struct {
+	svc    *service.IntegrityService
+	logger *slog.Logger
+}
+
+// NewIntegrityHandler creates a new integrity handler.
+func NewIntegrityHandler(svc *service.IntegrityService, logger *slog.Logger) *IntegrityHandler {
+	return &IntegrityHandler{
+		svc:    svc,
+		logger: logger,
+	}
+}
+
+// IntegrityStatusResponse reports scrub state and the most recent result.
+type IntegrityStatusResponse struct {
+	Running    bool                 `json:"running"`
+	LastReport *service.ScrubReport `json:"last_report,omitempty"`
+}
+
+// Status handles GET /api/v1/integrity
+func (h *IntegrityHandler) Status(w http.ResponseWriter, r *http.Request) {
+	h.writeJSON(w, http.StatusOK, IntegrityStatusResponse{
+		Running:    h.svc.Running(),
+		LastReport: h.svc.LastReport(),
+	})
+}
+
+// Scrub handles POST /api/v1/integrity/scrub
+// Starts a full scrub in the background; poll GET /api/v1/integrity for the report.
+func (h *IntegrityHandler) Scrub(w http.ResponseWriter, r *http.Request) {
+	if h.svc.Running() {
+		h.writeError(w, http.StatusConflict, service.ErrScrubInProgress.Error())
+		return
+	}
+
+	go func() {
+		if _, err := h.svc.Scrub(context.Background()); err != nil && !errors.Is(err, service.ErrScrubInProgress) {
+			h.logger.Error("integrity scrub failed", "error", err)
+		}
+	}()
+
+	h.writeJSON(w, http.StatusAccepted, map[string]string{
+		"status":  "started",
+		"message": "Integrity scrub started",
+	})
+}
+
+func (h *IntegrityHandler) writeJSON(w http.ResponseWriter, status int, data interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(data)
+}
+
+func (h *IntegrityHandler) writeError(w http.ResponseWriter, status int, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(map[string]string{"error": message})
+}
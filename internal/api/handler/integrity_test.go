@@ -0,0 +1,32 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/iconidentify/xgrabba/internal/config"
+	"github.com/iconidentify/xgrabba/internal/service"
+)
+
+func TestIntegrityHandler_StatusBeforeFirstScrub(t *testing.T) {
+	svc := service.NewIntegrityService(nil, config.IntegrityConfig{}, testLogger(), nil)
+	h := NewIntegrityHandler(svc, testLogger())
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/integrity", nil)
+	w := httptest.NewRecorder()
+
+	h.Status(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d", w.Code, http.StatusOK)
+	}
+	var resp IntegrityStatusResponse
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatal(err)
+	}
+	if resp.Running || resp.LastReport != nil {
+		t.Errorf("resp = %+v, want idle with no report", resp)
+	}
+}
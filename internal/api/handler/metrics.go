@@ -0,0 +1,107 @@
+package handler
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/iconidentify/xgrabba/internal/domain"
+	"github.com/iconidentify/xgrabba/internal/service"
+)
+
+// MetricsHandler handles engagement metrics history HTTP requests.
+type MetricsHandler struct {
+	svc    *service.MetricsService
+	logger *slog.Logger
+}
+
+// NewMetricsHandler creates a new metrics handler.
+func NewMetricsHandler(svc *service.MetricsService, logger *slog.Logger) *MetricsHandler {
+	return &MetricsHandler{
+		svc:    svc,
+		logger: logger,
+	}
+}
+
+// MetricsHistoryResponse is the JSON form of a tweet's metrics history.
+type MetricsHistoryResponse struct {
+	TweetID string                 `json:"tweet_id"`
+	Samples []domain.MetricsSample `json:"samples"`
+}
+
+// History handles GET /api/v1/tweets/{tweetID}/metrics/history
+// Pass ?format=csv to download the samples as CSV.
+func (h *MetricsHandler) History(w http.ResponseWriter, r *http.Request) {
+	tweetID := chi.URLParam(r, "tweetID")
+	if tweetID == "" {
+		h.writeError(w, http.StatusBadRequest, "missing tweet ID")
+		return
+	}
+
+	samples, err := h.svc.History(r.Context(), domain.TweetID(tweetID))
+	if err != nil {
+		if errors.Is(err, domain.ErrVideoNotFound) {
+			h.writeError(w, http.StatusNotFound, "tweet not found")
+			return
+		}
+		h.logger.Error("read metrics history failed", "tweet_id", tweetID, "error", err)
+		h.writeError(w, http.StatusInternalServerError, "failed to read metrics history")
+		return
+	}
+
+	switch r.URL.Query().Get("format") {
+	case "", "json":
+		h.writeJSON(w, http.StatusOK, MetricsHistoryResponse{TweetID: tweetID, Samples: samples})
+	case "csv":
+		h.writeCSV(w, tweetID, samples)
+	default:
+		h.writeError(w, http.StatusBadRequest, "format must be json or csv")
+	}
+}
+
+func (h *MetricsHandler) writeCSV(w http.ResponseWriter, tweetID string, samples []domain.MetricsSample) {
+	w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="metrics-%s.csv"`, tweetID))
+	w.WriteHeader(http.StatusOK)
+
+	cw := csv.NewWriter(w)
+	cw.Write([]string{"sampled_at", "source", "likes", "retweets", "replies", "quotes", "views"})
+	for _, s := range samples {
+		views := "" // Left empty when the sample has no view count
+		if s.Views != nil {
+			views = strconv.Itoa(*s.Views)
+		}
+		cw.Write([]string{
+			s.SampledAt.UTC().Format(time.RFC3339),
+			s.Source,
+			strconv.Itoa(s.Likes),
+			strconv.Itoa(s.Retweets),
+			strconv.Itoa(s.Replies),
+			strconv.Itoa(s.Quotes),
+			views,
+		})
+	}
+	cw.Flush()
+	if err := cw.Error(); err != nil {
+		h.logger.Warn("failed to write metrics CSV", "tweet_id", tweetID, "error", err)
+	}
+}
+
+func (h *MetricsHandler) writeJSON(w http.ResponseWriter, status int, data interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(data)
+}
+
+func (h *MetricsHandler) writeError(w http.ResponseWriter, status int, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(map[string]string{"error": message})
+}
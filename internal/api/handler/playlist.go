@@ -51,6 +51,7 @@ type PlaylistResponse struct {
 	SmartConfig *domain.SmartPlaylistConfig `json:"smart_config,omitempty"`
 	Items       []string                   `json:"items"`
 	ItemCount   int                        `json:"item_count"`
+	Digest      *DocumentResponse          `json:"digest,omitempty"`
 	CreatedAt   string                     `json:"created_at"`
 	UpdatedAt   string                     `json:"updated_at"`
 }
@@ -61,6 +62,11 @@ func toPlaylistResponse(p *domain.Playlist) PlaylistResponse {
 	if playlistType == "" {
 		playlistType = string(domain.PlaylistTypeManual) // Default for legacy playlists
 	}
+	var digest *DocumentResponse
+	if p.Digest != nil {
+		d := newDocumentResponse(*p.Digest)
+		digest = &d
+	}
 	return PlaylistResponse{
 		ID:          p.ID.String(),
 		Name:        p.Name,
@@ -69,6 +75,7 @@ func toPlaylistResponse(p *domain.Playlist) PlaylistResponse {
 		SmartConfig: p.SmartConfig,
 		Items:       p.Items,
 		ItemCount:   len(p.Items),
+		Digest:      digest,
 		CreatedAt:   p.CreatedAt.Format("2006-01-02T15:04:05Z"),
 		UpdatedAt:   p.UpdatedAt.Format("2006-01-02T15:04:05Z"),
 	}
@@ -471,3 +478,71 @@ func (h *PlaylistHandler) Preview(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(PreviewResponse{Items: items, Total: mediaCount})
 }
+
+// writeDigestError maps digest errors to HTTP responses.
+func (h *PlaylistHandler) writeDigestError(w http.ResponseWriter, id string, err error, action string) {
+	if status := documentErrorStatus(err); status != 0 {
+		http.Error(w, err.Error(), status)
+		return
+	}
+	h.logger.Error("failed to "+action, "id", id, "error", err)
+	http.Error(w, "Failed to "+action, http.StatusInternalServerError)
+}
+
+// GenerateDigest starts generating an AI digest of the playlist's tweets.
+func (h *PlaylistHandler) GenerateDigest(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	if id == "" {
+		http.Error(w, "Missing playlist ID", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.svc.StartGenerateDigest(r.Context(), domain.PlaylistID(id)); err != nil {
+		h.writeDigestError(w, id, err, "generate digest")
+		return
+	}
+	digest, err := h.svc.GetDigest(r.Context(), domain.PlaylistID(id))
+	if err != nil {
+		h.writeDigestError(w, id, err, "get digest")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusAccepted)
+	json.NewEncoder(w).Encode(newDocumentResponse(*digest))
+}
+
+// GetDigest returns the playlist's digest. Poll until status is no longer
+// "generating".
+func (h *PlaylistHandler) GetDigest(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	if id == "" {
+		http.Error(w, "Missing playlist ID", http.StatusBadRequest)
+		return
+	}
+
+	digest, err := h.svc.GetDigest(r.Context(), domain.PlaylistID(id))
+	if err != nil {
+		h.writeDigestError(w, id, err, "get digest")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(newDocumentResponse(*digest))
+}
+
+// DeleteDigest removes the playlist's digest.
+func (h *PlaylistHandler) DeleteDigest(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	if id == "" {
+		http.Error(w, "Missing playlist ID", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.svc.DeleteDigest(r.Context(), domain.PlaylistID(id)); err != nil {
+		h.writeDigestError(w, id, err, "delete digest")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
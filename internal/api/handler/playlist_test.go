@@ -263,3 +263,38 @@ func TestToPlaylistResponse(t *testing.T) {
 		t.Errorf("ItemCount = %d, want 2", resp.ItemCount)
 	}
 }
+
+func TestPlaylistHandler_Digest(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelError}))
+	repo := repository.NewFilesystemPlaylistRepository(t.TempDir())
+	svc := service.NewPlaylistService(repo, nil, logger)
+	handler := NewPlaylistHandler(svc, logger)
+
+	playlist, _ := svc.Create(context.Background(), "Digest Test", "")
+
+	tests := []struct {
+		name    string
+		method  string
+		handler http.HandlerFunc
+		id      string
+		want    int
+	}{
+		{"get without digest", http.MethodGet, handler.GetDigest, playlist.ID.String(), http.StatusNotFound},
+		{"generate without tweets", http.MethodPost, handler.GenerateDigest, playlist.ID.String(), http.StatusUnprocessableEntity},
+		{"delete missing playlist", http.MethodDelete, handler.DeleteDigest, "nonexistent", http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, "/api/v1/playlists/"+tt.id+"/digest", nil)
+			rctx := chi.NewRouteContext()
+			rctx.URLParams.Add("id", tt.id)
+			req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+
+			w := httptest.NewRecorder()
+			tt.handler(w, req)
+			if w.Code != tt.want {
+				t.Errorf("status = %d, want %d: %s", w.Code, tt.want, w.Body.String())
+			}
+		})
+	}
+}
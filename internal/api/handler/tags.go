@@ -0,0 +1,196 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/url"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/iconidentify/xgrabba/internal/domain"
+	"github.com/iconidentify/xgrabba/internal/service"
+)
+
+// TagHandler handles AI tag listing and curation.
+type TagHandler struct {
+	svc    *service.TweetService
+	logger *slog.Logger
+}
+
+// NewTagHandler creates a new tag handler.
+func NewTagHandler(svc *service.TweetService, logger *slog.Logger) *TagHandler {
+	return &TagHandler{
+		svc:    svc,
+		logger: logger,
+	}
+}
+
+// TagRequest is the body of requests that name a single tag.
+type TagRequest struct {
+	Tag string `json:"tag"`
+}
+
+// MergeTagsRequest is the body of POST /api/v1/tags/merge.
+type MergeTagsRequest struct {
+	From []string `json:"from"`
+	Into string   `json:"into"`
+}
+
+// RenameTagRequest is the body of POST /api/v1/tags/rename.
+type RenameTagRequest struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+// TagRegistryResponse is returned by requests that change the registry.
+type TagRegistryResponse struct {
+	Registry domain.TagRegistry     `json:"registry"`
+	Apply    service.TagApplyStatus `json:"apply"` // Background rewrite of archived tags
+}
+
+// List handles GET /api/v1/tags?q=...
+// Returns the curated AI tags and topics with the number of tweets carrying
+// each, most used first, and the blocklist.
+func (h *TagHandler) List(w http.ResponseWriter, r *http.Request) {
+	tags := h.svc.TagCounts(r.URL.Query().Get("q"))
+	h.writeJSON(w, http.StatusOK, map[string]interface{}{
+		"tags":    tags,
+		"total":   len(tags),
+		"blocked": h.svc.TagRegistry().Blocked,
+	})
+}
+
+// Registry handles GET /api/v1/tags/registry
+func (h *TagHandler) Registry(w http.ResponseWriter, r *http.Request) {
+	h.writeJSON(w, http.StatusOK, h.svc.TagRegistry())
+}
+
+// Apply handles POST /api/v1/tags/apply
+// Starts curating the tags of every archived tweet with the current registry
+// in the background.
+func (h *TagHandler) Apply(w http.ResponseWriter, r *http.Request) {
+	h.writeJSON(w, http.StatusAccepted, TagRegistryResponse{
+		Registry: h.svc.TagRegistry(),
+		Apply:    h.svc.StartTagRegistryApply(),
+	})
+}
+
+// ApplyStatus handles GET /api/v1/tags/apply
+// Reports whether archived tags are being rewritten and how many tweets the
+// last run changed.
+func (h *TagHandler) ApplyStatus(w http.ResponseWriter, r *http.Request) {
+	h.writeJSON(w, http.StatusOK, h.svc.TagApplyStatus())
+}
+
+// AddCanonical handles POST /api/v1/tags/canonical
+func (h *TagHandler) AddCanonical(w http.ResponseWriter, r *http.Request) {
+	var req TagRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.writeError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	h.update(w, func(reg *domain.TagRegistry) error { return reg.AddCanonical(req.Tag) })
+}
+
+// RemoveCanonical handles DELETE /api/v1/tags/canonical/{tag}
+func (h *TagHandler) RemoveCanonical(w http.ResponseWriter, r *http.Request) {
+	tag := h.tagParam(r, "tag")
+	h.update(w, func(reg *domain.TagRegistry) error {
+		reg.RemoveCanonical(tag)
+		return nil
+	})
+}
+
+// Merge handles POST /api/v1/tags/merge
+// Makes every tag in from an alias of into and rewrites archived tweets.
+func (h *TagHandler) Merge(w http.ResponseWriter, r *http.Request) {
+	var req MergeTagsRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.writeError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	h.update(w, func(reg *domain.TagRegistry) error { return reg.Merge(req.Into, req.From...) })
+}
+
+// Rename handles POST /api/v1/tags/rename
+// Renames a tag; the old name becomes an alias of the new one.
+func (h *TagHandler) Rename(w http.ResponseWriter, r *http.Request) {
+	var req RenameTagRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.writeError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	h.update(w, func(reg *domain.TagRegistry) error { return reg.Merge(req.To, req.From) })
+}
+
+// RemoveAlias handles DELETE /api/v1/tags/aliases/{alias}
+// Archived tweets get the original tag back from their raw AI tags.
+func (h *TagHandler) RemoveAlias(w http.ResponseWriter, r *http.Request) {
+	alias := h.tagParam(r, "alias")
+	h.update(w, func(reg *domain.TagRegistry) error { return reg.RemoveAlias(alias) })
+}
+
+// Block handles POST /api/v1/tags/blocked
+// Adds a tag to the blocklist and removes it from archived tweets.
+func (h *TagHandler) Block(w http.ResponseWriter, r *http.Request) {
+	var req TagRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.writeError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	h.update(w, func(reg *domain.TagRegistry) error { return reg.Block(req.Tag) })
+}
+
+// Unblock handles DELETE /api/v1/tags/blocked/{tag}
+func (h *TagHandler) Unblock(w http.ResponseWriter, r *http.Request) {
+	tag := h.tagParam(r, "tag")
+	h.update(w, func(reg *domain.TagRegistry) error {
+		reg.Unblock(tag)
+		return nil
+	})
+}
+
+// update changes the registry, starts applying it to archived tweets and
+// writes the result.
+func (h *TagHandler) update(w http.ResponseWriter, fn func(*domain.TagRegistry) error) {
+	registry, apply, err := h.svc.UpdateTagRegistry(fn)
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrEmptyTag):
+			h.writeError(w, http.StatusBadRequest, err.Error())
+		case errors.Is(err, domain.ErrTagBlocked):
+			h.writeError(w, http.StatusConflict, err.Error())
+		case errors.Is(err, domain.ErrTagAliasNotFound):
+			h.writeError(w, http.StatusNotFound, err.Error())
+		default:
+			h.logger.Error("tag registry update failed", "error", err)
+			h.writeError(w, http.StatusInternalServerError, "failed to update tag registry")
+		}
+		return
+	}
+	h.writeJSON(w, http.StatusOK, TagRegistryResponse{Registry: registry, Apply: apply})
+}
+
+// tagParam returns a URL parameter, decoding escapes such as %20 in tags
+// with spaces.
+func (h *TagHandler) tagParam(r *http.Request, name string) string {
+	value := chi.URLParam(r, name)
+	if unescaped, err := url.PathUnescape(value); err == nil {
+		return unescaped
+	}
+	return value
+}
+
+func (h *TagHandler) writeJSON(w http.ResponseWriter, status int, data interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(data)
+}
+
+func (h *TagHandler) writeError(w http.ResponseWriter, status int, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(map[string]string{"error": message})
+}
@@ -0,0 +1,76 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/iconidentify/xgrabba/internal/config"
+	"github.com/iconidentify/xgrabba/internal/service"
+	"github.com/iconidentify/xgrabba/pkg/grok"
+)
+
+func TestTagHandler(t *testing.T) {
+	base := t.TempDir()
+	svc := service.NewTweetService(grok.NewRouter(), nil, nil, config.StorageConfig{BasePath: base}, config.AIConfig{}, false, testLogger(), nil)
+	svc.SetTagRegistry(service.NewTagRegistryService(base, testLogger()))
+	h := NewTagHandler(svc, testLogger())
+
+	tests := []struct {
+		name    string
+		handler http.HandlerFunc
+		body    string
+		want    int
+	}{
+		{"merge", h.Merge, `{"from":["AI","artificial-intelligence"],"into":"Artificial Intelligence"}`, http.StatusOK},
+		{"merge without target", h.Merge, `{"from":["x"]}`, http.StatusBadRequest},
+		{"block", h.Block, `{"tag":"#Viral"}`, http.StatusOK},
+		{"merge into blocked", h.Merge, `{"from":["trending"],"into":"viral"}`, http.StatusConflict},
+		{"rename", h.Rename, `{"from":"Machine Learning","to":"ml"}`, http.StatusOK},
+		{"invalid body", h.AddCanonical, `{`, http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		w := httptest.NewRecorder()
+		tt.handler(w, httptest.NewRequest(http.MethodPost, "/api/v1/tags", strings.NewReader(tt.body)))
+		if w.Code != tt.want {
+			t.Errorf("%s: status = %d, want %d (%s)", tt.name, w.Code, tt.want, w.Body.String())
+		}
+	}
+
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("alias", "machine%20learning")
+	req := httptest.NewRequest(http.MethodDelete, "/api/v1/tags/aliases/x", nil)
+	w := httptest.NewRecorder()
+	h.RemoveAlias(w, req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx)))
+	if w.Code != http.StatusOK {
+		t.Errorf("remove alias: status = %d, want %d", w.Code, http.StatusOK)
+	}
+
+	w = httptest.NewRecorder()
+	h.List(w, httptest.NewRequest(http.MethodGet, "/api/v1/tags", nil))
+	var list struct {
+		Tags []struct {
+			Tag     string   `json:"tag"`
+			Count   int      `json:"count"`
+			Aliases []string `json:"aliases"`
+		} `json:"tags"`
+		Total   int      `json:"total"`
+		Blocked []string `json:"blocked"`
+	}
+	if err := json.NewDecoder(w.Body).Decode(&list); err != nil {
+		t.Fatal(err)
+	}
+	if list.Total != 2 || len(list.Blocked) != 1 || list.Blocked[0] != "viral" {
+		t.Errorf("list = %+v", list)
+	}
+	for _, tag := range list.Tags {
+		if tag.Tag == "artificial intelligence" && strings.Join(tag.Aliases, ",") != "ai" {
+			t.Errorf("aliases = %v, want [ai]", tag.Aliases)
+		}
+	}
+}
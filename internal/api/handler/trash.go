@@ -0,0 +1,119 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/iconidentify/xgrabba/internal/domain"
+	"github.com/iconidentify/xgrabba/internal/service"
+)
+
+// TrashHandler handles HTTP requests for deleted archives.
+type TrashHandler struct {
+	tweetSvc *service.TweetService
+	logger   *slog.Logger
+}
+
+// NewTrashHandler creates a new trash handler.
+func NewTrashHandler(tweetSvc *service.TweetService, logger *slog.Logger) *TrashHandler {
+	return &TrashHandler{
+		tweetSvc: tweetSvc,
+		logger:   logger,
+	}
+}
+
+// TrashListResponse lists deleted archives.
+type TrashListResponse struct {
+	Entries []service.TrashEntry `json:"entries"`
+	Total   int                  `json:"total"`
+}
+
+// List handles GET /api/v1/trash
+func (h *TrashHandler) List(w http.ResponseWriter, r *http.Request) {
+	entries, err := h.tweetSvc.ListTrash(r.Context())
+	if err != nil {
+		h.logger.Error("list trash failed", "error", err)
+		h.writeError(w, http.StatusInternalServerError, "failed to list trash")
+		return
+	}
+	h.writeJSON(w, http.StatusOK, TrashListResponse{Entries: entries, Total: len(entries)})
+}
+
+// Restore handles POST /api/v1/trash/{tweetID}/restore
+func (h *TrashHandler) Restore(w http.ResponseWriter, r *http.Request) {
+	tweetID := chi.URLParam(r, "tweetID")
+	if tweetID == "" {
+		h.writeError(w, http.StatusBadRequest, "missing tweet ID")
+		return
+	}
+
+	tweet, err := h.tweetSvc.RestoreFromTrash(r.Context(), domain.TweetID(tweetID))
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrVideoNotFound):
+			h.writeError(w, http.StatusNotFound, "tweet not in trash")
+		case errors.Is(err, domain.ErrDuplicateVideo):
+			h.writeError(w, http.StatusConflict, "tweet has been archived again; purge the trashed copy instead")
+		default:
+			h.logger.Error("restore from trash failed", "tweet_id", tweetID, "error", err)
+			h.writeError(w, http.StatusInternalServerError, "failed to restore tweet")
+		}
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, map[string]string{
+		"tweet_id":     string(tweet.ID),
+		"status":       "restored",
+		"archive_path": tweet.ArchivePath,
+	})
+}
+
+// Purge handles DELETE /api/v1/trash/{tweetID}
+// Permanently deletes one trashed archive.
+func (h *TrashHandler) Purge(w http.ResponseWriter, r *http.Request) {
+	tweetID := chi.URLParam(r, "tweetID")
+	if tweetID == "" {
+		h.writeError(w, http.StatusBadRequest, "missing tweet ID")
+		return
+	}
+
+	if err := h.tweetSvc.PurgeTrash(r.Context(), domain.TweetID(tweetID)); err != nil {
+		if errors.Is(err, domain.ErrVideoNotFound) {
+			h.writeError(w, http.StatusNotFound, "tweet not in trash")
+			return
+		}
+		h.logger.Error("purge from trash failed", "tweet_id", tweetID, "error", err)
+		h.writeError(w, http.StatusInternalServerError, "failed to purge tweet")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Empty handles DELETE /api/v1/trash
+// Permanently deletes every trashed archive.
+func (h *TrashHandler) Empty(w http.ResponseWriter, r *http.Request) {
+	purged, err := h.tweetSvc.EmptyTrash(r.Context())
+	if err != nil {
+		h.logger.Error("empty trash failed", "error", err)
+		h.writeError(w, http.StatusInternalServerError, "failed to empty trash")
+		return
+	}
+	h.writeJSON(w, http.StatusOK, map[string]int{"purged": purged})
+}
+
+func (h *TrashHandler) writeJSON(w http.ResponseWriter, status int, data interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(data)
+}
+
+func (h *TrashHandler) writeError(w http.ResponseWriter, status int, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(map[string]string{"error": message})
+}
@@ -40,13 +40,39 @@ type ArchiveRequest struct {
 	AuthorAvatarURL   string `json:"author_avatar_url,omitempty"`
 	AuthorDisplayName string `json:"author_display_name,omitempty"`
 	AuthorUsername    string `json:"author_username,omitempty"`
+	// Optional media quality policy for this tweet (defaults to the server's)
+	Quality *domain.MediaQuality `json:"quality,omitempty"`
+	// Optional pipeline switches
+	SkipAI            bool     `json:"skip_ai,omitempty"`
+	SkipTranscription bool     `json:"skip_transcription,omitempty"`
+	MediaOnly         bool     `json:"media_only,omitempty"`
+	TextOnly          bool     `json:"text_only,omitempty"`
+	PlaylistIDs       []string `json:"playlist_ids,omitempty"`
+	Tags              []string `json:"tags,omitempty"`
+	Priority          string   `json:"priority,omitempty"`
+}
+
+// options converts the request's pipeline switches.
+func (r ArchiveRequest) options() domain.ArchiveOptions {
+	opts := domain.ArchiveOptions{
+		SkipAI:            r.SkipAI,
+		SkipTranscription: r.SkipTranscription,
+		MediaOnly:         r.MediaOnly,
+		TextOnly:          r.TextOnly,
+		Priority:          domain.ArchivePriority(r.Priority),
+	}
+	for _, id := range r.PlaylistIDs {
+		opts.PlaylistIDs = append(opts.PlaylistIDs, domain.PlaylistID(id))
+	}
+	return opts
 }
 
 // ArchiveResponse is the JSON response after submission.
 type ArchiveResponse struct {
-	TweetID string `json:"tweet_id"`
-	Status  string `json:"status"`
-	Message string `json:"message"`
+	TweetID       string `json:"tweet_id"`
+	Status        string `json:"status"`
+	Message       string `json:"message"`
+	FailureReason string `json:"failure_reason,omitempty"`
 }
 
 // TweetResponse represents a tweet in list/get responses.
@@ -71,6 +97,8 @@ type TweetResponse struct {
 	ReplyCount   int `json:"reply_count,omitempty"`
 	QuoteCount   int `json:"quote_count,omitempty"`
 	ViewCount    int `json:"view_count,omitempty"`
+	// User tags from the archive request
+	Tags []string `json:"tags,omitempty"`
 	// AI metadata
 	AITitle       string   `json:"ai_title,omitempty"`
 	AISummary     string   `json:"ai_summary,omitempty"`
@@ -81,11 +109,14 @@ type TweetResponse struct {
 	AIInProgress bool `json:"ai_in_progress,omitempty"`
 	// Video transcripts (combined from all video media)
 	Transcripts []string `json:"transcripts,omitempty"`
+	// Timed transcript hits (search responses only)
+	TranscriptMatches []service.TranscriptMatch `json:"transcript_matches,omitempty"`
 	// Aggregated per-media AI (for search)
 	MediaTags     []string  `json:"media_tags,omitempty"`
 	MediaCaptions []string  `json:"media_captions,omitempty"`
 	ArchivePath   string    `json:"archive_path,omitempty"`
 	Error         string    `json:"error,omitempty"`
+	FailureReason string    `json:"failure_reason,omitempty"` // Machine-readable cause when status is failed
 	CreatedAt     time.Time `json:"created_at"`
 	// Article-specific fields (when content_type == "article")
 	ContentType    string `json:"content_type,omitempty"`
@@ -93,6 +124,13 @@ type TweetResponse struct {
 	ArticleBody    string `json:"article_body,omitempty"`
 	WordCount      int    `json:"word_count,omitempty"`
 	ReadingMinutes int    `json:"reading_minutes,omitempty"`
+	// Whether the tweet is still on X (empty until checked)
+	UpstreamStatus    string     `json:"upstream_status,omitempty"`
+	UpstreamChangedAt *time.Time `json:"upstream_changed_at,omitempty"`
+	// Only visible to the user's logged-in session when archived
+	FetchedWithCredentials bool `json:"fetched_with_credentials,omitempty"`
+	// Transcription and AI analysis wait for the AI budget to reset
+	AIPending bool `json:"ai_pending,omitempty"`
 }
 
 // MediaPreview represents a media item in list responses for thumbnails.
@@ -103,12 +141,20 @@ type MediaPreview struct {
 	Duration           int    `json:"duration,omitempty"`
 	Transcript         string `json:"transcript,omitempty"`
 	TranscriptLanguage string `json:"transcript_language,omitempty"`
+	SubtitleURL        string `json:"subtitle_url,omitempty"` // WebVTT track for video playback
+	OCRText            string `json:"ocr_text,omitempty"`     // Text extracted from the image/keyframes
+	AltText            string `json:"alt_text,omitempty"`     // Author's alt text, or the AI description
+	AltTextGenerated   bool   `json:"alt_text_generated,omitempty"`
 	// Essay fields
 	Essay         string `json:"essay,omitempty"`
 	EssayTitle    string `json:"essay_title,omitempty"`
 	EssayStatus   string `json:"essay_status,omitempty"`
 	EssayError    string `json:"essay_error,omitempty"`
 	EssayWordCount int   `json:"essay_word_count,omitempty"`
+	// AI-generated video chapters
+	Chapters    []domain.Chapter `json:"chapters,omitempty"`
+	ChaptersURL string           `json:"chapters_url,omitempty"` // WebVTT chapters track
+	PlaybackURL string           `json:"playback_url,omitempty"` // Copy of the video with chapters embedded
 }
 
 // TweetListResponse contains paginated tweet list.
@@ -133,6 +179,17 @@ func (h *TweetHandler) Archive(w http.ResponseWriter, r *http.Request) {
 		h.writeError(w, http.StatusBadRequest, "tweet_url is required")
 		return
 	}
+	if req.Quality != nil {
+		if err := req.Quality.Validate(); err != nil {
+			h.writeError(w, http.StatusBadRequest, "invalid quality: "+err.Error())
+			return
+		}
+	}
+	opts := req.options()
+	if err := opts.Validate(); err != nil {
+		h.writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
 
 	h.logger.Info("archive request received", "url", req.TweetURL,
 		"has_avatar_hint", req.AuthorAvatarURL != "")
@@ -142,6 +199,9 @@ func (h *TweetHandler) Archive(w http.ResponseWriter, r *http.Request) {
 		AuthorAvatarURL:   req.AuthorAvatarURL,
 		AuthorDisplayName: req.AuthorDisplayName,
 		AuthorUsername:    req.AuthorUsername,
+		Quality:           req.Quality,
+		Options:           opts,
+		Tags:              req.Tags,
 	})
 
 	if err != nil {
@@ -149,15 +209,20 @@ func (h *TweetHandler) Archive(w http.ResponseWriter, r *http.Request) {
 			h.writeError(w, http.StatusBadRequest, "invalid tweet URL - must be a valid x.com or twitter.com URL")
 			return
 		}
+		if errors.Is(err, domain.ErrPlaylistNotFound) || errors.Is(err, domain.ErrSmartPlaylistNoManualItems) {
+			h.writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
 		h.logger.Error("archive failed", "error", err)
 		h.writeError(w, http.StatusInternalServerError, "failed to archive tweet")
 		return
 	}
 
 	h.writeJSON(w, http.StatusAccepted, ArchiveResponse{
-		TweetID: string(result.TweetID),
-		Status:  string(result.Status),
-		Message: result.Message,
+		TweetID:       string(result.TweetID),
+		Status:        string(result.Status),
+		Message:       result.Message,
+		FailureReason: string(result.FailureReason),
 	})
 }
 
@@ -189,6 +254,9 @@ func (h *TweetHandler) Search(w http.ResponseWriter, r *http.Request) {
 	}
 
 	response := h.buildTweetListResponse(tweets, total, limit, offset)
+	for i, t := range tweets {
+		response.Tweets[i].TranscriptMatches = h.tweetSvc.TranscriptMatches(t, query)
+	}
 	h.writeJSON(w, http.StatusOK, response)
 }
 
@@ -238,12 +306,25 @@ func (h *TweetHandler) buildTweetListResponse(tweets []*domain.Tweet, total, lim
 				Duration:           m.Duration,
 				Transcript:         m.Transcript,
 				TranscriptLanguage: m.TranscriptLanguage,
+				OCRText:            m.OCRText,
+				AltText:            m.Alt(),
+				AltTextGenerated:   m.AltText == "" && m.AIAltText != "",
 				Essay:              m.Essay,
 				EssayTitle:         m.EssayTitle,
 				EssayStatus:        m.EssayStatus,
 				EssayError:         m.EssayError,
 				EssayWordCount:     m.EssayWordCount,
 			}
+			if len(m.TranscriptSegments) > 0 {
+				mp.SubtitleURL = fmt.Sprintf("/api/v1/tweets/%s/media/%s", t.ID, service.SubtitleFilename(m.ID))
+			}
+			if len(m.Chapters) > 0 {
+				mp.Chapters = m.Chapters
+				mp.ChaptersURL = fmt.Sprintf("/api/v1/tweets/%s/media/%s", t.ID, service.ChaptersFilename(m.ID))
+				if m.ChaptersVideoPath != "" {
+					mp.PlaybackURL = fmt.Sprintf("/api/v1/tweets/%s/media/%s", t.ID, service.ChaptersVideoFilename(m.ID))
+				}
+			}
 			// For videos, use locally downloaded thumbnail; for images, use the image itself
 			if m.Type == domain.MediaTypeVideo || m.Type == domain.MediaTypeGIF {
 				// PreviewURL now contains local path after download
@@ -304,6 +385,7 @@ func (h *TweetHandler) buildTweetListResponse(tweets []*domain.Tweet, total, lim
 			QuoteCount:        t.Metrics.Quotes,
 			ViewCount:         t.Metrics.Views,
 			AITitle:           t.AITitle,
+			Tags:              t.Tags,
 			AISummary:         t.AISummary,
 			AITags:            t.AITags,
 			AIContentType:     t.AIContentType,
@@ -314,6 +396,7 @@ func (h *TweetHandler) buildTweetListResponse(tweets []*domain.Tweet, total, lim
 			MediaCaptions:     mediaCaptions,
 			ArchivePath:       t.ArchivePath,
 			Error:             t.Error,
+			FailureReason:     string(t.FailureReason),
 			CreatedAt:         t.CreatedAt,
 			// Article fields
 			ContentType:    string(t.ContentType),
@@ -321,6 +404,12 @@ func (h *TweetHandler) buildTweetListResponse(tweets []*domain.Tweet, total, lim
 			ArticleBody:    t.ArticleBody,
 			WordCount:      t.WordCount,
 			ReadingMinutes: t.ReadingMinutes,
+			// Upstream availability
+			UpstreamStatus:    string(t.UpstreamStatus),
+			UpstreamChangedAt: t.UpstreamChangedAt,
+
+			FetchedWithCredentials: t.FetchedWithCredentials,
+			AIPending:              t.AIPending,
 		}
 		response.Tweets = append(response.Tweets, tr)
 	}
@@ -348,16 +437,17 @@ func (h *TweetHandler) Get(w http.ResponseWriter, r *http.Request) {
 	}
 
 	h.writeJSON(w, http.StatusOK, TweetResponse{
-		TweetID:     string(status.TweetID),
-		URL:         "",
-		Status:      string(status.Status),
-		Author:      status.Author,
-		Text:        status.Text,
-		MediaCount:  status.MediaCount,
-		AITitle:     status.AITitle,
-		ArchivePath: status.ArchivePath,
-		Error:       status.Error,
-		CreatedAt:   status.CreatedAt,
+		TweetID:       string(status.TweetID),
+		URL:           "",
+		Status:        string(status.Status),
+		Author:        status.Author,
+		Text:          status.Text,
+		MediaCount:    status.MediaCount,
+		AITitle:       status.AITitle,
+		ArchivePath:   status.ArchivePath,
+		Error:         status.Error,
+		FailureReason: string(status.FailureReason),
+		CreatedAt:     status.CreatedAt,
 	})
 }
 
@@ -413,6 +503,10 @@ func (h *TweetHandler) RegenerateAI(w http.ResponseWriter, r *http.Request) {
 			})
 			return
 		}
+		if errors.Is(err, domain.ErrAIBudgetExceeded) {
+			h.writeError(w, http.StatusTooManyRequests, err.Error())
+			return
+		}
 		h.logger.Error("regenerate AI failed", "error", err)
 		h.writeError(w, http.StatusInternalServerError, "failed to regenerate AI metadata")
 		return
@@ -679,6 +773,7 @@ func (h *TweetHandler) GetDiagnostics(w http.ResponseWriter, r *http.Request) {
 
 // MediaFileResponse represents a media file in the list response.
 type MediaFileResponse struct {
+	MediaID            string   `json:"media_id,omitempty"`
 	Filename           string   `json:"filename"`
 	Type               string   `json:"type"`
 	Size               int64    `json:"size"`
@@ -690,6 +785,11 @@ type MediaFileResponse struct {
 	Duration           int      `json:"duration_seconds,omitempty"`
 	Transcript         string   `json:"transcript,omitempty"`          // Video transcript
 	TranscriptLanguage string   `json:"transcript_language,omitempty"` // Detected language
+	SubtitleURL        string   `json:"subtitle_url,omitempty"`        // WebVTT track for video playback
+	SRTURL             string   `json:"srt_url,omitempty"`             // SRT download
+	OCRText            string   `json:"ocr_text,omitempty"`            // Text extracted from the image/keyframes
+	AltText            string   `json:"alt_text,omitempty"`            // Author's alt text, or the AI description
+	AltTextGenerated   bool     `json:"alt_text_generated,omitempty"`
 	AICaption          string   `json:"ai_caption,omitempty"`
 	AITags             []string `json:"ai_tags,omitempty"`
 	AIContentType      string   `json:"ai_content_type,omitempty"`
@@ -700,6 +800,20 @@ type MediaFileResponse struct {
 	EssayStatus    string `json:"essay_status,omitempty"`
 	EssayError     string `json:"essay_error,omitempty"`
 	EssayWordCount int    `json:"essay_word_count,omitempty"`
+	// AI-generated video chapters
+	Chapters    []domain.Chapter `json:"chapters,omitempty"`
+	ChaptersURL string           `json:"chapters_url,omitempty"` // WebVTT chapters track
+	PlaybackURL string           `json:"playback_url,omitempty"` // Copy of the video with chapters embedded
+	// Other video renditions kept on disk (media quality keep_all_variants)
+	Variants []MediaVariantResponse `json:"variants,omitempty"`
+}
+
+// MediaVariantResponse is a stored alternative rendition of a video.
+type MediaVariantResponse struct {
+	URL     string `json:"url"`
+	Width   int    `json:"width,omitempty"`
+	Height  int    `json:"height,omitempty"`
+	Bitrate int    `json:"bitrate,omitempty"`
 }
 
 // MediaListResponse contains the list of media files.
@@ -725,6 +839,26 @@ type FullTweetResponse struct {
 	AITags        []string            `json:"ai_tags,omitempty"`
 	AIContentType string              `json:"ai_content_type,omitempty"`
 	AITopics      []string            `json:"ai_topics,omitempty"`
+	// User tags and pipeline switches from the archive request
+	Tags    []string               `json:"tags,omitempty"`
+	Options *domain.ArchiveOptions `json:"archive_options,omitempty"`
+	// Poll, link preview and Community Note shown with the tweet
+	Poll          *domain.Poll          `json:"poll,omitempty"`
+	Card          *domain.LinkCard      `json:"card,omitempty"`
+	CardImageURL  string                `json:"card_image_url,omitempty"`
+	CommunityNote *domain.CommunityNote `json:"community_note,omitempty"`
+	// Whether the tweet is still on X (empty until checked)
+	UpstreamStatus    string     `json:"upstream_status,omitempty"`
+	UpstreamCheckedAt *time.Time `json:"upstream_checked_at,omitempty"`
+	UpstreamChangedAt *time.Time `json:"upstream_changed_at,omitempty"`
+	// Only visible to the user's logged-in session when archived
+	FetchedWithCredentials bool `json:"fetched_with_credentials,omitempty"`
+	// Transcription and AI analysis wait for the AI budget to reset
+	AIPending bool `json:"ai_pending,omitempty"`
+	// Translations of the text and transcripts, keyed by language
+	Translations map[string]TranslationResponse `json:"translations,omitempty"`
+	// Documents derived by AI (TL;DR, notes, FAQ, ...) in display order
+	Documents []DocumentResponse `json:"documents,omitempty"`
 }
 
 // ListMedia handles GET /api/v1/tweets/{tweetID}/media
@@ -780,7 +914,13 @@ func (h *TweetHandler) ServeMedia(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	filePath, err := h.tweetSvc.GetMediaFilePath(r.Context(), domain.TweetID(tweetID), filename)
+	// Let clients fetch directly from object storage when possible
+	if url, ok := h.tweetSvc.PresignedMediaURL(r.Context(), domain.TweetID(tweetID), filename); ok {
+		http.Redirect(w, r, url, http.StatusTemporaryRedirect)
+		return
+	}
+
+	file, info, err := h.tweetSvc.OpenMediaFile(r.Context(), domain.TweetID(tweetID), filename)
 	if err != nil {
 		if errors.Is(err, domain.ErrVideoNotFound) {
 			h.writeError(w, http.StatusNotFound, "tweet not found")
@@ -794,28 +934,14 @@ func (h *TweetHandler) ServeMedia(w http.ResponseWriter, r *http.Request) {
 		h.writeError(w, http.StatusInternalServerError, "failed to get media")
 		return
 	}
-
-	// Open file
-	file, err := os.Open(filePath)
-	if err != nil {
-		h.writeError(w, http.StatusNotFound, "media file not found")
-		return
-	}
 	defer file.Close()
 
-	// Get file info for size and modtime
-	stat, err := file.Stat()
-	if err != nil {
-		h.writeError(w, http.StatusInternalServerError, "failed to stat file")
-		return
-	}
-
 	// Determine content type
 	contentType := getContentTypeFromFilename(filename)
 	w.Header().Set("Content-Type", contentType)
 
 	// http.ServeContent handles Range requests automatically
-	http.ServeContent(w, r, filename, stat.ModTime(), file)
+	http.ServeContent(w, r, filename, info.ModTime, file)
 }
 
 // ServeAvatar handles GET /api/v1/tweets/{tweetID}/avatar
@@ -826,31 +952,24 @@ func (h *TweetHandler) ServeAvatar(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	filePath, err := h.tweetSvc.GetAvatarPath(r.Context(), domain.TweetID(tweetID))
+	file, info, err := h.tweetSvc.OpenAvatar(r.Context(), domain.TweetID(tweetID))
 	if err != nil {
 		if errors.Is(err, domain.ErrVideoNotFound) {
 			h.writeError(w, http.StatusNotFound, "tweet not found")
 			return
 		}
-		h.writeError(w, http.StatusNotFound, "avatar not found")
-		return
-	}
-
-	file, err := os.Open(filePath)
-	if err != nil {
-		h.writeError(w, http.StatusNotFound, "avatar not found")
+		if errors.Is(err, domain.ErrMediaNotFound) {
+			h.writeError(w, http.StatusNotFound, "avatar not found")
+			return
+		}
+		h.logger.Error("get avatar failed", "error", err)
+		h.writeError(w, http.StatusInternalServerError, "failed to get avatar")
 		return
 	}
 	defer file.Close()
 
-	stat, err := file.Stat()
-	if err != nil {
-		h.writeError(w, http.StatusInternalServerError, "failed to stat file")
-		return
-	}
-
 	w.Header().Set("Content-Type", "image/jpeg")
-	http.ServeContent(w, r, "avatar.jpg", stat.ModTime(), file)
+	http.ServeContent(w, r, "avatar.jpg", info.ModTime, file)
 }
 
 // GetFull handles GET /api/v1/tweets/{tweetID}/full
@@ -881,13 +1000,11 @@ func (h *TweetHandler) GetFull(w http.ResponseWriter, r *http.Request) {
 		}
 
 		// Get file size
-		var size int64
-		if info, err := os.Stat(m.LocalPath); err == nil {
-			size = info.Size()
-		}
+		size := h.tweetSvc.MediaFileSize(r.Context(), m.LocalPath)
 
 		mediaURL := fmt.Sprintf("/api/v1/tweets/%s/media/%s", tweetID, filename)
 		mediaResp := MediaFileResponse{
+			MediaID:        m.ID,
 			Filename:       filename,
 			Type:           string(m.Type),
 			URL:            mediaURL,
@@ -896,6 +1013,8 @@ func (h *TweetHandler) GetFull(w http.ResponseWriter, r *http.Request) {
 			Width:          m.Width,
 			Height:         m.Height,
 			Duration:       m.Duration,
+			OCRText:        m.OCRText,
+			AltText:        m.Alt(),
 			AICaption:      m.AICaption,
 			AITags:         m.AITags,
 			AIContentType:  m.AIContentType,
@@ -920,6 +1039,29 @@ func (h *TweetHandler) GetFull(w http.ResponseWriter, r *http.Request) {
 			mediaResp.Transcript = m.Transcript
 			mediaResp.TranscriptLanguage = m.TranscriptLanguage
 		}
+		if len(m.TranscriptSegments) > 0 {
+			mediaResp.SubtitleURL = fmt.Sprintf("/api/v1/tweets/%s/media/%s", tweetID, service.SubtitleFilename(m.ID))
+			mediaResp.SRTURL = fmt.Sprintf("/api/v1/tweets/%s/media/%s", tweetID, service.SRTFilename(m.ID))
+		}
+		mediaResp.AltTextGenerated = m.AltText == "" && m.AIAltText != ""
+		if len(m.Chapters) > 0 {
+			mediaResp.Chapters = m.Chapters
+			mediaResp.ChaptersURL = fmt.Sprintf("/api/v1/tweets/%s/media/%s", tweetID, service.ChaptersFilename(m.ID))
+			if m.ChaptersVideoPath != "" {
+				mediaResp.PlaybackURL = fmt.Sprintf("/api/v1/tweets/%s/media/%s", tweetID, service.ChaptersVideoFilename(m.ID))
+			}
+		}
+		for _, v := range m.Variants {
+			if v.LocalPath == "" || v.LocalPath == m.LocalPath {
+				continue
+			}
+			mediaResp.Variants = append(mediaResp.Variants, MediaVariantResponse{
+				URL:     fmt.Sprintf("/api/v1/tweets/%s/media/%s", tweetID, filepath.Base(v.LocalPath)),
+				Width:   v.Width,
+				Height:  v.Height,
+				Bitrate: v.Bitrate,
+			})
+		}
 		mediaResponses = append(mediaResponses, mediaResp)
 	}
 
@@ -945,6 +1087,36 @@ func (h *TweetHandler) GetFull(w http.ResponseWriter, r *http.Request) {
 		AITags:        stored.AITags,
 		AIContentType: stored.AIContentType,
 		AITopics:      stored.AITopics,
+		Tags:          stored.Tags,
+		Options:       stored.Options,
+		Poll:          stored.Poll,
+		CommunityNote: stored.CommunityNote,
+
+		UpstreamStatus:    stored.UpstreamStatus,
+		UpstreamCheckedAt: stored.UpstreamCheckedAt,
+		UpstreamChangedAt: stored.UpstreamChangedAt,
+
+		FetchedWithCredentials: stored.FetchedWithCredentials,
+		AIPending:              stored.AIPending,
+	}
+	if stored.Card != nil {
+		card := *stored.Card
+		if card.LocalImagePath != "" {
+			response.CardImageURL = fmt.Sprintf("/api/v1/tweets/%s/media/%s", tweetID, filepath.Base(card.LocalImagePath))
+			card.LocalImagePath = ""
+		}
+		response.Card = &card
+	}
+	for lang, tr := range stored.Translations {
+		if response.Translations == nil {
+			response.Translations = make(map[string]TranslationResponse, len(stored.Translations))
+		}
+		response.Translations[lang] = newTranslationResponse(tweetID, tr)
+	}
+	for _, typ := range domain.DocumentTypes {
+		if doc, ok := stored.Documents[typ]; ok {
+			response.Documents = append(response.Documents, newDocumentResponse(doc))
+		}
 	}
 
 	h.writeJSON(w, http.StatusOK, response)
@@ -967,6 +1139,10 @@ func getContentTypeFromFilename(filename string) string {
 		return "video/webm"
 	case ".mov":
 		return "video/quicktime"
+	case ".vtt":
+		return "text/vtt; charset=utf-8"
+	case ".srt":
+		return "application/x-subrip; charset=utf-8"
 	default:
 		return "application/octet-stream"
 	}
@@ -1189,6 +1365,10 @@ func (h *TweetHandler) GenerateEssay(w http.ResponseWriter, r *http.Request) {
 			})
 			return
 		}
+		if errors.Is(err, domain.ErrAIBudgetExceeded) {
+			h.writeError(w, http.StatusTooManyRequests, err.Error())
+			return
+		}
 		h.logger.Error("essay generation failed", "error", err)
 		h.writeError(w, http.StatusInternalServerError, "failed to start essay generation")
 		return
@@ -1300,3 +1480,454 @@ func (h *TweetHandler) DeleteEssay(w http.ResponseWriter, r *http.Request) {
 		Message:    "Essay deleted successfully",
 	})
 }
+
+// ChaptersResponse contains the chapters of a video for API responses.
+type ChaptersResponse struct {
+	TweetID     string           `json:"tweet_id"`
+	MediaIndex  int              `json:"media_index"`
+	Chapters    []domain.Chapter `json:"chapters"`
+	ChaptersURL string           `json:"chapters_url,omitempty"` // WebVTT chapters track
+}
+
+// chaptersParams reads the tweet ID and media index route parameters.
+func (h *TweetHandler) chaptersParams(w http.ResponseWriter, r *http.Request) (string, int, bool) {
+	tweetID := chi.URLParam(r, "tweetID")
+	if tweetID == "" {
+		h.writeError(w, http.StatusBadRequest, "missing tweet ID")
+		return "", 0, false
+	}
+	mediaIndex, err := strconv.Atoi(chi.URLParam(r, "mediaIndex"))
+	if err != nil {
+		h.writeError(w, http.StatusBadRequest, "invalid media index")
+		return "", 0, false
+	}
+	return tweetID, mediaIndex, true
+}
+
+func chaptersResponse(tweetID string, mediaIndex int, mediaID string, chapters []domain.Chapter) ChaptersResponse {
+	resp := ChaptersResponse{TweetID: tweetID, MediaIndex: mediaIndex, Chapters: chapters}
+	if resp.Chapters == nil {
+		resp.Chapters = []domain.Chapter{}
+	}
+	if len(chapters) > 0 {
+		resp.ChaptersURL = fmt.Sprintf("/api/v1/tweets/%s/media/%s", tweetID, service.ChaptersFilename(mediaID))
+	}
+	return resp
+}
+
+// GenerateChapters handles POST /api/v1/tweets/{tweetID}/media/{mediaIndex}/chapters
+// Splits the video into titled chapters from its timed transcript, replacing
+// any existing chapters.
+func (h *TweetHandler) GenerateChapters(w http.ResponseWriter, r *http.Request) {
+	tweetID, mediaIndex, ok := h.chaptersParams(w, r)
+	if !ok {
+		return
+	}
+
+	chapters, err := h.tweetSvc.GenerateChapters(r.Context(), domain.TweetID(tweetID), mediaIndex)
+	if err != nil {
+		h.writeChaptersError(w, err)
+		return
+	}
+
+	stored, err := h.tweetSvc.GetFullTweet(r.Context(), domain.TweetID(tweetID))
+	if err != nil {
+		h.writeChaptersError(w, err)
+		return
+	}
+	h.writeJSON(w, http.StatusOK, chaptersResponse(tweetID, mediaIndex, stored.Media[mediaIndex].ID, chapters))
+}
+
+// GetChapters handles GET /api/v1/tweets/{tweetID}/media/{mediaIndex}/chapters
+func (h *TweetHandler) GetChapters(w http.ResponseWriter, r *http.Request) {
+	tweetID, mediaIndex, ok := h.chaptersParams(w, r)
+	if !ok {
+		return
+	}
+
+	stored, err := h.tweetSvc.GetFullTweet(r.Context(), domain.TweetID(tweetID))
+	if err != nil {
+		h.writeChaptersError(w, err)
+		return
+	}
+	if mediaIndex < 0 || mediaIndex >= len(stored.Media) {
+		h.writeError(w, http.StatusBadRequest, "invalid media index")
+		return
+	}
+
+	media := stored.Media[mediaIndex]
+	h.writeJSON(w, http.StatusOK, chaptersResponse(tweetID, mediaIndex, media.ID, media.Chapters))
+}
+
+// DeleteChapters handles DELETE /api/v1/tweets/{tweetID}/media/{mediaIndex}/chapters
+func (h *TweetHandler) DeleteChapters(w http.ResponseWriter, r *http.Request) {
+	tweetID, mediaIndex, ok := h.chaptersParams(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.tweetSvc.DeleteChapters(r.Context(), domain.TweetID(tweetID), mediaIndex); err != nil {
+		h.writeChaptersError(w, err)
+		return
+	}
+	h.writeJSON(w, http.StatusOK, chaptersResponse(tweetID, mediaIndex, "", nil))
+}
+
+// writeChaptersError maps chapter errors to HTTP responses.
+func (h *TweetHandler) writeChaptersError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, domain.ErrVideoNotFound):
+		h.writeError(w, http.StatusNotFound, "tweet not found")
+	case errors.Is(err, service.ErrNoTimedTranscript):
+		h.writeError(w, http.StatusBadRequest, err.Error())
+	case strings.Contains(err.Error(), "invalid media index"):
+		h.writeError(w, http.StatusBadRequest, "invalid media index")
+	case errors.Is(err, service.ErrTooFewChapters):
+		h.writeError(w, http.StatusUnprocessableEntity, err.Error())
+	case errors.Is(err, domain.ErrAIBudgetExceeded):
+		h.writeError(w, http.StatusTooManyRequests, err.Error())
+	default:
+		h.logger.Error("chapters request failed", "error", err)
+		h.writeError(w, http.StatusInternalServerError, "failed to process chapters")
+	}
+}
+
+// RevisionListResponse lists the recorded revisions of a tweet.
+type RevisionListResponse struct {
+	TweetID            string                 `json:"tweet_id"`
+	EditedAfterArchive bool                   `json:"edited_after_archive"`
+	Revisions          []domain.TweetRevision `json:"revisions"`
+}
+
+// ListRevisions handles GET /api/v1/tweets/{tweetID}/revisions
+func (h *TweetHandler) ListRevisions(w http.ResponseWriter, r *http.Request) {
+	tweetID := chi.URLParam(r, "tweetID")
+	if tweetID == "" {
+		h.writeError(w, http.StatusBadRequest, "missing tweet ID")
+		return
+	}
+
+	revisions, err := h.tweetSvc.ListRevisions(r.Context(), domain.TweetID(tweetID))
+	if err != nil {
+		if errors.Is(err, domain.ErrVideoNotFound) {
+			h.writeError(w, http.StatusNotFound, "tweet not found")
+			return
+		}
+		h.logger.Error("list revisions failed", "error", err)
+		h.writeError(w, http.StatusInternalServerError, "failed to list revisions")
+		return
+	}
+
+	resp := RevisionListResponse{TweetID: tweetID, Revisions: revisions}
+	for _, rev := range revisions {
+		if rev.Edited {
+			resp.EditedAfterArchive = true
+		}
+	}
+	h.writeJSON(w, http.StatusOK, resp)
+}
+
+// DiffRevisions handles GET /api/v1/tweets/{tweetID}/revisions/diff
+// Query parameters:
+//   - from: revision number (default: the one before to)
+//   - to: revision number (default: latest)
+func (h *TweetHandler) DiffRevisions(w http.ResponseWriter, r *http.Request) {
+	tweetID := chi.URLParam(r, "tweetID")
+	if tweetID == "" {
+		h.writeError(w, http.StatusBadRequest, "missing tweet ID")
+		return
+	}
+
+	var from, to int
+	for name, dst := range map[string]*int{"from": &from, "to": &to} {
+		v := r.URL.Query().Get(name)
+		if v == "" {
+			continue
+		}
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 {
+			h.writeError(w, http.StatusBadRequest, fmt.Sprintf("invalid %s revision", name))
+			return
+		}
+		*dst = n
+	}
+
+	diff, err := h.tweetSvc.DiffRevisions(r.Context(), domain.TweetID(tweetID), from, to)
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrVideoNotFound):
+			h.writeError(w, http.StatusNotFound, "tweet not found")
+		case errors.Is(err, service.ErrRevisionNotFound):
+			h.writeError(w, http.StatusNotFound, err.Error())
+		default:
+			h.logger.Error("diff revisions failed", "error", err)
+			h.writeError(w, http.StatusInternalServerError, "failed to diff revisions")
+		}
+		return
+	}
+	h.writeJSON(w, http.StatusOK, diff)
+}
+
+// TranslationResponse is a tweet's translation into one language.
+type TranslationResponse struct {
+	TweetID        string     `json:"tweet_id"`
+	Language       string     `json:"language"`
+	Status         string     `json:"status"` // translating or completed
+	SourceLanguage string     `json:"source_language,omitempty"`
+	Text           string     `json:"text,omitempty"`
+	ArticleBody    string     `json:"article_body,omitempty"`
+	TranslatedAt   *time.Time `json:"translated_at,omitempty"`
+	PromptVersion  string     `json:"prompt_version,omitempty"`
+	// Media holds translated transcripts keyed by media ID
+	Media map[string]TranslatedMediaResponse `json:"media,omitempty"`
+}
+
+// TranslatedMediaResponse is a translated transcript with its subtitle files.
+type TranslatedMediaResponse struct {
+	Transcript  string                     `json:"transcript"`
+	Segments    []domain.TranscriptSegment `json:"segments,omitempty"`
+	SubtitleURL string                     `json:"subtitle_url,omitempty"` // WebVTT track for video playback
+	SRTURL      string                     `json:"srt_url,omitempty"`      // SRT download
+}
+
+func newTranslationResponse(tweetID string, tr domain.TweetTranslation) TranslationResponse {
+	resp := TranslationResponse{
+		TweetID:        tweetID,
+		Language:       tr.Language,
+		Status:         "completed",
+		SourceLanguage: tr.SourceLanguage,
+		Text:           tr.Text,
+		ArticleBody:    tr.ArticleBody,
+		TranslatedAt:   &tr.TranslatedAt,
+		PromptVersion:  tr.PromptVersion,
+	}
+	for mediaID, m := range tr.Media {
+		if resp.Media == nil {
+			resp.Media = make(map[string]TranslatedMediaResponse, len(tr.Media))
+		}
+		item := TranslatedMediaResponse{Transcript: m.Transcript, Segments: m.Segments}
+		if len(m.Segments) > 0 {
+			item.SubtitleURL = fmt.Sprintf("/api/v1/tweets/%s/media/%s", tweetID, service.TranslatedSubtitleFilename(mediaID, tr.Language))
+			item.SRTURL = fmt.Sprintf("/api/v1/tweets/%s/media/%s", tweetID, service.TranslatedSRTFilename(mediaID, tr.Language))
+		}
+		resp.Media[mediaID] = item
+	}
+	return resp
+}
+
+// writeTranslationError maps translation errors to HTTP responses.
+func (h *TweetHandler) writeTranslationError(w http.ResponseWriter, err error, action string) {
+	switch {
+	case errors.Is(err, domain.ErrVideoNotFound):
+		h.writeError(w, http.StatusNotFound, "tweet not found")
+	case errors.Is(err, service.ErrTranslationNotFound):
+		h.writeError(w, http.StatusNotFound, err.Error())
+	case errors.Is(err, service.ErrInvalidLanguage):
+		h.writeError(w, http.StatusBadRequest, err.Error())
+	case errors.Is(err, service.ErrNothingToTranslate):
+		h.writeError(w, http.StatusUnprocessableEntity, err.Error())
+	case errors.Is(err, service.ErrTranslationInProgress):
+		h.writeError(w, http.StatusConflict, err.Error())
+	case errors.Is(err, domain.ErrAIBudgetExceeded):
+		h.writeError(w, http.StatusTooManyRequests, err.Error())
+	default:
+		h.logger.Error(action+" failed", "error", err)
+		h.writeError(w, http.StatusInternalServerError, "failed to "+action)
+	}
+}
+
+// Translate handles POST /api/v1/tweets/{tweetID}/translations/{lang}
+// Starts translating the tweet's text, article body and transcripts into
+// lang (an ISO-639-1 code) in the background. An existing translation into
+// lang is replaced.
+func (h *TweetHandler) Translate(w http.ResponseWriter, r *http.Request) {
+	tweetID := chi.URLParam(r, "tweetID")
+	lang := strings.ToLower(chi.URLParam(r, "lang"))
+
+	h.logger.Info("translation request", "tweet_id", tweetID, "language", lang)
+
+	if err := h.tweetSvc.StartTranslate(domain.TweetID(tweetID), lang); err != nil {
+		h.writeTranslationError(w, err, "start translation")
+		return
+	}
+
+	h.writeJSON(w, http.StatusAccepted, TranslationResponse{
+		TweetID:  tweetID,
+		Language: lang,
+		Status:   "translating",
+	})
+}
+
+// GetTranslation handles GET /api/v1/tweets/{tweetID}/translations/{lang}
+// Returns 202 while a translation into lang is still running.
+func (h *TweetHandler) GetTranslation(w http.ResponseWriter, r *http.Request) {
+	tweetID := chi.URLParam(r, "tweetID")
+	lang := strings.ToLower(chi.URLParam(r, "lang"))
+
+	if h.tweetSvc.IsTranslating(domain.TweetID(tweetID), lang) {
+		h.writeJSON(w, http.StatusAccepted, TranslationResponse{
+			TweetID:  tweetID,
+			Language: lang,
+			Status:   "translating",
+		})
+		return
+	}
+
+	translation, err := h.tweetSvc.GetTranslation(domain.TweetID(tweetID), lang)
+	if err != nil {
+		h.writeTranslationError(w, err, "get translation")
+		return
+	}
+	h.writeJSON(w, http.StatusOK, newTranslationResponse(tweetID, *translation))
+}
+
+// DeleteTranslation handles DELETE /api/v1/tweets/{tweetID}/translations/{lang}
+func (h *TweetHandler) DeleteTranslation(w http.ResponseWriter, r *http.Request) {
+	tweetID := chi.URLParam(r, "tweetID")
+	lang := strings.ToLower(chi.URLParam(r, "lang"))
+
+	if err := h.tweetSvc.DeleteTranslation(r.Context(), domain.TweetID(tweetID), lang); err != nil {
+		h.writeTranslationError(w, err, "delete translation")
+		return
+	}
+	h.writeJSON(w, http.StatusOK, map[string]string{
+		"tweet_id": tweetID,
+		"language": lang,
+		"status":   "deleted",
+	})
+}
+
+// DocumentResponse is an AI-derived document in API responses.
+type DocumentResponse struct {
+	Type           string     `json:"type"`
+	Label          string     `json:"label"`
+	Status         string     `json:"status"` // generating, completed or failed
+	Error          string     `json:"error,omitempty"`
+	Title          string     `json:"title,omitempty"`
+	Content        string     `json:"content,omitempty"` // Markdown
+	WordCount      int        `json:"word_count,omitempty"`
+	SourceTweetIDs []string   `json:"source_tweet_ids,omitempty"`
+	PromptVersion  string     `json:"prompt_version,omitempty"`
+	GeneratedAt    *time.Time `json:"generated_at,omitempty"`
+}
+
+func newDocumentResponse(doc domain.DerivedDocument) DocumentResponse {
+	resp := DocumentResponse{
+		Type:          string(doc.Type),
+		Label:         doc.Type.Label(),
+		Status:        doc.Status,
+		Error:         doc.Error,
+		Title:         doc.Title,
+		Content:       doc.Content,
+		WordCount:     doc.WordCount,
+		PromptVersion: doc.PromptVersion,
+		GeneratedAt:   doc.GeneratedAt,
+	}
+	for _, id := range doc.SourceTweetIDs {
+		resp.SourceTweetIDs = append(resp.SourceTweetIDs, id.String())
+	}
+	return resp
+}
+
+// documentErrorStatus maps document errors to an HTTP status, or 0 for
+// unexpected errors.
+func documentErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, domain.ErrVideoNotFound),
+		errors.Is(err, domain.ErrPlaylistNotFound),
+		errors.Is(err, service.ErrDocumentNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, service.ErrUnknownDocumentType):
+		return http.StatusBadRequest
+	case errors.Is(err, service.ErrNothingToDerive),
+		errors.Is(err, service.ErrNotAThread):
+		return http.StatusUnprocessableEntity
+	case errors.Is(err, service.ErrDocumentInProgress):
+		return http.StatusConflict
+	case errors.Is(err, domain.ErrAIBudgetExceeded):
+		return http.StatusTooManyRequests
+	default:
+		return 0
+	}
+}
+
+// writeDocumentError maps document errors to HTTP responses.
+func (h *TweetHandler) writeDocumentError(w http.ResponseWriter, err error, action string) {
+	if status := documentErrorStatus(err); status != 0 {
+		h.writeError(w, status, err.Error())
+		return
+	}
+	h.logger.Error(action+" failed", "error", err)
+	h.writeError(w, http.StatusInternalServerError, "failed to "+action)
+}
+
+// ListDocuments handles GET /api/v1/tweets/{tweetID}/documents
+func (h *TweetHandler) ListDocuments(w http.ResponseWriter, r *http.Request) {
+	tweetID := chi.URLParam(r, "tweetID")
+
+	docs, err := h.tweetSvc.ListDocuments(domain.TweetID(tweetID))
+	if err != nil {
+		h.writeDocumentError(w, err, "list documents")
+		return
+	}
+	response := make([]DocumentResponse, 0, len(docs))
+	for _, doc := range docs {
+		response = append(response, newDocumentResponse(doc))
+	}
+	h.writeJSON(w, http.StatusOK, map[string]interface{}{
+		"tweet_id":  tweetID,
+		"documents": response,
+	})
+}
+
+// GenerateDocument handles POST /api/v1/tweets/{tweetID}/documents/{type}
+// Starts generating a document (tldr, notes, faq, flashcards, outline or
+// digest) in the background. A digest covers the tweet's whole thread. An
+// existing document of the same type is replaced.
+func (h *TweetHandler) GenerateDocument(w http.ResponseWriter, r *http.Request) {
+	tweetID := chi.URLParam(r, "tweetID")
+	typ := chi.URLParam(r, "type")
+
+	h.logger.Info("document generation request", "tweet_id", tweetID, "type", typ)
+
+	if err := h.tweetSvc.StartGenerateDocument(domain.TweetID(tweetID), typ); err != nil {
+		h.writeDocumentError(w, err, "start document generation")
+		return
+	}
+	doc, err := h.tweetSvc.GetDocument(domain.TweetID(tweetID), typ)
+	if err != nil {
+		h.writeDocumentError(w, err, "get document")
+		return
+	}
+	h.writeJSON(w, http.StatusAccepted, newDocumentResponse(*doc))
+}
+
+// GetDocument handles GET /api/v1/tweets/{tweetID}/documents/{type}
+// Poll until status is no longer "generating".
+func (h *TweetHandler) GetDocument(w http.ResponseWriter, r *http.Request) {
+	tweetID := chi.URLParam(r, "tweetID")
+	typ := chi.URLParam(r, "type")
+
+	doc, err := h.tweetSvc.GetDocument(domain.TweetID(tweetID), typ)
+	if err != nil {
+		h.writeDocumentError(w, err, "get document")
+		return
+	}
+	h.writeJSON(w, http.StatusOK, newDocumentResponse(*doc))
+}
+
+// DeleteDocument handles DELETE /api/v1/tweets/{tweetID}/documents/{type}
+func (h *TweetHandler) DeleteDocument(w http.ResponseWriter, r *http.Request) {
+	tweetID := chi.URLParam(r, "tweetID")
+	typ := chi.URLParam(r, "type")
+
+	if err := h.tweetSvc.DeleteDocument(r.Context(), domain.TweetID(tweetID), typ); err != nil {
+		h.writeDocumentError(w, err, "delete document")
+		return
+	}
+	h.writeJSON(w, http.StatusOK, map[string]string{
+		"tweet_id": tweetID,
+		"type":     typ,
+		"status":   "deleted",
+	})
+}
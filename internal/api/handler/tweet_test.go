@@ -0,0 +1,113 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/iconidentify/xgrabba/internal/config"
+	"github.com/iconidentify/xgrabba/internal/service"
+	"github.com/iconidentify/xgrabba/pkg/grok"
+)
+
+func TestTweetHandler_TranslationErrors(t *testing.T) {
+	svc := service.NewTweetService(grok.NewRouter(), nil, nil, config.StorageConfig{BasePath: t.TempDir()}, config.AIConfig{}, false, testLogger(), nil)
+	h := NewTweetHandler(svc, testLogger())
+
+	tests := []struct {
+		name    string
+		method  string
+		handler http.HandlerFunc
+		lang    string
+		want    int
+	}{
+		{"start invalid language", http.MethodPost, h.Translate, "english", http.StatusBadRequest},
+		{"start missing tweet", http.MethodPost, h.Translate, "en", http.StatusNotFound},
+		{"get missing tweet", http.MethodGet, h.GetTranslation, "EN", http.StatusNotFound},
+		{"delete invalid language", http.MethodDelete, h.DeleteTranslation, "e", http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, "/api/v1/tweets/1/translations/"+tt.lang, nil)
+			rctx := chi.NewRouteContext()
+			rctx.URLParams.Add("tweetID", "1")
+			rctx.URLParams.Add("lang", tt.lang)
+			req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+
+			w := httptest.NewRecorder()
+			tt.handler(w, req)
+			if w.Code != tt.want {
+				t.Errorf("status = %d, want %d: %s", w.Code, tt.want, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestTweetHandler_DocumentErrors(t *testing.T) {
+	svc := service.NewTweetService(grok.NewRouter(), nil, nil, config.StorageConfig{BasePath: t.TempDir()}, config.AIConfig{}, false, testLogger(), nil)
+	h := NewTweetHandler(svc, testLogger())
+
+	tests := []struct {
+		name    string
+		method  string
+		handler http.HandlerFunc
+		typ     string
+		want    int
+	}{
+		{"generate unknown type", http.MethodPost, h.GenerateDocument, "poem", http.StatusBadRequest},
+		{"generate missing tweet", http.MethodPost, h.GenerateDocument, "notes", http.StatusNotFound},
+		{"get missing tweet", http.MethodGet, h.GetDocument, "faq", http.StatusNotFound},
+		{"delete unknown type", http.MethodDelete, h.DeleteDocument, "essay", http.StatusBadRequest},
+		{"list missing tweet", http.MethodGet, h.ListDocuments, "", http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, "/api/v1/tweets/1/documents/"+tt.typ, nil)
+			rctx := chi.NewRouteContext()
+			rctx.URLParams.Add("tweetID", "1")
+			rctx.URLParams.Add("type", tt.typ)
+			req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+
+			w := httptest.NewRecorder()
+			tt.handler(w, req)
+			if w.Code != tt.want {
+				t.Errorf("status = %d, want %d: %s", w.Code, tt.want, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestTweetHandler_ChapterErrors(t *testing.T) {
+	svc := service.NewTweetService(grok.NewRouter(), nil, nil, config.StorageConfig{BasePath: t.TempDir()}, config.AIConfig{}, false, testLogger(), nil)
+	h := NewTweetHandler(svc, testLogger())
+
+	tests := []struct {
+		name       string
+		method     string
+		handler    http.HandlerFunc
+		mediaIndex string
+		want       int
+	}{
+		{"generate invalid index", http.MethodPost, h.GenerateChapters, "first", http.StatusBadRequest},
+		{"generate missing tweet", http.MethodPost, h.GenerateChapters, "0", http.StatusNotFound},
+		{"get missing tweet", http.MethodGet, h.GetChapters, "0", http.StatusNotFound},
+		{"delete missing tweet", http.MethodDelete, h.DeleteChapters, "0", http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, "/api/v1/tweets/1/media/"+tt.mediaIndex+"/chapters", nil)
+			rctx := chi.NewRouteContext()
+			rctx.URLParams.Add("tweetID", "1")
+			rctx.URLParams.Add("mediaIndex", tt.mediaIndex)
+			req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+
+			w := httptest.NewRecorder()
+			tt.handler(w, req)
+			if w.Code != tt.want {
+				t.Errorf("status = %d, want %d: %s", w.Code, tt.want, w.Body.String())
+			}
+		})
+	}
+}
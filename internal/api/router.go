@@ -22,6 +22,15 @@ func NewRouter(
 	eventHandler *handler.EventHandler,
 	extensionHandler *handler.ExtensionHandler,
 	playlistHandler *handler.PlaylistHandler,
+	duplicateHandler *handler.DuplicateHandler,
+	integrityHandler *handler.IntegrityHandler,
+	trashHandler *handler.TrashHandler,
+	metricsHandler *handler.MetricsHandler,
+	aiUsageHandler *handler.AIUsageHandler,
+	aiCacheHandler *handler.AICacheHandler,
+	aiPromptsHandler *handler.AIPromptsHandler,
+	askHandler *handler.AskHandler,
+	tagHandler *handler.TagHandler,
 	apiKey string,
 ) *chi.Mux {
 	r := chi.NewRouter()
@@ -96,9 +105,23 @@ func NewRouter(
 		r.Post("/tweets/{tweetID}/resync", tweetHandler.Resync)
 		r.Get("/tweets/{tweetID}/ai-status", tweetHandler.CheckAIAnalysisStatus)
 		r.Get("/tweets/{tweetID}/diagnostics", tweetHandler.GetDiagnostics)
+		r.Get("/tweets/{tweetID}/revisions", tweetHandler.ListRevisions)
+		r.Get("/tweets/{tweetID}/revisions/diff", tweetHandler.DiffRevisions)
 		r.Post("/tweets/{tweetID}/media/{mediaIndex}/essay", tweetHandler.GenerateEssay)
 		r.Get("/tweets/{tweetID}/media/{mediaIndex}/essay", tweetHandler.GetEssay)
 		r.Delete("/tweets/{tweetID}/media/{mediaIndex}/essay", tweetHandler.DeleteEssay)
+		r.Post("/tweets/{tweetID}/media/{mediaIndex}/chapters", tweetHandler.GenerateChapters)
+		r.Get("/tweets/{tweetID}/media/{mediaIndex}/chapters", tweetHandler.GetChapters)
+		r.Delete("/tweets/{tweetID}/media/{mediaIndex}/chapters", tweetHandler.DeleteChapters)
+		r.Post("/tweets/{tweetID}/translations/{lang}", tweetHandler.Translate)
+		r.Get("/tweets/{tweetID}/translations/{lang}", tweetHandler.GetTranslation)
+		r.Delete("/tweets/{tweetID}/translations/{lang}", tweetHandler.DeleteTranslation)
+
+		// AI-derived documents (TL;DR, notes, FAQ, flashcards, outline, thread digest)
+		r.Get("/tweets/{tweetID}/documents", tweetHandler.ListDocuments)
+		r.Post("/tweets/{tweetID}/documents/{type}", tweetHandler.GenerateDocument)
+		r.Get("/tweets/{tweetID}/documents/{type}", tweetHandler.GetDocument)
+		r.Delete("/tweets/{tweetID}/documents/{type}", tweetHandler.DeleteDocument)
 
 		// Video operations (legacy - kept for backwards compatibility)
 		r.Post("/videos", videoHandler.Submit)
@@ -158,6 +181,73 @@ func NewRouter(
 			r.Post("/playlists/{id}/items", playlistHandler.AddItem)
 			r.Delete("/playlists/{id}/items/{tweetId}", playlistHandler.RemoveItem)
 			r.Put("/playlists/{id}/reorder", playlistHandler.Reorder)
+			r.Post("/playlists/{id}/digest", playlistHandler.GenerateDigest)
+			r.Get("/playlists/{id}/digest", playlistHandler.GetDigest)
+			r.Delete("/playlists/{id}/digest", playlistHandler.DeleteDigest)
+		}
+
+		// Duplicate media detection and hardlink dedupe
+		if duplicateHandler != nil {
+			r.Get("/duplicates", duplicateHandler.List)
+			r.Post("/duplicates/dedupe", duplicateHandler.Dedupe)
+		}
+
+		// Archive integrity scrubbing
+		if integrityHandler != nil {
+			r.Get("/integrity", integrityHandler.Status)
+			r.Post("/integrity/scrub", integrityHandler.Scrub)
+		}
+
+		// Trash (soft-deleted archives)
+		if trashHandler != nil {
+			r.Get("/trash", trashHandler.List)
+			r.Delete("/trash", trashHandler.Empty)
+			r.Post("/trash/{tweetID}/restore", trashHandler.Restore)
+			r.Delete("/trash/{tweetID}", trashHandler.Purge)
+		}
+
+		// Engagement metrics time series
+		if metricsHandler != nil {
+			r.Get("/tweets/{tweetID}/metrics/history", metricsHandler.History)
+		}
+
+		// AI usage accounting and budgets
+		if aiUsageHandler != nil {
+			r.Get("/ai/usage", aiUsageHandler.Usage)
+		}
+
+		// AI response cache
+		if aiCacheHandler != nil {
+			r.Get("/ai/cache", aiCacheHandler.Stats)
+			r.Delete("/ai/cache", aiCacheHandler.Invalidate)
+		}
+
+		// Prompt templates: version, preview and rerun of outdated analysis
+		if aiPromptsHandler != nil {
+			r.Get("/ai/prompts", aiPromptsHandler.List)
+			r.Get("/ai/prompts/preview", aiPromptsHandler.Preview)
+			r.Post("/ai/prompts/rerun", aiPromptsHandler.Rerun)
+		}
+
+		// Questions answered from the archive, optionally streamed as SSE
+		if askHandler != nil {
+			r.Get("/ask", askHandler.Ask)
+			r.Post("/ask", askHandler.Ask)
+		}
+
+		// AI tag counts and curation: canonical tags, merges, renames, blocklist
+		if tagHandler != nil {
+			r.Get("/tags", tagHandler.List)
+			r.Get("/tags/registry", tagHandler.Registry)
+			r.Get("/tags/apply", tagHandler.ApplyStatus)
+			r.Post("/tags/apply", tagHandler.Apply)
+			r.Post("/tags/canonical", tagHandler.AddCanonical)
+			r.Delete("/tags/canonical/{tag}", tagHandler.RemoveCanonical)
+			r.Post("/tags/merge", tagHandler.Merge)
+			r.Post("/tags/rename", tagHandler.Rename)
+			r.Delete("/tags/aliases/{alias}", tagHandler.RemoveAlias)
+			r.Post("/tags/blocked", tagHandler.Block)
+			r.Delete("/tags/blocked/{tag}", tagHandler.Unblock)
 		}
 	})
 
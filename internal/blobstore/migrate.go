@@ -0,0 +1,140 @@
+package blobstore
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+
+	"github.com/iconidentify/xgrabba/internal/domain"
+)
+
+// MigrationResult summarizes a migration of existing archives into the store.
+type MigrationResult struct {
+	DryRun        bool     `json:"dry_run"`
+	Archives      int      `json:"archives"`
+	MediaFiles    int      `json:"media_files"`
+	Ingested      int      `json:"ingested"`
+	AlreadyStored int      `json:"already_stored"`
+	BytesSaved    int64    `json:"bytes_saved"` // Bytes freed by replacing duplicates with links
+	Errors        []string `json:"errors,omitempty"`
+}
+
+// Migrate walks basePath for archives (tweet.json) and moves their media into
+// the store. Missing SHA-256 digests are computed and written back to tweet.json.
+// It should run while the server is stopped, since it rewrites tweet.json files.
+func Migrate(basePath string, store *Store, dryRun bool, logger *slog.Logger) (*MigrationResult, error) {
+	result := &MigrationResult{DryRun: dryRun}
+
+	err := filepath.Walk(basePath, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return nil // Skip unreadable entries
+		}
+		if info.IsDir() && info.Name() == DirName {
+			return filepath.SkipDir
+		}
+		if info.IsDir() || info.Name() != "tweet.json" {
+			return nil
+		}
+
+		result.Archives++
+		if err := migrateArchive(path, store, dryRun, result); err != nil {
+			logger.Warn("failed to migrate archive", "path", path, "error", err)
+			result.Errors = append(result.Errors, fmt.Sprintf("%s: %v", path, err))
+		}
+		return nil
+	})
+	if err != nil {
+		return result, fmt.Errorf("walk storage: %w", err)
+	}
+	return result, nil
+}
+
+func migrateArchive(jsonPath string, store *Store, dryRun bool, result *MigrationResult) error {
+	data, err := os.ReadFile(jsonPath)
+	if err != nil {
+		return fmt.Errorf("read tweet.json: %w", err)
+	}
+	var stored domain.StoredTweet
+	if err := json.Unmarshal(data, &stored); err != nil {
+		return fmt.Errorf("parse tweet.json: %w", err)
+	}
+
+	archiveDir := filepath.Dir(jsonPath)
+	updated := false
+	for i := range stored.Media {
+		m := &stored.Media[i]
+		if m.LocalPath == "" {
+			continue
+		}
+		path := m.LocalPath
+		if _, err := os.Stat(path); err != nil {
+			// Archive may have been moved since download; look next to tweet.json
+			path = filepath.Join(archiveDir, "media", filepath.Base(m.LocalPath))
+			if _, err := os.Stat(path); err != nil {
+				continue
+			}
+		}
+		result.MediaFiles++
+
+		if m.SHA256 == "" {
+			sum, err := FileSHA256(path)
+			if err != nil {
+				result.Errors = append(result.Errors, fmt.Sprintf("%s: %v", path, err))
+				continue
+			}
+			m.SHA256 = sum
+			updated = true
+		}
+
+		if store.RefCount(m.SHA256) > 0 {
+			if info, err := os.Stat(path); err == nil {
+				if blobInfo, err := os.Stat(store.BlobPath(m.SHA256)); err == nil && os.SameFile(info, blobInfo) {
+					result.AlreadyStored++
+					continue
+				}
+				result.BytesSaved += info.Size()
+			}
+		}
+
+		if dryRun {
+			result.Ingested++
+			continue
+		}
+		if err := store.Ingest(path, m.SHA256); err != nil {
+			result.Errors = append(result.Errors, fmt.Sprintf("%s: %v", path, err))
+			continue
+		}
+		result.Ingested++
+	}
+
+	if updated && !dryRun {
+		out, err := json.MarshalIndent(stored, "", "  ")
+		if err != nil {
+			return fmt.Errorf("marshal tweet.json: %w", err)
+		}
+		if err := os.WriteFile(jsonPath, out, 0644); err != nil {
+			return fmt.Errorf("write tweet.json: %w", err)
+		}
+	}
+	return nil
+}
+
+// FileSHA256 returns the hex SHA-256 digest of a file.
+func FileSHA256(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", fmt.Errorf("open file: %w", err)
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", fmt.Errorf("read file: %w", err)
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
@@ -0,0 +1,262 @@
+// Package blobstore implements a content-addressed media store.
+//
+// Each unique media file is stored once under its SHA-256 digest in
+// <base>/.blobs/sha256/<ab>/<digest>. Archive directories keep their usual
+// media/<id>.<ext> paths, but those paths are hardlinks to the blob, so the
+// rest of the system (serving, export, ffmpeg) is unaware of the store.
+//
+// A JSON index records which archive paths reference each blob. Releasing
+// the last reference removes the blob from disk.
+package blobstore
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// DirName is the store directory inside the storage base path.
+// Directory walkers looking for archives should skip it.
+const DirName = ".blobs"
+
+const indexFilename = "index.json"
+
+// entry tracks one blob and the archive paths that reference it.
+type entry struct {
+	Size int64    `json:"size"`
+	Refs []string `json:"refs"`
+}
+
+// Stats summarizes the store.
+type Stats struct {
+	Blobs        int   `json:"blobs"`
+	References   int   `json:"references"`
+	StoredBytes  int64 `json:"stored_bytes"`  // Bytes actually on disk
+	LogicalBytes int64 `json:"logical_bytes"` // Bytes if every reference were a separate copy
+}
+
+// Store is a content-addressed blob store with reference counting.
+// It is safe for concurrent use.
+type Store struct {
+	root      string
+	indexPath string
+
+	mu    sync.Mutex
+	blobs map[string]*entry
+}
+
+// Open opens (or creates) the store under basePath.
+func Open(basePath string) (*Store, error) {
+	root := filepath.Join(basePath, DirName)
+	if err := os.MkdirAll(filepath.Join(root, "sha256"), 0755); err != nil {
+		return nil, fmt.Errorf("create blob store: %w", err)
+	}
+
+	s := &Store{
+		root:      root,
+		indexPath: filepath.Join(root, indexFilename),
+		blobs:     make(map[string]*entry),
+	}
+
+	data, err := os.ReadFile(s.indexPath)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("read blob index: %w", err)
+	}
+	if len(data) > 0 {
+		if err := json.Unmarshal(data, &s.blobs); err != nil {
+			return nil, fmt.Errorf("parse blob index: %w", err)
+		}
+	}
+	return s, nil
+}
+
+// BlobPath returns the on-disk path for a digest.
+func (s *Store) BlobPath(sum string) string {
+	prefix := "00"
+	if len(sum) >= 2 {
+		prefix = sum[:2]
+	}
+	return filepath.Join(s.root, "sha256", prefix, sum)
+}
+
+// Ingest stores the file at path under its SHA-256 digest sum and replaces
+// path with a hardlink to the blob. If the blob already exists, the local copy
+// is discarded in favor of the shared one. The caller must supply the correct
+// digest for the file's contents.
+func (s *Store) Ingest(path, sum string) error {
+	if sum == "" {
+		return fmt.Errorf("missing digest for %s", path)
+	}
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return fmt.Errorf("resolve path: %w", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	info, err := os.Stat(absPath)
+	if err != nil {
+		return fmt.Errorf("stat media: %w", err)
+	}
+
+	blob := s.BlobPath(sum)
+	blobInfo, err := os.Stat(blob)
+	switch {
+	case err == nil:
+		if !os.SameFile(blobInfo, info) {
+			if blobInfo.Size() != info.Size() {
+				return fmt.Errorf("size mismatch for blob %s", sum)
+			}
+			if err := ReplaceWithLink(blob, absPath); err != nil {
+				return err
+			}
+		}
+	case os.IsNotExist(err):
+		if err := os.MkdirAll(filepath.Dir(blob), 0755); err != nil {
+			return fmt.Errorf("create blob dir: %w", err)
+		}
+		if err := os.Link(absPath, blob); err != nil {
+			return fmt.Errorf("link blob: %w", err)
+		}
+	default:
+		return fmt.Errorf("stat blob: %w", err)
+	}
+
+	e := s.blobs[sum]
+	if e == nil {
+		e = &entry{Size: info.Size()}
+		s.blobs[sum] = e
+	}
+	if !containsString(e.Refs, absPath) {
+		e.Refs = append(e.Refs, absPath)
+		sort.Strings(e.Refs)
+	}
+	return s.saveLocked()
+}
+
+// ReleasePrefix drops every reference under dir (an archive directory) and
+// deletes blobs that are no longer referenced. It returns the bytes freed.
+func (s *Store) ReleasePrefix(dir string) (int64, error) {
+	absDir, err := filepath.Abs(dir)
+	if err != nil {
+		return 0, fmt.Errorf("resolve path: %w", err)
+	}
+	prefix := absDir + string(filepath.Separator)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.releaseLocked(func(ref string) bool { return strings.HasPrefix(ref, prefix) })
+}
+
+// Release drops the reference held by a single archive path, e.g. before the
+// file is rewritten, and deletes the blob if nothing else references it. The
+// file at path itself is left alone. It returns the bytes freed.
+func (s *Store) Release(path string) (int64, error) {
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return 0, fmt.Errorf("resolve path: %w", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.releaseLocked(func(ref string) bool { return ref == absPath })
+}
+
+// releaseLocked drops the references matched by drop and deletes blobs that
+// are no longer referenced. Caller must hold s.mu.
+func (s *Store) releaseLocked(drop func(ref string) bool) (int64, error) {
+	var freed int64
+	changed := false
+	for sum, e := range s.blobs {
+		kept := e.Refs[:0]
+		for _, ref := range e.Refs {
+			if drop(ref) {
+				changed = true
+				continue
+			}
+			kept = append(kept, ref)
+		}
+		e.Refs = kept
+		if len(e.Refs) == 0 {
+			if err := os.Remove(s.BlobPath(sum)); err != nil && !os.IsNotExist(err) {
+				return freed, fmt.Errorf("remove blob %s: %w", sum, err)
+			}
+			delete(s.blobs, sum)
+			freed += e.Size
+		}
+	}
+
+	if !changed {
+		return 0, nil
+	}
+	return freed, s.saveLocked()
+}
+
+// RefCount returns the number of archive paths referencing a digest.
+func (s *Store) RefCount(sum string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if e := s.blobs[sum]; e != nil {
+		return len(e.Refs)
+	}
+	return 0
+}
+
+// Stats returns store totals.
+func (s *Store) Stats() Stats {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var st Stats
+	for _, e := range s.blobs {
+		st.Blobs++
+		st.References += len(e.Refs)
+		st.StoredBytes += e.Size
+		st.LogicalBytes += e.Size * int64(len(e.Refs))
+	}
+	return st
+}
+
+// saveLocked persists the index atomically. Caller must hold s.mu.
+func (s *Store) saveLocked() error {
+	data, err := json.MarshalIndent(s.blobs, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshal blob index: %w", err)
+	}
+	tmp := s.indexPath + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return fmt.Errorf("write blob index: %w", err)
+	}
+	if err := os.Rename(tmp, s.indexPath); err != nil {
+		return fmt.Errorf("rename blob index: %w", err)
+	}
+	return nil
+}
+
+// ReplaceWithLink atomically replaces dst with a hardlink to src.
+func ReplaceWithLink(src, dst string) error {
+	tmp := dst + ".link-tmp"
+	_ = os.Remove(tmp)
+	if err := os.Link(src, tmp); err != nil {
+		return fmt.Errorf("link: %w", err)
+	}
+	if err := os.Rename(tmp, dst); err != nil {
+		os.Remove(tmp)
+		return fmt.Errorf("rename: %w", err)
+	}
+	return nil
+}
+
+func containsString(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,232 @@
+package blobstore
+
+import (
+	"encoding/json"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/iconidentify/xgrabba/internal/domain"
+)
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func sameFile(t *testing.T, a, b string) bool {
+	t.Helper()
+	ia, err := os.Stat(a)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ib, err := os.Stat(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return os.SameFile(ia, ib)
+}
+
+func TestFileSHA256(t *testing.T) {
+	p := filepath.Join(t.TempDir(), "f")
+	writeFile(t, p, "abc")
+	sum, err := FileSHA256(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sum != "ba7816bf8f01cfea414140de5dae2223b00361a396177a9cb410ff61f20015ad" {
+		t.Errorf("sum = %s", sum)
+	}
+}
+
+func TestStore_IngestAndRelease(t *testing.T) {
+	base := t.TempDir()
+	store, err := Open(base)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	a := filepath.Join(base, "2024", "01", "a", "media", "1.jpg")
+	b := filepath.Join(base, "2024", "01", "b", "media", "2.jpg")
+	writeFile(t, a, "same-bytes")
+	writeFile(t, b, "same-bytes")
+	sum, _ := FileSHA256(a)
+
+	if err := store.Ingest(a, sum); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.Ingest(b, sum); err != nil {
+		t.Fatal(err)
+	}
+	// Ingesting the same path twice must not double count
+	if err := store.Ingest(b, sum); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := store.RefCount(sum); got != 2 {
+		t.Fatalf("refcount = %d, want 2", got)
+	}
+	if !sameFile(t, a, b) || !sameFile(t, a, store.BlobPath(sum)) {
+		t.Fatal("archive paths should be hardlinks to the blob")
+	}
+	stats := store.Stats()
+	if stats.Blobs != 1 || stats.StoredBytes != 10 || stats.LogicalBytes != 20 {
+		t.Errorf("stats = %+v", stats)
+	}
+
+	// Index survives reopen
+	reopened, err := Open(base)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := reopened.RefCount(sum); got != 2 {
+		t.Fatalf("reopened refcount = %d, want 2", got)
+	}
+
+	// Releasing one archive keeps the blob for the other
+	freed, err := reopened.ReleasePrefix(filepath.Join(base, "2024", "01", "a"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if freed != 0 || reopened.RefCount(sum) != 1 {
+		t.Fatalf("freed = %d, refcount = %d", freed, reopened.RefCount(sum))
+	}
+	if _, err := os.Stat(reopened.BlobPath(sum)); err != nil {
+		t.Fatal("blob should still exist")
+	}
+
+	// Releasing the last reference frees the blob
+	freed, err = reopened.ReleasePrefix(filepath.Join(base, "2024", "01", "b"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if freed != 10 {
+		t.Errorf("freed = %d, want 10", freed)
+	}
+	if _, err := os.Stat(reopened.BlobPath(sum)); !os.IsNotExist(err) {
+		t.Error("unreferenced blob should be removed")
+	}
+}
+
+func TestStore_ReleasePrefixIgnoresSiblings(t *testing.T) {
+	base := t.TempDir()
+	store, _ := Open(base)
+
+	p := filepath.Join(base, "ab", "media", "1.jpg")
+	writeFile(t, p, "x")
+	sum, _ := FileSHA256(p)
+	if err := store.Ingest(p, sum); err != nil {
+		t.Fatal(err)
+	}
+
+	// "a" is a string prefix of "ab" but not a parent directory
+	if _, err := store.ReleasePrefix(filepath.Join(base, "a")); err != nil {
+		t.Fatal(err)
+	}
+	if store.RefCount(sum) != 1 {
+		t.Error("sibling directory reference should be kept")
+	}
+}
+
+func TestStore_Release(t *testing.T) {
+	base := t.TempDir()
+	store, _ := Open(base)
+
+	a := filepath.Join(base, "a", "media", "1.mp4")
+	b := filepath.Join(base, "b", "media", "1.mp4")
+	writeFile(t, a, "video")
+	writeFile(t, b, "video")
+	sum, _ := FileSHA256(a)
+	for _, p := range []string{a, b} {
+		if err := store.Ingest(p, sum); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if freed, err := store.Release(a); err != nil || freed != 0 {
+		t.Fatalf("Release(a) = %d, %v", freed, err)
+	}
+	if store.RefCount(sum) != 1 {
+		t.Errorf("refcount = %d, want 1", store.RefCount(sum))
+	}
+	if _, err := os.Stat(a); err != nil {
+		t.Errorf("released file should be left in place: %v", err)
+	}
+
+	if freed, err := store.Release(b); err != nil || freed != int64(len("video")) {
+		t.Fatalf("Release(b) = %d, %v", freed, err)
+	}
+	if _, err := os.Stat(store.BlobPath(sum)); !os.IsNotExist(err) {
+		t.Error("unreferenced blob should be removed")
+	}
+}
+
+func TestMigrate(t *testing.T) {
+	base := t.TempDir()
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+
+	writeArchive := func(dir, mediaID string) string {
+		mediaPath := filepath.Join(base, dir, "media", mediaID+".mp4")
+		writeFile(t, mediaPath, "video-bytes")
+		stored := domain.StoredTweet{
+			TweetID: dir,
+			Media:   []domain.Media{{ID: mediaID, Type: domain.MediaTypeVideo, LocalPath: mediaPath}},
+		}
+		data, _ := json.MarshalIndent(stored, "", "  ")
+		writeFile(t, filepath.Join(base, dir, "tweet.json"), string(data))
+		return mediaPath
+	}
+	a := writeArchive("a", "1")
+	b := writeArchive("b", "2")
+
+	store, err := Open(base)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dry, err := Migrate(base, store, true, logger)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if dry.Archives != 2 || dry.Ingested != 2 || store.Stats().Blobs != 0 {
+		t.Fatalf("dry run = %+v", dry)
+	}
+	if sameFile(t, a, b) {
+		t.Fatal("dry run must not link files")
+	}
+
+	res, err := Migrate(base, store, false, logger)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Ingested != 2 || res.BytesSaved != int64(len("video-bytes")) || len(res.Errors) != 0 {
+		t.Fatalf("migrate = %+v", res)
+	}
+	if !sameFile(t, a, b) {
+		t.Error("duplicate media should share one blob")
+	}
+
+	data, _ := os.ReadFile(filepath.Join(base, "a", "tweet.json"))
+	var stored domain.StoredTweet
+	if err := json.Unmarshal(data, &stored); err != nil {
+		t.Fatal(err)
+	}
+	if stored.Media[0].SHA256 == "" {
+		t.Error("migration should record SHA-256 in tweet.json")
+	}
+
+	again, err := Migrate(base, store, false, logger)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if again.AlreadyStored != 2 || again.Ingested != 0 {
+		t.Errorf("second run = %+v", again)
+	}
+}
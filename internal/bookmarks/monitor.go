@@ -39,20 +39,10 @@ type failedTweetsCache struct {
 }
 
 type failedTweetEntry struct {
-	Reason    string    `json:"reason"`
-	FailedAt  time.Time `json:"failed_at"`
-	Attempts  int       `json:"attempts"`
-}
-
-// permanentFailureReasons are error substrings that indicate a tweet will never succeed.
-var permanentFailureReasons = []string{
-	"author data unavailable",
-	"account suspended",
-	"account is suspended",
-	"tweet not found",
-	"tweet has been deleted",
-	"protected tweets",
-	"User not found",
+	Reason        string               `json:"reason"`
+	FailureReason domain.FailureReason `json:"failure_reason,omitempty"`
+	FailedAt      time.Time            `json:"failed_at"`
+	Attempts      int                  `json:"attempts"`
 }
 
 // MonitorState represents the current state of the bookmark monitor.
@@ -425,6 +415,19 @@ func (m *Monitor) pollOnce(ctx context.Context) (bool, bool) {
 			return false, true // Was rate limited, caller can retry
 		}
 
+		if errors.Is(err, domain.ErrAuthExpired) {
+			m.logger.Warn("bookmarks credentials expired", "error", err)
+			m.setLastError("authentication expired")
+			_ = m.activity.Append(ActivityEvent{
+				Status: "auth_expired",
+				Error:  err.Error(),
+			})
+			m.emitEvent(domain.EventSeverityError,
+				"X credentials expired; reconnect to resume bookmark polling",
+				domain.EventMetadata{"error": err.Error()})
+			return false, false
+		}
+
 		m.logger.Warn("bookmarks poll failed", "error", err)
 		m.setLastError(err.Error())
 		_ = m.activity.Append(ActivityEvent{
@@ -491,16 +494,16 @@ func (m *Monitor) pollOnce(ctx context.Context) (bool, bool) {
 		if err != nil {
 			m.logger.Warn("failed to enqueue bookmark archive", "tweet_id", id, "error", err)
 			// Mark as permanently failed if it's an unrecoverable error
-			if isPermanentFailure(err) {
-				m.markTweetFailed(id, err.Error())
-				m.logger.Info("marked tweet as permanently failed", "tweet_id", id, "reason", err.Error())
+			if reason := domain.FailureReasonFor(err); reason.Permanent() {
+				m.markTweetFailed(id, err.Error(), reason)
+				m.logger.Info("marked tweet as permanently failed", "tweet_id", id, "reason", reason, "error", err.Error())
 			}
 			continue
 		}
 		// Check if the tweet service returned a permanently failed status
 		if resp != nil && resp.Status == domain.ArchiveStatusFailed {
-			m.markTweetFailed(id, resp.Message)
-			m.logger.Info("tweet permanently unavailable", "tweet_id", id, "reason", resp.Message)
+			m.markTweetFailed(id, resp.Message, resp.FailureReason)
+			m.logger.Info("tweet permanently unavailable", "tweet_id", id, "reason", resp.FailureReason, "message", resp.Message)
 			continue
 		}
 		archivedIDs = append(archivedIDs, id)
@@ -620,7 +623,7 @@ func (m *Monitor) isFailedTweet(id string) bool {
 }
 
 // markTweetFailed adds a tweet ID to the permanent failure cache.
-func (m *Monitor) markTweetFailed(id string, reason string) {
+func (m *Monitor) markTweetFailed(id string, reason string, failure domain.FailureReason) {
 	if m.failedCache == nil {
 		m.failedCache = &failedTweetsCache{FailedIDs: make(map[string]failedTweetEntry)}
 	}
@@ -629,60 +632,15 @@ func (m *Monitor) markTweetFailed(id string, reason string) {
 	if exists {
 		entry.Attempts++
 		entry.Reason = reason
+		entry.FailureReason = failure
 		m.failedCache.FailedIDs[id] = entry
 	} else {
 		m.failedCache.FailedIDs[id] = failedTweetEntry{
-			Reason:   reason,
-			FailedAt: time.Now(),
-			Attempts: 1,
+			Reason:        reason,
+			FailureReason: failure,
+			FailedAt:      time.Now(),
+			Attempts:      1,
 		}
 	}
 	m.saveFailedCache()
 }
-
-// isPermanentFailure checks if an error indicates a permanent failure.
-func isPermanentFailure(err error) bool {
-	if err == nil {
-		return false
-	}
-	errStr := err.Error()
-	for _, reason := range permanentFailureReasons {
-		if contains(errStr, reason) {
-			return true
-		}
-	}
-	return false
-}
-
-// contains checks if s contains substr (case-insensitive).
-func contains(s, substr string) bool {
-	return len(s) >= len(substr) && (s == substr || len(substr) == 0 ||
-		(len(s) > 0 && len(substr) > 0 && containsLower(s, substr)))
-}
-
-func containsLower(s, substr string) bool {
-	for i := 0; i <= len(s)-len(substr); i++ {
-		if matchesAt(s, i, substr) {
-			return true
-		}
-	}
-	return false
-}
-
-func matchesAt(s string, pos int, substr string) bool {
-	for j := 0; j < len(substr); j++ {
-		sc := s[pos+j]
-		pc := substr[j]
-		// Simple lowercase for ASCII
-		if sc >= 'A' && sc <= 'Z' {
-			sc += 'a' - 'A'
-		}
-		if pc >= 'A' && pc <= 'Z' {
-			pc += 'a' - 'A'
-		}
-		if sc != pc {
-			return false
-		}
-	}
-	return true
-}
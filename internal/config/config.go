@@ -3,6 +3,7 @@ package config
 import (
 	"fmt"
 	"os"
+	"strings"
 	"time"
 
 	"github.com/kelseyhightower/envconfig"
@@ -17,7 +18,12 @@ type Config struct {
 	Grok      GrokConfig      `yaml:"grok"`
 	Whisper   WhisperConfig   `yaml:"whisper"`
 	Download  DownloadConfig  `yaml:"download"`
+	Media     MediaConfig     `yaml:"media"`
 	AI        AIConfig        `yaml:"ai"`
+	OCR       OCRConfig       `yaml:"ocr"`
+	Integrity IntegrityConfig `yaml:"integrity"`
+	Metrics   MetricsConfig   `yaml:"metrics"`
+	Upstream  UpstreamConfig  `yaml:"upstream"`
 	Bookmarks BookmarksConfig `yaml:"bookmarks"`
 	USB       USBConfig       `yaml:"usb"`
 }
@@ -36,6 +42,34 @@ type StorageConfig struct {
 	BasePath    string `yaml:"base_path" envconfig:"STORAGE_PATH" default:"/data/videos"`
 	TempPath    string `yaml:"temp_path" envconfig:"STORAGE_TEMP_PATH" default:"/data/temp"`
 	MaxFileSize int64  `yaml:"max_file_size" envconfig:"MAX_FILE_SIZE" default:"5368709120"` // 5GB
+	// ContentAddressed stores each unique media file once under .blobs/ and
+	// hardlinks it into archives. Run cmd/blobstore-migrate for existing data.
+	ContentAddressed bool `yaml:"content_addressed" envconfig:"STORAGE_CONTENT_ADDRESSED" default:"false"`
+
+	// Backend is local or s3. With s3, archives are still processed under
+	// BasePath and mirrored to the bucket once complete.
+	Backend string   `yaml:"backend" envconfig:"STORAGE_BACKEND" default:"local"`
+	S3      S3Config `yaml:"s3"`
+	// PresignMedia redirects media requests to presigned backend URLs when supported.
+	PresignMedia  bool          `yaml:"presign_media" envconfig:"STORAGE_PRESIGN_MEDIA" default:"true"`
+	PresignExpiry time.Duration `yaml:"presign_expiry" envconfig:"STORAGE_PRESIGN_EXPIRY" default:"1h"`
+	// EvictLocalMedia deletes local media files after they are mirrored to a
+	// remote backend. They are fetched back on demand for re-analysis and export.
+	EvictLocalMedia bool `yaml:"evict_local_media" envconfig:"STORAGE_EVICT_LOCAL_MEDIA" default:"false"`
+	// TrashRetention is how long deleted archives stay restorable in the trash
+	// before they are purged. Zero deletes archives immediately.
+	TrashRetention time.Duration `yaml:"trash_retention" envconfig:"STORAGE_TRASH_RETENTION" default:"720h"`
+}
+
+// S3Config holds S3-compatible object storage configuration.
+type S3Config struct {
+	Endpoint  string `yaml:"endpoint" envconfig:"S3_ENDPOINT"`
+	Region    string `yaml:"region" envconfig:"S3_REGION" default:"us-east-1"`
+	Bucket    string `yaml:"bucket" envconfig:"S3_BUCKET"`
+	AccessKey string `yaml:"access_key" envconfig:"S3_ACCESS_KEY"`
+	SecretKey string `yaml:"secret_key" envconfig:"S3_SECRET_KEY"`
+	Prefix    string `yaml:"prefix" envconfig:"S3_PREFIX"`
+	PathStyle bool   `yaml:"path_style" envconfig:"S3_PATH_STYLE" default:"true"` // Required for MinIO
 }
 
 // WorkerConfig holds worker pool configuration.
@@ -71,11 +105,253 @@ type DownloadConfig struct {
 	UserAgent     string        `yaml:"user_agent" envconfig:"DOWNLOAD_USER_AGENT" default:"Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36"`
 }
 
+// MediaConfig holds the default media quality policy. Archive requests can
+// override it per tweet.
+type MediaConfig struct {
+	// ImageSize is the image rendition to request from X: orig, 4096x4096,
+	// large, medium or small. Empty keeps the URL X returned.
+	ImageSize string `yaml:"image_size" envconfig:"MEDIA_IMAGE_SIZE"`
+	// MaxVideoResolution caps the shorter side of downloaded video, e.g. 720
+	// for 720p. Zero downloads the best rendition.
+	MaxVideoResolution int `yaml:"max_video_resolution" envconfig:"MEDIA_MAX_VIDEO_RESOLUTION" default:"0"`
+	// MaxVideoBitrate caps video bitrate in bits per second. Zero means no cap.
+	MaxVideoBitrate int `yaml:"max_video_bitrate" envconfig:"MEDIA_MAX_VIDEO_BITRATE" default:"0"`
+	// KeepAllVariants also stores every other MP4 rendition next to the
+	// selected one.
+	KeepAllVariants bool `yaml:"keep_all_variants" envconfig:"MEDIA_KEEP_ALL_VARIANTS" default:"false"`
+}
+
+// IntegrityConfig holds archive integrity scrubbing configuration.
+type IntegrityConfig struct {
+	ScrubEnabled  bool          `yaml:"scrub_enabled" envconfig:"INTEGRITY_SCRUB_ENABLED" default:"true"`
+	ScrubInterval time.Duration `yaml:"scrub_interval" envconfig:"INTEGRITY_SCRUB_INTERVAL" default:"168h"` // Weekly
+	// AutoRepair re-downloads damaged media through Resync.
+	AutoRepair bool `yaml:"auto_repair" envconfig:"INTEGRITY_AUTO_REPAIR" default:"true"`
+}
+
+// MetricsConfig holds engagement metrics re-polling configuration.
+type MetricsConfig struct {
+	PollEnabled bool `yaml:"poll_enabled" envconfig:"METRICS_POLL_ENABLED" default:"false"`
+	// Schedule is a comma-separated list of interval@max_age tiers. A tweet
+	// younger than max_age is re-polled every interval; tweets older than the
+	// last tier are no longer polled.
+	Schedule    string `yaml:"schedule" envconfig:"METRICS_POLL_SCHEDULE" default:"1h@24h,24h@720h,168h@8760h"`
+	MaxPerCycle int    `yaml:"max_per_cycle" envconfig:"METRICS_POLL_MAX_PER_CYCLE" default:"50"`
+}
+
+// MetricsTier is one step of the metrics re-poll schedule.
+type MetricsTier struct {
+	Interval time.Duration
+	MaxAge   time.Duration
+}
+
+// Tiers parses Schedule. Tiers must be ordered by increasing max age.
+func (c MetricsConfig) Tiers() ([]MetricsTier, error) {
+	var tiers []MetricsTier
+	for _, part := range strings.Split(c.Schedule, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		interval, maxAge, ok := strings.Cut(part, "@")
+		if !ok {
+			return nil, fmt.Errorf("metrics schedule tier %q: want interval@max_age", part)
+		}
+		var tier MetricsTier
+		var err error
+		if tier.Interval, err = time.ParseDuration(interval); err != nil || tier.Interval <= 0 {
+			return nil, fmt.Errorf("metrics schedule tier %q: invalid interval", part)
+		}
+		if tier.MaxAge, err = time.ParseDuration(maxAge); err != nil || tier.MaxAge <= 0 {
+			return nil, fmt.Errorf("metrics schedule tier %q: invalid max age", part)
+		}
+		if n := len(tiers); n > 0 && tier.MaxAge <= tiers[n-1].MaxAge {
+			return nil, fmt.Errorf("metrics schedule tier %q: max ages must increase", part)
+		}
+		tiers = append(tiers, tier)
+	}
+	return tiers, nil
+}
+
+// UpstreamConfig holds configuration for checking whether archived tweets
+// still exist on X.
+type UpstreamConfig struct {
+	CheckEnabled bool `yaml:"check_enabled" envconfig:"UPSTREAM_CHECK_ENABLED" default:"false"`
+	// CheckInterval is how often each archived tweet is re-checked.
+	CheckInterval time.Duration `yaml:"check_interval" envconfig:"UPSTREAM_CHECK_INTERVAL" default:"168h"`
+	MaxPerCycle   int           `yaml:"max_per_cycle" envconfig:"UPSTREAM_CHECK_MAX_PER_CYCLE" default:"100"`
+}
+
 // AIConfig holds orchestration timeouts for background AI jobs (not per-provider timeouts).
 type AIConfig struct {
 	// RegenerateTimeout is the max wall-clock time a background regenerate/backfill job is allowed to run.
 	// This prevents "AI in progress" from getting stuck forever if an external process hangs.
 	RegenerateTimeout time.Duration `yaml:"regenerate_timeout" envconfig:"AI_REGENERATE_TIMEOUT" default:"20m"`
+
+	// Provider is the default backend for every AI task: grok, openai (any
+	// OpenAI-compatible server such as Ollama, llama.cpp or vLLM) or none.
+	Provider string `yaml:"provider" envconfig:"AI_PROVIDER" default:"grok"`
+	// FallbackProvider is tried when the routed provider fails. Empty disables fallback.
+	FallbackProvider string `yaml:"fallback_provider" envconfig:"AI_FALLBACK_PROVIDER"`
+	// Per-task overrides of Provider.
+	FilenameProvider    string `yaml:"filename_provider" envconfig:"AI_FILENAME_PROVIDER"`
+	AnalysisProvider    string `yaml:"analysis_provider" envconfig:"AI_ANALYSIS_PROVIDER"`
+	VisionProvider      string `yaml:"vision_provider" envconfig:"AI_VISION_PROVIDER"`
+	EssayProvider       string `yaml:"essay_provider" envconfig:"AI_ESSAY_PROVIDER"`
+	TranslationProvider string `yaml:"translation_provider" envconfig:"AI_TRANSLATION_PROVIDER"`
+	AskProvider         string `yaml:"ask_provider" envconfig:"AI_ASK_PROVIDER"`
+	// Documents and chapters default to EssayProvider.
+	DocumentProvider string `yaml:"document_provider" envconfig:"AI_DOCUMENT_PROVIDER"`
+	ChaptersProvider string `yaml:"chapters_provider" envconfig:"AI_CHAPTERS_PROVIDER"`
+
+	OpenAI OpenAIConfig `yaml:"openai"`
+
+	// Budgets in USD of estimated AI and transcription spend. When one is
+	// exceeded, AI phases are paused until the next day or month. Zero is unlimited.
+	DailyBudgetUSD   float64 `yaml:"daily_budget_usd" envconfig:"AI_DAILY_BUDGET_USD" default:"0"`
+	MonthlyBudgetUSD float64 `yaml:"monthly_budget_usd" envconfig:"AI_MONTHLY_BUDGET_USD" default:"0"`
+
+	// CacheEnabled reuses stored responses for identical AI requests (same
+	// model, prompt and images) under STORAGE_PATH/.ai_cache.
+	CacheEnabled bool `yaml:"cache_enabled" envconfig:"AI_CACHE_ENABLED" default:"true"`
+	// CacheTTL is how long a cached response is reused. Zero keeps entries until invalidated.
+	CacheTTL time.Duration `yaml:"cache_ttl" envconfig:"AI_CACHE_TTL" default:"720h"`
+
+	// PromptsDir holds <name>.tmpl files that override the built-in prompt
+	// templates. Empty uses the built-in templates.
+	PromptsDir string `yaml:"prompts_dir" envconfig:"AI_PROMPTS_DIR"`
+
+	// TranslationLanguages is a comma-separated list of ISO-639-1 codes that
+	// every archived tweet is translated into after analysis. Empty disables
+	// automatic translation; tweets can still be translated on request.
+	TranslationLanguages string `yaml:"translation_languages" envconfig:"AI_TRANSLATION_LANGUAGES"`
+	// TranslationBatchSize is how many transcript segments are sent per
+	// translation request. Zero uses the default of 50.
+	TranslationBatchSize int `yaml:"translation_batch_size" envconfig:"AI_TRANSLATION_BATCH_SIZE" default:"50"`
+
+	// ChaptersMinDuration is the shortest transcribed video that is split
+	// into chapters after analysis. Zero disables automatic chapters;
+	// videos can still be chaptered on request.
+	ChaptersMinDuration time.Duration `yaml:"chapters_min_duration" envconfig:"AI_CHAPTERS_MIN_DURATION" default:"10m"`
+}
+
+// AI tasks that can be routed to different providers.
+const (
+	AITaskFilename    = "filename"
+	AITaskAnalysis    = "analysis"
+	AITaskVision      = "vision"
+	AITaskEssay       = "essay"
+	AITaskTranslation = "translation"
+	AITaskAsk         = "ask"
+	AITaskDocument    = "document"
+	AITaskChapters    = "chapters"
+)
+
+// AITasks lists every routable AI task.
+var AITasks = []string{AITaskFilename, AITaskAnalysis, AITaskVision, AITaskEssay, AITaskTranslation, AITaskAsk, AITaskDocument, AITaskChapters}
+
+// ProviderFor returns the provider routed for task, falling back to the
+// default provider and then to grok.
+func (c AIConfig) ProviderFor(task string) string {
+	var override string
+	switch task {
+	case AITaskFilename:
+		override = c.FilenameProvider
+	case AITaskAnalysis:
+		override = c.AnalysisProvider
+	case AITaskVision:
+		override = c.VisionProvider
+	case AITaskEssay:
+		override = c.EssayProvider
+	case AITaskTranslation:
+		override = c.TranslationProvider
+	case AITaskAsk:
+		override = c.AskProvider
+	case AITaskDocument:
+		override = c.DocumentProvider
+		if override == "" {
+			override = c.EssayProvider
+		}
+	case AITaskChapters:
+		override = c.ChaptersProvider
+		if override == "" {
+			override = c.EssayProvider
+		}
+	}
+	if override != "" {
+		return override
+	}
+	if c.Provider != "" {
+		return c.Provider
+	}
+	return "grok"
+}
+
+// TranslationTargets returns the lowercased languages from
+// TranslationLanguages, without duplicates.
+func (c AIConfig) TranslationTargets() []string {
+	var langs []string
+	seen := make(map[string]bool)
+	for _, lang := range strings.Split(c.TranslationLanguages, ",") {
+		lang = strings.ToLower(strings.TrimSpace(lang))
+		if lang == "" || seen[lang] {
+			continue
+		}
+		seen[lang] = true
+		langs = append(langs, lang)
+	}
+	return langs
+}
+
+// ValidLanguageCode reports whether lang is a lowercase two-letter
+// ISO-639-1 style code.
+func ValidLanguageCode(lang string) bool {
+	if len(lang) != 2 {
+		return false
+	}
+	for _, r := range lang {
+		if r < 'a' || r > 'z' {
+			return false
+		}
+	}
+	return true
+}
+
+// ProvidersInUse reports which providers are routed or used as fallback.
+func (c AIConfig) ProvidersInUse() map[string]bool {
+	used := make(map[string]bool)
+	for _, task := range AITasks {
+		used[c.ProviderFor(task)] = true
+	}
+	if c.FallbackProvider != "" {
+		used[c.FallbackProvider] = true
+	}
+	return used
+}
+
+// OpenAIConfig holds settings for an OpenAI-compatible chat completions backend.
+type OpenAIConfig struct {
+	BaseURL string `yaml:"base_url" envconfig:"AI_OPENAI_BASE_URL" default:"http://localhost:11434/v1"`
+	APIKey  string `yaml:"api_key" envconfig:"AI_OPENAI_API_KEY"` // Optional for local servers
+	Model   string `yaml:"model" envconfig:"AI_OPENAI_MODEL"`
+	// VisionModel and EssayModel default to Model.
+	VisionModel string        `yaml:"vision_model" envconfig:"AI_OPENAI_VISION_MODEL"`
+	EssayModel  string        `yaml:"essay_model" envconfig:"AI_OPENAI_ESSAY_MODEL"`
+	Timeout     time.Duration `yaml:"timeout" envconfig:"AI_OPENAI_TIMEOUT" default:"120s"`
+}
+
+// OCRConfig controls text extraction from images and video keyframes.
+type OCRConfig struct {
+	Enabled bool `yaml:"enabled" envconfig:"OCR_ENABLED" default:"true"`
+	// Engine is auto, tesseract, vision or none. Auto prefers a local tesseract binary
+	// and falls back to the vision endpoint when OCR_API_KEY is set.
+	Engine        string        `yaml:"engine" envconfig:"OCR_ENGINE" default:"auto"`
+	TesseractPath string        `yaml:"tesseract_path" envconfig:"OCR_TESSERACT_PATH" default:"tesseract"`
+	Languages     string        `yaml:"languages" envconfig:"OCR_LANGUAGES" default:"eng"`
+	APIKey        string        `yaml:"api_key" envconfig:"OCR_API_KEY"`
+	BaseURL       string        `yaml:"base_url" envconfig:"OCR_BASE_URL" default:"https://api.openai.com/v1"`
+	Model         string        `yaml:"model" envconfig:"OCR_MODEL" default:"gpt-4o-mini"`
+	Timeout       time.Duration `yaml:"timeout" envconfig:"OCR_TIMEOUT" default:"60s"`
 }
 
 // USBConfig holds USB export configuration.
@@ -144,12 +420,78 @@ func (c *Config) Validate() error {
 	if c.Server.APIKey == "" {
 		return fmt.Errorf("API_KEY is required")
 	}
-	if c.Grok.APIKey == "" {
+	for _, name := range []string{c.AI.Provider, c.AI.FallbackProvider, c.AI.FilenameProvider, c.AI.AnalysisProvider, c.AI.VisionProvider, c.AI.EssayProvider, c.AI.TranslationProvider, c.AI.AskProvider, c.AI.DocumentProvider, c.AI.ChaptersProvider} {
+		switch name {
+		case "", "grok", "openai", "none":
+		default:
+			return fmt.Errorf("AI provider must be grok, openai or none, got %q", name)
+		}
+	}
+	if c.AI.DailyBudgetUSD < 0 || c.AI.MonthlyBudgetUSD < 0 {
+		return fmt.Errorf("AI_DAILY_BUDGET_USD and AI_MONTHLY_BUDGET_USD must be >= 0")
+	}
+	if c.AI.CacheTTL < 0 {
+		return fmt.Errorf("AI_CACHE_TTL must be >= 0")
+	}
+	if c.AI.TranslationBatchSize < 0 {
+		return fmt.Errorf("AI_TRANSLATION_BATCH_SIZE must be >= 0")
+	}
+	for _, lang := range c.AI.TranslationTargets() {
+		if !ValidLanguageCode(lang) {
+			return fmt.Errorf("AI_TRANSLATION_LANGUAGES must be ISO-639-1 codes, got %q", lang)
+		}
+	}
+	aiProviders := c.AI.ProvidersInUse()
+	if aiProviders["grok"] && c.Grok.APIKey == "" {
 		return fmt.Errorf("GROK_API_KEY is required")
 	}
+	if aiProviders["openai"] {
+		if c.AI.OpenAI.BaseURL == "" {
+			return fmt.Errorf("AI_OPENAI_BASE_URL is required when an AI task uses the openai provider")
+		}
+		if c.AI.OpenAI.Model == "" {
+			return fmt.Errorf("AI_OPENAI_MODEL is required when an AI task uses the openai provider")
+		}
+	}
 	if c.Storage.BasePath == "" {
 		return fmt.Errorf("STORAGE_PATH is required")
 	}
+	switch c.Storage.Backend {
+	case "", "local":
+	case "s3":
+		if c.Storage.S3.Bucket == "" {
+			return fmt.Errorf("S3_BUCKET is required when STORAGE_BACKEND=s3")
+		}
+	default:
+		return fmt.Errorf("STORAGE_BACKEND must be local or s3, got %q", c.Storage.Backend)
+	}
+	switch c.Media.ImageSize {
+	case "", "orig", "4096x4096", "large", "medium", "small":
+	default:
+		return fmt.Errorf("MEDIA_IMAGE_SIZE must be orig, 4096x4096, large, medium or small, got %q", c.Media.ImageSize)
+	}
+	if c.Media.MaxVideoResolution < 0 {
+		return fmt.Errorf("MEDIA_MAX_VIDEO_RESOLUTION must be >= 0")
+	}
+	if c.Media.MaxVideoBitrate < 0 {
+		return fmt.Errorf("MEDIA_MAX_VIDEO_BITRATE must be >= 0")
+	}
+	if c.Metrics.PollEnabled {
+		if _, err := c.Metrics.Tiers(); err != nil {
+			return fmt.Errorf("METRICS_POLL_SCHEDULE: %w", err)
+		}
+		if c.Metrics.MaxPerCycle <= 0 {
+			return fmt.Errorf("METRICS_POLL_MAX_PER_CYCLE must be > 0")
+		}
+	}
+	if c.Upstream.CheckEnabled {
+		if c.Upstream.CheckInterval <= 0 {
+			return fmt.Errorf("UPSTREAM_CHECK_INTERVAL must be > 0")
+		}
+		if c.Upstream.MaxPerCycle <= 0 {
+			return fmt.Errorf("UPSTREAM_CHECK_MAX_PER_CYCLE must be > 0")
+		}
+	}
 	if c.Bookmarks.Enabled {
 		// We can learn user_id from the OAuth connect flow (stored on disk), so only require it if we
 		// don't have OAuth client credentials available.
@@ -83,6 +83,93 @@ func TestConfig_Validate_MissingStoragePath(t *testing.T) {
 	}
 }
 
+func TestConfig_Validate_Media(t *testing.T) {
+	tests := []struct {
+		name    string
+		media   MediaConfig
+		wantErr bool
+	}{
+		{"default", MediaConfig{}, false},
+		{"orig with 720p cap", MediaConfig{ImageSize: "orig", MaxVideoResolution: 720, KeepAllVariants: true}, false},
+		{"unknown image size", MediaConfig{ImageSize: "huge"}, true},
+		{"negative resolution", MediaConfig{MaxVideoResolution: -1}, true},
+		{"negative bitrate", MediaConfig{MaxVideoBitrate: -1}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &Config{
+				Server:  ServerConfig{APIKey: "test-api-key"},
+				Grok:    GrokConfig{APIKey: "test-grok-key"},
+				Storage: StorageConfig{BasePath: "/data/videos"},
+				Media:   tt.media,
+			}
+			err := cfg.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestConfig_Validate_AIProviders(t *testing.T) {
+	openai := OpenAIConfig{BaseURL: "http://localhost:11434/v1", Model: "llama3.2"}
+	tests := []struct {
+		name    string
+		grokKey string
+		ai      AIConfig
+		wantErr bool
+	}{
+		{"grok by default", "test-grok-key", AIConfig{}, false},
+		{"openai without grok key", "", AIConfig{Provider: "openai", OpenAI: openai}, false},
+		{"no AI without grok key", "", AIConfig{Provider: "none"}, false},
+		{"openai without model", "", AIConfig{Provider: "openai", OpenAI: OpenAIConfig{BaseURL: openai.BaseURL}}, true},
+		{"grok fallback needs key", "", AIConfig{Provider: "openai", FallbackProvider: "grok", OpenAI: openai}, true},
+		{"grok task override needs key", "", AIConfig{Provider: "openai", EssayProvider: "grok", OpenAI: openai}, true},
+		{"unknown provider", "test-grok-key", AIConfig{VisionProvider: "claude"}, true},
+		{"unknown document provider", "test-grok-key", AIConfig{DocumentProvider: "grk"}, true},
+		{"unknown chapters provider", "test-grok-key", AIConfig{ChaptersProvider: "claude"}, true},
+		{"grok chapters override needs key", "", AIConfig{Provider: "openai", ChaptersProvider: "grok", OpenAI: openai}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &Config{
+				Server:  ServerConfig{APIKey: "test-api-key"},
+				Grok:    GrokConfig{APIKey: tt.grokKey},
+				Storage: StorageConfig{BasePath: "/data/videos"},
+				AI:      tt.ai,
+			}
+			err := cfg.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestAIConfig_ProviderFor(t *testing.T) {
+	cfg := AIConfig{Provider: "openai", VisionProvider: "grok"}
+	if got := cfg.ProviderFor(AITaskFilename); got != "openai" {
+		t.Errorf("ProviderFor(filename) = %q, want openai", got)
+	}
+	if got := cfg.ProviderFor(AITaskVision); got != "grok" {
+		t.Errorf("ProviderFor(vision) = %q, want grok", got)
+	}
+	if got := (AIConfig{}).ProviderFor(AITaskEssay); got != "grok" {
+		t.Errorf("ProviderFor(essay) with no config = %q, want grok", got)
+	}
+
+	// Documents and chapters follow the essay provider unless overridden
+	cfg = AIConfig{Provider: "grok", EssayProvider: "openai", ChaptersProvider: "none"}
+	if got := cfg.ProviderFor(AITaskDocument); got != "openai" {
+		t.Errorf("ProviderFor(document) = %q, want openai", got)
+	}
+	if got := cfg.ProviderFor(AITaskChapters); got != "none" {
+		t.Errorf("ProviderFor(chapters) = %q, want none", got)
+	}
+}
+
 func TestConfig_Validate_Bookmarks(t *testing.T) {
 	tests := []struct {
 		name    string
@@ -267,6 +354,23 @@ func TestServerConfig_Address(t *testing.T) {
 	}
 }
 
+func TestMetricsConfig_Tiers(t *testing.T) {
+	tiers, err := MetricsConfig{Schedule: "1h@24h, 24h@720h"}.Tiers()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := []MetricsTier{{Interval: time.Hour, MaxAge: 24 * time.Hour}, {Interval: 24 * time.Hour, MaxAge: 720 * time.Hour}}
+	if len(tiers) != len(want) || tiers[0] != want[0] || tiers[1] != want[1] {
+		t.Errorf("Tiers() = %v, want %v", tiers, want)
+	}
+
+	for _, bad := range []string{"1h", "x@24h", "1h@0s", "24h@720h,1h@24h"} {
+		if _, err := (MetricsConfig{Schedule: bad}).Tiers(); err == nil {
+			t.Errorf("Tiers(%q) should fail", bad)
+		}
+	}
+}
+
 func TestLoad_FromYAMLFile(t *testing.T) {
 	tmpDir := t.TempDir()
 	configPath := filepath.Join(tmpDir, "config.yaml")
@@ -0,0 +1,20 @@
+package domain
+
+import "time"
+
+// AIUsageTaskTranscription is the usage task for Whisper transcription. Chat
+// tasks use the routing names from the AI config (filename, analysis, ...).
+const AIUsageTaskTranscription = "transcription"
+
+// AIUsageRecord is one billed AI or transcription call.
+type AIUsageRecord struct {
+	Time             time.Time `json:"time"`
+	Provider         string    `json:"provider"`
+	Model            string    `json:"model"`
+	Task             string    `json:"task"`
+	TweetID          TweetID   `json:"tweet_id,omitempty"` // Empty for calls outside an archive
+	PromptTokens     int       `json:"prompt_tokens,omitempty"`
+	CompletionTokens int       `json:"completion_tokens,omitempty"`
+	AudioSeconds     float64   `json:"audio_seconds,omitempty"`
+	CostUSD          float64   `json:"cost_usd"` // Estimated from list prices
+}
@@ -0,0 +1,96 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ArchivePriority orders archive requests waiting for a processing slot.
+type ArchivePriority string
+
+const (
+	PriorityHigh   ArchivePriority = "high"
+	PriorityNormal ArchivePriority = "normal"
+	PriorityLow    ArchivePriority = "low"
+)
+
+// Valid reports whether p is a known priority. Empty means normal.
+func (p ArchivePriority) Valid() bool {
+	switch p {
+	case "", PriorityHigh, PriorityNormal, PriorityLow:
+		return true
+	}
+	return false
+}
+
+// Rank returns 0 for high, 1 for normal and 2 for low priority.
+func (p ArchivePriority) Rank() int {
+	switch p {
+	case PriorityHigh:
+		return 0
+	case PriorityLow:
+		return 2
+	default:
+		return 1
+	}
+}
+
+// ArchiveOptions are per-request switches for the archive pipeline. They are
+// stored with the tweet so resumed archives and resyncs follow them.
+type ArchiveOptions struct {
+	SkipAI            bool            `json:"skip_ai,omitempty"`            // No AI naming, vision or text analysis
+	SkipTranscription bool            `json:"skip_transcription,omitempty"` // No Whisper transcription
+	MediaOnly         bool            `json:"media_only,omitempty"`         // Download files only; no analysis of any kind
+	TextOnly          bool            `json:"text_only,omitempty"`          // Keep text and metadata; skip media files
+	PlaylistIDs       []PlaylistID    `json:"playlist_ids,omitempty"`       // Added to these playlists once archived
+	Priority          ArchivePriority `json:"priority,omitempty"`
+}
+
+// IsZero reports whether no option is set.
+func (o ArchiveOptions) IsZero() bool {
+	return !o.SkipAI && !o.SkipTranscription && !o.MediaOnly && !o.TextOnly &&
+		len(o.PlaylistIDs) == 0 && o.Priority == ""
+}
+
+// Validate checks that the options are consistent.
+func (o ArchiveOptions) Validate() error {
+	if o.MediaOnly && o.TextOnly {
+		return errors.New("media_only and text_only cannot both be set")
+	}
+	if !o.Priority.Valid() {
+		return fmt.Errorf("priority must be high, normal or low, got %q", o.Priority)
+	}
+	return nil
+}
+
+// SkipsAI reports whether AI naming and analysis are disabled.
+func (o ArchiveOptions) SkipsAI() bool {
+	return o.SkipAI || o.MediaOnly
+}
+
+// SkipsTranscription reports whether video transcription is disabled.
+func (o ArchiveOptions) SkipsTranscription() bool {
+	return o.SkipTranscription || o.MediaOnly || o.TextOnly
+}
+
+// SkipsOCR reports whether text extraction from media is disabled.
+func (o ArchiveOptions) SkipsOCR() bool {
+	return o.MediaOnly || o.TextOnly
+}
+
+// NormalizeTags lowercases and trims user tags, strips a leading '#', and
+// drops empty and duplicate entries while keeping the original order.
+func NormalizeTags(tags []string) []string {
+	var out []string
+	seen := make(map[string]bool, len(tags))
+	for _, tag := range tags {
+		tag = strings.ToLower(strings.TrimPrefix(strings.TrimSpace(tag), "#"))
+		if tag == "" || seen[tag] {
+			continue
+		}
+		seen[tag] = true
+		out = append(out, tag)
+	}
+	return out
+}
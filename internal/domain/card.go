@@ -0,0 +1,46 @@
+package domain
+
+import "time"
+
+// Poll is a poll attached to a tweet, with the vote counts seen when the
+// tweet was last fetched.
+type Poll struct {
+	Options []PollOption `json:"options"`
+	EndsAt  *time.Time   `json:"ends_at,omitempty"`
+	Final   bool         `json:"final,omitempty"` // Voting has closed and counts will not change
+}
+
+// PollOption is one choice in a poll.
+type PollOption struct {
+	Label string `json:"label"`
+	Votes int    `json:"votes"`
+}
+
+// TotalVotes returns the number of votes across all options.
+func (p *Poll) TotalVotes() int {
+	if p == nil {
+		return 0
+	}
+	total := 0
+	for _, o := range p.Options {
+		total += o.Votes
+	}
+	return total
+}
+
+// LinkCard is the preview X shows for a link in a tweet.
+type LinkCard struct {
+	URL            string `json:"url"`              // Link destination
+	Domain         string `json:"domain,omitempty"` // Display domain, e.g. "example.com"
+	Title          string `json:"title,omitempty"`
+	Description    string `json:"description,omitempty"`
+	ImageURL       string `json:"image_url,omitempty"`
+	LocalImagePath string `json:"local_image_path,omitempty"` // Downloaded copy of ImageURL
+}
+
+// CommunityNote is a Community Notes annotation shown on a tweet.
+type CommunityNote struct {
+	ID   string `json:"id,omitempty"`
+	Text string `json:"text"`
+	URL  string `json:"url,omitempty"` // Note page on X
+}
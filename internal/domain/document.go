@@ -0,0 +1,87 @@
+package domain
+
+import "time"
+
+// DocumentType identifies a kind of AI-derived document.
+type DocumentType string
+
+const (
+	// DocumentTLDR is a few sentences with the gist of a tweet.
+	DocumentTLDR DocumentType = "tldr"
+	// DocumentNotes is bullet notes of the key points.
+	DocumentNotes DocumentType = "notes"
+	// DocumentFAQ is questions and answers about the content.
+	DocumentFAQ DocumentType = "faq"
+	// DocumentFlashcards is study flashcards.
+	DocumentFlashcards DocumentType = "flashcards"
+	// DocumentOutline is a chapter outline, with timestamps for videos.
+	DocumentOutline DocumentType = "outline"
+	// DocumentDigest combines a whole thread or playlist into one document.
+	DocumentDigest DocumentType = "digest"
+)
+
+// DocumentTypes lists every document type in display order.
+var DocumentTypes = []DocumentType{
+	DocumentTLDR,
+	DocumentNotes,
+	DocumentFAQ,
+	DocumentFlashcards,
+	DocumentOutline,
+	DocumentDigest,
+}
+
+// Valid reports whether t is a known document type.
+func (t DocumentType) Valid() bool {
+	for _, known := range DocumentTypes {
+		if t == known {
+			return true
+		}
+	}
+	return false
+}
+
+// Label returns a human-readable name for the document type.
+func (t DocumentType) Label() string {
+	switch t {
+	case DocumentTLDR:
+		return "TL;DR"
+	case DocumentNotes:
+		return "Notes"
+	case DocumentFAQ:
+		return "FAQ"
+	case DocumentFlashcards:
+		return "Flashcards"
+	case DocumentOutline:
+		return "Outline"
+	case DocumentDigest:
+		return "Digest"
+	default:
+		return string(t)
+	}
+}
+
+// Document generation statuses, the same values as Media.EssayStatus.
+const (
+	DocumentStatusGenerating = "generating"
+	DocumentStatusCompleted  = "completed"
+	DocumentStatusFailed     = "failed"
+)
+
+// DerivedDocument is a markdown document generated by AI from a tweet, or
+// from every tweet in a thread or playlist for digests.
+type DerivedDocument struct {
+	Type           DocumentType `json:"type"`
+	Status         string       `json:"status"`          // generating, completed, failed
+	Error          string       `json:"error,omitempty"` // Error message if generation failed
+	Title          string       `json:"title,omitempty"`
+	Content        string       `json:"content,omitempty"` // Markdown
+	WordCount      int          `json:"word_count,omitempty"`
+	SourceTweetIDs []TweetID    `json:"source_tweet_ids,omitempty"` // Tweets the document was generated from
+	PromptVersion  string       `json:"prompt_version,omitempty"`   // Prompt template version that produced the document
+	GeneratedAt    *time.Time   `json:"generated_at,omitempty"`
+}
+
+// Generating reports whether the document is being generated.
+func (d *DerivedDocument) Generating() bool {
+	return d.Status == DocumentStatusGenerating
+}
@@ -3,6 +3,7 @@ package domain
 import (
 	"encoding/json"
 	"errors"
+	"fmt"
 	"testing"
 	"time"
 )
@@ -907,3 +908,215 @@ func TestEventCategoryValues(t *testing.T) {
 		}
 	}
 }
+
+// =============================================================================
+// Failure Reason Tests
+// =============================================================================
+
+func TestFailureReasonFor(t *testing.T) {
+	tests := []struct {
+		name      string
+		err       error
+		want      FailureReason
+		permanent bool
+	}{
+		{"nil", nil, "", false},
+		{"deleted", fmt.Errorf("fetch tweet: %w", ErrTweetDeleted), FailureTweetDeleted, true},
+		{"suspended", ErrAuthorSuspended, FailureAuthorSuspended, true},
+		{"rate limited", fmt.Errorf("graphql: %w", ErrRateLimited), FailureRateLimited, false},
+		{"auth expired", ErrAuthExpired, FailureAuthExpired, false},
+		{"transient wins over permanent", fmt.Errorf("syndication: %w, graphql: %w", ErrTweetDeleted, ErrRateLimited), FailureRateLimited, false},
+		{"auth expired wins over permanent", fmt.Errorf("syndication: %w, tweetdetail: %w", ErrTweetProtected, ErrAuthExpired), FailureAuthExpired, false},
+		{"permanent sources agree", fmt.Errorf("syndication: %w, graphql: %w", ErrTweetDeleted, ErrTweetUnavailable), FailureTweetDeleted, true},
+		{"unclassified", errors.New("connection reset"), FailureUnknown, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := FailureReasonFor(tt.err)
+			if got != tt.want {
+				t.Errorf("FailureReasonFor() = %q, want %q", got, tt.want)
+			}
+			if got.Permanent() != tt.permanent {
+				t.Errorf("%q.Permanent() = %v, want %v", got, got.Permanent(), tt.permanent)
+			}
+		})
+	}
+}
+
+// =============================================================================
+// Media Quality Tests
+// =============================================================================
+
+func TestMediaQuality_ImageURL(t *testing.T) {
+	tests := []struct {
+		name string
+		size ImageSize
+		url  string
+		want string
+	}{
+		{"default keeps URL", ImageSizeDefault, "https://pbs.twimg.com/media/abc.jpg", "https://pbs.twimg.com/media/abc.jpg"},
+		{"orig from extension", ImageSizeOrig, "https://pbs.twimg.com/media/abc.jpg", "https://pbs.twimg.com/media/abc?format=jpg&name=orig"},
+		{"4096 replaces name", ImageSize4096, "https://pbs.twimg.com/media/abc?format=png&name=small", "https://pbs.twimg.com/media/abc?format=png&name=4096x4096"},
+		{"non-media host unchanged", ImageSizeOrig, "https://pbs.twimg.com/profile_images/1/a.jpg", "https://pbs.twimg.com/profile_images/1/a.jpg"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := MediaQuality{ImageSize: tt.size}.ImageURL(tt.url)
+			if got != tt.want {
+				t.Errorf("ImageURL() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMediaQuality_SelectVariant(t *testing.T) {
+	variants := []MediaVariant{
+		{URL: "360", Bitrate: 632000, Width: 640, Height: 360},
+		{URL: "1080", Bitrate: 10368000, Width: 1920, Height: 1080},
+		{URL: "720", Bitrate: 2176000, Width: 1280, Height: 720},
+	}
+
+	tests := []struct {
+		name    string
+		quality MediaQuality
+		want    string
+	}{
+		{"no caps picks best", MediaQuality{}, "1080"},
+		{"720p cap", MediaQuality{MaxVideoResolution: 720}, "720"},
+		{"bitrate cap", MediaQuality{MaxVideoBitrate: 1000000}, "360"},
+		{"nothing fits picks smallest", MediaQuality{MaxVideoResolution: 240}, "360"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := tt.quality.SelectVariant(variants)
+			if !ok || got.URL != tt.want {
+				t.Errorf("SelectVariant() = %q, %v, want %q", got.URL, ok, tt.want)
+			}
+		})
+	}
+
+	// Portrait video: 720x1280 is 720p
+	portrait := []MediaVariant{{URL: "p", Width: 720, Height: 1280}}
+	if got, _ := (MediaQuality{MaxVideoResolution: 720}).SelectVariant(portrait); got.URL != "p" {
+		t.Errorf("portrait 720p should fit a 720 cap, got %q", got.URL)
+	}
+	if _, ok := (MediaQuality{}).SelectVariant(nil); ok {
+		t.Error("SelectVariant(nil) should report no variant")
+	}
+}
+
+func TestMediaQuality_Validate(t *testing.T) {
+	if err := (MediaQuality{ImageSize: ImageSizeOrig, MaxVideoResolution: 720}).Validate(); err != nil {
+		t.Errorf("Validate() = %v, want nil", err)
+	}
+	if err := (MediaQuality{ImageSize: "huge"}).Validate(); err == nil {
+		t.Error("Validate() should reject unknown image size")
+	}
+	if err := (MediaQuality{MaxVideoBitrate: -1}).Validate(); err == nil {
+		t.Error("Validate() should reject negative caps")
+	}
+}
+
+// =============================================================================
+// Archive Options Tests
+// =============================================================================
+
+func TestArchiveOptions(t *testing.T) {
+	if err := (ArchiveOptions{MediaOnly: true, TextOnly: true}).Validate(); err == nil {
+		t.Error("Validate() should reject media_only with text_only")
+	}
+	if err := (ArchiveOptions{Priority: "urgent"}).Validate(); err == nil {
+		t.Error("Validate() should reject unknown priority")
+	}
+	if err := (ArchiveOptions{SkipAI: true, Priority: PriorityHigh}).Validate(); err != nil {
+		t.Errorf("Validate() = %v, want nil", err)
+	}
+
+	mediaOnly := ArchiveOptions{MediaOnly: true}
+	if !mediaOnly.SkipsAI() || !mediaOnly.SkipsTranscription() || !mediaOnly.SkipsOCR() {
+		t.Error("media_only should skip all analysis")
+	}
+	textOnly := ArchiveOptions{TextOnly: true}
+	if textOnly.SkipsAI() || !textOnly.SkipsTranscription() {
+		t.Error("text_only should keep AI text analysis but skip transcription")
+	}
+	if !(ArchiveOptions{}).IsZero() || (ArchiveOptions{PlaylistIDs: []PlaylistID{"p"}}).IsZero() {
+		t.Error("IsZero() mismatch")
+	}
+
+	if PriorityHigh.Rank() >= ArchivePriority("").Rank() || ArchivePriority("").Rank() >= PriorityLow.Rank() {
+		t.Error("priority ranks should order high < normal < low")
+	}
+}
+
+func TestNormalizeTags(t *testing.T) {
+	got := NormalizeTags([]string{" Cats ", "#research", "cats", "", "#"})
+	want := []string{"cats", "research"}
+	if fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Errorf("NormalizeTags() = %v, want %v", got, want)
+	}
+}
+
+func TestMedia_Alt(t *testing.T) {
+	m := Media{AIAltText: "A cat asleep on a keyboard"}
+	if got := m.Alt(); got != "A cat asleep on a keyboard" {
+		t.Errorf("Alt() = %q, want the AI description", got)
+	}
+	m.AltText = "My cat"
+	if got := m.Alt(); got != "My cat" {
+		t.Errorf("Alt() = %q, want the author's alt text", got)
+	}
+}
+
+func TestTagRegistry(t *testing.T) {
+	var none *TagRegistry
+	if got := none.Apply([]string{"AI", "#ai", "Machine_Learning", " "}); fmt.Sprint(got) != "[ai machine learning]" {
+		t.Errorf("nil registry Apply() = %v", got)
+	}
+
+	r := &TagRegistry{}
+	if err := r.Merge("artificial intelligence", "AI", "artificial-intelligence"); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.Merge("ML", "machine learning"); err != nil {
+		t.Fatal(err)
+	}
+	// Merging the canonical tag moves its aliases, so they never chain.
+	if err := r.Merge("artificial intelligence", "ml"); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.Block("Viral"); err != nil {
+		t.Fatal(err)
+	}
+
+	got := r.Apply([]string{"AI", "Machine Learning", "viral", "cats", "ML", "Artificial Intelligence"})
+	if want := "[artificial intelligence cats]"; fmt.Sprint(got) != want {
+		t.Errorf("Apply() = %v, want %v", got, want)
+	}
+	if fmt.Sprint(r.Canonical) != "[artificial intelligence]" {
+		t.Errorf("canonical = %v", r.Canonical)
+	}
+	if got := r.AliasesOf("artificial intelligence"); fmt.Sprint(got) != "[ai machine learning ml]" {
+		t.Errorf("AliasesOf() = %v", got)
+	}
+
+	if err := r.Merge("viral", "trending"); err != ErrTagBlocked {
+		t.Errorf("merge into blocked tag: err = %v, want ErrTagBlocked", err)
+	}
+	if err := r.Merge("", "x"); err != ErrEmptyTag {
+		t.Errorf("merge into empty tag: err = %v, want ErrEmptyTag", err)
+	}
+	if err := r.RemoveAlias("missing"); err != ErrTagAliasNotFound {
+		t.Errorf("RemoveAlias(missing) err = %v", err)
+	}
+	if err := r.RemoveAlias("AI"); err != nil || r.Apply([]string{"ai"})[0] != "ai" {
+		t.Errorf("removed alias should no longer resolve: err = %v", err)
+	}
+	r.Unblock("viral")
+	if r.IsBlocked("viral") || len(r.Apply([]string{"viral"})) != 1 {
+		t.Error("unblocked tag should be kept")
+	}
+}
@@ -43,6 +43,24 @@ var (
 	// ErrRateLimited is returned when rate limited by external services.
 	ErrRateLimited = errors.New("rate limited")
 
+	// ErrTweetDeleted is returned when X reports the tweet was deleted.
+	ErrTweetDeleted = errors.New("tweet has been deleted")
+
+	// ErrAuthorSuspended is returned when the tweet's author account is suspended.
+	ErrAuthorSuspended = errors.New("author account is suspended")
+
+	// ErrTweetProtected is returned when the tweet is from a protected account.
+	ErrTweetProtected = errors.New("tweet is from a protected account")
+
+	// ErrTweetWithheld is returned when the tweet is withheld in response to a legal demand.
+	ErrTweetWithheld = errors.New("tweet is withheld")
+
+	// ErrTweetUnavailable is returned when X hides the tweet without saying why.
+	ErrTweetUnavailable = errors.New("tweet is unavailable")
+
+	// ErrAuthExpired is returned when stored X credentials are no longer accepted.
+	ErrAuthExpired = errors.New("X authentication expired")
+
 	// ErrMediaNotFound is returned when a media file cannot be found.
 	ErrMediaNotFound = errors.New("media file not found")
 
@@ -66,6 +84,9 @@ var (
 
 	// ErrEmptySmartQuery is returned when a smart playlist is created with an empty query.
 	ErrEmptySmartQuery = errors.New("smart playlist query cannot be empty")
+
+	// ErrAIBudgetExceeded is returned when the daily or monthly AI budget is spent.
+	ErrAIBudgetExceeded = errors.New("AI budget exceeded")
 )
 
 // VideoError wraps an error with video context.
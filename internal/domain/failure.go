@@ -0,0 +1,58 @@
+package domain
+
+import "errors"
+
+// FailureReason classifies why an archive failed, so clients can tell a tweet
+// that will never be archivable from one worth retrying.
+type FailureReason string
+
+const (
+	FailureTweetDeleted    FailureReason = "tweet_deleted"
+	FailureAuthorSuspended FailureReason = "author_suspended"
+	FailureProtected       FailureReason = "protected"
+	FailureWithheld        FailureReason = "withheld"
+	FailureUnavailable     FailureReason = "unavailable"
+	FailureRateLimited     FailureReason = "rate_limited"
+	FailureAuthExpired     FailureReason = "auth_expired"
+	FailureUnknown         FailureReason = "unknown"
+)
+
+// failureReasons maps domain errors to reasons. Transient reasons come first
+// so they win when an error wraps several causes that disagree (e.g.
+// syndication reported the tweet deleted but the GraphQL fallback was rate
+// limited): the tweet is only given up on when no source might still answer.
+var failureReasons = []struct {
+	err    error
+	reason FailureReason
+}{
+	{ErrAuthExpired, FailureAuthExpired},
+	{ErrRateLimited, FailureRateLimited},
+	{ErrTweetDeleted, FailureTweetDeleted},
+	{ErrAuthorSuspended, FailureAuthorSuspended},
+	{ErrTweetProtected, FailureProtected},
+	{ErrTweetWithheld, FailureWithheld},
+	{ErrTweetUnavailable, FailureUnavailable},
+}
+
+// FailureReasonFor classifies err. It returns "" for a nil error and
+// FailureUnknown when err matches no known domain error.
+func FailureReasonFor(err error) FailureReason {
+	if err == nil {
+		return ""
+	}
+	for _, fr := range failureReasons {
+		if errors.Is(err, fr.err) {
+			return fr.reason
+		}
+	}
+	return FailureUnknown
+}
+
+// Permanent reports whether retrying can never succeed.
+func (r FailureReason) Permanent() bool {
+	switch r {
+	case FailureTweetDeleted, FailureAuthorSuspended, FailureProtected, FailureWithheld, FailureUnavailable:
+		return true
+	}
+	return false
+}
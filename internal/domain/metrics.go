@@ -0,0 +1,38 @@
+package domain
+
+import "time"
+
+// Metrics sample sources.
+const (
+	MetricsSourceArchive = "archive" // Counts captured when the tweet was archived
+	MetricsSourcePoll    = "poll"    // Scheduled re-poll
+)
+
+// MetricsSample is one point of a tweet's engagement time series.
+type MetricsSample struct {
+	SampledAt time.Time `json:"sampled_at"`
+	Source    string    `json:"source"`
+	Likes     int       `json:"likes"`
+	Retweets  int       `json:"retweets"`
+	Replies   int       `json:"replies"`
+	Quotes    int       `json:"quotes,omitempty"`
+	Views     *int      `json:"views,omitempty"` // Nil when the source did not report views
+}
+
+// NewMetricsSample creates a sample of metrics taken at at. A zero view count
+// means views were not reported (syndication never does) and is left out.
+func NewMetricsSample(at time.Time, source string, m TweetMetrics) MetricsSample {
+	sample := MetricsSample{
+		SampledAt: at,
+		Source:    source,
+		Likes:     m.Likes,
+		Retweets:  m.Retweets,
+		Replies:   m.Replies,
+		Quotes:    m.Quotes,
+	}
+	if m.Views > 0 {
+		views := m.Views
+		sample.Views = &views
+	}
+	return sample
+}
@@ -35,7 +35,8 @@ type Playlist struct {
 	Description string               `json:"description,omitempty"`
 	Type        PlaylistType         `json:"type"`
 	SmartConfig *SmartPlaylistConfig `json:"smart_config,omitempty"`
-	Items       []string             `json:"items"` // Tweet IDs in playback order
+	Items       []string             `json:"items"`            // Tweet IDs in playback order
+	Digest      *DerivedDocument     `json:"digest,omitempty"` // AI digest of the playlist's tweets
 	CreatedAt   time.Time            `json:"created_at"`
 	UpdatedAt   time.Time            `json:"updated_at"`
 }
@@ -0,0 +1,63 @@
+package domain
+
+import "time"
+
+// Fields tracked by TweetRevision.Changed.
+const (
+	RevisionFieldText        = "text"
+	RevisionFieldEditHistory = "edit_tweet_ids"
+	RevisionFieldMetrics     = "metrics"
+	RevisionFieldMedia       = "media"
+)
+
+// Revision sources.
+const (
+	RevisionSourceArchive = "archive" // State at archive time, recorded before the first change
+	RevisionSourceResync  = "resync"
+)
+
+// TweetRevision is a dated snapshot of the fields of a tweet that can change
+// upstream. Revisions are numbered from 1; the first one is the archived state.
+type TweetRevision struct {
+	Number     int       `json:"number"`
+	RecordedAt time.Time `json:"recorded_at"`
+	Source     string    `json:"source"`
+	Changed    []string  `json:"changed,omitempty"` // Fields that differ from the previous revision
+	// Edited is set when X reported new post edits since the previous revision.
+	Edited bool `json:"edited,omitempty"`
+
+	Text         string       `json:"text"`
+	EditTweetIDs []string     `json:"edit_tweet_ids,omitempty"`
+	Metrics      TweetMetrics `json:"metrics"`
+	MediaIDs     []string     `json:"media_ids,omitempty"`
+}
+
+// LatestEditID returns the ID of the newest version of an edited post, or ""
+// when there is no edit history.
+func (t *Tweet) LatestEditID() string {
+	if len(t.EditTweetIDs) == 0 {
+		return ""
+	}
+	return t.EditTweetIDs[len(t.EditTweetIDs)-1]
+}
+
+// EditedAfterArchive reports whether X's edit history grew after the tweet
+// was archived.
+func (t *Tweet) EditedAfterArchive() bool {
+	for _, r := range t.Revisions {
+		if r.Edited {
+			return true
+		}
+	}
+	return false
+}
+
+// Revision returns revision n, or nil if it does not exist.
+func (t *Tweet) Revision(n int) *TweetRevision {
+	for i := range t.Revisions {
+		if t.Revisions[i].Number == n {
+			return &t.Revisions[i]
+		}
+	}
+	return nil
+}
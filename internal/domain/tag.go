@@ -0,0 +1,246 @@
+package domain
+
+import (
+	"errors"
+	"sort"
+	"strings"
+	"time"
+)
+
+var (
+	// ErrEmptyTag is returned when a tag is empty after normalization.
+	ErrEmptyTag = errors.New("tag cannot be empty")
+
+	// ErrTagBlocked is returned when tags are merged into a blocked tag.
+	ErrTagBlocked = errors.New("tag is blocked")
+
+	// ErrTagAliasNotFound is returned when removing an alias that does not exist.
+	ErrTagAliasNotFound = errors.New("tag alias not found")
+)
+
+// TagRegistry curates the free-form tags and topics produced by AI analysis.
+// Tags are compared in their NormalizeTag form. Aliases replace a tag with
+// its canonical tag, and blocked tags are dropped. The zero value, and a nil
+// registry, only normalize.
+type TagRegistry struct {
+	Canonical []string          `json:"canonical,omitempty"` // Curated tags, sorted
+	Aliases   map[string]string `json:"aliases,omitempty"`   // Alias -> canonical tag
+	Blocked   []string          `json:"blocked,omitempty"`   // Tags dropped from analysis results, sorted
+	UpdatedAt time.Time         `json:"updated_at,omitempty"`
+}
+
+// TagCount is a tag with the number of archived tweets carrying it in their
+// tweet or media AI tags or topics.
+type TagCount struct {
+	Tag       string   `json:"tag"`
+	Count     int      `json:"count"`
+	Canonical bool     `json:"canonical,omitempty"`
+	Aliases   []string `json:"aliases,omitempty"`
+}
+
+// NormalizeTag lowercases and trims a tag, strips a leading '#', and treats
+// hyphens, underscores and runs of whitespace as a single space, so "AI",
+// "#ai" and "Machine_Learning" become "ai" and "machine learning".
+func NormalizeTag(tag string) string {
+	tag = strings.TrimPrefix(strings.TrimSpace(tag), "#")
+	tag = strings.NewReplacer("-", " ", "_", " ").Replace(strings.ToLower(tag))
+	return strings.Join(strings.Fields(tag), " ")
+}
+
+// Resolve returns the tag a registry keeps for tag: its canonical tag when
+// it is an alias, otherwise the normalized tag. It reports false for empty
+// and blocked tags.
+func (r *TagRegistry) Resolve(tag string) (string, bool) {
+	tag = NormalizeTag(tag)
+	if tag == "" {
+		return "", false
+	}
+	if r == nil {
+		return tag, true
+	}
+	if canonical, ok := r.Aliases[tag]; ok {
+		tag = canonical
+	}
+	if r.IsBlocked(tag) {
+		return "", false
+	}
+	return tag, true
+}
+
+// Apply resolves each tag, dropping empty, blocked and duplicate entries
+// while keeping the original order.
+func (r *TagRegistry) Apply(tags []string) []string {
+	var out []string
+	seen := make(map[string]bool, len(tags))
+	for _, tag := range tags {
+		tag, ok := r.Resolve(tag)
+		if !ok || seen[tag] {
+			continue
+		}
+		seen[tag] = true
+		out = append(out, tag)
+	}
+	return out
+}
+
+// IsCanonical reports whether tag is in the curated list.
+func (r *TagRegistry) IsCanonical(tag string) bool {
+	return r != nil && containsSorted(r.Canonical, NormalizeTag(tag))
+}
+
+// IsBlocked reports whether tag is on the blocklist.
+func (r *TagRegistry) IsBlocked(tag string) bool {
+	return r != nil && containsSorted(r.Blocked, NormalizeTag(tag))
+}
+
+// AliasesOf returns the aliases of a canonical tag, sorted.
+func (r *TagRegistry) AliasesOf(tag string) []string {
+	if r == nil {
+		return nil
+	}
+	tag = NormalizeTag(tag)
+	var aliases []string
+	for alias, canonical := range r.Aliases {
+		if canonical == tag {
+			aliases = append(aliases, alias)
+		}
+	}
+	sort.Strings(aliases)
+	return aliases
+}
+
+// AddCanonical adds a tag to the curated list. A tag that was an alias or
+// blocked stops being one.
+func (r *TagRegistry) AddCanonical(tag string) error {
+	tag = NormalizeTag(tag)
+	if tag == "" {
+		return ErrEmptyTag
+	}
+	delete(r.Aliases, tag)
+	r.Blocked = removeSorted(r.Blocked, tag)
+	r.Canonical = insertSorted(r.Canonical, tag)
+	return nil
+}
+
+// RemoveCanonical removes a tag from the curated list. Aliases of the tag
+// keep resolving to it.
+func (r *TagRegistry) RemoveCanonical(tag string) {
+	r.Canonical = removeSorted(r.Canonical, NormalizeTag(tag))
+}
+
+// Merge makes every tag in from an alias of into, which becomes canonical.
+// Aliases of a merged tag move to into, so aliases never chain. Renaming a
+// tag is a merge of one tag.
+func (r *TagRegistry) Merge(into string, from ...string) error {
+	into = r.resolveAlias(into)
+	if into == "" {
+		return ErrEmptyTag
+	}
+	if r.IsBlocked(into) {
+		return ErrTagBlocked
+	}
+	var sources []string
+	for _, tag := range from {
+		if tag = NormalizeTag(tag); tag != "" && tag != into {
+			sources = append(sources, tag)
+		}
+	}
+	if len(sources) == 0 {
+		return ErrEmptyTag
+	}
+
+	if r.Aliases == nil {
+		r.Aliases = make(map[string]string)
+	}
+	delete(r.Aliases, into)
+	for _, tag := range sources {
+		for alias, canonical := range r.Aliases {
+			if canonical == tag {
+				r.Aliases[alias] = into
+			}
+		}
+		r.Aliases[tag] = into
+		r.Canonical = removeSorted(r.Canonical, tag)
+		r.Blocked = removeSorted(r.Blocked, tag)
+	}
+	r.Canonical = insertSorted(r.Canonical, into)
+	return nil
+}
+
+// RemoveAlias stops replacing alias with its canonical tag.
+func (r *TagRegistry) RemoveAlias(alias string) error {
+	alias = NormalizeTag(alias)
+	if _, ok := r.Aliases[alias]; !ok {
+		return ErrTagAliasNotFound
+	}
+	delete(r.Aliases, alias)
+	return nil
+}
+
+// Block adds a tag to the blocklist. It stops being canonical or an alias;
+// aliases of it now resolve to a blocked tag and are dropped too.
+func (r *TagRegistry) Block(tag string) error {
+	tag = NormalizeTag(tag)
+	if tag == "" {
+		return ErrEmptyTag
+	}
+	delete(r.Aliases, tag)
+	r.Canonical = removeSorted(r.Canonical, tag)
+	r.Blocked = insertSorted(r.Blocked, tag)
+	return nil
+}
+
+// Unblock removes a tag from the blocklist.
+func (r *TagRegistry) Unblock(tag string) {
+	r.Blocked = removeSorted(r.Blocked, NormalizeTag(tag))
+}
+
+// Clone returns a deep copy of the registry.
+func (r *TagRegistry) Clone() TagRegistry {
+	out := TagRegistry{
+		Canonical: append([]string(nil), r.Canonical...),
+		Blocked:   append([]string(nil), r.Blocked...),
+		UpdatedAt: r.UpdatedAt,
+	}
+	if len(r.Aliases) > 0 {
+		out.Aliases = make(map[string]string, len(r.Aliases))
+		for alias, canonical := range r.Aliases {
+			out.Aliases[alias] = canonical
+		}
+	}
+	return out
+}
+
+// resolveAlias normalizes tag and follows it to its canonical tag, ignoring
+// the blocklist.
+func (r *TagRegistry) resolveAlias(tag string) string {
+	tag = NormalizeTag(tag)
+	if canonical, ok := r.Aliases[tag]; ok {
+		return canonical
+	}
+	return tag
+}
+
+func containsSorted(list []string, s string) bool {
+	i := sort.SearchStrings(list, s)
+	return i < len(list) && list[i] == s
+}
+
+func insertSorted(list []string, s string) []string {
+	i := sort.SearchStrings(list, s)
+	if i < len(list) && list[i] == s {
+		return list
+	}
+	list = append(list, "")
+	copy(list[i+1:], list[i:])
+	list[i] = s
+	return list
+}
+
+func removeSorted(list []string, s string) []string {
+	i := sort.SearchStrings(list, s)
+	if i == len(list) || list[i] != s {
+		return list
+	}
+	return append(list[:i], list[i+1:]...)
+}
@@ -0,0 +1,38 @@
+package domain
+
+import (
+	"sort"
+	"time"
+)
+
+// TweetTranslation is a translation of a tweet's text, article body and
+// video transcripts into one language.
+type TweetTranslation struct {
+	Language       string    `json:"language"`                  // Target language (ISO-639-1)
+	SourceLanguage string    `json:"source_language,omitempty"` // Language detected in the tweet text
+	Text           string    `json:"text,omitempty"`
+	ArticleBody    string    `json:"article_body,omitempty"`
+	TranslatedAt   time.Time `json:"translated_at"`
+	PromptVersion  string    `json:"prompt_version,omitempty"` // Prompt template version that produced the translation
+
+	// Media holds translated transcripts keyed by Media.ID. Media without a
+	// transcript, or whose transcript is already in Language, are left out.
+	Media map[string]MediaTranslation `json:"media,omitempty"`
+}
+
+// MediaTranslation is a translated video transcript. Segments keep the
+// timings of Media.TranscriptSegments so they can be used as subtitles.
+type MediaTranslation struct {
+	Transcript string              `json:"transcript"`
+	Segments   []TranscriptSegment `json:"segments,omitempty"`
+}
+
+// TranslationLanguages returns the languages the tweet has translations for.
+func (t *Tweet) TranslationLanguages() []string {
+	langs := make([]string, 0, len(t.Translations))
+	for lang := range t.Translations {
+		langs = append(langs, lang)
+	}
+	sort.Strings(langs)
+	return langs
+}
@@ -40,13 +40,31 @@ type Tweet struct {
 	QuotedTweet   *TweetID // If this quotes another tweet
 	Status        ArchiveStatus
 	Error         string
-	ArchivePath   string // Base path where tweet is stored
+	FailureReason FailureReason // Set with Error when Status is failed
+	ArchivePath   string        // Base path where tweet is stored
+
+	// FetchedWithCredentials is set when the tweet was only visible to the
+	// user's logged-in session (protected account, sensitive media).
+	FetchedWithCredentials bool
+
+	// MediaQuality overrides the server's media quality policy for this
+	// tweet. It is kept so re-downloads fetch the same renditions.
+	MediaQuality *MediaQuality
+
+	// Options are the pipeline switches from the archive request and Tags the
+	// user's own labels (distinct from AITags).
+	Options ArchiveOptions
+	Tags    []string
 
 	// Phase completion timestamps for incremental processing
 	FetchedAt    *time.Time // When metadata was retrieved from Twitter
 	DownloadedAt *time.Time // When all media finished downloading
 	AnalyzedAt   *time.Time // When AI analysis completed
 
+	// AIPending is set when transcription and AI analysis were skipped
+	// because an AI budget was exceeded. They run once the budget resets.
+	AIPending bool
+
 	// Progress tracking for UI
 	MediaDownloaded int // Number of media items downloaded so far
 	MediaTotal      int // Total media items to download
@@ -55,6 +73,12 @@ type Tweet struct {
 	AITags        []string // AI-generated searchable tags
 	AIContentType string   // AI-detected content type (documentary, news, etc.)
 	AITopics      []string // AI-detected main topics
+	// AIRawTags and AIRawTopics keep the tags and topics as the AI produced
+	// them; AITags and AITopics are their view curated by the tag registry.
+	// Nil on archives curated before raw tags were kept.
+	AIRawTags       []string
+	AIRawTopics     []string
+	AIPromptVersion string // Prompt template version that produced the AI analysis
 	CreatedAt     time.Time
 	ArchivedAt    *time.Time
 
@@ -66,6 +90,29 @@ type Tweet struct {
 	ArticleImages  []ArticleImage // Inline images within the article body
 	WordCount      int            // Word count for articles
 	ReadingMinutes int            // Estimated reading time
+
+	// Translations of the text, article body and transcripts, keyed by
+	// target language (ISO-639-1)
+	Translations map[string]TweetTranslation
+
+	// Documents derived from the tweet by AI (notes, FAQ, digest, ...)
+	Documents map[DocumentType]DerivedDocument
+
+	// Attachments rendered by X alongside the text
+	Poll          *Poll
+	Card          *LinkCard
+	CommunityNote *CommunityNote
+
+	// EditTweetIDs is X's edit history for the post, oldest first. It includes
+	// the original ID and is empty for posts that were never editable.
+	EditTweetIDs []string
+	// Revisions are dated snapshots of fields that changed on resync
+	Revisions []TweetRevision
+
+	// Upstream availability, maintained by the upstream checker
+	UpstreamStatus    UpstreamStatus // Empty until first checked
+	UpstreamCheckedAt *time.Time
+	UpstreamChangedAt *time.Time // When UpstreamStatus last changed
 }
 
 // Author represents the tweet author with metadata captured at archival time.
@@ -92,6 +139,9 @@ type Media struct {
 	Height     int       `json:"height,omitempty"`
 	Duration   int       `json:"duration_seconds,omitempty"` // For videos
 	Bitrate    int       `json:"bitrate,omitempty"`          // For videos
+	// Variants lists every MP4 rendition of a video, best first. URL is the
+	// one selected by the media quality policy.
+	Variants   []MediaVariant `json:"variants,omitempty"`
 	AltText    string    `json:"alt_text,omitempty"`
 	LocalPath  string    `json:"local_path,omitempty"` // Path after download
 	Downloaded bool      `json:"downloaded"`
@@ -100,10 +150,31 @@ type Media struct {
 	AITags        []string `json:"ai_tags,omitempty"`         // Searchable tags specific to this media
 	AIContentType string   `json:"ai_content_type,omitempty"` // Content type for this media
 	AITopics      []string `json:"ai_topics,omitempty"`       // Topics specific to this media
+	AIRawTags     []string `json:"ai_raw_tags,omitempty"`     // AITags before tag registry curation
+	AIRawTopics   []string `json:"ai_raw_topics,omitempty"`   // AITopics before tag registry curation
+	// AIAltText is an AI-written accessibility description for images and
+	// GIFs the author gave no alt text. Unlike AICaption it plainly describes
+	// what is shown, for screen readers.
+	AIAltText string `json:"ai_alt_text,omitempty"`
 
 	// Transcript fields for videos
 	Transcript         string `json:"transcript,omitempty"`          // Full audio transcript
 	TranscriptLanguage string `json:"transcript_language,omitempty"` // Detected language (ISO-639-1)
+	TranscriptSegments []TranscriptSegment `json:"transcript_segments,omitempty"` // Timed segments from Whisper
+
+	// OCR text extracted from the image (or video keyframes)
+	OCRText   string `json:"ocr_text,omitempty"`
+	OCREngine string `json:"ocr_engine,omitempty"` // Engine that produced OCRText
+
+	// Content and perceptual hashes for duplicate detection
+	SHA256         string   `json:"sha256,omitempty"`          // Hex SHA-256 of the downloaded file
+	PHash          string   `json:"phash,omitempty"`           // DCT hash of the image (or video thumbnail)
+	DHash          string   `json:"dhash,omitempty"`           // Difference hash of the image (or video thumbnail)
+	KeyframeHashes []string `json:"keyframe_hashes,omitempty"` // Per-keyframe pHash sequence for videos
+
+	// Corrupt is set when an integrity scrub finds the file missing or altered.
+	// Downloaded is cleared at the same time; Resync re-downloads the file.
+	Corrupt bool `json:"corrupt,omitempty"`
 
 	// Essay fields - AI-generated essays from transcript
 	Essay         string `json:"essay,omitempty"`          // Full markdown essay
@@ -111,6 +182,40 @@ type Media struct {
 	EssayStatus   string `json:"essay_status,omitempty"`   // pending, generating, completed, failed
 	EssayError    string `json:"essay_error,omitempty"`    // Error message if generation failed
 	EssayWordCount int   `json:"essay_word_count,omitempty"` // Word count of the essay
+	EssayPromptVersion string `json:"essay_prompt_version,omitempty"` // Prompt template version that produced the essay
+
+	// Chapters are AI-generated titled sections of a long video, derived
+	// from its transcript segments and ordered by start time.
+	Chapters              []Chapter `json:"chapters,omitempty"`
+	ChaptersPromptVersion string    `json:"chapters_prompt_version,omitempty"` // Prompt template version that produced the chapters
+	// ChaptersVideoPath is a playback copy of the video with the chapters
+	// embedded; LocalPath keeps the original bytes
+	ChaptersVideoPath string `json:"chapters_video_path,omitempty"`
+}
+
+// Chapter is a titled section of a video.
+// Start and End are offsets in seconds from the beginning of the video.
+type Chapter struct {
+	Start float64 `json:"start"`
+	End   float64 `json:"end"`
+	Title string  `json:"title"`
+}
+
+// Alt returns the text to use as the media's alt attribute: the author's
+// alt text, or the AI-generated description when the author gave none.
+func (m Media) Alt() string {
+	if m.AltText != "" {
+		return m.AltText
+	}
+	return m.AIAltText
+}
+
+// TranscriptSegment is a timed span of a video transcript.
+// Start and End are offsets in seconds from the beginning of the video.
+type TranscriptSegment struct {
+	Start float64 `json:"start"`
+	End   float64 `json:"end"`
+	Text  string  `json:"text"`
 }
 
 // ArticleImage represents an inline image within an article body.
@@ -166,11 +271,19 @@ type StoredTweet struct {
 	ReplyTo       string       `json:"reply_to,omitempty"`
 	QuotedTweet   string       `json:"quoted_tweet,omitempty"`
 
+	FetchedWithCredentials bool          `json:"fetched_with_credentials,omitempty"`
+	MediaQuality           *MediaQuality `json:"media_quality,omitempty"`
+	Options                *ArchiveOptions `json:"archive_options,omitempty"`
+	Tags                   []string        `json:"tags,omitempty"`
+
 	// Processing status and phase tracking
 	Status          string     `json:"status"`
+	Error           string     `json:"error,omitempty"`
+	FailureReason   string     `json:"failure_reason,omitempty"`
 	FetchedAt       *time.Time `json:"fetched_at,omitempty"`
 	DownloadedAt    *time.Time `json:"downloaded_at,omitempty"`
 	AnalyzedAt      *time.Time `json:"analyzed_at,omitempty"`
+	AIPending       bool       `json:"ai_pending,omitempty"`
 	MediaDownloaded int        `json:"media_downloaded,omitempty"`
 	MediaTotal      int        `json:"media_total,omitempty"`
 
@@ -180,6 +293,9 @@ type StoredTweet struct {
 	AITags        []string `json:"ai_tags,omitempty"`
 	AIContentType string   `json:"ai_content_type,omitempty"`
 	AITopics      []string `json:"ai_topics,omitempty"`
+	AIRawTags     []string `json:"ai_raw_tags,omitempty"`
+	AIRawTopics   []string `json:"ai_raw_topics,omitempty"`
+	AIPromptVersion string `json:"ai_prompt_version,omitempty"`
 
 	// Article-specific fields (when content_type == "article")
 	ContentType    string         `json:"content_type,omitempty"`
@@ -189,6 +305,20 @@ type StoredTweet struct {
 	ArticleImages  []ArticleImage `json:"article_images,omitempty"`
 	WordCount      int            `json:"word_count,omitempty"`
 	ReadingMinutes int            `json:"reading_minutes,omitempty"`
+
+	Translations map[string]TweetTranslation `json:"translations,omitempty"`
+	Documents    map[DocumentType]DerivedDocument `json:"documents,omitempty"`
+
+	Poll          *Poll          `json:"poll,omitempty"`
+	Card          *LinkCard      `json:"card,omitempty"`
+	CommunityNote *CommunityNote `json:"community_note,omitempty"`
+
+	EditTweetIDs []string        `json:"edit_tweet_ids,omitempty"`
+	Revisions    []TweetRevision `json:"revisions,omitempty"`
+
+	UpstreamStatus    string     `json:"upstream_status,omitempty"`
+	UpstreamCheckedAt *time.Time `json:"upstream_checked_at,omitempty"`
+	UpstreamChangedAt *time.Time `json:"upstream_changed_at,omitempty"`
 }
 
 // ToStoredTweet converts a Tweet to StoredTweet for JSON serialization.
@@ -209,9 +339,12 @@ func (t *Tweet) ToStoredTweet() StoredTweet {
 		Media:           t.Media,
 		Metrics:         t.Metrics,
 		Status:          string(t.Status),
+		Error:           t.Error,
+		FailureReason:   string(t.FailureReason),
 		FetchedAt:       t.FetchedAt,
 		DownloadedAt:    t.DownloadedAt,
 		AnalyzedAt:      t.AnalyzedAt,
+		AIPending:       t.AIPending,
 		MediaDownloaded: t.MediaDownloaded,
 		MediaTotal:      t.MediaTotal,
 		AITitle:         t.AITitle,
@@ -219,6 +352,9 @@ func (t *Tweet) ToStoredTweet() StoredTweet {
 		AITags:          t.AITags,
 		AIContentType:   t.AIContentType,
 		AITopics:        t.AITopics,
+		AIRawTags:       t.AIRawTags,
+		AIRawTopics:     t.AIRawTopics,
+		AIPromptVersion: t.AIPromptVersion,
 		// Article fields
 		ContentType:    string(t.ContentType),
 		ArticleTitle:   t.ArticleTitle,
@@ -227,6 +363,21 @@ func (t *Tweet) ToStoredTweet() StoredTweet {
 		ArticleImages:  t.ArticleImages,
 		WordCount:      t.WordCount,
 		ReadingMinutes: t.ReadingMinutes,
+		Translations:   t.Translations,
+		Documents:      t.Documents,
+		Poll:           t.Poll,
+		Card:           t.Card,
+		CommunityNote:  t.CommunityNote,
+		EditTweetIDs:   t.EditTweetIDs,
+		Revisions:      t.Revisions,
+
+		FetchedWithCredentials: t.FetchedWithCredentials,
+		MediaQuality:           t.MediaQuality,
+		Tags:                   t.Tags,
+
+		UpstreamStatus:    string(t.UpstreamStatus),
+		UpstreamCheckedAt: t.UpstreamCheckedAt,
+		UpstreamChangedAt: t.UpstreamChangedAt,
 	}
 
 	if t.ReplyTo != nil {
@@ -236,6 +387,10 @@ func (t *Tweet) ToStoredTweet() StoredTweet {
 		st.QuotedTweet = t.QuotedTweet.String()
 	}
 
+	if !t.Options.IsZero() {
+		opts := t.Options
+		st.Options = &opts
+	}
 	return st
 }
 
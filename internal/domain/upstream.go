@@ -0,0 +1,29 @@
+package domain
+
+// UpstreamStatus is whether an archived tweet is still visible on X.
+type UpstreamStatus string
+
+const (
+	UpstreamStatusAvailable   UpstreamStatus = "available"
+	UpstreamStatusDeleted     UpstreamStatus = "deleted"     // Removed by the author or X, or the account no longer exists
+	UpstreamStatusSuspended   UpstreamStatus = "suspended"   // Author account is suspended
+	UpstreamStatusProtected   UpstreamStatus = "protected"   // Author made their posts private
+	UpstreamStatusWithheld    UpstreamStatus = "withheld"    // Withheld in response to a legal demand
+	UpstreamStatusUnavailable UpstreamStatus = "unavailable" // Gone for a reason X does not state
+)
+
+// ParseUpstreamStatus returns the status named s, or false if s is not one.
+func ParseUpstreamStatus(s string) (UpstreamStatus, bool) {
+	switch st := UpstreamStatus(s); st {
+	case UpstreamStatusAvailable, UpstreamStatusDeleted, UpstreamStatusSuspended,
+		UpstreamStatusProtected, UpstreamStatusWithheld, UpstreamStatusUnavailable:
+		return st, true
+	}
+	return "", false
+}
+
+// Gone reports whether the tweet can no longer be viewed publicly on X.
+// An unchecked tweet (empty status) is not gone.
+func (s UpstreamStatus) Gone() bool {
+	return s != "" && s != UpstreamStatusAvailable
+}
@@ -0,0 +1,205 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/iconidentify/xgrabba/internal/config"
+	"github.com/iconidentify/xgrabba/internal/domain"
+	"github.com/iconidentify/xgrabba/pkg/grok"
+)
+
+var (
+	// ErrUnknownPromptTask is returned when previewing a task that has no prompt.
+	ErrUnknownPromptTask = errors.New("unknown AI task")
+
+	// ErrNoTranscript is returned when previewing an essay prompt for a tweet
+	// without a transcript.
+	ErrNoTranscript = errors.New("tweet has no transcript")
+
+	// ErrPromptRerunInProgress is returned when a prompt rerun is already running.
+	ErrPromptRerunInProgress = errors.New("prompt rerun already in progress")
+)
+
+// SetPrompts sets the prompt templates whose version is recorded on AI
+// results. They must match the templates given to the AI client.
+func (s *TweetService) SetPrompts(prompts *grok.Prompts) {
+	s.prompts = prompts
+}
+
+func (s *TweetService) promptSet() *grok.Prompts {
+	if s.prompts == nil {
+		return grok.DefaultPrompts()
+	}
+	return s.prompts
+}
+
+// Prompts returns the prompt templates in use.
+func (s *TweetService) Prompts() *grok.Prompts {
+	return s.promptSet()
+}
+
+// PreviewPrompt renders the prompt that would be sent for task (one of the
+// config.AITask* names) on a tweet, without calling the AI provider. The
+// essay prompt uses the first transcript in the tweet.
+func (s *TweetService) PreviewPrompt(ctx context.Context, tweetID domain.TweetID, task string) (*grok.RenderedPrompt, error) {
+	s.tweetsMu.RLock()
+	tweet, ok := s.tweets[tweetID]
+	s.tweetsMu.RUnlock()
+	if !ok {
+		return nil, domain.ErrVideoNotFound
+	}
+
+	prompts := s.promptSet()
+	var (
+		rendered grok.RenderedPrompt
+		err      error
+	)
+	switch task {
+	case config.AITaskFilename:
+		rendered, err = prompts.RenderFilename(s.filenameRequest(tweet))
+	case config.AITaskAnalysis:
+		rendered, err = prompts.RenderAnalysis(textAnalysisRequest(tweet))
+	case config.AITaskVision:
+		req, _, _, ok := visionRequest(tweet)
+		if !ok {
+			return nil, fmt.Errorf("%w: tweet has no local media for vision analysis", domain.ErrMediaNotFound)
+		}
+		rendered, err = prompts.RenderVision(req)
+	case config.AITaskEssay:
+		var transcript string
+		for _, m := range tweet.Media {
+			if m.Transcript != "" {
+				transcript = m.Transcript
+				break
+			}
+		}
+		if transcript == "" {
+			return nil, ErrNoTranscript
+		}
+		rendered, err = prompts.RenderEssay(grok.EssayRequest{
+			Transcript:  transcript,
+			ContentType: tweet.AIContentType,
+		})
+	default:
+		return nil, fmt.Errorf("%w: %q", ErrUnknownPromptTask, task)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &rendered, nil
+}
+
+// OutdatedPromptTweets returns the tweets whose AI analysis was produced by a
+// different prompt template version than the current one, oldest first.
+// Analysis from before prompt versions were recorded counts as outdated.
+// Tweets that opted out of AI or have no analysis are not included.
+func (s *TweetService) OutdatedPromptTweets() []domain.TweetID {
+	version := s.promptSet().Version()
+
+	s.tweetsMu.RLock()
+	defer s.tweetsMu.RUnlock()
+	var outdated []*domain.Tweet
+	for _, tweet := range s.tweets {
+		if tweet.Options.SkipsAI() || (tweet.AISummary == "" && len(tweet.AITags) == 0) {
+			continue
+		}
+		if tweet.AIPromptVersion != version {
+			outdated = append(outdated, tweet)
+		}
+	}
+	sort.Slice(outdated, func(i, j int) bool { return outdated[i].CreatedAt.Before(outdated[j].CreatedAt) })
+
+	ids := make([]domain.TweetID, len(outdated))
+	for i, tweet := range outdated {
+		ids[i] = tweet.ID
+	}
+	return ids
+}
+
+// StartRerunOutdatedPrompts re-runs AI analysis in the background for every
+// tweet from OutdatedPromptTweets and returns how many were queued.
+// Transcripts and OCR text are kept; only the AI analysis and title are
+// replaced. The rerun stops when an AI budget is exceeded.
+func (s *TweetService) StartRerunOutdatedPrompts() (int, error) {
+	if err := s.aiBudgetExceeded(); err != nil {
+		return 0, err
+	}
+	s.aiAnalysisLock.Lock()
+	if s.promptRerunning {
+		s.aiAnalysisLock.Unlock()
+		return 0, ErrPromptRerunInProgress
+	}
+	ids := s.OutdatedPromptTweets()
+	if len(ids) == 0 {
+		s.aiAnalysisLock.Unlock()
+		return 0, nil
+	}
+	s.promptRerunning = true
+	s.aiAnalysisLock.Unlock()
+
+	go func() {
+		defer func() {
+			s.aiAnalysisLock.Lock()
+			s.promptRerunning = false
+			s.aiAnalysisLock.Unlock()
+		}()
+
+		version := s.promptSet().Version()
+		s.logger.Info("re-running AI analysis with current prompts", "count", len(ids), "prompt_version", version)
+		for i, id := range ids {
+			if err := s.aiBudgetExceeded(); err != nil {
+				s.logger.Warn("prompt rerun paused", "processed", i, "error", err)
+				return
+			}
+			if err := s.rerunPrompts(id); err != nil {
+				s.logger.Warn("prompt rerun failed", "tweet_id", id, "error", err)
+			}
+
+			// Small delay to avoid rate limiting
+			time.Sleep(time.Second)
+		}
+		s.logger.Info("prompt rerun complete", "processed", len(ids), "prompt_version", version)
+	}()
+
+	return len(ids), nil
+}
+
+// rerunPrompts re-analyzes one tweet unless it is already being analyzed.
+func (s *TweetService) rerunPrompts(tweetID domain.TweetID) error {
+	s.aiAnalysisLock.Lock()
+	if s.processingAI[tweetID] {
+		s.aiAnalysisLock.Unlock()
+		return ErrAIAlreadyInProgress
+	}
+	s.processingAI[tweetID] = true
+	s.aiAnalysisLock.Unlock()
+	defer func() {
+		s.aiAnalysisLock.Lock()
+		delete(s.processingAI, tweetID)
+		s.aiAnalysisLock.Unlock()
+	}()
+
+	s.tweetsMu.RLock()
+	tweet, ok := s.tweets[tweetID]
+	s.tweetsMu.RUnlock()
+	if !ok {
+		return domain.ErrVideoNotFound
+	}
+
+	ctx := withUsageTweet(context.Background(), tweetID)
+	if s.aiCfg.RegenerateTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, s.aiCfg.RegenerateTimeout)
+		defer cancel()
+	}
+
+	// Media may only exist in remote storage
+	if err := s.EnsureLocalMedia(ctx, tweet); err != nil {
+		s.logger.Warn("failed to restore media from storage", "tweet_id", tweetID, "error", err)
+	}
+	return s.reanalyzeTweet(ctx, tweet)
+}
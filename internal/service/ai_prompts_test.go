@@ -0,0 +1,135 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/iconidentify/xgrabba/internal/domain"
+	"github.com/iconidentify/xgrabba/pkg/grok"
+)
+
+// analysisStub answers every AI call with a fixed analysis.
+type analysisStub struct{}
+
+func (analysisStub) GenerateFilename(ctx context.Context, req grok.FilenameRequest) (string, error) {
+	return "title", nil
+}
+
+func (analysisStub) AnalyzeContent(ctx context.Context, req grok.ContentAnalysisRequest) (*grok.ContentAnalysisResponse, error) {
+	return &grok.ContentAnalysisResponse{Summary: "cats", Tags: []string{"cats"}}, nil
+}
+
+func (analysisStub) AnalyzeContentWithVision(ctx context.Context, req grok.VisionAnalysisRequest) (*grok.ContentAnalysisResponse, error) {
+	return &grok.ContentAnalysisResponse{Summary: "cats", Tags: []string{"cats"}}, nil
+}
+
+func (analysisStub) GenerateEssay(ctx context.Context, req grok.EssayRequest) (*grok.EssayResponse, error) {
+	return &grok.EssayResponse{Title: "Cats", Essay: "About cats"}, nil
+}
+
+func (analysisStub) Translate(ctx context.Context, req grok.TranslationRequest) (*grok.TranslationResponse, error) {
+	return &grok.TranslationResponse{SourceLanguage: "ja", Texts: req.Texts}, nil
+}
+
+func (analysisStub) GenerateDocument(ctx context.Context, req grok.DocumentRequest) (*grok.DocumentResponse, error) {
+	return &grok.DocumentResponse{Title: "Cats", Content: "- cats", WordCount: 2}, nil
+}
+
+func (analysisStub) GenerateChapters(ctx context.Context, req grok.ChaptersRequest) (*grok.ChaptersResponse, error) {
+	return &grok.ChaptersResponse{Chapters: []grok.ChapterItem{
+		{Start: 0, Title: "Intro"},
+		{Start: 60, Title: "Cats"},
+	}}, nil
+}
+
+func (analysisStub) Ask(ctx context.Context, req grok.AskRequest, onDelta func(string)) (*grok.AskResponse, error) {
+	return &grok.AskResponse{Answer: "Cats purr [tweet:1]"}, nil
+}
+
+func customPrompts(t *testing.T) *grok.Prompts {
+	t.Helper()
+	dir := t.TempDir()
+	os.WriteFile(filepath.Join(dir, "analysis.tmpl"), []byte("Lab analysis of {{.TweetText}}\n"), 0644)
+	prompts, err := grok.LoadPrompts(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return prompts
+}
+
+func TestRunTextAnalysis_RecordsPromptVersion(t *testing.T) {
+	svc := &TweetService{grokClient: analysisStub{}, logger: testLogger()}
+	prompts := customPrompts(t)
+	svc.SetPrompts(prompts)
+
+	tweet := &domain.Tweet{ID: "1", Text: "a cat"}
+	svc.runTextAnalysis(context.Background(), tweet)
+	if tweet.AIPromptVersion != prompts.Version() {
+		t.Errorf("AIPromptVersion = %q, want %q", tweet.AIPromptVersion, prompts.Version())
+	}
+	stored := tweet.ToStoredTweet()
+	if loaded := svc.storedTweetToTweet(&stored, ""); loaded.AIPromptVersion != prompts.Version() {
+		t.Error("prompt version not stored")
+	}
+}
+
+func TestPreviewPrompt(t *testing.T) {
+	svc, tweet := newIntegrityTestService(t)
+	svc.SetPrompts(customPrompts(t))
+	tweet.Text = "a cat"
+	ctx := context.Background()
+
+	got, err := svc.PreviewPrompt(ctx, tweet.ID, "analysis")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.User != "Lab analysis of a cat" || got.Task != "analysis" {
+		t.Errorf("PreviewPrompt(analysis) = %+v", got)
+	}
+
+	got, err = svc.PreviewPrompt(ctx, tweet.ID, "vision")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(got.User, `Tweet text: "a cat"`) || !strings.Contains(got.User, "Media type: Video") {
+		t.Errorf("PreviewPrompt(vision) user = %q", got.User)
+	}
+
+	if _, err := svc.PreviewPrompt(ctx, tweet.ID, "essay"); !errors.Is(err, ErrNoTranscript) {
+		t.Errorf("essay without transcript: err = %v, want ErrNoTranscript", err)
+	}
+	if _, err := svc.PreviewPrompt(ctx, tweet.ID, "summary"); !errors.Is(err, ErrUnknownPromptTask) {
+		t.Errorf("unknown task: err = %v, want ErrUnknownPromptTask", err)
+	}
+	if _, err := svc.PreviewPrompt(ctx, "missing", "analysis"); !errors.Is(err, domain.ErrVideoNotFound) {
+		t.Errorf("missing tweet: err = %v, want ErrVideoNotFound", err)
+	}
+}
+
+func TestOutdatedPromptTweets(t *testing.T) {
+	current := grok.DefaultPrompts().Version()
+	svc := &TweetService{
+		logger: testLogger(),
+		tweets: map[domain.TweetID]*domain.Tweet{
+			"current":     {ID: "current", AISummary: "s", AIPromptVersion: current},
+			"old":         {ID: "old", AISummary: "s", AIPromptVersion: "0123456789ab"},
+			"unversioned": {ID: "unversioned", AITags: []string{"cats"}},
+			"unanalyzed":  {ID: "unanalyzed"},
+			"skipped":     {ID: "skipped", AISummary: "s", Options: domain.ArchiveOptions{SkipAI: true}},
+		},
+	}
+
+	got := svc.OutdatedPromptTweets()
+	if len(got) != 2 {
+		t.Fatalf("OutdatedPromptTweets() = %v, want old and unversioned", got)
+	}
+	for _, id := range got {
+		if id != "old" && id != "unversioned" {
+			t.Errorf("unexpected outdated tweet %s", id)
+		}
+	}
+}
@@ -0,0 +1,359 @@
+package service
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/iconidentify/xgrabba/internal/config"
+	"github.com/iconidentify/xgrabba/internal/domain"
+	"github.com/iconidentify/xgrabba/pkg/grok"
+	"github.com/iconidentify/xgrabba/pkg/whisper"
+)
+
+// AIUsageFilename is the usage ledger under the storage base path, one JSON
+// record per line.
+const AIUsageFilename = ".ai_usage.jsonl"
+
+// usageDayFormat keys usage by UTC day.
+const usageDayFormat = "2006-01-02"
+
+// AIUsageService records the usage and estimated cost of every AI and
+// transcription call, enforces spend budgets and reports usage.
+type AIUsageService struct {
+	path         string
+	cfg          config.AIConfig
+	logger       *slog.Logger
+	eventEmitter domain.EventEmitter
+	now          func() time.Time
+
+	mu      sync.Mutex
+	records []domain.AIUsageRecord // Only the current UTC month; older usage is read from the ledger
+}
+
+// NewAIUsageService creates a usage service backed by the ledger in basePath
+// and loads the usage recorded so far.
+func NewAIUsageService(basePath string, cfg config.AIConfig, logger *slog.Logger, eventEmitter domain.EventEmitter) *AIUsageService {
+	s := &AIUsageService{
+		path:         filepath.Join(basePath, AIUsageFilename),
+		cfg:          cfg,
+		logger:       logger,
+		eventEmitter: eventEmitter,
+		now:          time.Now,
+	}
+	records, err := readAIUsage(s.path)
+	if err != nil && !os.IsNotExist(err) {
+		logger.Warn("failed to load AI usage ledger", "path", s.path, "error", err)
+	}
+	s.records = records
+	return s
+}
+
+// usageTweetKey carries the tweet an AI call is made for.
+type usageTweetKey struct{}
+
+// withUsageTweet attributes AI calls made with ctx to a tweet.
+func withUsageTweet(ctx context.Context, tweetID domain.TweetID) context.Context {
+	return context.WithValue(ctx, usageTweetKey{}, tweetID)
+}
+
+func usageTweet(ctx context.Context) domain.TweetID {
+	id, _ := ctx.Value(usageTweetKey{}).(domain.TweetID)
+	return id
+}
+
+// RecordAIUsage implements grok.UsageRecorder.
+func (s *AIUsageService) RecordAIUsage(ctx context.Context, usage grok.Usage) {
+	s.record(domain.AIUsageRecord{
+		Provider:         usage.Provider,
+		Model:            usage.Model,
+		Task:             usage.Task,
+		TweetID:          usageTweet(ctx),
+		PromptTokens:     usage.PromptTokens,
+		CompletionTokens: usage.CompletionTokens,
+		CostUSD:          grok.EstimateCost(usage.Model, usage.PromptTokens, usage.CompletionTokens),
+	})
+}
+
+// RecordTranscription records a Whisper transcription of the given length.
+func (s *AIUsageService) RecordTranscription(ctx context.Context, model string, seconds float64) {
+	s.record(domain.AIUsageRecord{
+		Provider:     "whisper",
+		Model:        model,
+		Task:         domain.AIUsageTaskTranscription,
+		TweetID:      usageTweet(ctx),
+		AudioSeconds: seconds,
+		CostUSD:      whisper.EstimateCost(seconds, model),
+	})
+}
+
+func (s *AIUsageService) record(rec domain.AIUsageRecord) {
+	rec.Time = s.now().UTC()
+
+	s.mu.Lock()
+	s.pruneLocked(rec.Time)
+	wasExceeded := s.budgetErrorLocked(rec.Time) != nil
+	s.records = append(s.records, rec)
+	exceeded := s.budgetErrorLocked(rec.Time)
+	if err := s.appendLocked(rec); err != nil {
+		s.logger.Warn("failed to write AI usage", "error", err)
+	}
+	s.mu.Unlock()
+
+	if exceeded != nil && !wasExceeded {
+		s.logger.Warn("AI budget exceeded, pausing AI phases", "error", exceeded)
+		if s.eventEmitter != nil {
+			s.eventEmitter.Emit(domain.Event{
+				Timestamp: rec.Time,
+				Severity:  domain.EventSeverityWarning,
+				Category:  domain.EventCategoryAI,
+				Message:   fmt.Sprintf("AI phases paused: %s", exceeded.Error()),
+				Source:    "AIUsageService",
+			})
+		}
+	}
+}
+
+func (s *AIUsageService) appendLocked(rec domain.AIUsageRecord) error {
+	line, err := json.Marshal(rec)
+	if err != nil {
+		return fmt.Errorf("marshal usage: %w", err)
+	}
+	f, err := os.OpenFile(s.path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	_, err = f.Write(append(line, '\n'))
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return fmt.Errorf("write %s: %w", AIUsageFilename, err)
+	}
+	return nil
+}
+
+// BudgetExceeded returns an error wrapping domain.ErrAIBudgetExceeded while
+// the daily or monthly budget is spent, and nil otherwise.
+func (s *AIUsageService) BudgetExceeded() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := s.now().UTC()
+	s.pruneLocked(now)
+	return s.budgetErrorLocked(now)
+}
+
+func (s *AIUsageService) budgetErrorLocked(now time.Time) error {
+	daily, monthly := s.spentLocked(now)
+	if s.cfg.DailyBudgetUSD > 0 && daily >= s.cfg.DailyBudgetUSD {
+		return fmt.Errorf("%w: $%.2f of $%.2f daily budget spent", domain.ErrAIBudgetExceeded, daily, s.cfg.DailyBudgetUSD)
+	}
+	if s.cfg.MonthlyBudgetUSD > 0 && monthly >= s.cfg.MonthlyBudgetUSD {
+		return fmt.Errorf("%w: $%.2f of $%.2f monthly budget spent", domain.ErrAIBudgetExceeded, monthly, s.cfg.MonthlyBudgetUSD)
+	}
+	return nil
+}
+
+// budgetWindowStart returns the start of the UTC month containing now, the
+// oldest usage any budget counts.
+func budgetWindowStart(now time.Time) time.Time {
+	now = now.UTC()
+	return time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+}
+
+// pruneLocked drops in-memory records from before the current budget window.
+func (s *AIUsageService) pruneLocked(now time.Time) {
+	start := budgetWindowStart(now)
+	kept := s.records[:0]
+	for _, rec := range s.records {
+		if !rec.Time.Before(start) {
+			kept = append(kept, rec)
+		}
+	}
+	clear(s.records[len(kept):])
+	s.records = kept
+}
+
+// spentLocked returns the spend for the UTC day and month containing now.
+func (s *AIUsageService) spentLocked(now time.Time) (daily, monthly float64) {
+	day := now.Format(usageDayFormat)
+	month := now.Format("2006-01")
+	for _, rec := range s.records {
+		t := rec.Time.UTC()
+		if t.Format("2006-01") != month {
+			continue
+		}
+		monthly += rec.CostUSD
+		if t.Format(usageDayFormat) == day {
+			daily += rec.CostUSD
+		}
+	}
+	return daily, monthly
+}
+
+// AIUsageTotals sums the usage of a set of calls.
+type AIUsageTotals struct {
+	Calls            int     `json:"calls"`
+	PromptTokens     int     `json:"prompt_tokens"`
+	CompletionTokens int     `json:"completion_tokens"`
+	AudioSeconds     float64 `json:"audio_seconds"`
+	CostUSD          float64 `json:"cost_usd"`
+}
+
+func (t *AIUsageTotals) add(rec domain.AIUsageRecord) {
+	t.Calls++
+	t.PromptTokens += rec.PromptTokens
+	t.CompletionTokens += rec.CompletionTokens
+	t.AudioSeconds += rec.AudioSeconds
+	t.CostUSD += rec.CostUSD
+}
+
+// AIUsageDay is the usage of one UTC day.
+type AIUsageDay struct {
+	Date string `json:"date"`
+	AIUsageTotals
+	ByTask map[string]AIUsageTotals `json:"by_task"`
+}
+
+// AIUsageBudget reports current spend against the configured budgets.
+type AIUsageBudget struct {
+	DailyLimitUSD   float64 `json:"daily_limit_usd"` // Zero is unlimited
+	DailySpentUSD   float64 `json:"daily_spent_usd"`
+	MonthlyLimitUSD float64 `json:"monthly_limit_usd"` // Zero is unlimited
+	MonthlySpentUSD float64 `json:"monthly_spent_usd"`
+	Paused          bool    `json:"paused"`
+	Reason          string  `json:"reason,omitempty"`
+}
+
+// AIUsageReport is the usage between two UTC days, inclusive.
+type AIUsageReport struct {
+	From       string                   `json:"from"`
+	To         string                   `json:"to"`
+	Total      AIUsageTotals            `json:"total"`
+	ByTask     map[string]AIUsageTotals `json:"by_task"`
+	ByProvider map[string]AIUsageTotals `json:"by_provider"` // Keyed by provider/model
+	ByDay      []AIUsageDay             `json:"by_day"`      // Oldest first; days without usage are omitted
+	Budget     AIUsageBudget            `json:"budget"`
+}
+
+// Report summarizes usage from the start of the from day to the end of the
+// to day, both in UTC.
+func (s *AIUsageService) Report(from, to time.Time) AIUsageReport {
+	fromDay := from.UTC().Format(usageDayFormat)
+	toDay := to.UTC().Format(usageDayFormat)
+	report := AIUsageReport{
+		From:       fromDay,
+		To:         toDay,
+		ByTask:     make(map[string]AIUsageTotals),
+		ByProvider: make(map[string]AIUsageTotals),
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now().UTC()
+	s.pruneLocked(now)
+	records := s.records
+	if fromDay < budgetWindowStart(now).Format(usageDayFormat) {
+		// Usage before this month is only in the ledger
+		ledger, err := readAIUsage(s.path)
+		if err != nil && !os.IsNotExist(err) {
+			s.logger.Warn("failed to read AI usage ledger", "path", s.path, "error", err)
+		} else {
+			records = ledger
+		}
+	}
+
+	days := make(map[string]*AIUsageDay)
+	for _, rec := range records {
+		day := rec.Time.UTC().Format(usageDayFormat)
+		if day < fromDay || day > toDay {
+			continue
+		}
+		report.Total.add(rec)
+		addUsage(report.ByTask, rec.Task, rec)
+		addUsage(report.ByProvider, rec.Provider+"/"+rec.Model, rec)
+
+		d, ok := days[day]
+		if !ok {
+			d = &AIUsageDay{Date: day, ByTask: make(map[string]AIUsageTotals)}
+			days[day] = d
+		}
+		d.add(rec)
+		addUsage(d.ByTask, rec.Task, rec)
+	}
+	for _, d := range days {
+		report.ByDay = append(report.ByDay, *d)
+	}
+	sort.Slice(report.ByDay, func(i, j int) bool { return report.ByDay[i].Date < report.ByDay[j].Date })
+
+	daily, monthly := s.spentLocked(now)
+	report.Budget = AIUsageBudget{
+		DailyLimitUSD:   s.cfg.DailyBudgetUSD,
+		DailySpentUSD:   daily,
+		MonthlyLimitUSD: s.cfg.MonthlyBudgetUSD,
+		MonthlySpentUSD: monthly,
+	}
+	if err := s.budgetErrorLocked(now); err != nil {
+		report.Budget.Paused = true
+		report.Budget.Reason = err.Error()
+	}
+	return report
+}
+
+func addUsage(m map[string]AIUsageTotals, key string, rec domain.AIUsageRecord) {
+	t := m[key]
+	t.add(rec)
+	m[key] = t
+}
+
+// readAIUsage parses the usage ledger. Malformed lines (e.g. a write cut short
+// by a crash) are skipped.
+func readAIUsage(path string) ([]domain.AIUsageRecord, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var records []domain.AIUsageRecord
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+		var rec domain.AIUsageRecord
+		if json.Unmarshal(line, &rec) != nil {
+			continue
+		}
+		records = append(records, rec)
+	}
+	return records, scanner.Err()
+}
+
+// SetAIUsage enables usage accounting for transcriptions and pauses AI phases
+// while a budget is exceeded. Chat usage is recorded by the AI client itself.
+func (s *TweetService) SetAIUsage(usage *AIUsageService) {
+	s.usage = usage
+}
+
+// aiBudgetExceeded returns the budget error while AI phases are paused.
+func (s *TweetService) aiBudgetExceeded() error {
+	if s.usage == nil {
+		return nil
+	}
+	return s.usage.BudgetExceeded()
+}
+
+// SetAICache reports the AI response cache in pipeline diagnostics. The cache
+// itself is consulted by the AI client.
+func (s *TweetService) SetAICache(cache *grok.ResponseCache) {
+	s.aiCache = cache
+}
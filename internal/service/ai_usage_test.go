@@ -0,0 +1,176 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/iconidentify/xgrabba/internal/config"
+	"github.com/iconidentify/xgrabba/internal/domain"
+	"github.com/iconidentify/xgrabba/pkg/grok"
+)
+
+func newTestAIUsageService(t *testing.T, dir string, cfg config.AIConfig, now *time.Time) *AIUsageService {
+	t.Helper()
+	svc := NewAIUsageService(dir, cfg, testLogger(), nil)
+	svc.now = func() time.Time { return *now }
+	return svc
+}
+
+func TestAIUsageService_ReportAndReload(t *testing.T) {
+	dir := t.TempDir()
+	now := time.Date(2024, 3, 10, 12, 0, 0, 0, time.UTC)
+	svc := newTestAIUsageService(t, dir, config.AIConfig{}, &now)
+
+	ctx := withUsageTweet(context.Background(), "42")
+	svc.RecordAIUsage(ctx, grok.Usage{Provider: "grok", Model: "grok-3", Task: config.AITaskVision, PromptTokens: 1000, CompletionTokens: 200})
+	svc.RecordTranscription(ctx, "whisper-1", 120)
+	now = now.Add(24 * time.Hour)
+	svc.RecordAIUsage(context.Background(), grok.Usage{Provider: "openai", Model: "llama3.2", Task: config.AITaskFilename, PromptTokens: 50, CompletionTokens: 10})
+
+	// A new service sees the same ledger
+	reloaded := newTestAIUsageService(t, dir, config.AIConfig{}, &now)
+	report := reloaded.Report(time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC), now)
+
+	if report.Total.Calls != 3 {
+		t.Fatalf("total calls = %d, want 3", report.Total.Calls)
+	}
+	wantCost := 0.003 + 0.003 + 0.012 // grok-3 input + output, 2 minutes of whisper-1
+	if diff := report.Total.CostUSD - wantCost; diff > 1e-9 || diff < -1e-9 {
+		t.Errorf("total cost = %f, want %f", report.Total.CostUSD, wantCost)
+	}
+	if got := report.ByTask[domain.AIUsageTaskTranscription].AudioSeconds; got != 120 {
+		t.Errorf("transcription seconds = %f, want 120", got)
+	}
+	if got := report.ByProvider["openai/llama3.2"]; got.Calls != 1 || got.CostUSD != 0 {
+		t.Errorf("local provider usage = %+v, want one free call", got)
+	}
+	if len(report.ByDay) != 2 || report.ByDay[0].Date != "2024-03-10" || report.ByDay[0].ByTask[config.AITaskVision].PromptTokens != 1000 {
+		t.Errorf("by day = %+v", report.ByDay)
+	}
+	if reloaded.records[0].TweetID != "42" || reloaded.records[2].TweetID != "" {
+		t.Errorf("tweet IDs = %q, %q; want 42 and none", reloaded.records[0].TweetID, reloaded.records[2].TweetID)
+	}
+
+	// Days outside the range are left out
+	if got := reloaded.Report(now, now).Total.Calls; got != 1 {
+		t.Errorf("calls on the last day = %d, want 1", got)
+	}
+}
+
+func TestAIUsageService_Budgets(t *testing.T) {
+	now := time.Date(2024, 3, 10, 12, 0, 0, 0, time.UTC)
+	svc := newTestAIUsageService(t, t.TempDir(), config.AIConfig{DailyBudgetUSD: 0.01, MonthlyBudgetUSD: 0.02}, &now)
+
+	if err := svc.BudgetExceeded(); err != nil {
+		t.Fatalf("BudgetExceeded() before any usage = %v", err)
+	}
+	// 1000 output tokens of grok-3 cost $0.015
+	svc.RecordAIUsage(context.Background(), grok.Usage{Model: "grok-3", CompletionTokens: 1000})
+	if err := svc.BudgetExceeded(); !errors.Is(err, domain.ErrAIBudgetExceeded) {
+		t.Errorf("daily budget: err = %v, want ErrAIBudgetExceeded", err)
+	}
+
+	// The daily budget resets, the monthly one does not
+	now = now.Add(24 * time.Hour)
+	if err := svc.BudgetExceeded(); err != nil {
+		t.Errorf("next day: err = %v, want nil", err)
+	}
+	svc.RecordAIUsage(context.Background(), grok.Usage{Model: "grok-3", CompletionTokens: 500})
+	if err := svc.BudgetExceeded(); !errors.Is(err, domain.ErrAIBudgetExceeded) {
+		t.Errorf("monthly budget: err = %v, want ErrAIBudgetExceeded", err)
+	}
+	if report := svc.Report(now, now); !report.Budget.Paused || report.Budget.Reason == "" {
+		t.Errorf("budget = %+v, want paused with a reason", report.Budget)
+	}
+
+	now = time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC)
+	if err := svc.BudgetExceeded(); err != nil {
+		t.Errorf("next month: err = %v, want nil", err)
+	}
+}
+
+func TestGenerateAIMetadata_BudgetExceeded(t *testing.T) {
+	now := time.Date(2024, 3, 10, 12, 0, 0, 0, time.UTC)
+	usage := newTestAIUsageService(t, t.TempDir(), config.AIConfig{DailyBudgetUSD: 0.001}, &now)
+	usage.RecordAIUsage(context.Background(), grok.Usage{Model: "grok-3", CompletionTokens: 1000})
+
+	svc := &TweetService{logger: testLogger()}
+	svc.SetAIUsage(usage)
+	tweet := &domain.Tweet{
+		Author:   domain.Author{Username: "someone"},
+		PostedAt: time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC),
+		Text:     "a tweet about cats",
+	}
+
+	// No AI client is configured, so a call would panic
+	title, _ := svc.generateAIMetadata(context.Background(), tweet)
+	if title != grok.FallbackFilename("someone", tweet.PostedAt, tweet.Text) {
+		t.Errorf("generateAIMetadata() title = %q, want the fallback title", title)
+	}
+}
+
+func TestAIUsageService_PrunesPastMonths(t *testing.T) {
+	dir := t.TempDir()
+	now := time.Date(2024, 3, 31, 12, 0, 0, 0, time.UTC)
+	svc := newTestAIUsageService(t, dir, config.AIConfig{}, &now)
+	svc.RecordAIUsage(context.Background(), grok.Usage{Model: "grok-3", CompletionTokens: 1000})
+
+	now = time.Date(2024, 4, 1, 12, 0, 0, 0, time.UTC)
+	svc.RecordAIUsage(context.Background(), grok.Usage{Model: "grok-3", CompletionTokens: 500})
+	if len(svc.records) != 1 || svc.records[0].Time.Month() != time.April {
+		t.Errorf("records = %+v, want only April's", svc.records)
+	}
+	reloaded := newTestAIUsageService(t, dir, config.AIConfig{}, &now)
+	if reloaded.BudgetExceeded(); len(reloaded.records) != 1 {
+		t.Errorf("reloaded %d records, want 1", len(reloaded.records))
+	}
+
+	// Reports of past months read the ledger
+	report := svc.Report(time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC), now)
+	if report.Total.Calls != 2 || report.Budget.MonthlySpentUSD != 0.0075 {
+		t.Errorf("report total = %+v, budget = %+v", report.Total, report.Budget)
+	}
+}
+
+func TestPhase3_PausesAIOverBudget(t *testing.T) {
+	now := time.Date(2024, 3, 10, 12, 0, 0, 0, time.UTC)
+	usage := newTestAIUsageService(t, t.TempDir(), config.AIConfig{DailyBudgetUSD: 0.001}, &now)
+	usage.RecordAIUsage(context.Background(), grok.Usage{Model: "grok-3", CompletionTokens: 1000})
+
+	svc, tweet := newIntegrityTestService(t)
+	svc.processingAI = make(map[domain.TweetID]bool)
+	svc.SetAIUsage(usage)
+	// Media-only archives make no AI calls, so none is configured
+	tweet.Options = domain.ArchiveOptions{MediaOnly: true}
+	tweet.Status = domain.ArchiveStatusDownloaded
+
+	svc.processPhase3Analyze(context.Background(), tweet)
+	if tweet.Status != domain.ArchiveStatusCompleted || !tweet.AIPending {
+		t.Fatalf("status = %s, AIPending = %v; want completed and pending", tweet.Status, tweet.AIPending)
+	}
+	if n := svc.ResumePausedAnalysis(context.Background()); n != 0 || !tweet.AIPending {
+		t.Errorf("resumed %d over budget, AIPending = %v", n, tweet.AIPending)
+	}
+
+	// The daily budget resets
+	now = now.Add(24 * time.Hour)
+	if n := svc.ResumePausedAnalysis(context.Background()); n != 1 || tweet.AIPending {
+		t.Errorf("resumed %d after reset, AIPending = %v", n, tweet.AIPending)
+	}
+	data, err := os.ReadFile(filepath.Join(tweet.ArchivePath, "tweet.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var stored domain.StoredTweet
+	if err := json.Unmarshal(data, &stored); err != nil {
+		t.Fatal(err)
+	}
+	if stored.AIPending {
+		t.Error("saved tweet is still AI pending")
+	}
+}
@@ -0,0 +1,61 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/iconidentify/xgrabba/internal/domain"
+)
+
+// SetPlaylistService enables adding tweets to the playlists named in archive
+// requests.
+func (s *TweetService) SetPlaylistService(playlists *PlaylistService) {
+	s.playlists = playlists
+}
+
+// validatePlaylists checks up front that every requested playlist exists and
+// accepts manual items, so a bad ID fails the request instead of the archive.
+func (s *TweetService) validatePlaylists(ctx context.Context, ids []domain.PlaylistID) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	if s.playlists == nil {
+		return fmt.Errorf("playlists are not available: %w", domain.ErrPlaylistNotFound)
+	}
+	for _, id := range ids {
+		playlist, err := s.playlists.repo.Get(ctx, id)
+		if err != nil {
+			return fmt.Errorf("playlist %s: %w", id, err)
+		}
+		if playlist.IsSmart() {
+			return fmt.Errorf("playlist %s: %w", id, domain.ErrSmartPlaylistNoManualItems)
+		}
+	}
+	return nil
+}
+
+// addToRequestedPlaylists adds a completed archive to the playlists from its
+// request. Failures are logged; the archive itself has succeeded.
+func (s *TweetService) addToRequestedPlaylists(ctx context.Context, tweet *domain.Tweet) {
+	if s.playlists == nil || len(tweet.Options.PlaylistIDs) == 0 {
+		return
+	}
+	for _, id := range tweet.Options.PlaylistIDs {
+		if err := s.playlists.AddItem(ctx, id, string(tweet.ID)); err != nil {
+			s.logger.Warn("failed to add archived tweet to playlist", "tweet_id", tweet.ID, "playlist_id", id, "error", err)
+		}
+	}
+}
+
+// tagsMarkdown renders the user's tags for README.md, or "" when there are none.
+func tagsMarkdown(tweet *domain.Tweet) string {
+	if len(tweet.Tags) == 0 {
+		return ""
+	}
+	tags := make([]string, len(tweet.Tags))
+	for i, tag := range tweet.Tags {
+		tags[i] = "#" + tag
+	}
+	return fmt.Sprintf("**Tags:** %s\n\n", strings.Join(tags, " "))
+}
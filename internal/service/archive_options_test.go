@@ -0,0 +1,140 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/iconidentify/xgrabba/internal/domain"
+)
+
+func TestPrioritySemaphore_HighPriorityFirst(t *testing.T) {
+	sem := newPrioritySemaphore(1)
+	sem.acquire(domain.PriorityNormal)
+
+	order := make(chan domain.ArchivePriority, 3)
+	start := func(p domain.ArchivePriority) {
+		go func() {
+			sem.acquire(p)
+			order <- p
+			sem.release()
+		}()
+		// Let the goroutine queue before the next one
+		for {
+			sem.mu.Lock()
+			queued := len(sem.waiters[p.Rank()]) > 0
+			sem.mu.Unlock()
+			if queued {
+				return
+			}
+			time.Sleep(time.Millisecond)
+		}
+	}
+	start(domain.PriorityLow)
+	start(domain.PriorityNormal)
+	start(domain.PriorityHigh)
+
+	sem.release()
+	want := []domain.ArchivePriority{domain.PriorityHigh, domain.PriorityNormal, domain.PriorityLow}
+	for i, w := range want {
+		select {
+		case got := <-order:
+			if got != w {
+				t.Errorf("slot %d went to %q, want %q", i, got, w)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("slot %d never granted", i)
+		}
+	}
+}
+
+func TestProcessPhase2Download_TextOnly(t *testing.T) {
+	svc, tweet := newIntegrityTestService(t)
+	tweet.Options = domain.ArchiveOptions{TextOnly: true}
+	tweet.Author.AvatarURL = ""
+	for i := range tweet.Media {
+		tweet.Media[i].LocalPath = ""
+		tweet.Media[i].Downloaded = false
+		tweet.Media[i].URL = "http://invalid.invalid/media"
+	}
+
+	// No downloader is configured, so any download attempt would panic
+	if err := svc.processPhase2Download(context.Background(), tweet); err != nil {
+		t.Fatalf("processPhase2Download() = %v", err)
+	}
+	if tweet.Status != domain.ArchiveStatusDownloaded || tweet.MediaDownloaded != 0 {
+		t.Errorf("status = %s, downloaded = %d; want downloaded with no media", tweet.Status, tweet.MediaDownloaded)
+	}
+}
+
+func TestGenerateAIMetadata_SkipAI(t *testing.T) {
+	svc := &TweetService{logger: testLogger()}
+	tweet := &domain.Tweet{
+		Author:   domain.Author{Username: "someone"},
+		PostedAt: time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC),
+		Text:     "a tweet about cats",
+		Options:  domain.ArchiveOptions{SkipAI: true},
+	}
+
+	// No AI client is configured, so a call would panic
+	title, summary := svc.generateAIMetadata(context.Background(), tweet)
+	if title == "" || summary != "" {
+		t.Errorf("generateAIMetadata() = %q, %q; want fallback title only", title, summary)
+	}
+}
+
+func TestValidatePlaylists(t *testing.T) {
+	playlists := setupPlaylistService(t)
+	ctx := context.Background()
+	manual, err := playlists.Create(ctx, "Manual", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	svc := &TweetService{logger: testLogger()}
+
+	if err := svc.validatePlaylists(ctx, []domain.PlaylistID{manual.ID}); !errors.Is(err, domain.ErrPlaylistNotFound) {
+		t.Errorf("without playlist service: err = %v, want ErrPlaylistNotFound", err)
+	}
+
+	svc.SetPlaylistService(playlists)
+	if err := svc.validatePlaylists(ctx, []domain.PlaylistID{manual.ID}); err != nil {
+		t.Errorf("manual playlist: err = %v", err)
+	}
+	if err := svc.validatePlaylists(ctx, []domain.PlaylistID{"missing"}); !errors.Is(err, domain.ErrPlaylistNotFound) {
+		t.Errorf("missing playlist: err = %v, want ErrPlaylistNotFound", err)
+	}
+
+	// Playlist IDs are per-second timestamps, so use a separate store
+	smartPlaylists := setupPlaylistService(t)
+	smart, err := smartPlaylists.CreateSmart(ctx, "Smart", "", "cats", 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	svc.SetPlaylistService(smartPlaylists)
+	if err := svc.validatePlaylists(ctx, []domain.PlaylistID{smart.ID}); !errors.Is(err, domain.ErrSmartPlaylistNoManualItems) {
+		t.Errorf("smart playlist: err = %v, want ErrSmartPlaylistNoManualItems", err)
+	}
+	svc.SetPlaylistService(playlists)
+
+	tweet := &domain.Tweet{ID: "42", Options: domain.ArchiveOptions{PlaylistIDs: []domain.PlaylistID{manual.ID}}}
+	svc.addToRequestedPlaylists(ctx, tweet)
+	got, err := playlists.Get(ctx, manual.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got.Items) != 1 || got.Items[0] != "42" {
+		t.Errorf("playlist items = %v, want [42]", got.Items)
+	}
+}
+
+func TestTagsMarkdown(t *testing.T) {
+	if got := tagsMarkdown(&domain.Tweet{}); got != "" {
+		t.Errorf("tagsMarkdown() without tags = %q", got)
+	}
+	got := tagsMarkdown(&domain.Tweet{Tags: []string{"cats", "research"}})
+	if !strings.Contains(got, "#cats #research") {
+		t.Errorf("tagsMarkdown() = %q", got)
+	}
+}
@@ -0,0 +1,532 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/iconidentify/xgrabba/internal/domain"
+	"github.com/iconidentify/xgrabba/internal/storage"
+)
+
+// SetStorage sets the archive storage backend. Archives are always processed
+// and read under BasePath; a remote backend is only a mirror of each completed
+// archive and the eviction target for media, serving media that is no longer
+// on local disk.
+func (s *TweetService) SetStorage(st storage.Storage) {
+	s.store = st
+}
+
+// Storage returns the archive storage backend.
+func (s *TweetService) Storage() storage.Storage {
+	return s.store
+}
+
+// remoteStorage reports whether archives are mirrored to a non-local backend.
+func (s *TweetService) remoteStorage() bool {
+	if s.store == nil {
+		return false
+	}
+	_, local := s.store.(*storage.Local)
+	return !local
+}
+
+// storageKey maps a path under BasePath to its storage key.
+func (s *TweetService) storageKey(path string) (string, error) {
+	rel, err := filepath.Rel(s.cfg.BasePath, path)
+	if err != nil || rel == "." || strings.HasPrefix(rel, "..") {
+		return "", fmt.Errorf("path %s is outside storage root", path)
+	}
+	return filepath.ToSlash(rel), nil
+}
+
+// syncArchiveToStorage uploads new or changed files of an archive to the
+// remote backend, then evicts local media copies if configured.
+func (s *TweetService) syncArchiveToStorage(ctx context.Context, tweet *domain.Tweet) error {
+	if !s.remoteStorage() || tweet.ArchivePath == "" {
+		return nil
+	}
+
+	uploaded := 0
+	err := filepath.Walk(tweet.ArchivePath, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return nil
+		}
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if info.IsDir() {
+			if info.Name() == "temp_processing" {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if isPartialFile(info.Name()) {
+			return nil
+		}
+
+		key, err := s.storageKey(path)
+		if err != nil {
+			return err
+		}
+		// A remote copy of the same size written after the local file last
+		// changed is current; rewritten files of the same size are uploaded
+		if remote, err := s.statRemote(ctx, key); err == nil && remote.Size == info.Size() && !info.ModTime().After(remote.ModTime) {
+			return nil
+		}
+		if err := storage.PutFile(ctx, s.store, key, path, getContentType(info.Name())); err != nil {
+			return fmt.Errorf("upload %s: %w", key, err)
+		}
+		s.rememberRemote(storage.ObjectInfo{Key: key, Size: info.Size(), ModTime: time.Now()})
+		uploaded++
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	s.logger.Debug("archive mirrored to storage",
+		"tweet_id", tweet.ID,
+		"backend", s.store.Name(),
+		"uploaded", uploaded,
+	)
+
+	if s.cfg.EvictLocalMedia {
+		s.evictLocalMedia(tweet)
+	}
+	return nil
+}
+
+// evictLocalMedia removes local copies of downloaded media files. They remain
+// available from the remote backend and are restored on demand.
+func (s *TweetService) evictLocalMedia(tweet *domain.Tweet) {
+	for _, m := range tweet.Media {
+		if m.LocalPath == "" {
+			continue
+		}
+		if err := os.Remove(m.LocalPath); err != nil && !os.IsNotExist(err) {
+			s.logger.Warn("failed to evict local media", "tweet_id", tweet.ID, "media_id", m.ID, "error", err)
+		}
+		// Kept renditions are only served, never analysed, so they stay remote
+		for _, v := range m.Variants {
+			if v.LocalPath == "" || v.LocalPath == m.LocalPath {
+				continue
+			}
+			if err := os.Remove(v.LocalPath); err != nil && !os.IsNotExist(err) {
+				s.logger.Warn("failed to evict local media variant", "tweet_id", tweet.ID, "media_id", m.ID, "error", err)
+			}
+		}
+		if m.ChaptersVideoPath != "" {
+			if err := os.Remove(m.ChaptersVideoPath); err != nil && !os.IsNotExist(err) {
+				s.logger.Warn("failed to evict chapters video", "tweet_id", tweet.ID, "media_id", m.ID, "error", err)
+			}
+		}
+	}
+}
+
+// EnsureLocalMedia fetches a tweet's media files back from the remote backend
+// when they are missing locally (e.g. after eviction).
+func (s *TweetService) EnsureLocalMedia(ctx context.Context, tweet *domain.Tweet) error {
+	_, err := s.restoreLocalMedia(ctx, tweet)
+	return err
+}
+
+// restoreLocalMedia is EnsureLocalMedia, also returning how many files were
+// restored.
+func (s *TweetService) restoreLocalMedia(ctx context.Context, tweet *domain.Tweet) (int, error) {
+	if !s.remoteStorage() || tweet == nil {
+		return 0, nil
+	}
+	restored := 0
+	var errs []error
+	for _, m := range tweet.Media {
+		if m.LocalPath == "" {
+			continue
+		}
+		if _, err := os.Stat(m.LocalPath); err == nil {
+			continue
+		}
+		if err := s.restoreMediaFile(ctx, m.LocalPath); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		restored++
+	}
+	return restored, errors.Join(errs...)
+}
+
+// restoreMediaFile downloads an archive file from the remote backend to path.
+func (s *TweetService) restoreMediaFile(ctx context.Context, path string) error {
+	key, err := s.storageKey(path)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	if err := s.restoreFile(ctx, key, path); err != nil {
+		return fmt.Errorf("restore %s: %w", key, err)
+	}
+	return nil
+}
+
+// restoreFile downloads key to path and dates the file to the remote copy, so
+// the next sync does not upload it again.
+func (s *TweetService) restoreFile(ctx context.Context, key, path string) error {
+	remote, err := s.statRemote(ctx, key)
+	if err != nil {
+		return err
+	}
+	if err := storage.GetFile(ctx, s.store, key, path); err != nil {
+		return err
+	}
+	if !remote.ModTime.IsZero() {
+		os.Chtimes(path, remote.ModTime, remote.ModTime)
+	}
+	return nil
+}
+
+// withLocalMedia runs fn with a tweet's evicted media restored from the remote
+// backend, evicting the restored copies again afterwards. Files that cannot be
+// restored are logged and left for fn to skip.
+func (s *TweetService) withLocalMedia(ctx context.Context, tweet *domain.Tweet, fn func()) {
+	restored, err := s.restoreLocalMedia(ctx, tweet)
+	if err != nil {
+		s.logger.Warn("failed to restore media from storage", "tweet_id", tweet.ID, "error", err)
+	}
+	fn()
+	if restored > 0 && s.cfg.EvictLocalMedia {
+		s.evictLocalMedia(tweet)
+	}
+}
+
+// MediaFileSize returns the size of a media file on local disk or, failing
+// that, on the remote backend. It returns 0 when the file cannot be found.
+func (s *TweetService) MediaFileSize(ctx context.Context, path string) int64 {
+	if info, err := os.Stat(path); err == nil {
+		return info.Size()
+	}
+	if !s.remoteStorage() {
+		return 0
+	}
+	key, err := s.storageKey(path)
+	if err != nil {
+		return 0
+	}
+	if info, err := s.statRemote(ctx, key); err == nil {
+		return info.Size
+	}
+	return 0
+}
+
+// statRemote returns the metadata of a remote object, from the cache of
+// objects known to be mirrored when possible.
+func (s *TweetService) statRemote(ctx context.Context, key string) (storage.ObjectInfo, error) {
+	s.remoteMu.Lock()
+	info, ok := s.remoteObjects[key]
+	s.remoteMu.Unlock()
+	if ok {
+		return info, nil
+	}
+	info, err := s.store.Stat(ctx, key)
+	if err != nil {
+		return storage.ObjectInfo{}, err
+	}
+	s.rememberRemote(info)
+	return info, nil
+}
+
+// rememberRemote records an object as present in the remote backend.
+func (s *TweetService) rememberRemote(info storage.ObjectInfo) {
+	s.remoteMu.Lock()
+	defer s.remoteMu.Unlock()
+	if s.remoteObjects == nil {
+		s.remoteObjects = make(map[string]storage.ObjectInfo)
+	}
+	s.remoteObjects[info.Key] = info
+}
+
+// forgetRemote drops the cached objects under prefix, and any metadata
+// uploads queued for them, when they are deleted.
+func (s *TweetService) forgetRemote(prefix string) {
+	s.dropMetadataUploads(prefix)
+	s.remoteMu.Lock()
+	defer s.remoteMu.Unlock()
+	for key := range s.remoteObjects {
+		if strings.HasPrefix(key, prefix) {
+			delete(s.remoteObjects, key)
+		}
+	}
+}
+
+// metadataMirrorTimeout bounds each background upload of tweet.json or
+// README.md, so an unreachable bucket only delays the mirror.
+const metadataMirrorTimeout = 30 * time.Second
+
+// metadataUpload is a queued upload of an archive metadata file.
+type metadataUpload struct {
+	tweetID domain.TweetID
+	name    string
+	data    []byte
+}
+
+// mirrorMetadata queues tweet.json and README.md of a completed archive for
+// upload. Callers may hold tweetsMu, so the upload runs in the background;
+// a newer save of the same file replaces one still queued. Failures are
+// logged; the local copy remains authoritative.
+func (s *TweetService) mirrorMetadata(tweet *domain.Tweet, files map[string][]byte) {
+	if !s.remoteStorage() || tweet.Status != domain.ArchiveStatusCompleted {
+		return
+	}
+	s.metadataMu.Lock()
+	defer s.metadataMu.Unlock()
+	if s.metadataQueue == nil {
+		s.metadataQueue = make(map[string]metadataUpload)
+	}
+	for name, data := range files {
+		key, err := s.storageKey(filepath.Join(tweet.ArchivePath, name))
+		if err != nil {
+			return
+		}
+		s.metadataQueue[key] = metadataUpload{tweetID: tweet.ID, name: name, data: data}
+	}
+	if !s.metadataMirroring {
+		s.metadataMirroring = true
+		go s.runMetadataMirror()
+	}
+}
+
+// runMetadataMirror uploads queued metadata files until the queue is empty.
+func (s *TweetService) runMetadataMirror() {
+	for {
+		s.metadataMu.Lock()
+		var key string
+		var upload metadataUpload
+		for key, upload = range s.metadataQueue {
+			break
+		}
+		if key == "" {
+			s.metadataMirroring = false
+			s.metadataMu.Unlock()
+			return
+		}
+		delete(s.metadataQueue, key)
+		s.metadataInFlight, s.metadataCanceled = key, false
+		s.metadataMu.Unlock()
+
+		ctx, cancel := context.WithTimeout(context.Background(), metadataMirrorTimeout)
+		err := s.store.Put(ctx, key, bytes.NewReader(upload.data), int64(len(upload.data)), getContentType(upload.name))
+
+		s.metadataMu.Lock()
+		canceled := s.metadataCanceled
+		s.metadataInFlight, s.metadataCanceled = "", false
+		s.metadataMu.Unlock()
+
+		switch {
+		case canceled:
+			// The archive or file was deleted while it uploaded
+			if err == nil {
+				_ = s.store.Delete(ctx, key)
+			}
+		case err != nil:
+			s.logger.Warn("failed to mirror metadata", "tweet_id", upload.tweetID, "file", upload.name, "error", err)
+		default:
+			s.rememberRemote(storage.ObjectInfo{Key: key, Size: int64(len(upload.data)), ModTime: time.Now()})
+		}
+		cancel()
+	}
+}
+
+// dropMetadataUploads discards queued metadata uploads under prefix, and
+// has an upload in flight removed again once it lands.
+func (s *TweetService) dropMetadataUploads(prefix string) {
+	s.metadataMu.Lock()
+	defer s.metadataMu.Unlock()
+	for key := range s.metadataQueue {
+		if strings.HasPrefix(key, prefix) {
+			delete(s.metadataQueue, key)
+		}
+	}
+	if s.metadataInFlight != "" && strings.HasPrefix(s.metadataInFlight, prefix) {
+		s.metadataCanceled = true
+	}
+}
+
+// OpenMediaFile opens a media file for serving. Local copies are preferred;
+// otherwise the file is read from the remote backend with ranged requests.
+func (s *TweetService) OpenMediaFile(ctx context.Context, tweetID domain.TweetID, filename string) (io.ReadSeekCloser, storage.ObjectInfo, error) {
+	archivePath, err := s.GetArchivePath(ctx, tweetID)
+	if err != nil {
+		return nil, storage.ObjectInfo{}, err
+	}
+	if strings.Contains(filename, "..") || strings.Contains(filename, "/") || strings.Contains(filename, "\\") {
+		return nil, storage.ObjectInfo{}, domain.ErrMediaNotFound
+	}
+	return s.openArchiveFile(ctx, filepath.Join(archivePath, "media", filename))
+}
+
+// OpenAvatar opens the author avatar saved with a tweet's archive.
+func (s *TweetService) OpenAvatar(ctx context.Context, tweetID domain.TweetID) (io.ReadSeekCloser, storage.ObjectInfo, error) {
+	s.tweetsMu.RLock()
+	tweet, ok := s.tweets[tweetID]
+	if !ok {
+		s.tweetsMu.RUnlock()
+		return nil, storage.ObjectInfo{}, domain.ErrVideoNotFound
+	}
+	avatarPath := tweet.Author.LocalAvatarURL
+	if avatarPath == "" && tweet.ArchivePath != "" {
+		avatarPath = filepath.Join(tweet.ArchivePath, "avatar.jpg")
+	}
+	s.tweetsMu.RUnlock()
+
+	if avatarPath == "" {
+		return nil, storage.ObjectInfo{}, domain.ErrMediaNotFound
+	}
+	return s.openArchiveFile(ctx, avatarPath)
+}
+
+// openArchiveFile opens a file of an archive for serving. Local copies are
+// preferred; otherwise the file is read from the remote backend with ranged
+// requests.
+func (s *TweetService) openArchiveFile(ctx context.Context, filePath string) (io.ReadSeekCloser, storage.ObjectInfo, error) {
+	if f, err := os.Open(filePath); err == nil {
+		info, err := f.Stat()
+		if err != nil || info.IsDir() {
+			f.Close()
+			return nil, storage.ObjectInfo{}, domain.ErrMediaNotFound
+		}
+		return f, storage.ObjectInfo{Size: info.Size(), ModTime: info.ModTime()}, nil
+	}
+
+	if !s.remoteStorage() {
+		return nil, storage.ObjectInfo{}, domain.ErrMediaNotFound
+	}
+	key, err := s.storageKey(filePath)
+	if err != nil {
+		return nil, storage.ObjectInfo{}, domain.ErrMediaNotFound
+	}
+	rc, info, err := storage.Open(ctx, s.store, key)
+	if errors.Is(err, storage.ErrNotFound) {
+		return nil, storage.ObjectInfo{}, domain.ErrMediaNotFound
+	}
+	if err != nil {
+		return nil, storage.ObjectInfo{}, fmt.Errorf("open from storage: %w", err)
+	}
+	return rc, info, nil
+}
+
+// PresignedMediaURL returns a time-limited backend URL for a media file when
+// presigned serving is enabled and the backend supports it.
+func (s *TweetService) PresignedMediaURL(ctx context.Context, tweetID domain.TweetID, filename string) (string, bool) {
+	if !s.cfg.PresignMedia || !s.remoteStorage() {
+		return "", false
+	}
+	presigner, ok := s.store.(storage.Presigner)
+	if !ok {
+		return "", false
+	}
+	archivePath, err := s.GetArchivePath(ctx, tweetID)
+	if err != nil {
+		return "", false
+	}
+	key, err := s.storageKey(filepath.Join(archivePath, "media", filename))
+	if err != nil {
+		return "", false
+	}
+	// Archives still processing have not been mirrored yet. Known objects
+	// are cached, so only the first request for a file stats it.
+	if _, err := s.statRemote(ctx, key); err != nil {
+		return "", false
+	}
+	url, err := presigner.PresignGet(key, s.cfg.PresignExpiry)
+	if err != nil {
+		s.logger.Warn("failed to presign media URL", "tweet_id", tweetID, "error", err)
+		return "", false
+	}
+	return url, true
+}
+
+// RestoreFromStorage downloads archive metadata that exists in the remote
+// backend but not on local disk (e.g. a fresh volume) and reloads the index.
+// Media stays remote and is served or restored on demand.
+func (s *TweetService) RestoreFromStorage(ctx context.Context) (int, error) {
+	if !s.remoteStorage() {
+		return 0, nil
+	}
+	objects, err := s.store.List(ctx, "")
+	if err != nil {
+		return 0, fmt.Errorf("list storage: %w", err)
+	}
+
+	restored := 0
+	for _, obj := range objects {
+		name := filepath.Base(obj.Key)
+		if name != "tweet.json" && name != "README.md" && name != "avatar.jpg" && name != MetricsHistoryFilename {
+			continue
+		}
+		localPath := filepath.Join(s.cfg.BasePath, filepath.FromSlash(obj.Key))
+		if _, err := os.Stat(localPath); err == nil {
+			continue
+		}
+		if err := os.MkdirAll(filepath.Join(filepath.Dir(localPath), "media"), 0755); err != nil {
+			return restored, fmt.Errorf("create archive directory: %w", err)
+		}
+		s.rememberRemote(obj)
+		if err := s.restoreFile(ctx, obj.Key, localPath); err != nil {
+			s.logger.Warn("failed to restore archive file", "key", obj.Key, "error", err)
+			continue
+		}
+		if name == "tweet.json" {
+			restored++
+		}
+	}
+
+	if restored > 0 {
+		if err := s.LoadFromDisk(); err != nil {
+			return restored, err
+		}
+	}
+	return restored, nil
+}
+
+// deleteFromStorage removes a deleted archive's objects from the remote backend.
+func (s *TweetService) deleteFromStorage(ctx context.Context, tweetID domain.TweetID, archivePath string) {
+	if !s.remoteStorage() {
+		return
+	}
+	key, err := s.storageKey(archivePath)
+	if err != nil {
+		return
+	}
+	s.forgetRemote(key + "/")
+	if _, err := storage.DeletePrefix(ctx, s.store, key+"/"); err != nil {
+		s.logger.Warn("failed to delete archive from storage", "tweet_id", tweetID, "error", err)
+	}
+}
+
+// deleteFileFromStorage removes the remote copy of a file deleted from an
+// archive, which syncArchiveToStorage would otherwise leave behind.
+func (s *TweetService) deleteFileFromStorage(ctx context.Context, tweet *domain.Tweet, path string) {
+	if !s.remoteStorage() {
+		return
+	}
+	key, err := s.storageKey(path)
+	if err != nil {
+		return
+	}
+	s.forgetRemote(key)
+	if err := s.store.Delete(ctx, key); err != nil {
+		s.logger.Warn("failed to delete file from storage", "tweet_id", tweet.ID, "key", key, "error", err)
+	}
+}
+
+// isPartialFile reports whether name is a temporary file from an in-flight write.
+func isPartialFile(name string) bool {
+	return strings.HasSuffix(name, ".tmp") || strings.HasSuffix(name, ".download") || strings.HasSuffix(name, ".link-tmp")
+}
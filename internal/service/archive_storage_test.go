@@ -0,0 +1,295 @@
+package service
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/iconidentify/xgrabba/internal/config"
+	"github.com/iconidentify/xgrabba/internal/domain"
+	"github.com/iconidentify/xgrabba/internal/storage"
+)
+
+// remoteDir is a local directory posing as a remote backend.
+type remoteDir struct {
+	*storage.Local
+}
+
+func (remoteDir) Name() string { return "remote" }
+
+func newStorageTestService(t *testing.T, evict bool) (*TweetService, *domain.Tweet, storage.Storage) {
+	t.Helper()
+	base := t.TempDir()
+	remote := remoteDir{storage.NewLocal(t.TempDir())}
+
+	archive := filepath.Join(base, "2024", "01", "user_2024-01-02_1")
+	mediaPath := filepath.Join(archive, "media", "m1.mp4")
+	if err := os.MkdirAll(filepath.Dir(mediaPath), 0755); err != nil {
+		t.Fatal(err)
+	}
+	os.WriteFile(mediaPath, []byte("video-bytes"), 0644)
+	os.MkdirAll(filepath.Join(archive, "temp_processing"), 0755)
+	os.WriteFile(filepath.Join(archive, "temp_processing", "chunk"), []byte("x"), 0644)
+
+	tweet := &domain.Tweet{
+		ID:          "1",
+		Status:      domain.ArchiveStatusCompleted,
+		ArchivePath: archive,
+		Media:       []domain.Media{{ID: "m1", Type: domain.MediaTypeVideo, LocalPath: mediaPath}},
+	}
+	svc := &TweetService{
+		cfg:    config.StorageConfig{BasePath: base, EvictLocalMedia: evict},
+		store:  remote,
+		logger: slog.New(slog.NewTextHandler(io.Discard, nil)),
+		tweets: map[domain.TweetID]*domain.Tweet{tweet.ID: tweet},
+	}
+	if err := svc.saveTweetMetadata(tweet); err != nil {
+		t.Fatal(err)
+	}
+	waitMetadataMirror(t, svc)
+	return svc, tweet, remote
+}
+
+func TestSyncArchiveToStorage(t *testing.T) {
+	svc, tweet, remote := newStorageTestService(t, true)
+	ctx := context.Background()
+
+	if err := svc.syncArchiveToStorage(ctx, tweet); err != nil {
+		t.Fatal(err)
+	}
+
+	objects, err := remote.List(ctx, "2024/01/user_2024-01-02_1/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	keys := map[string]bool{}
+	for _, o := range objects {
+		keys[o.Key] = true
+	}
+	for _, want := range []string{"media/m1.mp4", "tweet.json", "README.md"} {
+		if !keys["2024/01/user_2024-01-02_1/"+want] {
+			t.Errorf("missing %s in %v", want, keys)
+		}
+	}
+	if keys["2024/01/user_2024-01-02_1/temp_processing/chunk"] {
+		t.Error("temp_processing should not be mirrored")
+	}
+
+	// Media was evicted locally but can still be served and restored
+	if _, err := os.Stat(tweet.Media[0].LocalPath); !os.IsNotExist(err) {
+		t.Fatal("local media should be evicted")
+	}
+	f, info, err := svc.OpenMediaFile(ctx, tweet.ID, "m1.mp4")
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, _ := io.ReadAll(f)
+	f.Close()
+	if string(data) != "video-bytes" || info.Size != int64(len("video-bytes")) {
+		t.Errorf("served %q (size %d)", data, info.Size)
+	}
+	if got := svc.MediaFileSize(ctx, tweet.Media[0].LocalPath); got != int64(len("video-bytes")) {
+		t.Errorf("MediaFileSize = %d", got)
+	}
+
+	if err := svc.EnsureLocalMedia(ctx, tweet); err != nil {
+		t.Fatal(err)
+	}
+	if data, _ := os.ReadFile(tweet.Media[0].LocalPath); string(data) != "video-bytes" {
+		t.Errorf("restored %q", data)
+	}
+}
+
+func TestRestoreFromStorage(t *testing.T) {
+	svc, tweet, _ := newStorageTestService(t, false)
+	ctx := context.Background()
+	if err := svc.syncArchiveToStorage(ctx, tweet); err != nil {
+		t.Fatal(err)
+	}
+
+	// Simulate a fresh volume
+	os.RemoveAll(tweet.ArchivePath)
+	svc.tweets = make(map[domain.TweetID]*domain.Tweet)
+
+	restored, err := svc.RestoreFromStorage(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if restored != 1 || len(svc.tweets) != 1 {
+		t.Errorf("restored = %d, tweets = %d", restored, len(svc.tweets))
+	}
+}
+
+func TestDelete_RemovesFromStorage(t *testing.T) {
+	svc, tweet, remote := newStorageTestService(t, false)
+	ctx := context.Background()
+	if err := svc.syncArchiveToStorage(ctx, tweet); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := svc.Delete(ctx, tweet.ID); err != nil {
+		t.Fatal(err)
+	}
+	objects, err := remote.List(ctx, "2024/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(objects) != 0 {
+		t.Errorf("objects left after delete: %+v", objects)
+	}
+}
+
+// countingRemote counts Stat calls and presigns every key.
+type countingRemote struct {
+	remoteDir
+	stats int
+}
+
+func (c *countingRemote) Stat(ctx context.Context, key string) (storage.ObjectInfo, error) {
+	c.stats++
+	return c.remoteDir.Stat(ctx, key)
+}
+
+func (c *countingRemote) PresignGet(key string, expiry time.Duration) (string, error) {
+	return "https://bucket.example/" + key, nil
+}
+
+func TestSyncArchiveToStorage_ChangedFiles(t *testing.T) {
+	svc, tweet, remote := newStorageTestService(t, false)
+	ctx := context.Background()
+	if err := svc.syncArchiveToStorage(ctx, tweet); err != nil {
+		t.Fatal(err)
+	}
+
+	// Rewrite the media with different bytes of the same size
+	mediaPath := tweet.Media[0].LocalPath
+	os.WriteFile(mediaPath, []byte("VIDEO-BYTES"), 0644)
+	later := time.Now().Add(time.Minute)
+	os.Chtimes(mediaPath, later, later)
+	if err := svc.syncArchiveToStorage(ctx, tweet); err != nil {
+		t.Fatal(err)
+	}
+
+	rc, err := remote.Get(ctx, "2024/01/user_2024-01-02_1/media/m1.mp4")
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, _ := io.ReadAll(rc)
+	rc.Close()
+	if string(data) != "VIDEO-BYTES" {
+		t.Errorf("remote copy = %q, want the rewritten file", data)
+	}
+}
+
+func TestPresignedMediaURL_CachesPresence(t *testing.T) {
+	svc, tweet, _ := newStorageTestService(t, false)
+	remote := &countingRemote{remoteDir: svc.store.(remoteDir)}
+	svc.store = remote
+	svc.cfg.PresignMedia = true
+	ctx := context.Background()
+
+	if _, ok := svc.PresignedMediaURL(ctx, tweet.ID, "m1.mp4"); ok {
+		t.Error("media that is not mirrored yet should not be presigned")
+	}
+	if err := svc.syncArchiveToStorage(ctx, tweet); err != nil {
+		t.Fatal(err)
+	}
+	remote.stats = 0
+	for i := 0; i < 3; i++ {
+		if _, ok := svc.PresignedMediaURL(ctx, tweet.ID, "m1.mp4"); !ok {
+			t.Fatal("mirrored media should be presigned")
+		}
+	}
+	if remote.stats != 0 {
+		t.Errorf("presigning statted the backend %d times, want 0", remote.stats)
+	}
+}
+
+func TestComputeMediaHashes_RestoresEvictedMedia(t *testing.T) {
+	svc, tweet, _ := newStorageTestService(t, true)
+	ctx := context.Background()
+	if err := svc.syncArchiveToStorage(ctx, tweet); err != nil {
+		t.Fatal(err)
+	}
+
+	svc.computeMediaHashes(ctx, tweet, false)
+	if tweet.Media[0].SHA256 == "" {
+		t.Error("evicted media was not hashed")
+	}
+	if _, err := os.Stat(tweet.Media[0].LocalPath); !os.IsNotExist(err) {
+		t.Error("restored media should be evicted again")
+	}
+}
+
+// blockingRemote holds every upload until release is closed.
+type blockingRemote struct {
+	remoteDir
+	release chan struct{}
+}
+
+func (b *blockingRemote) Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error {
+	select {
+	case <-b.release:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	return b.remoteDir.Put(ctx, key, r, size, contentType)
+}
+
+func TestSaveTweetMetadata_MirrorsInBackground(t *testing.T) {
+	svc, tweet, remote := newStorageTestService(t, false)
+	blocking := &blockingRemote{remoteDir: remote.(remoteDir), release: make(chan struct{})}
+	svc.store = blocking
+
+	// A slow bucket must not hold up saves made under tweetsMu
+	tweet.AITitle = "Cats in boxes"
+	done := make(chan error, 1)
+	go func() {
+		svc.tweetsMu.Lock()
+		defer svc.tweetsMu.Unlock()
+		done <- svc.saveTweetMetadata(tweet)
+	}()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatal(err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("saveTweetMetadata waited for the remote upload")
+	}
+
+	close(blocking.release)
+	waitMetadataMirror(t, svc)
+	rc, err := remote.Get(context.Background(), "2024/01/user_2024-01-02_1/tweet.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, _ := io.ReadAll(rc)
+	rc.Close()
+	if !strings.Contains(string(data), "Cats in boxes") {
+		t.Errorf("mirrored tweet.json = %s", data)
+	}
+}
+
+// waitMetadataMirror waits for queued metadata uploads to finish.
+func waitMetadataMirror(t *testing.T, svc *TweetService) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		svc.metadataMu.Lock()
+		busy := svc.metadataMirroring
+		svc.metadataMu.Unlock()
+		if !busy {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("metadata mirror did not finish")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
@@ -0,0 +1,403 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/iconidentify/xgrabba/internal/domain"
+	"github.com/iconidentify/xgrabba/pkg/grok"
+)
+
+// Retrieval limits for questions about the archive. They keep the prompt to
+// a few thousand words however large the archive is.
+const (
+	defaultAskSources   = 8
+	maxAskSources       = 20
+	askExcerptChars     = 1200 // Per text field of a source
+	askTranscriptLines  = 24   // Transcript lines per video
+	askTranscriptWindow = 1    // Segments kept either side of a matching one
+)
+
+var (
+	// ErrEmptyQuestion is returned when a question has no text.
+	ErrEmptyQuestion = errors.New("question is required")
+	// ErrNoAskSources is returned when no archived tweet matches a question.
+	ErrNoAskSources = errors.New("no archived tweets match the question")
+)
+
+// AskOptions is a question about the archive and the tweets it may be
+// answered from.
+type AskOptions struct {
+	Question   string
+	TweetIDs   []string  // Only these tweets, e.g. a playlist's items; nil searches the whole archive
+	Since      time.Time // Only tweets posted at or after Since; zero is unbounded
+	Until      time.Time // Only tweets posted before Until; zero is unbounded
+	MaxSources int       // Tweets sent to the AI; zero uses 8, at most 20
+}
+
+// AskSource is an archived tweet retrieved to answer a question.
+type AskSource struct {
+	TweetID  domain.TweetID `json:"tweet_id"`
+	Author   string         `json:"author"`
+	Title    string         `json:"title,omitempty"`
+	URL      string         `json:"url,omitempty"`
+	PostedAt time.Time      `json:"posted_at"`
+	Score    int            `json:"score"`
+	excerpt  string         // Relevant text sent to the AI
+}
+
+// AskCitation is a reference in an answer to a tweet and, for transcript
+// lines, the moment in its video.
+type AskCitation struct {
+	TweetID   domain.TweetID `json:"tweet_id"`
+	Timestamp string         `json:"timestamp,omitempty"` // "mm:ss" as cited
+	Seconds   int            `json:"seconds,omitempty"`
+}
+
+// AskAnswer is the answer to a question about the archive.
+type AskAnswer struct {
+	Question  string        `json:"question"`
+	Answer    string        `json:"answer"` // Markdown citing tweets as [tweet:ID] or [tweet:ID@mm:ss]
+	Citations []AskCitation `json:"citations"`
+	Sources   []AskSource   `json:"sources"`
+}
+
+// askStopWords are left out of retrieval terms because nearly every tweet
+// or question contains them.
+var askStopWords = map[string]bool{
+	"the": true, "and": true, "for": true, "are": true, "was": true, "were": true,
+	"what": true, "which": true, "who": true, "whom": true, "whose": true, "when": true,
+	"where": true, "why": true, "how": true, "that": true, "this": true, "these": true,
+	"those": true, "with": true, "from": true, "about": true, "into": true, "does": true,
+	"did": true, "have": true, "has": true, "had": true, "there": true, "their": true,
+	"they": true, "them": true, "then": true, "than": true, "can": true, "could": true,
+	"would": true, "should": true, "will": true, "you": true, "your": true, "our": true,
+	"his": true, "her": true, "its": true, "not": true, "but": true, "all": true,
+	"any": true, "some": true, "someone": true, "something": true, "tweet": true,
+	"tweets": true, "post": true, "posts": true, "said": true, "say": true, "says": true,
+	"explain": true, "explained": true, "talk": true, "talked": true, "remember": true,
+}
+
+// askTerms splits a question into lowercase retrieval terms, dropping stop
+// words, duplicates and words shorter than three letters.
+func askTerms(question string) []string {
+	var terms []string
+	seen := make(map[string]bool)
+	words := strings.FieldsFunc(strings.ToLower(question), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	for _, w := range words {
+		if len([]rune(w)) < 3 || askStopWords[w] || seen[w] {
+			continue
+		}
+		seen[w] = true
+		terms = append(terms, w)
+	}
+	return terms
+}
+
+// askField is a piece of tweet text searched for retrieval terms.
+type askField struct {
+	text   string
+	weight int
+}
+
+// askScore scores a tweet for a question: each term counts up to three
+// times per field, weighted by field, plus a bonus per distinct term so
+// tweets matching more of the question rank first. Zero means no match.
+func askScore(t *domain.Tweet, terms []string) int {
+	fields := []askField{
+		{t.Author.Username + " " + t.Author.DisplayName, 1},
+		{t.Text, 2},
+		{t.ArticleTitle, 3},
+		{t.ArticleBody, 1},
+		{t.AITitle, 3},
+		{t.AISummary, 2},
+		{strings.Join(t.AITags, " ") + " " + strings.Join(t.AITopics, " ") + " " + strings.Join(t.Tags, " "), 2},
+	}
+	for _, m := range t.Media {
+		fields = append(fields, askField{m.Transcript, 1}, askField{m.EssayTitle + " " + m.Essay, 1})
+	}
+
+	score, matched := 0, 0
+	for _, term := range terms {
+		found := false
+		for _, f := range fields {
+			if n := strings.Count(strings.ToLower(f.text), term); n > 0 {
+				score += f.weight * min(n, 3)
+				found = true
+			}
+		}
+		if found {
+			matched++
+		}
+	}
+	if matched == 0 {
+		return 0
+	}
+	return score + 5*matched
+}
+
+// excerptAround returns up to askExcerptChars of text centred on the first
+// retrieval term it contains, or its start when it contains none.
+func excerptAround(text string, terms []string) string {
+	text = strings.TrimSpace(text)
+	runes := []rune(text)
+	if len(runes) <= askExcerptChars {
+		return text
+	}
+	lower := strings.ToLower(text)
+	start := 0
+	for _, term := range terms {
+		if i := strings.Index(lower, term); i >= 0 {
+			start = min(max(utf8.RuneCountInString(lower[:i])-askExcerptChars/3, 0), len(runes)-askExcerptChars)
+			break
+		}
+	}
+	end := start + askExcerptChars
+	excerpt := strings.TrimSpace(string(runes[start:end]))
+	if start > 0 {
+		excerpt = "…" + excerpt
+	}
+	if end < len(runes) {
+		excerpt += "…"
+	}
+	return excerpt
+}
+
+// formatTimestamp formats seconds into a video as mm:ss.
+func formatTimestamp(seconds float64) string {
+	secs := int(seconds)
+	return fmt.Sprintf("%02d:%02d", secs/60, secs%60)
+}
+
+// transcriptExcerpt returns the "[mm:ss] text" lines of a timed transcript
+// that contain a retrieval term, with a segment of context either side.
+// Gaps between runs of lines are marked with "…". Untimed transcripts are
+// excerpted like other text.
+func transcriptExcerpt(m domain.Media, terms []string) string {
+	if len(m.TranscriptSegments) == 0 {
+		return excerptAround(m.Transcript, terms)
+	}
+	keep := make([]bool, len(m.TranscriptSegments))
+	for i, seg := range m.TranscriptSegments {
+		text := strings.ToLower(seg.Text)
+		for _, term := range terms {
+			if strings.Contains(text, term) {
+				for j := max(i-askTranscriptWindow, 0); j <= min(i+askTranscriptWindow, len(keep)-1); j++ {
+					keep[j] = true
+				}
+				break
+			}
+		}
+	}
+
+	var lines []string
+	last := -1
+	for i, seg := range m.TranscriptSegments {
+		if !keep[i] {
+			continue
+		}
+		if len(lines) >= askTranscriptLines {
+			break
+		}
+		if last >= 0 && i > last+1 {
+			lines = append(lines, "…")
+		}
+		lines = append(lines, fmt.Sprintf("[%s] %s", formatTimestamp(seg.Start), strings.TrimSpace(seg.Text)))
+		last = i
+	}
+	if len(lines) == 0 {
+		// Matched elsewhere in the tweet; the opening gives the video's context
+		for i, seg := range m.TranscriptSegments {
+			if i >= askTranscriptLines/2 {
+				break
+			}
+			lines = append(lines, fmt.Sprintf("[%s] %s", formatTimestamp(seg.Start), strings.TrimSpace(seg.Text)))
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// askExcerpt collects the parts of a tweet relevant to a question: its text,
+// article, essays and transcript lines.
+func askExcerpt(t *domain.Tweet, terms []string) string {
+	var parts []string
+	if t.AITitle != "" {
+		parts = append(parts, "Title: "+t.AITitle)
+	}
+	if text := strings.TrimSpace(t.Text); text != "" {
+		parts = append(parts, excerptAround(text, terms))
+	}
+	if t.ArticleBody != "" {
+		parts = append(parts, "Article: "+strings.TrimSpace(t.ArticleTitle)+"\n"+excerptAround(t.ArticleBody, terms))
+	}
+	for _, m := range t.Media {
+		if m.Essay != "" {
+			parts = append(parts, fmt.Sprintf("Essay %q:\n%s", m.EssayTitle, excerptAround(m.Essay, terms)))
+		}
+		if m.Transcript != "" {
+			parts = append(parts, "Video transcript:\n"+transcriptExcerpt(m, terms))
+		}
+	}
+	return strings.Join(parts, "\n\n")
+}
+
+// findAskSources returns the tweets in scope most relevant to a question,
+// best first.
+func (s *TweetService) findAskSources(opts AskOptions) []AskSource {
+	terms := askTerms(opts.Question)
+	if len(terms) == 0 {
+		return nil
+	}
+	limit := opts.MaxSources
+	if limit <= 0 {
+		limit = defaultAskSources
+	}
+	limit = min(limit, maxAskSources)
+
+	var scope map[domain.TweetID]bool
+	if opts.TweetIDs != nil {
+		scope = make(map[domain.TweetID]bool, len(opts.TweetIDs))
+		for _, id := range opts.TweetIDs {
+			scope[domain.TweetID(id)] = true
+		}
+	}
+
+	type candidate struct {
+		tweet *domain.Tweet
+		score int
+	}
+	s.tweetsMu.RLock()
+	defer s.tweetsMu.RUnlock()
+	var candidates []candidate
+	for id, t := range s.tweets {
+		if scope != nil && !scope[id] {
+			continue
+		}
+		posted := t.PostedAt
+		if posted.IsZero() {
+			posted = t.CreatedAt
+		}
+		if (!opts.Since.IsZero() && posted.Before(opts.Since)) || (!opts.Until.IsZero() && !posted.Before(opts.Until)) {
+			continue
+		}
+		if score := askScore(t, terms); score > 0 {
+			candidates = append(candidates, candidate{t, score})
+		}
+	}
+	sort.Slice(candidates, func(i, j int) bool {
+		if candidates[i].score != candidates[j].score {
+			return candidates[i].score > candidates[j].score
+		}
+		return candidates[i].tweet.PostedAt.After(candidates[j].tweet.PostedAt)
+	})
+	if len(candidates) > limit {
+		candidates = candidates[:limit]
+	}
+
+	sources := make([]AskSource, 0, len(candidates))
+	for _, c := range candidates {
+		sources = append(sources, AskSource{
+			TweetID:  c.tweet.ID,
+			Author:   c.tweet.Author.Username,
+			Title:    c.tweet.AITitle,
+			URL:      c.tweet.URL,
+			PostedAt: c.tweet.PostedAt,
+			Score:    c.score,
+			excerpt:  askExcerpt(c.tweet, terms),
+		})
+	}
+	return sources
+}
+
+// citationGroupPattern finds bracketed citations, which may list several
+// tweets, e.g. "[tweet:1, tweet:2@01:05]".
+var citationGroupPattern = regexp.MustCompile(`\[([^\[\]]*tweet:[^\[\]]*)\]`)
+
+// citationPattern matches one tweet citation inside a bracketed group.
+var citationPattern = regexp.MustCompile(`tweet:\s*(\w+)(?:\s*@\s*(\d+:\d{2}(?::\d{2})?))?`)
+
+// parseCitations returns the citations in an answer, in order and without
+// duplicates. Citations of tweets that were not offered as sources are
+// dropped, since the model made them up.
+func parseCitations(answer string, sources []AskSource) []AskCitation {
+	known := make(map[domain.TweetID]bool, len(sources))
+	for _, src := range sources {
+		known[src.TweetID] = true
+	}
+	citations := []AskCitation{}
+	seen := make(map[string]bool)
+	for _, group := range citationGroupPattern.FindAllStringSubmatch(answer, -1) {
+		for _, m := range citationPattern.FindAllStringSubmatch(group[1], -1) {
+			id := domain.TweetID(m[1])
+			if !known[id] || seen[m[0]] {
+				continue
+			}
+			seen[m[0]] = true
+			citation := AskCitation{TweetID: id, Timestamp: m[2]}
+			if m[2] != "" {
+				citation.Seconds = parseTimestamp(m[2])
+			}
+			citations = append(citations, citation)
+		}
+	}
+	return citations
+}
+
+// parseTimestamp converts mm:ss or hh:mm:ss to seconds.
+func parseTimestamp(ts string) int {
+	secs := 0
+	for _, part := range strings.Split(ts, ":") {
+		n, _ := strconv.Atoi(part)
+		secs = secs*60 + n
+	}
+	return secs
+}
+
+// Ask answers a question from the archived tweets most relevant to it,
+// citing tweets and transcript timestamps. When onDelta is set, the answer
+// is streamed to it as the AI generates it.
+func (s *TweetService) Ask(ctx context.Context, opts AskOptions, onDelta func(string)) (*AskAnswer, error) {
+	question := strings.TrimSpace(opts.Question)
+	if question == "" {
+		return nil, ErrEmptyQuestion
+	}
+	sources := s.findAskSources(opts)
+	if len(sources) == 0 {
+		return nil, ErrNoAskSources
+	}
+	if err := s.aiBudgetExceeded(); err != nil {
+		return nil, err
+	}
+
+	req := grok.AskRequest{Question: question}
+	for _, src := range sources {
+		source := grok.AskSource{TweetID: string(src.TweetID), Author: src.Author, Text: src.excerpt}
+		if !src.PostedAt.IsZero() {
+			source.PostedAt = src.PostedAt.Format("2006-01-02")
+		}
+		req.Sources = append(req.Sources, source)
+	}
+
+	s.logger.Info("answering question", "sources", len(sources), "streaming", onDelta != nil)
+	resp, err := s.grokClient.Ask(ctx, req, onDelta)
+	if err != nil {
+		return nil, fmt.Errorf("answer question: %w", err)
+	}
+
+	return &AskAnswer{
+		Question:  question,
+		Answer:    resp.Answer,
+		Citations: parseCitations(resp.Answer, sources),
+		Sources:   sources,
+	}, nil
+}
@@ -0,0 +1,130 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/iconidentify/xgrabba/internal/domain"
+	"github.com/iconidentify/xgrabba/pkg/grok"
+)
+
+// askStub records questions and streams a fixed answer in two pieces.
+type askStub struct {
+	analysisStub
+	answer   string
+	requests []grok.AskRequest
+}
+
+func (s *askStub) Ask(ctx context.Context, req grok.AskRequest, onDelta func(string)) (*grok.AskResponse, error) {
+	s.requests = append(s.requests, req)
+	if onDelta != nil {
+		half := len(s.answer) / 2
+		onDelta(s.answer[:half])
+		onDelta(s.answer[half:])
+	}
+	return &grok.AskResponse{Answer: s.answer}, nil
+}
+
+func newAskTestService(t *testing.T) (*TweetService, *askStub) {
+	t.Helper()
+	svc, tweet, _ := newDocumentTestService(t)
+	stub := &askStub{answer: "Purring is calming [tweet:1@01:15], see also [tweet:2, tweet:99]."}
+	svc.grokClient = stub
+	tweet.Media[0].Transcript = "Cats purr. It is calming. Dogs bark."
+	tweet.Media[0].TranscriptSegments = append(tweet.Media[0].TranscriptSegments, domain.TranscriptSegment{Start: 200, End: 202, Text: "Unrelated outro."})
+	svc.tweets["2"] = &domain.Tweet{ID: "2", Author: domain.Author{Username: "bob"}, Text: "My cat purrs all night",
+		PostedAt: time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)}
+	svc.tweets["3"] = &domain.Tweet{ID: "3", Author: domain.Author{Username: "carol"}, Text: "Dogs are great",
+		PostedAt: time.Date(2024, 3, 2, 0, 0, 0, 0, time.UTC)}
+	return svc, stub
+}
+
+func TestAsk_RetrievesAndCites(t *testing.T) {
+	svc, stub := newAskTestService(t)
+
+	var streamed string
+	answer, err := svc.Ask(context.Background(), AskOptions{Question: "Why do cats purr?"}, func(text string) { streamed += text })
+	if err != nil {
+		t.Fatal(err)
+	}
+	if streamed != stub.answer || answer.Answer != stub.answer {
+		t.Errorf("streamed %q, answer %q", streamed, answer.Answer)
+	}
+
+	req := stub.requests[0]
+	if len(req.Sources) != 2 || req.Sources[0].TweetID != "1" || req.Sources[1].TweetID != "2" {
+		t.Fatalf("sources = %+v, want tweets 1 and 2, best match first", req.Sources)
+	}
+	if src := req.Sources[0].Text; !strings.Contains(src, "[01:15] It is calming.") || strings.Contains(src, "Unrelated outro") {
+		t.Errorf("transcript excerpt = %q", src)
+	}
+
+	want := []AskCitation{{TweetID: "1", Timestamp: "01:15", Seconds: 75}, {TweetID: "2"}}
+	if len(answer.Citations) != len(want) {
+		t.Fatalf("citations = %+v, want %+v (unknown tweet 99 dropped)", answer.Citations, want)
+	}
+	for i := range want {
+		if answer.Citations[i] != want[i] {
+			t.Errorf("citation %d = %+v, want %+v", i, answer.Citations[i], want[i])
+		}
+	}
+}
+
+func TestAsk_Scope(t *testing.T) {
+	svc, stub := newAskTestService(t)
+	ctx := context.Background()
+
+	if _, err := svc.Ask(ctx, AskOptions{Question: "cats purr", TweetIDs: []string{"2", "3"}}, nil); err != nil {
+		t.Fatal(err)
+	}
+	if got := stub.requests[0].Sources; len(got) != 1 || got[0].TweetID != "2" {
+		t.Errorf("playlist-scoped sources = %+v, want only tweet 2", got)
+	}
+
+	since := time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)
+	if _, err := svc.Ask(ctx, AskOptions{Question: "cats purr", Since: since}, nil); err != nil {
+		t.Fatal(err)
+	}
+	if got := stub.requests[1].Sources; len(got) != 1 || got[0].TweetID != "2" {
+		t.Errorf("date-scoped sources = %+v, want only tweet 2", got)
+	}
+
+	if _, err := svc.Ask(ctx, AskOptions{Question: "cats", Until: since}, nil); err != nil {
+		t.Fatal(err)
+	}
+	if got := stub.requests[2].Sources; len(got) != 1 || got[0].TweetID != "1" {
+		t.Errorf("until-scoped sources = %+v, want only tweet 1", got)
+	}
+}
+
+func TestAsk_Errors(t *testing.T) {
+	svc, _ := newAskTestService(t)
+	ctx := context.Background()
+
+	if _, err := svc.Ask(ctx, AskOptions{Question: "  "}, nil); !errors.Is(err, ErrEmptyQuestion) {
+		t.Errorf("empty question: err = %v", err)
+	}
+	if _, err := svc.Ask(ctx, AskOptions{Question: "quantum chromodynamics"}, nil); !errors.Is(err, ErrNoAskSources) {
+		t.Errorf("unmatched question: err = %v", err)
+	}
+	if _, err := svc.Ask(ctx, AskOptions{Question: "what was that?"}, nil); !errors.Is(err, ErrNoAskSources) {
+		t.Errorf("stop words only: err = %v", err)
+	}
+}
+
+func TestExcerptAround(t *testing.T) {
+	text := strings.Repeat("filler ", 400) + "the purring part " + strings.Repeat("more ", 400)
+	got := excerptAround(text, []string{"purring"})
+	if !strings.Contains(got, "purring") || !strings.HasPrefix(got, "…") || !strings.HasSuffix(got, "…") {
+		t.Errorf("excerpt misses the match: %q", got)
+	}
+	if n := len([]rune(got)); n > askExcerptChars+2 {
+		t.Errorf("excerpt is %d chars", n)
+	}
+	if got := excerptAround("short text", []string{"x"}); got != "short text" {
+		t.Errorf("short text excerpt = %q", got)
+	}
+}
@@ -0,0 +1,48 @@
+package service
+
+import (
+	"github.com/iconidentify/xgrabba/internal/blobstore"
+	"github.com/iconidentify/xgrabba/internal/domain"
+)
+
+// SetBlobStore enables content-addressed media storage. Downloaded media is
+// moved into the store and archive paths become hardlinks to shared blobs.
+func (s *TweetService) SetBlobStore(store *blobstore.Store) {
+	s.blobs = store
+}
+
+// ingestMediaBlobs moves a tweet's hashed media into the blob store.
+// Failures leave the regular file in place, so they are only logged.
+func (s *TweetService) ingestMediaBlobs(tweet *domain.Tweet) {
+	if s.blobs == nil || tweet == nil {
+		return
+	}
+	for _, m := range tweet.Media {
+		if m.LocalPath == "" || m.SHA256 == "" {
+			continue
+		}
+		if err := s.blobs.Ingest(m.LocalPath, m.SHA256); err != nil {
+			s.logger.Warn("failed to add media to blob store",
+				"tweet_id", tweet.ID,
+				"media_id", m.ID,
+				"error", err,
+			)
+		}
+	}
+}
+
+// releaseMediaBlobs drops blob references held by a deleted archive and frees
+// blobs no other archive uses.
+func (s *TweetService) releaseMediaBlobs(tweetID domain.TweetID, archivePath string) {
+	if s.blobs == nil {
+		return
+	}
+	freed, err := s.blobs.ReleasePrefix(archivePath)
+	if err != nil {
+		s.logger.Warn("failed to release media blobs", "tweet_id", tweetID, "error", err)
+		return
+	}
+	if freed > 0 {
+		s.logger.Info("freed unreferenced media blobs", "tweet_id", tweetID, "bytes", freed)
+	}
+}
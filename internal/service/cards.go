@@ -0,0 +1,112 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/iconidentify/xgrabba/internal/domain"
+)
+
+// CardImageFilename is the link card preview image, stored with the media so
+// it is served by the media endpoint.
+const CardImageFilename = "card.jpg"
+
+// downloadCardImage saves the link card's preview image into the archive.
+// It is best-effort: a card without its image still renders.
+func (s *TweetService) downloadCardImage(ctx context.Context, tweet *domain.Tweet) error {
+	card := tweet.Card
+	if card == nil || card.ImageURL == "" || tweet.ArchivePath == "" {
+		return nil
+	}
+	mediaDir := filepath.Join(tweet.ArchivePath, "media")
+	if err := os.MkdirAll(mediaDir, 0755); err != nil {
+		return fmt.Errorf("create media directory: %w", err)
+	}
+	dest := filepath.Join(mediaDir, CardImageFilename)
+	if err := s.downloadThumbnail(ctx, card.ImageURL, dest); err != nil {
+		return err
+	}
+	card.LocalImagePath = dest
+	return nil
+}
+
+// mergeAttachments updates poll counts, Community Notes and the link card
+// from a re-fetch. A card that disappeared upstream is kept, and the local
+// image is kept while the preview image is unchanged.
+func mergeAttachments(tweet, fetched *domain.Tweet) {
+	if fetched.Poll != nil {
+		tweet.Poll = fetched.Poll
+	}
+	if fetched.CommunityNote != nil {
+		tweet.CommunityNote = fetched.CommunityNote
+	}
+	if fetched.Card != nil {
+		if tweet.Card != nil && tweet.Card.ImageURL == fetched.Card.ImageURL {
+			fetched.Card.LocalImagePath = tweet.Card.LocalImagePath
+		}
+		tweet.Card = fetched.Card
+	}
+}
+
+// attachmentsMarkdown renders the poll, link card and Community Note sections
+// of README.md, or "" when the tweet has none.
+func attachmentsMarkdown(tweet *domain.Tweet) string {
+	var sb strings.Builder
+
+	if poll := tweet.Poll; poll != nil {
+		total := poll.TotalVotes()
+		sb.WriteString("\n---\n\n## Poll\n\n")
+		for _, o := range poll.Options {
+			pct := 0.0
+			if total > 0 {
+				pct = float64(o.Votes) * 100 / float64(total)
+			}
+			sb.WriteString(fmt.Sprintf("- %s: %d votes (%.1f%%)\n", o.Label, o.Votes, pct))
+		}
+		state := "Ends"
+		if poll.Final {
+			state = "Final results"
+		}
+		sb.WriteString(fmt.Sprintf("\n%d votes", total))
+		if poll.EndsAt != nil {
+			sb.WriteString(fmt.Sprintf(" · %s %s", state, poll.EndsAt.Format("January 2, 2006 at 3:04 PM")))
+		} else if poll.Final {
+			sb.WriteString(" · " + state)
+		}
+		sb.WriteString("\n\n")
+	}
+
+	if card := tweet.Card; card != nil {
+		sb.WriteString("\n---\n\n## Link\n\n")
+		title := card.Title
+		if title == "" {
+			title = card.URL
+		}
+		sb.WriteString(fmt.Sprintf("**[%s](%s)**\n\n", title, card.URL))
+		if card.Description != "" {
+			sb.WriteString(fmt.Sprintf("%s\n\n", card.Description))
+		}
+		if card.LocalImagePath != "" {
+			sb.WriteString(fmt.Sprintf("![Link preview](media/%s)\n\n", filepath.Base(card.LocalImagePath)))
+		}
+		if card.Domain != "" {
+			sb.WriteString(fmt.Sprintf("*%s*\n\n", card.Domain))
+		}
+	}
+
+	if note := tweet.CommunityNote; note != nil {
+		sb.WriteString("\n---\n\n## Community Note\n\n")
+		for _, line := range strings.Split(note.Text, "\n") {
+			sb.WriteString(fmt.Sprintf("> %s\n", line))
+		}
+		if note.URL != "" {
+			sb.WriteString(fmt.Sprintf("\n[View note on X](%s)\n", note.URL))
+		}
+		sb.WriteString("\n")
+	}
+
+	return sb.String()
+}
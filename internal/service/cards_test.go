@@ -0,0 +1,83 @@
+package service
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/iconidentify/xgrabba/internal/domain"
+)
+
+func TestAttachmentsMarkdown(t *testing.T) {
+	ends := time.Date(2024, 1, 2, 15, 4, 0, 0, time.UTC)
+	tweet := &domain.Tweet{
+		Poll: &domain.Poll{
+			Options: []domain.PollOption{{Label: "Yes", Votes: 3}, {Label: "No", Votes: 1}},
+			EndsAt:  &ends,
+			Final:   true,
+		},
+		Card: &domain.LinkCard{
+			URL:            "https://example.com/story",
+			Domain:         "example.com",
+			Title:          "A story",
+			LocalImagePath: "/data/archive/media/card.jpg",
+		},
+		CommunityNote: &domain.CommunityNote{Text: "Line one\nLine two", URL: "https://x.com/i/birdwatch/n/1"},
+	}
+
+	md := attachmentsMarkdown(tweet)
+	for _, want := range []string{
+		"## Poll",
+		"- Yes: 3 votes (75.0%)",
+		"4 votes · Final results January 2, 2024 at 3:04 PM",
+		"**[A story](https://example.com/story)**",
+		"![Link preview](media/card.jpg)",
+		"## Community Note",
+		"> Line one\n> Line two",
+		"[View note on X](https://x.com/i/birdwatch/n/1)",
+	} {
+		if !strings.Contains(md, want) {
+			t.Errorf("markdown missing %q:\n%s", want, md)
+		}
+	}
+
+	if got := attachmentsMarkdown(&domain.Tweet{}); got != "" {
+		t.Errorf("tweet without attachments should render nothing, got %q", got)
+	}
+}
+
+func TestMergeAttachments(t *testing.T) {
+	tweet := &domain.Tweet{
+		Poll: &domain.Poll{Options: []domain.PollOption{{Label: "Yes", Votes: 1}}},
+		Card: &domain.LinkCard{URL: "https://example.com", ImageURL: "https://img/1.jpg", LocalImagePath: "/a/media/card.jpg"},
+	}
+	fetched := &domain.Tweet{
+		Poll:          &domain.Poll{Options: []domain.PollOption{{Label: "Yes", Votes: 5}}, Final: true},
+		Card:          &domain.LinkCard{URL: "https://example.com", Title: "New title", ImageURL: "https://img/1.jpg"},
+		CommunityNote: &domain.CommunityNote{Text: "context"},
+	}
+
+	mergeAttachments(tweet, fetched)
+
+	if tweet.Poll.TotalVotes() != 5 || !tweet.Poll.Final {
+		t.Errorf("poll not refreshed: %+v", tweet.Poll)
+	}
+	if tweet.Card.Title != "New title" || tweet.Card.LocalImagePath != "/a/media/card.jpg" {
+		t.Errorf("card = %+v, want new title with the local image kept", tweet.Card)
+	}
+	if tweet.CommunityNote == nil {
+		t.Error("community note added after archiving should be recorded")
+	}
+
+	// A changed preview image must be downloaded again
+	mergeAttachments(tweet, &domain.Tweet{Card: &domain.LinkCard{URL: "https://example.com", ImageURL: "https://img/2.jpg"}})
+	if tweet.Card.LocalImagePath != "" {
+		t.Errorf("local image kept for a different preview: %q", tweet.Card.LocalImagePath)
+	}
+
+	// Attachments missing from a re-fetch are kept
+	mergeAttachments(tweet, &domain.Tweet{})
+	if tweet.Poll == nil || tweet.Card == nil || tweet.CommunityNote == nil {
+		t.Error("attachments should survive a re-fetch that omits them")
+	}
+}
@@ -0,0 +1,313 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/iconidentify/xgrabba/internal/domain"
+	"github.com/iconidentify/xgrabba/pkg/ffmpeg"
+	"github.com/iconidentify/xgrabba/pkg/grok"
+)
+
+var (
+	// ErrNoTimedTranscript is returned when chapters are requested for media
+	// without a timed transcript.
+	ErrNoTimedTranscript = errors.New("media has no timed transcript")
+
+	// ErrTooFewChapters is returned when the AI reply does not split the
+	// video into at least two usable chapters.
+	ErrTooFewChapters = errors.New("video could not be split into chapters")
+)
+
+// ChaptersFilename returns the WebVTT chapters track filename for a media item.
+func ChaptersFilename(mediaID string) string {
+	return mediaID + ".chapters.vtt"
+}
+
+// ChaptersVideoFilename returns the filename of the playback copy of a video
+// with its chapters embedded.
+func ChaptersVideoFilename(mediaID string) string {
+	return mediaID + ".chapters.mp4"
+}
+
+// mediaDuration returns the length of a video in seconds, from the duration
+// X reported or the end of the last transcript segment, whichever is later.
+func mediaDuration(m domain.Media) float64 {
+	duration := float64(m.Duration)
+	if n := len(m.TranscriptSegments); n > 0 && m.TranscriptSegments[n-1].End > duration {
+		duration = m.TranscriptSegments[n-1].End
+	}
+	return duration
+}
+
+// normalizeChapters turns AI chapter items into contiguous chapters: sorted,
+// without duplicate starts or starts past the end, with the first chapter at
+// the beginning and each ending where the next starts.
+func normalizeChapters(items []grok.ChapterItem, duration float64) []domain.Chapter {
+	sorted := append([]grok.ChapterItem{}, items...)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].Start < sorted[j].Start })
+
+	var chapters []domain.Chapter
+	for _, item := range sorted {
+		title := strings.TrimSpace(item.Title)
+		if title == "" || item.Start < 0 || (duration > 0 && item.Start >= duration) {
+			continue
+		}
+		if n := len(chapters); n > 0 && item.Start-chapters[n-1].Start < 1 {
+			continue
+		}
+		chapters = append(chapters, domain.Chapter{Start: item.Start, Title: title})
+	}
+	if len(chapters) == 0 {
+		return nil
+	}
+	chapters[0].Start = 0
+	for i := range chapters {
+		if i+1 < len(chapters) {
+			chapters[i].End = chapters[i+1].Start
+		} else {
+			chapters[i].End = duration
+		}
+	}
+	return chapters
+}
+
+// buildChaptersVTT renders chapters as a WebVTT chapters track.
+func buildChaptersVTT(chapters []domain.Chapter) string {
+	var sb strings.Builder
+	sb.WriteString("WEBVTT\n\n")
+	for i, ch := range chapters {
+		sb.WriteString(fmt.Sprintf("%d\n", i+1))
+		sb.WriteString(fmt.Sprintf("%s --> %s\n", formatSubtitleTimestamp(ch.Start, "."), formatSubtitleTimestamp(ch.End, ".")))
+		sb.WriteString(strings.ReplaceAll(ch.Title, "-->", "->"))
+		sb.WriteString("\n\n")
+	}
+	return sb.String()
+}
+
+// markdownChapters renders chapters as a nested list under a video bullet in
+// the Markdown summary.
+func markdownChapters(chapters []domain.Chapter) string {
+	var sb strings.Builder
+	for _, ch := range chapters {
+		sb.WriteString(fmt.Sprintf("  - `%s` %s\n", formatTimestamp(ch.Start), ch.Title))
+	}
+	return sb.String()
+}
+
+// writeChaptersFile writes {mediaID}.chapters.vtt next to the video.
+func writeChaptersFile(archivePath string, media *domain.Media) error {
+	mediaDir := filepath.Join(archivePath, "media")
+	if err := os.MkdirAll(mediaDir, 0755); err != nil {
+		return fmt.Errorf("create media directory: %w", err)
+	}
+	path := filepath.Join(mediaDir, ChaptersFilename(media.ID))
+	if err := os.WriteFile(path, []byte(buildChaptersVTT(media.Chapters)), 0644); err != nil {
+		return fmt.Errorf("write chapters vtt: %w", err)
+	}
+	return nil
+}
+
+// removeChaptersFile deletes the chapters track and playback copy for a media
+// item, ignoring missing files.
+func removeChaptersFile(archivePath string, media *domain.Media) {
+	_ = os.Remove(filepath.Join(archivePath, "media", ChaptersFilename(media.ID)))
+	_ = os.Remove(filepath.Join(archivePath, "media", ChaptersVideoFilename(media.ID)))
+}
+
+// GenerateChapters splits a transcribed video into titled chapters. The
+// chapters are stored on the media item, written as a WebVTT chapters track
+// and embedded in a playback copy of the downloaded video.
+func (s *TweetService) GenerateChapters(ctx context.Context, tweetID domain.TweetID, mediaIndex int) ([]domain.Chapter, error) {
+	s.tweetsMu.RLock()
+	tweet, ok := s.tweets[tweetID]
+	if !ok {
+		s.tweetsMu.RUnlock()
+		return nil, domain.ErrVideoNotFound
+	}
+	if mediaIndex < 0 || mediaIndex >= len(tweet.Media) {
+		s.tweetsMu.RUnlock()
+		return nil, fmt.Errorf("invalid media index: %d", mediaIndex)
+	}
+	media := tweet.Media[mediaIndex]
+	s.tweetsMu.RUnlock()
+
+	if len(media.TranscriptSegments) == 0 {
+		return nil, ErrNoTimedTranscript
+	}
+	if err := s.aiBudgetExceeded(); err != nil {
+		return nil, err
+	}
+
+	duration := mediaDuration(media)
+	resp, err := s.grokClient.GenerateChapters(withUsageTweet(ctx, tweetID), grok.ChaptersRequest{
+		Transcript: timedTranscript(media),
+		Duration:   formatTimestamp(duration),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("generate chapters: %w", err)
+	}
+	chapters := normalizeChapters(resp.Chapters, duration)
+	if len(chapters) < 2 {
+		return nil, ErrTooFewChapters
+	}
+
+	s.tweetsMu.Lock()
+	m := &tweet.Media[mediaIndex]
+	m.Chapters = chapters
+	m.ChaptersPromptVersion = s.promptSet().Version()
+	s.tweetsMu.Unlock()
+
+	if err := writeChaptersFile(tweet.ArchivePath, m); err != nil {
+		s.logger.Warn("failed to write chapters track", "tweet_id", tweetID, "media_id", media.ID, "error", err)
+	}
+	s.embedChapters(ctx, tweet, mediaIndex)
+
+	if err := s.saveTweetMetadata(tweet); err != nil {
+		return nil, fmt.Errorf("save tweet metadata: %w", err)
+	}
+	s.syncChapters(ctx, tweet, media)
+
+	s.logger.Info("chapters generated",
+		"tweet_id", tweetID,
+		"media_index", mediaIndex,
+		"chapters", len(chapters))
+
+	s.emitEvent(domain.EventSeverityInfo, domain.EventCategoryAI, "Video chapters generated",
+		domain.EventMetadata{
+			"tweet_id": string(tweetID),
+			"chapters": len(chapters),
+		})
+
+	return chapters, nil
+}
+
+// DeleteChapters removes the chapters of a media item, including the
+// chapters track and the playback copy of the video.
+func (s *TweetService) DeleteChapters(ctx context.Context, tweetID domain.TweetID, mediaIndex int) error {
+	s.tweetsMu.Lock()
+	tweet, ok := s.tweets[tweetID]
+	if !ok {
+		s.tweetsMu.Unlock()
+		return domain.ErrVideoNotFound
+	}
+	if mediaIndex < 0 || mediaIndex >= len(tweet.Media) {
+		s.tweetsMu.Unlock()
+		return fmt.Errorf("invalid media index: %d", mediaIndex)
+	}
+	m := &tweet.Media[mediaIndex]
+	hadChapters := len(m.Chapters) > 0
+	m.Chapters = nil
+	m.ChaptersPromptVersion = ""
+	m.ChaptersVideoPath = ""
+	removeChaptersFile(tweet.ArchivePath, m)
+	s.tweetsMu.Unlock()
+
+	if hadChapters {
+		s.deleteFileFromStorage(ctx, tweet, filepath.Join(tweet.ArchivePath, "media", ChaptersFilename(m.ID)))
+		s.deleteFileFromStorage(ctx, tweet, filepath.Join(tweet.ArchivePath, "media", ChaptersVideoFilename(m.ID)))
+	}
+
+	if err := s.saveTweetMetadata(tweet); err != nil {
+		return fmt.Errorf("save tweet metadata: %w", err)
+	}
+	s.syncChapters(ctx, tweet, *m)
+	s.logger.Info("chapters deleted", "tweet_id", tweetID, "media_index", mediaIndex)
+	return nil
+}
+
+// syncChapters records the checksums of the chapters track and playback copy of a
+// media item whose chapters changed and, once the tweet is archived, mirrors
+// them to remote storage. During archiving phase 3 does both when it
+// finishes.
+func (s *TweetService) syncChapters(ctx context.Context, tweet *domain.Tweet, m domain.Media) {
+	s.tweetsMu.RLock()
+	completed := tweet.Status == domain.ArchiveStatusCompleted
+	s.tweetsMu.RUnlock()
+	if !completed {
+		return
+	}
+	s.writeChecksums(tweet, "media/"+ChaptersFilename(m.ID), "media/"+ChaptersVideoFilename(m.ID))
+	if err := s.syncArchiveToStorage(ctx, tweet); err != nil {
+		s.logger.Warn("failed to mirror archive to storage", "tweet_id", tweet.ID, "error", err)
+	}
+}
+
+// embedChapters writes media/{mediaID}.chapters.mp4, a playback copy of the
+// downloaded video with the media item's current chapters as container
+// metadata. The original video, its hash and its blob store entry are left
+// untouched; a video evicted to remote storage is restored for the remux and
+// evicted again. Callers rewrite the archive checksums and mirror the
+// archive. Failures are only logged, since the chapters track still carries
+// the chapters.
+func (s *TweetService) embedChapters(ctx context.Context, tweet *domain.Tweet, mediaIndex int) {
+	if s.videoProcessor == nil {
+		return
+	}
+	s.tweetsMu.RLock()
+	m := tweet.Media[mediaIndex]
+	s.tweetsMu.RUnlock()
+	if m.LocalPath == "" || len(m.Chapters) == 0 {
+		return
+	}
+	logger := s.logger.With("tweet_id", tweet.ID, "media_id", m.ID)
+
+	restored := false
+	if _, err := os.Stat(m.LocalPath); os.IsNotExist(err) && s.remoteStorage() {
+		if err := s.restoreMediaFile(ctx, m.LocalPath); err != nil {
+			logger.Warn("failed to restore video for chapters", "error", err)
+			return
+		}
+		restored = true
+	}
+
+	chapters := make([]ffmpeg.Chapter, 0, len(m.Chapters))
+	for _, ch := range m.Chapters {
+		chapters = append(chapters, ffmpeg.Chapter{Start: ch.Start, End: ch.End, Title: ch.Title})
+	}
+	out := filepath.Join(tweet.ArchivePath, "media", ChaptersVideoFilename(m.ID))
+	err := s.videoProcessor.EmbedChapters(ctx, m.LocalPath, out, chapters)
+	if restored && s.cfg.EvictLocalMedia {
+		_ = os.Remove(m.LocalPath)
+	}
+	if err != nil {
+		logger.Warn("failed to embed chapters in video", "error", err)
+		return
+	}
+
+	s.tweetsMu.Lock()
+	if mediaIndex < len(tweet.Media) {
+		tweet.Media[mediaIndex].ChaptersVideoPath = out
+	}
+	s.tweetsMu.Unlock()
+}
+
+// runChapters splits each transcribed video of a tweet being archived that
+// is at least AIConfig.ChaptersMinDuration long into chapters. The caller
+// saves the tweet.
+func (s *TweetService) runChapters(ctx context.Context, tweet *domain.Tweet) {
+	minDuration := s.aiCfg.ChaptersMinDuration.Seconds()
+	if minDuration <= 0 {
+		return
+	}
+	for i := range tweet.Media {
+		s.tweetsMu.RLock()
+		m := tweet.Media[i]
+		s.tweetsMu.RUnlock()
+		if m.Type != domain.MediaTypeVideo || len(m.TranscriptSegments) == 0 || len(m.Chapters) > 0 || mediaDuration(m) < minDuration {
+			continue
+		}
+		if _, err := s.GenerateChapters(ctx, tweet.ID, i); err != nil {
+			if errors.Is(err, domain.ErrAIBudgetExceeded) {
+				s.logger.Warn("chapters paused", "tweet_id", tweet.ID, "error", err)
+				return
+			}
+			s.logger.Warn("chapter generation failed", "tweet_id", tweet.ID, "media_id", m.ID, "error", err)
+		}
+	}
+}
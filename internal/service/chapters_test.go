@@ -0,0 +1,178 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/iconidentify/xgrabba/internal/domain"
+	"github.com/iconidentify/xgrabba/internal/storage"
+	"github.com/iconidentify/xgrabba/pkg/grok"
+)
+
+func TestNormalizeChapters(t *testing.T) {
+	items := []grok.ChapterItem{
+		{Start: 300, Title: "Wrap up"},
+		{Start: 5, Title: " Intro "},
+		{Start: 120, Title: "Cats"},
+		{Start: 120.4, Title: "Cats again"},
+		{Start: 200, Title: ""},
+		{Start: 900, Title: "Past the end"},
+	}
+	got := normalizeChapters(items, 600)
+	want := []domain.Chapter{
+		{Start: 0, End: 120, Title: "Intro"},
+		{Start: 120, End: 300, Title: "Cats"},
+		{Start: 300, End: 600, Title: "Wrap up"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("normalizeChapters = %+v, want %+v", got, want)
+	}
+	if got := normalizeChapters(nil, 600); got != nil {
+		t.Errorf("no items = %+v, want nil", got)
+	}
+}
+
+func TestBuildChaptersVTT(t *testing.T) {
+	got := buildChaptersVTT([]domain.Chapter{
+		{Start: 0, End: 61.5, Title: "Intro --> setup"},
+		{Start: 61.5, End: 3725, Title: "Cats"},
+	})
+	want := "WEBVTT\n\n" +
+		"1\n00:00:00.000 --> 00:01:01.500\nIntro -> setup\n\n" +
+		"2\n00:01:01.500 --> 01:02:05.000\nCats\n\n"
+	if got != want {
+		t.Errorf("buildChaptersVTT = %q, want %q", got, want)
+	}
+}
+
+func TestGenerateChapters_StoresChapters(t *testing.T) {
+	svc, tweet, _ := newDocumentTestService(t)
+
+	chapters, err := svc.GenerateChapters(context.Background(), tweet.ID, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []domain.Chapter{
+		{Start: 0, End: 60, Title: "Intro"},
+		{Start: 60, End: 78, Title: "Cats"},
+	}
+	if !reflect.DeepEqual(chapters, want) || !reflect.DeepEqual(tweet.Media[0].Chapters, want) {
+		t.Errorf("chapters = %+v, stored %+v", chapters, tweet.Media[0].Chapters)
+	}
+	if tweet.Media[0].ChaptersPromptVersion == "" {
+		t.Error("prompt version not recorded")
+	}
+
+	vtt, err := os.ReadFile(filepath.Join(tweet.ArchivePath, "media", ChaptersFilename("m1")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(vtt), "00:01:00.000 --> 00:01:18.000\nCats") {
+		t.Errorf("chapters track = %q", vtt)
+	}
+	if md := buildMarkdownSummary(tweet); !strings.Contains(md, "  - `01:00` Cats\n") {
+		t.Errorf("markdown summary missing chapters:\n%s", md)
+	}
+
+	if err := svc.DeleteChapters(context.Background(), tweet.ID, 0); err != nil {
+		t.Fatal(err)
+	}
+	if tweet.Media[0].Chapters != nil {
+		t.Errorf("chapters not cleared: %+v", tweet.Media[0].Chapters)
+	}
+	if _, err := os.Stat(filepath.Join(tweet.ArchivePath, "media", ChaptersFilename("m1"))); !os.IsNotExist(err) {
+		t.Error("chapters track should be removed")
+	}
+}
+
+func TestGenerateChapters_NeedsTimedTranscript(t *testing.T) {
+	svc, tweet, _ := newDocumentTestService(t)
+	tweet.Media[0].TranscriptSegments = nil
+
+	if _, err := svc.GenerateChapters(context.Background(), tweet.ID, 0); !errors.Is(err, ErrNoTimedTranscript) {
+		t.Errorf("err = %v, want ErrNoTimedTranscript", err)
+	}
+	if _, err := svc.GenerateChapters(context.Background(), tweet.ID, 5); err == nil {
+		t.Error("invalid media index should fail")
+	}
+}
+
+func TestRunChapters_MinDuration(t *testing.T) {
+	svc, tweet, _ := newDocumentTestService(t)
+
+	svc.aiCfg.ChaptersMinDuration = 2 * time.Minute
+	svc.runChapters(context.Background(), tweet)
+	if len(tweet.Media[0].Chapters) != 0 {
+		t.Error("video shorter than the minimum should not get chapters")
+	}
+
+	svc.aiCfg.ChaptersMinDuration = time.Minute
+	svc.runChapters(context.Background(), tweet)
+	if len(tweet.Media[0].Chapters) != 2 {
+		t.Errorf("chapters = %+v, want 2", tweet.Media[0].Chapters)
+	}
+}
+
+func TestGenerateChapters_MirrorsToStorage(t *testing.T) {
+	svc, tweet, _ := newDocumentTestService(t)
+	remote := remoteDir{storage.NewLocal(t.TempDir())}
+	svc.store = remote
+	ctx := context.Background()
+	key := "2024/01/user_2024-01-02_1/media/" + ChaptersFilename("m1")
+
+	if _, err := svc.GenerateChapters(ctx, tweet.ID, 0); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := remote.Stat(ctx, key); err != nil {
+		t.Errorf("chapters track not mirrored: %v", err)
+	}
+
+	if err := svc.DeleteChapters(ctx, tweet.ID, 0); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := remote.Stat(ctx, key); !errors.Is(err, storage.ErrNotFound) {
+		t.Errorf("mirrored chapters track not removed: %v", err)
+	}
+}
+
+func TestDeleteChapters_RemovesPlaybackCopy(t *testing.T) {
+	svc, tweet, _ := newDocumentTestService(t)
+	ctx := context.Background()
+	original, err := os.ReadFile(tweet.Media[0].LocalPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := svc.GenerateChapters(ctx, tweet.ID, 0); err != nil {
+		t.Fatal(err)
+	}
+	// ffmpeg is not available in tests, so stand in for the remuxed copy
+	playback := filepath.Join(tweet.ArchivePath, "media", ChaptersVideoFilename("m1"))
+	if err := os.WriteFile(playback, []byte("video with chapters"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	tweet.Media[0].ChaptersVideoPath = playback
+
+	if err := svc.DeleteChapters(ctx, tweet.ID, 0); err != nil {
+		t.Fatal(err)
+	}
+	if tweet.Media[0].ChaptersVideoPath != "" {
+		t.Errorf("playback path not cleared: %q", tweet.Media[0].ChaptersVideoPath)
+	}
+	if _, err := os.Stat(playback); !os.IsNotExist(err) {
+		t.Error("playback copy should be removed")
+	}
+	got, err := os.ReadFile(tweet.Media[0].LocalPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != string(original) {
+		t.Error("original video should be left untouched")
+	}
+}
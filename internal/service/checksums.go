@@ -0,0 +1,348 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/iconidentify/xgrabba/internal/blobstore"
+	"github.com/iconidentify/xgrabba/internal/domain"
+)
+
+// ChecksumsFilename is the per-archive integrity manifest.
+const ChecksumsFilename = "checksums.json"
+
+// Integrity issue kinds reported by verifyChecksums.
+const (
+	IntegrityMissing  = "missing"  // File listed in the manifest no longer exists
+	IntegritySize     = "size"     // File size differs from the manifest
+	IntegrityChecksum = "checksum" // File contents differ from the manifest
+)
+
+// ChecksumManifest records the size and SHA-256 of every file in an archive
+// at the time it completed. tweet.json, README.md and metrics.jsonl are
+// excluded because they are rewritten after completion.
+type ChecksumManifest struct {
+	Version     int                      `json:"version"`
+	Algorithm   string                   `json:"algorithm"`
+	GeneratedAt time.Time                `json:"generated_at"`
+	Files       map[string]ChecksumEntry `json:"files"` // Keyed by slash path relative to the archive
+}
+
+// ChecksumEntry is one file in a ChecksumManifest.
+type ChecksumEntry struct {
+	Size   int64  `json:"size"`
+	SHA256 string `json:"sha256"`
+}
+
+// IntegrityIssue describes one file that failed verification.
+type IntegrityIssue struct {
+	Path     string `json:"path"` // Relative to the archive directory
+	Kind     string `json:"kind"`
+	Expected string `json:"expected,omitempty"`
+	Actual   string `json:"actual,omitempty"`
+}
+
+// buildChecksumManifest hashes every file in archivePath.
+func buildChecksumManifest(archivePath string) (*ChecksumManifest, error) {
+	manifest := &ChecksumManifest{
+		Version:   1,
+		Algorithm: "sha256",
+		Files:     make(map[string]ChecksumEntry),
+	}
+	if err := addChecksums(archivePath, manifest, nil); err != nil {
+		return nil, err
+	}
+	return manifest, nil
+}
+
+// addChecksums hashes the files in archivePath that manifest does not list
+// yet, plus those in rehash (slash paths relative to the archive), and
+// stamps the manifest.
+func addChecksums(archivePath string, manifest *ChecksumManifest, rehash map[string]bool) error {
+	err := filepath.Walk(archivePath, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return nil
+		}
+		if info.IsDir() {
+			if info.Name() == "temp_processing" {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		rel, err := filepath.Rel(archivePath, path)
+		if err != nil {
+			return nil
+		}
+		rel = filepath.ToSlash(rel)
+		if !checksummed(rel) {
+			return nil
+		}
+		if _, listed := manifest.Files[rel]; listed && !rehash[rel] {
+			return nil
+		}
+
+		sum, err := blobstore.FileSHA256(path)
+		if err != nil {
+			return fmt.Errorf("hash %s: %w", rel, err)
+		}
+		manifest.Files[rel] = ChecksumEntry{Size: info.Size(), SHA256: sum}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	manifest.GeneratedAt = time.Now().UTC()
+	return nil
+}
+
+// archiveRel returns path relative to archivePath as a slash path, the form
+// manifest entries are keyed by.
+func archiveRel(archivePath, path string) string {
+	rel, err := filepath.Rel(archivePath, path)
+	if err != nil {
+		return filepath.ToSlash(path)
+	}
+	return filepath.ToSlash(rel)
+}
+
+// checksummed reports whether a relative archive path belongs in the manifest.
+func checksummed(rel string) bool {
+	switch rel {
+	case "tweet.json", "README.md", ChecksumsFilename, MetricsHistoryFilename:
+		return false
+	}
+	return !isPartialFile(filepath.Base(rel))
+}
+
+// writeChecksumManifest saves checksums.json for an archive. A new manifest
+// hashes every file. An existing one keeps the entries it has, so media
+// damaged since the archive completed is still caught by the scrub and media
+// evicted to remote storage stays listed; only files not listed yet and the
+// rewritten ones (slash paths relative to the archive) are hashed, and
+// rewritten files that no longer exist are dropped.
+func writeChecksumManifest(archivePath string, rewritten ...string) error {
+	manifest, err := readChecksumManifest(archivePath)
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+		manifest, err = buildChecksumManifest(archivePath)
+		if err != nil {
+			return err
+		}
+	case err != nil:
+		return err
+	default:
+		rehash := make(map[string]bool, len(rewritten))
+		for _, rel := range rewritten {
+			if _, err := os.Stat(filepath.Join(archivePath, filepath.FromSlash(rel))); err != nil {
+				delete(manifest.Files, rel)
+				continue
+			}
+			rehash[rel] = true
+		}
+		if manifest.Files == nil {
+			manifest.Files = make(map[string]ChecksumEntry)
+		}
+		if err := addChecksums(archivePath, manifest, rehash); err != nil {
+			return err
+		}
+	}
+	data, err := jsonMarshalIndent(manifest)
+	if err != nil {
+		return fmt.Errorf("marshal checksums: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(archivePath, ChecksumsFilename), data, 0644); err != nil {
+		return fmt.Errorf("write checksums: %w", err)
+	}
+	return nil
+}
+
+// readChecksumManifest loads checksums.json. It returns os.ErrNotExist (wrapped)
+// for archives completed before manifests existed.
+func readChecksumManifest(archivePath string) (*ChecksumManifest, error) {
+	data, err := os.ReadFile(filepath.Join(archivePath, ChecksumsFilename))
+	if err != nil {
+		return nil, err
+	}
+	var manifest ChecksumManifest
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return nil, fmt.Errorf("parse checksums: %w", err)
+	}
+	return &manifest, nil
+}
+
+// verifyChecksums re-hashes the files listed in manifest. remoteSize, when
+// non-nil, is consulted for files missing locally (e.g. media evicted to a
+// remote backend); a matching remote size counts as intact.
+func verifyChecksums(archivePath string, manifest *ChecksumManifest, remoteSize func(path string) int64) []IntegrityIssue {
+	paths := make([]string, 0, len(manifest.Files))
+	for rel := range manifest.Files {
+		paths = append(paths, rel)
+	}
+	sort.Strings(paths)
+
+	var issues []IntegrityIssue
+	for _, rel := range paths {
+		want := manifest.Files[rel]
+		path := filepath.Join(archivePath, filepath.FromSlash(rel))
+
+		info, err := os.Stat(path)
+		if err != nil {
+			if remoteSize != nil && remoteSize(path) == want.Size {
+				continue
+			}
+			issues = append(issues, IntegrityIssue{Path: rel, Kind: IntegrityMissing})
+			continue
+		}
+		if info.Size() != want.Size {
+			issues = append(issues, IntegrityIssue{
+				Path:     rel,
+				Kind:     IntegritySize,
+				Expected: fmt.Sprintf("%d", want.Size),
+				Actual:   fmt.Sprintf("%d", info.Size()),
+			})
+			continue
+		}
+		sum, err := blobstore.FileSHA256(path)
+		if err != nil {
+			issues = append(issues, IntegrityIssue{Path: rel, Kind: IntegrityMissing, Actual: err.Error()})
+			continue
+		}
+		if sum != want.SHA256 {
+			issues = append(issues, IntegrityIssue{Path: rel, Kind: IntegrityChecksum, Expected: want.SHA256, Actual: sum})
+		}
+	}
+	return issues
+}
+
+// writeChecksums saves the integrity manifest for a finished archive, adding
+// new files and re-hashing the rewritten ones; see writeChecksumManifest.
+// Failures are logged; a missing manifest is backfilled by the next scrub.
+func (s *TweetService) writeChecksums(tweet *domain.Tweet, rewritten ...string) {
+	if tweet.ArchivePath == "" {
+		return
+	}
+	if err := writeChecksumManifest(tweet.ArchivePath, rewritten...); err != nil {
+		s.logger.Warn("failed to write checksums", "tweet_id", tweet.ID, "error", err)
+	}
+}
+
+// MarkCorruptMedia flags media whose files failed verification so the next
+// Resync re-downloads them. relPaths are relative to the archive directory.
+// A damaged avatar or link card image is removed so Resync fetches it again.
+// It returns the IDs of the flagged media.
+func (s *TweetService) MarkCorruptMedia(tweetID domain.TweetID, relPaths []string) ([]string, error) {
+	s.tweetsMu.Lock()
+	tweet, ok := s.tweets[tweetID]
+	if !ok {
+		s.tweetsMu.Unlock()
+		return nil, domain.ErrVideoNotFound
+	}
+
+	damaged := make(map[string]bool, len(relPaths))
+	cardDamaged := false
+	for _, rel := range relPaths {
+		damaged[filepath.Base(filepath.FromSlash(rel))] = true
+		if rel == "avatar.jpg" {
+			os.Remove(filepath.Join(tweet.ArchivePath, "avatar.jpg"))
+		}
+		if rel == "media/"+CardImageFilename && tweet.Card != nil && tweet.Card.LocalImagePath != "" {
+			os.Remove(tweet.Card.LocalImagePath)
+			tweet.Card.LocalImagePath = ""
+			cardDamaged = true
+		}
+	}
+
+	var flagged []string
+	for i := range tweet.Media {
+		m := &tweet.Media[i]
+		if m.LocalPath == "" {
+			continue
+		}
+		// A damaged thumbnail is repaired by re-downloading its media item
+		if !damaged[filepath.Base(m.LocalPath)] && !damaged[m.ID+"_thumb.jpg"] {
+			continue
+		}
+		m.Corrupt = true
+		m.Downloaded = false
+		flagged = append(flagged, m.ID)
+	}
+	s.tweetsMu.Unlock()
+
+	if len(flagged) > 0 || cardDamaged {
+		if err := s.saveTweetMetadata(tweet); err != nil {
+			return flagged, fmt.Errorf("save metadata: %w", err)
+		}
+	}
+	return flagged, nil
+}
+
+// repairCorruptMedia re-downloads media flagged by the integrity scrubber,
+// using fresh URLs from fetched when available. refetched lists archive files
+// Resync already downloaded again (avatar, link card image); their checksums
+// are replaced along with the repaired media's. It returns the number
+// repaired.
+func (s *TweetService) repairCorruptMedia(ctx context.Context, tweet, fetched *domain.Tweet, refetched ...string) int {
+	fresh := make(map[string]domain.Media)
+	if fetched != nil {
+		for _, m := range fetched.Media {
+			fresh[m.ID] = m
+		}
+	}
+
+	repaired := 0
+	rewritten := refetched
+	for i := range tweet.Media {
+		m := &tweet.Media[i]
+		if !m.Corrupt {
+			continue
+		}
+		if f, ok := fresh[m.ID]; ok {
+			if f.URL != "" {
+				m.URL = f.URL
+			}
+			if f.PreviewURL != "" {
+				m.PreviewURL = f.PreviewURL
+			}
+			if len(f.Variants) > 0 {
+				m.Variants = f.Variants
+			}
+		}
+		if err := s.downloadMediaWithoutAnalysis(ctx, m, tweet.ArchivePath, s.mediaQualityFor(tweet)); err != nil {
+			s.logger.Warn("failed to re-download corrupt media", "tweet_id", tweet.ID, "media_id", m.ID, "error", err)
+			continue
+		}
+		m.Corrupt = false
+		repaired++
+		rewritten = append(rewritten, archiveRel(tweet.ArchivePath, m.LocalPath), "media/"+m.ID+"_thumb.jpg")
+		for _, v := range m.Variants {
+			if v.LocalPath != "" && v.LocalPath != m.LocalPath {
+				rewritten = append(rewritten, archiveRel(tweet.ArchivePath, v.LocalPath))
+			}
+		}
+	}
+	if len(rewritten) == 0 {
+		return 0
+	}
+
+	if repaired > 0 {
+		s.computeMediaHashes(ctx, tweet, false)
+		s.ingestMediaBlobs(tweet)
+	}
+	s.writeChecksums(tweet, rewritten...)
+	if err := s.syncArchiveToStorage(ctx, tweet); err != nil {
+		s.logger.Warn("failed to mirror repaired media", "tweet_id", tweet.ID, "error", err)
+	}
+	if repaired == 0 {
+		return 0
+	}
+	s.emitEvent(domain.EventSeveritySuccess, domain.EventCategoryDisk,
+		fmt.Sprintf("Repaired %d damaged media file(s)", repaired),
+		domain.EventMetadata{"tweet_id": string(tweet.ID), "repaired": fmt.Sprintf("%d", repaired)})
+	return repaired
+}
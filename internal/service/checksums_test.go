@@ -0,0 +1,252 @@
+package service
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/iconidentify/xgrabba/internal/config"
+	"github.com/iconidentify/xgrabba/internal/domain"
+)
+
+func newIntegrityTestService(t *testing.T) (*TweetService, *domain.Tweet) {
+	t.Helper()
+	base := t.TempDir()
+	archive := filepath.Join(base, "2024", "01", "user_2024-01-02_1")
+	mediaDir := filepath.Join(archive, "media")
+	if err := os.MkdirAll(mediaDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	os.WriteFile(filepath.Join(mediaDir, "m1.mp4"), []byte("video-bytes"), 0644)
+	os.WriteFile(filepath.Join(mediaDir, "m1_thumb.jpg"), []byte("thumb"), 0644)
+	os.WriteFile(filepath.Join(mediaDir, "m2.jpg"), []byte("image-bytes"), 0644)
+	os.WriteFile(filepath.Join(mediaDir, "m2.jpg.tmp"), []byte("partial"), 0644)
+
+	tweet := &domain.Tweet{
+		ID:          "1",
+		Status:      domain.ArchiveStatusCompleted,
+		ArchivePath: archive,
+		Media: []domain.Media{
+			{ID: "m1", Type: domain.MediaTypeVideo, LocalPath: filepath.Join(mediaDir, "m1.mp4"), Downloaded: true},
+			{ID: "m2", Type: domain.MediaTypeImage, LocalPath: filepath.Join(mediaDir, "m2.jpg"), Downloaded: true},
+		},
+	}
+	svc := &TweetService{
+		cfg:    config.StorageConfig{BasePath: base},
+		logger: slog.New(slog.NewTextHandler(io.Discard, nil)),
+		tweets: map[domain.TweetID]*domain.Tweet{tweet.ID: tweet},
+	}
+	if err := svc.saveTweetMetadata(tweet); err != nil {
+		t.Fatal(err)
+	}
+	return svc, tweet
+}
+
+func TestChecksumManifest_WriteAndVerify(t *testing.T) {
+	_, tweet := newIntegrityTestService(t)
+
+	if err := writeChecksumManifest(tweet.ArchivePath); err != nil {
+		t.Fatal(err)
+	}
+	manifest, err := readChecksumManifest(tweet.ArchivePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"media/m1.mp4", "media/m1_thumb.jpg", "media/m2.jpg"} {
+		if _, ok := manifest.Files[want]; !ok {
+			t.Errorf("manifest missing %s", want)
+		}
+	}
+	for _, skip := range []string{"tweet.json", "README.md", "media/m2.jpg.tmp"} {
+		if _, ok := manifest.Files[skip]; ok {
+			t.Errorf("manifest should not include %s", skip)
+		}
+	}
+	if issues := verifyChecksums(tweet.ArchivePath, manifest, nil); len(issues) != 0 {
+		t.Fatalf("fresh archive has issues: %+v", issues)
+	}
+
+	// Same size, different content; truncated; removed
+	os.WriteFile(filepath.Join(tweet.ArchivePath, "media", "m1.mp4"), []byte("VIDEO-BYTES"), 0644)
+	os.WriteFile(filepath.Join(tweet.ArchivePath, "media", "m2.jpg"), []byte("image"), 0644)
+	os.Remove(filepath.Join(tweet.ArchivePath, "media", "m1_thumb.jpg"))
+
+	issues := verifyChecksums(tweet.ArchivePath, manifest, nil)
+	got := map[string]string{}
+	for _, issue := range issues {
+		got[issue.Path] = issue.Kind
+	}
+	want := map[string]string{
+		"media/m1.mp4":       IntegrityChecksum,
+		"media/m2.jpg":       IntegritySize,
+		"media/m1_thumb.jpg": IntegrityMissing,
+	}
+	for path, kind := range want {
+		if got[path] != kind {
+			t.Errorf("%s: kind = %q, want %q", path, got[path], kind)
+		}
+	}
+
+	// A remote copy of the right size counts as intact
+	issues = verifyChecksums(tweet.ArchivePath, manifest, func(path string) int64 {
+		return manifest.Files["media/m1_thumb.jpg"].Size
+	})
+	for _, issue := range issues {
+		if issue.Path == "media/m1_thumb.jpg" {
+			t.Error("evicted file with matching remote size reported as damaged")
+		}
+	}
+}
+
+func TestChecksumManifest_UpdateKeepsListedFiles(t *testing.T) {
+	_, tweet := newIntegrityTestService(t)
+	if err := writeChecksumManifest(tweet.ArchivePath); err != nil {
+		t.Fatal(err)
+	}
+	original, err := readChecksumManifest(tweet.ArchivePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// m1 is damaged after completion and m2 evicted; an essay is written and
+	// the thumbnail rewritten
+	media := filepath.Join(tweet.ArchivePath, "media")
+	os.WriteFile(filepath.Join(media, "m1.mp4"), []byte("VIDEO-BYTES"), 0644)
+	os.Remove(filepath.Join(media, "m2.jpg"))
+	os.WriteFile(filepath.Join(tweet.ArchivePath, "essay_0.md"), []byte("# Essay"), 0644)
+	os.WriteFile(filepath.Join(media, "m1_thumb.jpg"), []byte("new thumb"), 0644)
+
+	if err := writeChecksumManifest(tweet.ArchivePath, "essay_0.md", "media/m1_thumb.jpg"); err != nil {
+		t.Fatal(err)
+	}
+	manifest, err := readChecksumManifest(tweet.ArchivePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if manifest.Files["media/m1.mp4"] != original.Files["media/m1.mp4"] {
+		t.Error("damaged media was re-hashed")
+	}
+	if manifest.Files["media/m2.jpg"] != original.Files["media/m2.jpg"] {
+		t.Error("evicted media left the manifest")
+	}
+	if manifest.Files["media/m1_thumb.jpg"].Size != int64(len("new thumb")) {
+		t.Errorf("rewritten thumbnail = %+v", manifest.Files["media/m1_thumb.jpg"])
+	}
+	if _, ok := manifest.Files["essay_0.md"]; !ok {
+		t.Error("new essay not added")
+	}
+	issues := verifyChecksums(tweet.ArchivePath, manifest, nil)
+	if len(issues) != 2 || issues[0].Path != "media/m1.mp4" || issues[1].Path != "media/m2.jpg" {
+		t.Errorf("issues = %+v, want the damaged and evicted media", issues)
+	}
+
+	// A removed file named as rewritten leaves the manifest
+	os.Remove(filepath.Join(tweet.ArchivePath, "essay_0.md"))
+	if err := writeChecksumManifest(tweet.ArchivePath, "essay_0.md"); err != nil {
+		t.Fatal(err)
+	}
+	manifest, _ = readChecksumManifest(tweet.ArchivePath)
+	if _, ok := manifest.Files["essay_0.md"]; ok {
+		t.Error("deleted essay still listed")
+	}
+}
+
+func TestMarkCorruptMedia(t *testing.T) {
+	svc, tweet := newIntegrityTestService(t)
+	os.WriteFile(filepath.Join(tweet.ArchivePath, "avatar.jpg"), []byte("avatar"), 0644)
+
+	flagged, err := svc.MarkCorruptMedia(tweet.ID, []string{"media/m1_thumb.jpg", "avatar.jpg"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(flagged) != 1 || flagged[0] != "m1" {
+		t.Fatalf("flagged = %v, want [m1]", flagged)
+	}
+	if !tweet.Media[0].Corrupt || tweet.Media[0].Downloaded {
+		t.Error("m1 should be corrupt and not downloaded")
+	}
+	if tweet.Media[1].Corrupt {
+		t.Error("m2 should not be flagged")
+	}
+	if _, err := os.Stat(filepath.Join(tweet.ArchivePath, "avatar.jpg")); !os.IsNotExist(err) {
+		t.Error("damaged avatar should be removed")
+	}
+
+	if _, err := svc.MarkCorruptMedia("missing", nil); err == nil {
+		t.Error("expected error for unknown tweet")
+	}
+}
+
+func TestIntegrityService_Scrub(t *testing.T) {
+	svc, tweet := newIntegrityTestService(t)
+	integrity := NewIntegrityService(svc, config.IntegrityConfig{}, svc.logger, nil)
+	ctx := context.Background()
+
+	// First pass backfills the missing manifest
+	report, err := integrity.Scrub(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Archives != 1 || report.ManifestsCreated != 1 || len(report.Damaged) != 0 {
+		t.Fatalf("first scrub = %+v", report)
+	}
+
+	// Second pass verifies and catches a truncated file
+	os.WriteFile(filepath.Join(tweet.ArchivePath, "media", "m2.jpg"), []byte("im"), 0644)
+	report, err = integrity.Scrub(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.FilesVerified != 3 {
+		t.Errorf("files verified = %d, want 3", report.FilesVerified)
+	}
+	if len(report.Damaged) != 1 {
+		t.Fatalf("damaged = %+v, want 1 archive", report.Damaged)
+	}
+	damaged := report.Damaged[0]
+	if len(damaged.CorruptMedia) != 1 || damaged.CorruptMedia[0] != "m2" {
+		t.Errorf("corrupt media = %v, want [m2]", damaged.CorruptMedia)
+	}
+	if damaged.RepairQueued {
+		t.Error("repair should not be queued with auto-repair disabled")
+	}
+	if integrity.LastReport() != report {
+		t.Error("last report not recorded")
+	}
+}
+
+func TestIntegrityService_DerivedFiles(t *testing.T) {
+	svc, tweet := newIntegrityTestService(t)
+	integrity := NewIntegrityService(svc, config.IntegrityConfig{AutoRepair: true}, svc.logger, nil)
+	ctx := context.Background()
+
+	os.WriteFile(filepath.Join(tweet.ArchivePath, "essay_0.md"), []byte("# Essay"), 0644)
+	svc.writeChecksums(tweet)
+
+	// Deleting an essay rewrites the manifest, so the next scrub is clean
+	if err := svc.DeleteEssay(ctx, tweet.ID, 0); err != nil {
+		t.Fatal(err)
+	}
+	report, err := integrity.Scrub(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(report.Damaged) != 0 {
+		t.Fatalf("damaged after essay delete = %+v", report.Damaged)
+	}
+
+	// Damage to a derived file is reported but cannot be repaired by Resync
+	os.WriteFile(filepath.Join(tweet.ArchivePath, "essay_0.md"), []byte("# Essay"), 0644)
+	svc.writeChecksums(tweet)
+	os.WriteFile(filepath.Join(tweet.ArchivePath, "essay_0.md"), []byte("# Edited"), 0644)
+	report, err = integrity.Scrub(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(report.Damaged) != 1 || report.Damaged[0].RepairQueued {
+		t.Errorf("damaged = %+v, want 1 archive without a repair queued", report.Damaged)
+	}
+}
@@ -0,0 +1,558 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/iconidentify/xgrabba/internal/domain"
+	"github.com/iconidentify/xgrabba/pkg/grok"
+)
+
+var (
+	// ErrUnknownDocumentType is returned for a document type that is not one
+	// of domain.DocumentTypes.
+	ErrUnknownDocumentType = errors.New("unknown document type")
+
+	// ErrNothingToDerive is returned when the source tweets have no text or
+	// transcript to generate a document from.
+	ErrNothingToDerive = errors.New("no text or transcript to generate a document from")
+
+	// ErrNotAThread is returned when a thread digest is requested for a tweet
+	// without archived replies or parents by the same author.
+	ErrNotAThread = errors.New("tweet is not part of an archived thread")
+
+	// ErrDocumentNotFound is returned when a document has not been generated.
+	ErrDocumentNotFound = errors.New("document not found")
+
+	// ErrDocumentInProgress is returned when the same document is already
+	// being generated.
+	ErrDocumentInProgress = errors.New("document generation already in progress")
+)
+
+// parseDocumentType checks that typ is a known document type.
+func parseDocumentType(typ string) (domain.DocumentType, error) {
+	t := domain.DocumentType(strings.ToLower(strings.TrimSpace(typ)))
+	if !t.Valid() {
+		return "", fmt.Errorf("%w: %q", ErrUnknownDocumentType, typ)
+	}
+	return t, nil
+}
+
+// documentFilename is the markdown copy of a document kept in the archive
+// folder, next to the essay_N.md files.
+func documentFilename(typ domain.DocumentType) string {
+	return fmt.Sprintf("document_%s.md", typ)
+}
+
+// timedTranscript formats a transcript as "[mm:ss] text" lines so outlines
+// can cite where each chapter starts. Untimed transcripts are returned as is.
+func timedTranscript(m domain.Media) string {
+	if len(m.TranscriptSegments) == 0 {
+		return m.Transcript
+	}
+	lines := make([]string, 0, len(m.TranscriptSegments))
+	for _, seg := range m.TranscriptSegments {
+		lines = append(lines, fmt.Sprintf("[%s] %s", formatTimestamp(seg.Start), strings.TrimSpace(seg.Text)))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// documentSources converts tweets to document sources, leaving out tweets
+// with neither text nor a transcript.
+func documentSources(tweets []*domain.Tweet) ([]grok.DocumentSource, []domain.TweetID) {
+	var sources []grok.DocumentSource
+	var ids []domain.TweetID
+	for _, t := range tweets {
+		src := grok.DocumentSource{
+			Author: t.Author.Username,
+			Text:   strings.TrimSpace(t.Text),
+		}
+		if t.ArticleBody != "" {
+			src.Text = strings.TrimSpace(t.ArticleTitle + "\n\n" + t.ArticleBody)
+		}
+		if !t.PostedAt.IsZero() {
+			src.PostedAt = t.PostedAt.Format("2006-01-02")
+		}
+		var transcripts []string
+		for _, m := range t.Media {
+			if m.Transcript != "" {
+				transcripts = append(transcripts, timedTranscript(m))
+			}
+		}
+		src.Transcript = strings.Join(transcripts, "\n\n")
+		if src.Text == "" && src.Transcript == "" {
+			continue
+		}
+		sources = append(sources, src)
+		ids = append(ids, t.ID)
+	}
+	return sources, ids
+}
+
+// threadTweets returns the archived tweets of the thread tweet belongs to,
+// oldest first: the chain of replies by the same author. The caller must
+// hold tweetsMu.
+func (s *TweetService) threadTweets(tweet *domain.Tweet) []*domain.Tweet {
+	author := tweet.Author.Username
+	root := tweet
+	seen := map[domain.TweetID]bool{root.ID: true}
+	for root.ReplyTo != nil {
+		parent, ok := s.tweets[*root.ReplyTo]
+		if !ok || parent.Author.Username != author || seen[parent.ID] {
+			break
+		}
+		seen[parent.ID] = true
+		root = parent
+	}
+
+	replies := make(map[domain.TweetID][]*domain.Tweet)
+	for _, t := range s.tweets {
+		if t.ReplyTo != nil && t.Author.Username == author {
+			replies[*t.ReplyTo] = append(replies[*t.ReplyTo], t)
+		}
+	}
+	thread := []*domain.Tweet{root}
+	included := map[domain.TweetID]bool{root.ID: true}
+	for i := 0; i < len(thread); i++ {
+		for _, reply := range replies[thread[i].ID] {
+			if !included[reply.ID] {
+				included[reply.ID] = true
+				thread = append(thread, reply)
+			}
+		}
+	}
+	sort.SliceStable(thread, func(i, j int) bool {
+		return thread[i].PostedAt.Before(thread[j].PostedAt)
+	})
+	return thread
+}
+
+// documentRequest builds the request for a document of typ about a tweet:
+// the tweet alone, or its whole thread for digests.
+func (s *TweetService) documentRequest(tweetID domain.TweetID, typ domain.DocumentType) (grok.DocumentRequest, []domain.TweetID, error) {
+	s.tweetsMu.RLock()
+	defer s.tweetsMu.RUnlock()
+	tweet, ok := s.tweets[tweetID]
+	if !ok {
+		return grok.DocumentRequest{}, nil, domain.ErrVideoNotFound
+	}
+
+	tweets := []*domain.Tweet{tweet}
+	if typ == domain.DocumentDigest {
+		tweets = s.threadTweets(tweet)
+		if len(tweets) < 2 {
+			return grok.DocumentRequest{}, nil, ErrNotAThread
+		}
+	}
+	sources, ids := documentSources(tweets)
+	if len(sources) == 0 {
+		return grok.DocumentRequest{}, nil, ErrNothingToDerive
+	}
+	return grok.DocumentRequest{Type: string(typ), Sources: sources}, ids, nil
+}
+
+// collectionDocumentRequest builds a digest request for a named collection
+// of tweets, such as a playlist. Tweets that are no longer archived are
+// skipped.
+func (s *TweetService) collectionDocumentRequest(title string, tweetIDs []string) (grok.DocumentRequest, []domain.TweetID, error) {
+	s.tweetsMu.RLock()
+	tweets := make([]*domain.Tweet, 0, len(tweetIDs))
+	for _, id := range tweetIDs {
+		if t, ok := s.tweets[domain.TweetID(id)]; ok {
+			tweets = append(tweets, t)
+		}
+	}
+	sources, ids := documentSources(tweets)
+	s.tweetsMu.RUnlock()
+
+	if len(sources) == 0 {
+		return grok.DocumentRequest{}, nil, ErrNothingToDerive
+	}
+	return grok.DocumentRequest{Type: string(domain.DocumentDigest), Title: title, Sources: sources}, ids, nil
+}
+
+// deriveDocument asks the AI for a document and returns it as completed.
+func (s *TweetService) deriveDocument(ctx context.Context, req grok.DocumentRequest, sourceIDs []domain.TweetID) (domain.DerivedDocument, error) {
+	typ := domain.DocumentType(req.Type)
+	resp, err := s.grokClient.GenerateDocument(ctx, req)
+	if err != nil {
+		return domain.DerivedDocument{}, err
+	}
+	title := resp.Title
+	if title == "" {
+		title = typ.Label()
+	}
+	now := time.Now()
+	return domain.DerivedDocument{
+		Type:           typ,
+		Status:         domain.DocumentStatusCompleted,
+		Title:          title,
+		Content:        resp.Content,
+		WordCount:      resp.WordCount,
+		SourceTweetIDs: sourceIDs,
+		PromptVersion:  s.promptSet().Version(),
+		GeneratedAt:    &now,
+	}, nil
+}
+
+// markDocumentGenerating sets a tweet's document to generating, keeping any
+// earlier version visible until the new one replaces it.
+func (s *TweetService) markDocumentGenerating(tweetID domain.TweetID, typ domain.DocumentType) error {
+	s.tweetsMu.Lock()
+	tweet, ok := s.tweets[tweetID]
+	if !ok {
+		s.tweetsMu.Unlock()
+		return domain.ErrVideoNotFound
+	}
+	doc, ok := tweet.Documents[typ]
+	if ok && doc.Generating() {
+		s.tweetsMu.Unlock()
+		return ErrDocumentInProgress
+	}
+	doc.Type = typ
+	doc.Status = domain.DocumentStatusGenerating
+	doc.Error = ""
+	if tweet.Documents == nil {
+		tweet.Documents = make(map[domain.DocumentType]domain.DerivedDocument)
+	}
+	tweet.Documents[typ] = doc
+	s.tweetsMu.Unlock()
+
+	// Save intermediate state
+	_ = s.saveTweetMetadata(tweet)
+	return nil
+}
+
+// GenerateDocument generates a document of typ from a tweet, or from its
+// thread for digests, and stores it with the tweet, replacing any earlier
+// version. A markdown copy is written to the archive folder.
+func (s *TweetService) GenerateDocument(ctx context.Context, tweetID domain.TweetID, typ string) (*domain.DerivedDocument, error) {
+	t, err := parseDocumentType(typ)
+	if err != nil {
+		return nil, err
+	}
+	req, ids, err := s.documentRequest(tweetID, t)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.aiBudgetExceeded(); err != nil {
+		return nil, err
+	}
+	if err := s.markDocumentGenerating(tweetID, t); err != nil {
+		return nil, err
+	}
+	return s.finishDocument(ctx, tweetID, req, ids)
+}
+
+// StartGenerateDocument generates a document in the background. The
+// document's status is "generating" until it completes or fails.
+func (s *TweetService) StartGenerateDocument(tweetID domain.TweetID, typ string) error {
+	t, err := parseDocumentType(typ)
+	if err != nil {
+		return err
+	}
+	req, ids, err := s.documentRequest(tweetID, t)
+	if err != nil {
+		return err
+	}
+	if err := s.aiBudgetExceeded(); err != nil {
+		return err
+	}
+	if err := s.markDocumentGenerating(tweetID, t); err != nil {
+		return err
+	}
+
+	go func() {
+		ctx := context.Background()
+		if s.aiCfg.RegenerateTimeout > 0 {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, s.aiCfg.RegenerateTimeout)
+			defer cancel()
+		}
+		if _, err := s.finishDocument(ctx, tweetID, req, ids); err != nil {
+			s.logger.Error("background document generation failed", "tweet_id", tweetID, "type", t, "error", err)
+		}
+	}()
+	return nil
+}
+
+// finishDocument generates a document that has been marked generating and
+// saves the result, or the failure, with the tweet.
+func (s *TweetService) finishDocument(ctx context.Context, tweetID domain.TweetID, req grok.DocumentRequest, ids []domain.TweetID) (*domain.DerivedDocument, error) {
+	typ := domain.DocumentType(req.Type)
+	s.logger.Info("starting document generation", "tweet_id", tweetID, "type", typ, "sources", len(req.Sources))
+
+	doc, genErr := s.deriveDocument(withUsageTweet(ctx, tweetID), req, ids)
+
+	s.tweetsMu.Lock()
+	tweet, ok := s.tweets[tweetID]
+	if !ok {
+		s.tweetsMu.Unlock()
+		return nil, domain.ErrVideoNotFound
+	}
+	if genErr != nil {
+		doc = tweet.Documents[typ]
+		doc.Status = domain.DocumentStatusFailed
+		doc.Error = genErr.Error()
+	}
+	if tweet.Documents == nil {
+		tweet.Documents = make(map[domain.DocumentType]domain.DerivedDocument)
+	}
+	tweet.Documents[typ] = doc
+	archivePath := tweet.ArchivePath
+	s.tweetsMu.Unlock()
+
+	if err := s.saveTweetMetadata(tweet); err != nil {
+		s.logger.Error("failed to save document metadata", "tweet_id", tweetID, "error", err)
+	}
+	if genErr != nil {
+		s.logger.Error("document generation failed", "tweet_id", tweetID, "type", typ, "error", genErr)
+		return nil, fmt.Errorf("document generation failed: %w", genErr)
+	}
+
+	content := fmt.Sprintf("# %s\n\n%s\n", doc.Title, doc.Content)
+	path := filepath.Join(archivePath, documentFilename(typ))
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		s.logger.Warn("failed to save document file", "path", path, "error", err)
+	}
+	s.writeChecksums(tweet, documentFilename(typ))
+	if err := s.syncArchiveToStorage(ctx, tweet); err != nil {
+		s.logger.Warn("failed to mirror archive to storage", "tweet_id", tweetID, "error", err)
+	}
+
+	s.logger.Info("document generation completed",
+		"tweet_id", tweetID,
+		"type", typ,
+		"title", doc.Title,
+		"word_count", doc.WordCount)
+
+	s.emitEvent(domain.EventSeverityInfo, domain.EventCategoryAI, "Document generated",
+		domain.EventMetadata{
+			"tweet_id":   string(tweetID),
+			"type":       string(typ),
+			"title":      doc.Title,
+			"word_count": doc.WordCount,
+		})
+
+	return &doc, nil
+}
+
+// GetDocument returns a tweet's document of typ, whatever its status.
+func (s *TweetService) GetDocument(tweetID domain.TweetID, typ string) (*domain.DerivedDocument, error) {
+	t, err := parseDocumentType(typ)
+	if err != nil {
+		return nil, err
+	}
+	s.tweetsMu.RLock()
+	defer s.tweetsMu.RUnlock()
+	tweet, ok := s.tweets[tweetID]
+	if !ok {
+		return nil, domain.ErrVideoNotFound
+	}
+	doc, ok := tweet.Documents[t]
+	if !ok {
+		return nil, ErrDocumentNotFound
+	}
+	return &doc, nil
+}
+
+// ListDocuments returns a tweet's documents in domain.DocumentTypes order.
+func (s *TweetService) ListDocuments(tweetID domain.TweetID) ([]domain.DerivedDocument, error) {
+	s.tweetsMu.RLock()
+	defer s.tweetsMu.RUnlock()
+	tweet, ok := s.tweets[tweetID]
+	if !ok {
+		return nil, domain.ErrVideoNotFound
+	}
+	docs := make([]domain.DerivedDocument, 0, len(tweet.Documents))
+	for _, t := range domain.DocumentTypes {
+		if doc, ok := tweet.Documents[t]; ok {
+			docs = append(docs, doc)
+		}
+	}
+	return docs, nil
+}
+
+// DeleteDocument removes a tweet's document of typ and its markdown file.
+func (s *TweetService) DeleteDocument(ctx context.Context, tweetID domain.TweetID, typ string) error {
+	t, err := parseDocumentType(typ)
+	if err != nil {
+		return err
+	}
+	s.tweetsMu.Lock()
+	tweet, ok := s.tweets[tweetID]
+	if !ok {
+		s.tweetsMu.Unlock()
+		return domain.ErrVideoNotFound
+	}
+	doc, ok := tweet.Documents[t]
+	if !ok {
+		s.tweetsMu.Unlock()
+		return ErrDocumentNotFound
+	}
+	if doc.Generating() {
+		s.tweetsMu.Unlock()
+		return ErrDocumentInProgress
+	}
+	delete(tweet.Documents, t)
+	if len(tweet.Documents) == 0 {
+		tweet.Documents = nil
+	}
+	s.tweetsMu.Unlock()
+
+	path := filepath.Join(tweet.ArchivePath, documentFilename(t))
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		s.logger.Warn("failed to delete document file", "path", path, "error", err)
+	}
+	if err := s.saveTweetMetadata(tweet); err != nil {
+		return fmt.Errorf("failed to save tweet metadata: %w", err)
+	}
+	s.writeChecksums(tweet, documentFilename(t))
+	if err := s.syncArchiveToStorage(ctx, tweet); err != nil {
+		s.logger.Warn("failed to mirror archive to storage", "tweet_id", tweetID, "error", err)
+	}
+
+	s.logger.Info("document deleted", "tweet_id", tweetID, "type", t)
+	return nil
+}
+
+// interruptedDocumentsFailed marks documents that were still generating when
+// the server stopped as failed, so they can be generated again.
+func interruptedDocumentsFailed(docs map[domain.DocumentType]domain.DerivedDocument) map[domain.DocumentType]domain.DerivedDocument {
+	for typ, doc := range docs {
+		if doc.Generating() {
+			doc.Status = domain.DocumentStatusFailed
+			doc.Error = "generation was interrupted by a restart"
+			docs[typ] = doc
+		}
+	}
+	return docs
+}
+
+// failInterruptedDigests marks playlist digests that were still generating
+// when the server stopped as failed, so they can be generated again or
+// deleted.
+func (s *PlaylistService) failInterruptedDigests() {
+	ctx := context.Background()
+	playlists, err := s.repo.List(ctx)
+	if err != nil {
+		s.logger.Warn("failed to check playlist digests", "error", err)
+		return
+	}
+	for _, playlist := range playlists {
+		if playlist.Digest == nil || !playlist.Digest.Generating() {
+			continue
+		}
+		digest := *playlist.Digest
+		digest.Status = domain.DocumentStatusFailed
+		digest.Error = "generation was interrupted by a restart"
+		playlist.Digest = &digest
+		if err := s.repo.Update(ctx, playlist); err != nil {
+			s.logger.Warn("failed to save interrupted playlist digest", "playlist_id", playlist.ID, "error", err)
+		}
+	}
+}
+
+// StartGenerateDigest generates a digest of every tweet in a playlist in the
+// background and stores it with the playlist.
+func (s *PlaylistService) StartGenerateDigest(ctx context.Context, id domain.PlaylistID) error {
+	if s.tweetSvc == nil {
+		return ErrNothingToDerive
+	}
+	playlist, err := s.Get(ctx, id)
+	if err != nil {
+		return err
+	}
+	if playlist.Digest != nil && playlist.Digest.Generating() {
+		return ErrDocumentInProgress
+	}
+	req, ids, err := s.tweetSvc.collectionDocumentRequest(playlist.Name, playlist.Items)
+	if err != nil {
+		return err
+	}
+	if err := s.tweetSvc.aiBudgetExceeded(); err != nil {
+		return err
+	}
+
+	// Smart playlist items are filled in by Get; store the playlist as saved
+	stored, err := s.repo.Get(ctx, id)
+	if err != nil {
+		return err
+	}
+	digest := domain.DerivedDocument{Type: domain.DocumentDigest}
+	if stored.Digest != nil {
+		digest = *stored.Digest
+	}
+	digest.Status = domain.DocumentStatusGenerating
+	digest.Error = ""
+	stored.Digest = &digest
+	if err := s.repo.Update(ctx, stored); err != nil {
+		return err
+	}
+
+	go func() {
+		ctx := context.Background()
+		if s.tweetSvc.aiCfg.RegenerateTimeout > 0 {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, s.tweetSvc.aiCfg.RegenerateTimeout)
+			defer cancel()
+		}
+		s.logger.Info("starting playlist digest", "playlist_id", id, "sources", len(req.Sources))
+		doc, genErr := s.tweetSvc.deriveDocument(ctx, req, ids)
+
+		stored, err := s.repo.Get(ctx, id)
+		if err != nil {
+			s.logger.Warn("playlist removed during digest generation", "playlist_id", id, "error", err)
+			return
+		}
+		if genErr != nil {
+			s.logger.Error("playlist digest failed", "playlist_id", id, "error", genErr)
+			doc = digest
+			doc.Status = domain.DocumentStatusFailed
+			doc.Error = genErr.Error()
+		}
+		stored.Digest = &doc
+		if err := s.repo.Update(ctx, stored); err != nil {
+			s.logger.Error("failed to save playlist digest", "playlist_id", id, "error", err)
+			return
+		}
+		if genErr == nil {
+			s.logger.Info("playlist digest completed", "playlist_id", id, "title", doc.Title, "word_count", doc.WordCount)
+		}
+	}()
+	return nil
+}
+
+// GetDigest returns a playlist's digest, whatever its status.
+func (s *PlaylistService) GetDigest(ctx context.Context, id domain.PlaylistID) (*domain.DerivedDocument, error) {
+	playlist, err := s.repo.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if playlist.Digest == nil {
+		return nil, ErrDocumentNotFound
+	}
+	return playlist.Digest, nil
+}
+
+// DeleteDigest removes a playlist's digest.
+func (s *PlaylistService) DeleteDigest(ctx context.Context, id domain.PlaylistID) error {
+	playlist, err := s.repo.Get(ctx, id)
+	if err != nil {
+		return err
+	}
+	if playlist.Digest == nil {
+		return ErrDocumentNotFound
+	}
+	if playlist.Digest.Generating() {
+		return ErrDocumentInProgress
+	}
+	playlist.Digest = nil
+	return s.repo.Update(ctx, playlist)
+}
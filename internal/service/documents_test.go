@@ -0,0 +1,257 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/iconidentify/xgrabba/internal/config"
+	"github.com/iconidentify/xgrabba/internal/domain"
+	"github.com/iconidentify/xgrabba/internal/repository"
+	"github.com/iconidentify/xgrabba/pkg/grok"
+)
+
+// documentStub records document requests and fails when err is set.
+type documentStub struct {
+	analysisStub
+	requests []grok.DocumentRequest
+	err      error
+}
+
+func (s *documentStub) GenerateDocument(ctx context.Context, req grok.DocumentRequest) (*grok.DocumentResponse, error) {
+	s.requests = append(s.requests, req)
+	if s.err != nil {
+		return nil, s.err
+	}
+	return &grok.DocumentResponse{Title: "Cat notes", Content: "- Cats purr", WordCount: 3}, nil
+}
+
+func newDocumentTestService(t *testing.T) (*TweetService, *domain.Tweet, *documentStub) {
+	t.Helper()
+	svc, tweet := newIntegrityTestService(t)
+	stub := &documentStub{}
+	svc.grokClient = stub
+	tweet.Author.Username = "alice"
+	tweet.Text = "Why cats purr, a thread"
+	tweet.PostedAt = time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+	tweet.Media[0].Transcript = "Cats purr. It is calming."
+	tweet.Media[0].TranscriptSegments = []domain.TranscriptSegment{
+		{Start: 0, End: 2, Text: "Cats purr."},
+		{Start: 75.5, End: 78, Text: "It is calming."},
+	}
+	return svc, tweet, stub
+}
+
+func TestGenerateDocument_StoresDocument(t *testing.T) {
+	svc, tweet, stub := newDocumentTestService(t)
+
+	doc, err := svc.GenerateDocument(context.Background(), tweet.ID, "Outline")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if doc.Type != domain.DocumentOutline || doc.Status != domain.DocumentStatusCompleted || doc.Title != "Cat notes" || doc.GeneratedAt == nil {
+		t.Errorf("document = %+v", doc)
+	}
+
+	src := stub.requests[0].Sources
+	if len(src) != 1 || src[0].Author != "alice" || src[0].PostedAt != "2024-01-02" {
+		t.Fatalf("sources = %+v", src)
+	}
+	if src[0].Transcript != "[00:00] Cats purr.\n[01:15] It is calming." {
+		t.Errorf("transcript = %q, want timestamped lines", src[0].Transcript)
+	}
+
+	md, err := os.ReadFile(filepath.Join(tweet.ArchivePath, documentFilename(domain.DocumentOutline)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(md) != "# Cat notes\n\n- Cats purr\n" {
+		t.Errorf("markdown = %q", md)
+	}
+
+	stored := tweet.ToStoredTweet()
+	if loaded := svc.storedTweetToTweet(&stored, ""); loaded.Documents[domain.DocumentOutline].Content != "- Cats purr" {
+		t.Errorf("document not stored: %+v", loaded.Documents)
+	}
+	if docs, err := svc.ListDocuments(tweet.ID); err != nil || len(docs) != 1 {
+		t.Errorf("ListDocuments = %+v, %v", docs, err)
+	}
+
+	if err := svc.DeleteDocument(context.Background(), tweet.ID, "outline"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := svc.GetDocument(tweet.ID, "outline"); !errors.Is(err, ErrDocumentNotFound) {
+		t.Errorf("GetDocument after delete: err = %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(tweet.ArchivePath, documentFilename(domain.DocumentOutline))); !os.IsNotExist(err) {
+		t.Error("markdown file should be removed")
+	}
+}
+
+func TestGenerateDocument_FailureKeepsPreviousVersion(t *testing.T) {
+	svc, tweet, stub := newDocumentTestService(t)
+	ctx := context.Background()
+
+	if _, err := svc.GenerateDocument(ctx, tweet.ID, "tldr"); err != nil {
+		t.Fatal(err)
+	}
+	stub.err = errors.New("model overloaded")
+	if _, err := svc.GenerateDocument(ctx, tweet.ID, "tldr"); err == nil {
+		t.Fatal("GenerateDocument should fail when the AI fails")
+	}
+	doc, err := svc.GetDocument(tweet.ID, "tldr")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if doc.Status != domain.DocumentStatusFailed || doc.Error != "model overloaded" || doc.Content != "- Cats purr" {
+		t.Errorf("document after failure = %+v", doc)
+	}
+}
+
+func TestGenerateDocument_ThreadDigest(t *testing.T) {
+	svc, tweet, stub := newDocumentTestService(t)
+	first := tweet.ID
+	second := domain.TweetID("2")
+	svc.tweets[second] = &domain.Tweet{ID: second, Author: domain.Author{Username: "alice"}, Text: "Part two", ReplyTo: &first,
+		PostedAt: tweet.PostedAt.Add(time.Minute), ArchivePath: t.TempDir()}
+	svc.tweets["3"] = &domain.Tweet{ID: "3", Author: domain.Author{Username: "bob"}, Text: "Nice thread", ReplyTo: &second,
+		PostedAt: tweet.PostedAt.Add(2 * time.Minute)}
+
+	doc, err := svc.GenerateDocument(context.Background(), second, "digest")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(doc.SourceTweetIDs) != 2 || doc.SourceTweetIDs[0] != first || doc.SourceTweetIDs[1] != second {
+		t.Errorf("digest sources = %v, want the same-author thread oldest first", doc.SourceTweetIDs)
+	}
+	if got := stub.requests[0]; got.Type != "digest" || len(got.Sources) != 2 {
+		t.Errorf("digest request = %+v", got)
+	}
+
+	if _, err := svc.GenerateDocument(context.Background(), "3", "digest"); !errors.Is(err, ErrNotAThread) {
+		t.Errorf("digest of a lone reply: err = %v, want ErrNotAThread", err)
+	}
+}
+
+func TestGenerateDocument_Errors(t *testing.T) {
+	svc, tweet, _ := newDocumentTestService(t)
+
+	if err := svc.StartGenerateDocument(tweet.ID, "poem"); !errors.Is(err, ErrUnknownDocumentType) {
+		t.Errorf("unknown type: err = %v", err)
+	}
+	if err := svc.StartGenerateDocument("missing", "notes"); !errors.Is(err, domain.ErrVideoNotFound) {
+		t.Errorf("missing tweet: err = %v", err)
+	}
+
+	tweet.Documents = map[domain.DocumentType]domain.DerivedDocument{
+		domain.DocumentNotes: {Type: domain.DocumentNotes, Status: domain.DocumentStatusGenerating},
+	}
+	if err := svc.StartGenerateDocument(tweet.ID, "notes"); !errors.Is(err, ErrDocumentInProgress) {
+		t.Errorf("already generating: err = %v", err)
+	}
+	if err := svc.DeleteDocument(context.Background(), tweet.ID, "notes"); !errors.Is(err, ErrDocumentInProgress) {
+		t.Errorf("delete while generating: err = %v", err)
+	}
+
+	stored := tweet.ToStoredTweet()
+	if loaded := svc.storedTweetToTweet(&stored, ""); loaded.Documents[domain.DocumentNotes].Status != domain.DocumentStatusFailed {
+		t.Errorf("interrupted document after reload = %+v", loaded.Documents[domain.DocumentNotes])
+	}
+
+	tweet.Text = ""
+	tweet.Media[0].Transcript = ""
+	if err := svc.StartGenerateDocument(tweet.ID, "faq"); !errors.Is(err, ErrNothingToDerive) {
+		t.Errorf("empty tweet: err = %v, want ErrNothingToDerive", err)
+	}
+}
+
+func TestPlaylistService_GenerateDigest(t *testing.T) {
+	tweetSvc, tweet, stub := newDocumentTestService(t)
+	svc := NewPlaylistService(repository.NewFilesystemPlaylistRepository(t.TempDir()), tweetSvc, testLogger())
+	ctx := context.Background()
+
+	playlist, err := svc.Create(ctx, "Cats", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	svc.AddItem(ctx, playlist.ID, string(tweet.ID))
+	svc.AddItem(ctx, playlist.ID, "deleted")
+
+	if err := svc.StartGenerateDigest(ctx, playlist.ID); err != nil {
+		t.Fatal(err)
+	}
+	var digest *domain.DerivedDocument
+	for i := 0; i < 100; i++ {
+		digest, err = svc.GetDigest(ctx, playlist.ID)
+		if err != nil || !digest.Generating() {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	if err != nil || digest.Status != domain.DocumentStatusCompleted || len(digest.SourceTweetIDs) != 1 {
+		t.Fatalf("digest = %+v, %v", digest, err)
+	}
+	if req := stub.requests[0]; req.Title != "Cats" || !strings.Contains(req.Sources[0].Text, "Why cats purr") {
+		t.Errorf("digest request = %+v", req)
+	}
+
+	if err := svc.DeleteDigest(ctx, playlist.ID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := svc.GetDigest(ctx, playlist.ID); !errors.Is(err, ErrDocumentNotFound) {
+		t.Errorf("GetDigest after delete: err = %v", err)
+	}
+
+	empty, _ := svc.Create(ctx, "Empty", "")
+	if err := svc.StartGenerateDigest(ctx, empty.ID); !errors.Is(err, ErrNothingToDerive) {
+		t.Errorf("empty playlist: err = %v, want ErrNothingToDerive", err)
+	}
+}
+
+func TestPlaylistService_InterruptedDigest(t *testing.T) {
+	tweetSvc, tweet, _ := newDocumentTestService(t)
+	dir := t.TempDir()
+	repo := repository.NewFilesystemPlaylistRepository(dir)
+	svc := NewPlaylistService(repo, tweetSvc, testLogger())
+	ctx := context.Background()
+
+	playlist, err := svc.Create(ctx, "Cats", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	svc.AddItem(ctx, playlist.ID, string(tweet.ID))
+	stored, _ := repo.Get(ctx, playlist.ID)
+	stored.Digest = &domain.DerivedDocument{Type: domain.DocumentDigest, Status: domain.DocumentStatusGenerating}
+	if err := repo.Update(ctx, stored); err != nil {
+		t.Fatal(err)
+	}
+
+	// A restart finds the digest still generating and marks it failed
+	svc = NewPlaylistService(repository.NewFilesystemPlaylistRepository(dir), tweetSvc, testLogger())
+	digest, err := svc.GetDigest(ctx, playlist.ID)
+	if err != nil || digest.Status != domain.DocumentStatusFailed || digest.Error == "" {
+		t.Fatalf("digest after restart = %+v, %v", digest, err)
+	}
+	if err := svc.DeleteDigest(ctx, playlist.ID); err != nil {
+		t.Errorf("DeleteDigest after restart: err = %v", err)
+	}
+}
+
+func TestGenerateDocument_BudgetExceeded(t *testing.T) {
+	svc, tweet, stub := newDocumentTestService(t)
+	now := time.Date(2024, 3, 10, 12, 0, 0, 0, time.UTC)
+	usage := newTestAIUsageService(t, t.TempDir(), config.AIConfig{DailyBudgetUSD: 0.001}, &now)
+	usage.RecordAIUsage(context.Background(), grok.Usage{Model: "grok-3", CompletionTokens: 1000})
+	svc.SetAIUsage(usage)
+
+	if _, err := svc.GenerateDocument(context.Background(), tweet.ID, "tldr"); !errors.Is(err, domain.ErrAIBudgetExceeded) {
+		t.Errorf("err = %v, want ErrAIBudgetExceeded", err)
+	}
+	if len(stub.requests) != 0 || len(tweet.Documents) != 0 {
+		t.Error("no document should be generated or marked generating")
+	}
+}
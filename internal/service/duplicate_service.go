@@ -0,0 +1,352 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/iconidentify/xgrabba/internal/blobstore"
+	"github.com/iconidentify/xgrabba/internal/domain"
+	"github.com/iconidentify/xgrabba/pkg/phash"
+)
+
+// DefaultDuplicateThreshold is the default maximum pHash Hamming distance
+// (out of 64 bits) for two media items to be considered near-duplicates.
+const DefaultDuplicateThreshold = 8
+
+// Duplicate cluster kinds.
+const (
+	DuplicateKindIdentical = "identical" // Byte-for-byte identical files
+	DuplicateKindSimilar   = "similar"   // Perceptually similar (re-encoded, resized, cropped)
+)
+
+// DuplicateService finds duplicate media across archives and can hardlink
+// identical files to reclaim disk space.
+type DuplicateService struct {
+	tweetSvc     *TweetService
+	logger       *slog.Logger
+	eventEmitter domain.EventEmitter
+}
+
+// NewDuplicateService creates a new duplicate detection service.
+func NewDuplicateService(tweetSvc *TweetService, logger *slog.Logger, eventEmitter domain.EventEmitter) *DuplicateService {
+	return &DuplicateService{
+		tweetSvc:     tweetSvc,
+		logger:       logger,
+		eventEmitter: eventEmitter,
+	}
+}
+
+// DuplicateMedia identifies one media item within a duplicate cluster.
+type DuplicateMedia struct {
+	TweetID   string `json:"tweet_id"`
+	MediaID   string `json:"media_id"`
+	Type      string `json:"type"`
+	Author    string `json:"author,omitempty"`
+	Filename  string `json:"filename"`
+	Size      int64  `json:"size"`
+	SHA256    string `json:"sha256,omitempty"`
+	PHash     string `json:"phash,omitempty"`
+	localPath string
+}
+
+// DuplicateCluster is a group of media items that are identical or visually similar.
+type DuplicateCluster struct {
+	Kind             string           `json:"kind"`
+	MediaType        string           `json:"media_type"`
+	Items            []DuplicateMedia `json:"items"`
+	ReclaimableBytes int64            `json:"reclaimable_bytes"` // Space freed by hardlinking identical files
+}
+
+// DedupeResult summarizes a hardlink deduplication run.
+type DedupeResult struct {
+	DryRun         bool     `json:"dry_run"`
+	Groups         int      `json:"groups"`
+	FilesLinked    int      `json:"files_linked"`
+	BytesReclaimed int64    `json:"bytes_reclaimed"`
+	RemoteOnly     int      `json:"remote_only,omitempty"` // Copies evicted to remote storage, left as they are
+	Errors         []string `json:"errors,omitempty"`
+}
+
+// hashedMedia is a media item with parsed hashes used during clustering.
+type hashedMedia struct {
+	item      DuplicateMedia
+	isVideo   bool
+	phash     uint64
+	hasPHash  bool
+	keyframes []uint64
+}
+
+// FindDuplicates groups media into clusters of identical or near-identical items.
+// threshold is the maximum pHash Hamming distance for near-duplicates; 0 only
+// reports byte-identical files.
+func (s *DuplicateService) FindDuplicates(ctx context.Context, threshold int) ([]DuplicateCluster, error) {
+	items, err := s.collectMedia(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	uf := newUnionFind(len(items))
+
+	// Identical files always cluster together
+	bySHA := make(map[string]int)
+	for i, it := range items {
+		if first, ok := bySHA[it.item.SHA256]; ok {
+			uf.union(first, i)
+		} else {
+			bySHA[it.item.SHA256] = i
+		}
+	}
+
+	// Perceptual similarity (one representative per SHA-256 keeps this cheap)
+	if threshold > 0 {
+		reps := make([]int, 0, len(bySHA))
+		for _, idx := range bySHA {
+			reps = append(reps, idx)
+		}
+		sort.Ints(reps)
+		for a := 0; a < len(reps); a++ {
+			if ctx.Err() != nil {
+				return nil, ctx.Err()
+			}
+			for b := a + 1; b < len(reps); b++ {
+				if mediaSimilar(items[reps[a]], items[reps[b]], threshold) {
+					uf.union(reps[a], reps[b])
+				}
+			}
+		}
+	}
+
+	groups := make(map[int][]int)
+	for i := range items {
+		root := uf.find(i)
+		groups[root] = append(groups[root], i)
+	}
+
+	var clusters []DuplicateCluster
+	for _, members := range groups {
+		if len(members) < 2 {
+			continue
+		}
+		clusters = append(clusters, buildCluster(items, members))
+	}
+
+	sort.Slice(clusters, func(i, j int) bool {
+		if len(clusters[i].Items) != len(clusters[j].Items) {
+			return len(clusters[i].Items) > len(clusters[j].Items)
+		}
+		return clusters[i].Items[0].TweetID < clusters[j].Items[0].TweetID
+	})
+	return clusters, nil
+}
+
+// Dedupe replaces byte-identical media files with hardlinks to a single copy.
+// Archives keep their own directory entries, so deleting one archive never
+// affects another. With dryRun set, nothing is changed on disk.
+func (s *DuplicateService) Dedupe(ctx context.Context, dryRun bool) (*DedupeResult, error) {
+	items, err := s.collectMedia(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	bySHA := make(map[string][]DuplicateMedia)
+	for _, it := range items {
+		bySHA[it.item.SHA256] = append(bySHA[it.item.SHA256], it.item)
+	}
+
+	result := &DedupeResult{DryRun: dryRun}
+	for sum, group := range bySHA {
+		if len(group) < 2 {
+			continue
+		}
+		if ctx.Err() != nil {
+			return result, ctx.Err()
+		}
+		result.Groups++
+
+		// Copies evicted to remote storage have no local bytes to reclaim; the
+		// oldest copy still on disk is kept
+		var local []DuplicateMedia
+		var infos []os.FileInfo
+		for _, m := range group {
+			info, err := os.Stat(m.localPath)
+			if os.IsNotExist(err) && s.tweetSvc.remoteStorage() {
+				result.RemoteOnly++
+				continue
+			}
+			if err != nil {
+				result.Errors = append(result.Errors, fmt.Sprintf("%s: %v", m.localPath, err))
+				continue
+			}
+			local = append(local, m)
+			infos = append(infos, info)
+		}
+		if len(local) < 2 {
+			continue
+		}
+
+		keep, keepInfo := local[0], infos[0]
+		for i, dup := range local[1:] {
+			info := infos[i+1]
+			if os.SameFile(keepInfo, info) {
+				continue // Already linked
+			}
+			if info.Size() != keepInfo.Size() {
+				result.Errors = append(result.Errors, fmt.Sprintf("%s: size mismatch for sha256 %s", dup.localPath, sum))
+				continue
+			}
+			if !dryRun {
+				if err := blobstore.ReplaceWithLink(keep.localPath, dup.localPath); err != nil {
+					result.Errors = append(result.Errors, fmt.Sprintf("%s: %v", dup.localPath, err))
+					continue
+				}
+			}
+			result.FilesLinked++
+			result.BytesReclaimed += info.Size()
+		}
+	}
+
+	s.logger.Info("media dedupe complete",
+		"dry_run", dryRun,
+		"groups", result.Groups,
+		"files_linked", result.FilesLinked,
+		"bytes_reclaimed", result.BytesReclaimed,
+		"errors", len(result.Errors),
+	)
+	if !dryRun && s.eventEmitter != nil && result.FilesLinked > 0 {
+		s.eventEmitter.EmitSuccess(domain.EventCategoryDisk, "duplicate_service",
+			fmt.Sprintf("Deduplicated %d media files, reclaimed %s", result.FilesLinked, formatBytes(result.BytesReclaimed)),
+			domain.EventMetadata{
+				"files_linked":    result.FilesLinked,
+				"bytes_reclaimed": result.BytesReclaimed,
+				"errors":          len(result.Errors),
+			})
+	}
+
+	return result, nil
+}
+
+// collectMedia gathers all downloaded media that has a content hash.
+func (s *DuplicateService) collectMedia(ctx context.Context) ([]hashedMedia, error) {
+	tweets, _, err := s.tweetSvc.List(ctx, 0, 0)
+	if err != nil {
+		return nil, fmt.Errorf("list tweets: %w", err)
+	}
+
+	// Oldest archives first so the original copy is kept when deduplicating
+	sort.SliceStable(tweets, func(i, j int) bool {
+		return tweets[i].CreatedAt.Before(tweets[j].CreatedAt)
+	})
+
+	var items []hashedMedia
+	for _, t := range tweets {
+		for _, m := range t.Media {
+			if m.LocalPath == "" || m.SHA256 == "" {
+				continue
+			}
+			hm := hashedMedia{
+				item: DuplicateMedia{
+					TweetID:   string(t.ID),
+					MediaID:   m.ID,
+					Type:      string(m.Type),
+					Author:    t.Author.Username,
+					Filename:  filepath.Base(m.LocalPath),
+					SHA256:    m.SHA256,
+					PHash:     m.PHash,
+					localPath: m.LocalPath,
+				},
+				isVideo: m.Type == domain.MediaTypeVideo || m.Type == domain.MediaTypeGIF,
+			}
+			hm.item.Size = s.tweetSvc.MediaFileSize(ctx, m.LocalPath)
+			if m.PHash != "" {
+				if h, err := phash.Parse(m.PHash); err == nil {
+					hm.phash = h
+					hm.hasPHash = true
+				}
+			}
+			for _, kf := range m.KeyframeHashes {
+				if h, err := phash.Parse(kf); err == nil {
+					hm.keyframes = append(hm.keyframes, h)
+				}
+			}
+			items = append(items, hm)
+		}
+	}
+	return items, nil
+}
+
+// mediaSimilar compares two media items of the same kind by perceptual hash.
+// Videos use their keyframe sequences when both have them, otherwise the thumbnail hash.
+func mediaSimilar(a, b hashedMedia, threshold int) bool {
+	if a.isVideo != b.isVideo {
+		return false
+	}
+	if a.isVideo {
+		if d, ok := phash.SequenceDistance(a.keyframes, b.keyframes); ok {
+			return d <= float64(threshold)
+		}
+	}
+	if !a.hasPHash || !b.hasPHash {
+		return false
+	}
+	return phash.Distance(a.phash, b.phash) <= threshold
+}
+
+func buildCluster(items []hashedMedia, members []int) DuplicateCluster {
+	sort.Ints(members)
+	cluster := DuplicateCluster{
+		Kind:      DuplicateKindIdentical,
+		MediaType: items[members[0]].item.Type,
+		Items:     make([]DuplicateMedia, 0, len(members)),
+	}
+
+	seenSHA := make(map[string]bool)
+	for _, idx := range members {
+		it := items[idx].item
+		if it.SHA256 != items[members[0]].item.SHA256 {
+			cluster.Kind = DuplicateKindSimilar
+		}
+		if seenSHA[it.SHA256] {
+			cluster.ReclaimableBytes += it.Size
+		}
+		seenSHA[it.SHA256] = true
+		cluster.Items = append(cluster.Items, it)
+	}
+	return cluster
+}
+
+// unionFind is a minimal disjoint-set used for clustering.
+type unionFind struct {
+	parent []int
+}
+
+func newUnionFind(n int) *unionFind {
+	uf := &unionFind{parent: make([]int, n)}
+	for i := range uf.parent {
+		uf.parent[i] = i
+	}
+	return uf
+}
+
+func (u *unionFind) find(x int) int {
+	for u.parent[x] != x {
+		u.parent[x] = u.parent[u.parent[x]]
+		x = u.parent[x]
+	}
+	return x
+}
+
+func (u *unionFind) union(a, b int) {
+	ra, rb := u.find(a), u.find(b)
+	if ra == rb {
+		return
+	}
+	if ra < rb {
+		u.parent[rb] = ra
+	} else {
+		u.parent[ra] = rb
+	}
+}
@@ -0,0 +1,139 @@
+package service
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/iconidentify/xgrabba/internal/domain"
+)
+
+func newDuplicateTestService(t *testing.T, tweets ...*domain.Tweet) *DuplicateService {
+	t.Helper()
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	tweetSvc := &TweetService{
+		logger: logger,
+		tweets: make(map[domain.TweetID]*domain.Tweet),
+	}
+	for _, tw := range tweets {
+		tweetSvc.tweets[tw.ID] = tw
+	}
+	return NewDuplicateService(tweetSvc, logger, nil)
+}
+
+func writeMediaFile(t *testing.T, dir, name, content string) string {
+	t.Helper()
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	p := filepath.Join(dir, name)
+	if err := os.WriteFile(p, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return p
+}
+
+func TestDuplicateService_FindDuplicates(t *testing.T) {
+	base := t.TempDir()
+	now := time.Now()
+
+	a := writeMediaFile(t, filepath.Join(base, "a", "media"), "1.jpg", "same-bytes")
+	b := writeMediaFile(t, filepath.Join(base, "b", "media"), "2.jpg", "same-bytes")
+	c := writeMediaFile(t, filepath.Join(base, "c", "media"), "3.jpg", "re-encoded")
+	d := writeMediaFile(t, filepath.Join(base, "d", "media"), "4.jpg", "unrelated")
+
+	tweets := []*domain.Tweet{
+		{ID: "a", CreatedAt: now, Media: []domain.Media{{ID: "1", Type: domain.MediaTypeImage, LocalPath: a, SHA256: "s1", PHash: "ff00ff00ff00ff00"}}},
+		{ID: "b", CreatedAt: now.Add(time.Minute), Media: []domain.Media{{ID: "2", Type: domain.MediaTypeImage, LocalPath: b, SHA256: "s1", PHash: "ff00ff00ff00ff00"}}},
+		{ID: "c", CreatedAt: now.Add(2 * time.Minute), Media: []domain.Media{{ID: "3", Type: domain.MediaTypeImage, LocalPath: c, SHA256: "s2", PHash: "ff00ff00ff00ff03"}}},
+		{ID: "d", CreatedAt: now.Add(3 * time.Minute), Media: []domain.Media{{ID: "4", Type: domain.MediaTypeImage, LocalPath: d, SHA256: "s3", PHash: "00ff00ff00ff00ff"}}},
+	}
+	svc := newDuplicateTestService(t, tweets...)
+
+	t.Run("identical only", func(t *testing.T) {
+		clusters, err := svc.FindDuplicates(context.Background(), 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(clusters) != 1 || clusters[0].Kind != DuplicateKindIdentical || len(clusters[0].Items) != 2 {
+			t.Fatalf("clusters = %+v", clusters)
+		}
+		if clusters[0].ReclaimableBytes != int64(len("same-bytes")) {
+			t.Errorf("reclaimable = %d", clusters[0].ReclaimableBytes)
+		}
+	})
+
+	t.Run("near duplicates", func(t *testing.T) {
+		clusters, err := svc.FindDuplicates(context.Background(), DefaultDuplicateThreshold)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(clusters) != 1 || clusters[0].Kind != DuplicateKindSimilar || len(clusters[0].Items) != 3 {
+			t.Fatalf("clusters = %+v", clusters)
+		}
+	})
+}
+
+func TestMediaSimilar_VideoKeyframes(t *testing.T) {
+	a := hashedMedia{isVideo: true, keyframes: []uint64{0, 0xff}}
+	b := hashedMedia{isVideo: true, keyframes: []uint64{1, 0xff, 0xabc}}
+	c := hashedMedia{isVideo: true, keyframes: []uint64{^uint64(0), 0}}
+	img := hashedMedia{hasPHash: true}
+
+	if !mediaSimilar(a, b, 4) {
+		t.Error("matching keyframe sequences should be similar")
+	}
+	if mediaSimilar(a, c, 4) {
+		t.Error("different keyframe sequences should not be similar")
+	}
+	if mediaSimilar(a, img, 64) {
+		t.Error("video and image should never match")
+	}
+}
+
+func TestDuplicateService_Dedupe(t *testing.T) {
+	base := t.TempDir()
+	now := time.Now()
+	a := writeMediaFile(t, filepath.Join(base, "a", "media"), "1.mp4", "video-bytes")
+	b := writeMediaFile(t, filepath.Join(base, "b", "media"), "2.mp4", "video-bytes")
+
+	svc := newDuplicateTestService(t,
+		&domain.Tweet{ID: "a", CreatedAt: now, Media: []domain.Media{{ID: "1", Type: domain.MediaTypeVideo, LocalPath: a, SHA256: "s"}}},
+		&domain.Tweet{ID: "b", CreatedAt: now.Add(time.Second), Media: []domain.Media{{ID: "2", Type: domain.MediaTypeVideo, LocalPath: b, SHA256: "s"}}},
+	)
+
+	dry, err := svc.Dedupe(context.Background(), true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if dry.FilesLinked != 1 || dry.BytesReclaimed != int64(len("video-bytes")) {
+		t.Errorf("dry run = %+v", dry)
+	}
+	ia, _ := os.Stat(a)
+	ib, _ := os.Stat(b)
+	if os.SameFile(ia, ib) {
+		t.Fatal("dry run must not link files")
+	}
+
+	res, err := svc.Dedupe(context.Background(), false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.FilesLinked != 1 || len(res.Errors) != 0 {
+		t.Errorf("dedupe = %+v", res)
+	}
+	ia, _ = os.Stat(a)
+	ib, _ = os.Stat(b)
+	if !os.SameFile(ia, ib) {
+		t.Error("files should be hardlinked")
+	}
+
+	again, _ := svc.Dedupe(context.Background(), false)
+	if again.FilesLinked != 0 {
+		t.Error("already-linked files should be skipped")
+	}
+}
@@ -132,9 +132,7 @@ func (s *ExportService) EstimateExport(ctx context.Context) (*ExportEstimate, er
 		for _, media := range tweet.Media {
 			mediaCount++
 			if media.LocalPath != "" {
-				if stat, err := os.Stat(media.LocalPath); err == nil {
-					totalSize += stat.Size()
-				}
+				totalSize += s.tweetSvc.MediaFileSize(ctx, media.LocalPath)
 			}
 		}
 
@@ -483,7 +481,7 @@ func (s *ExportService) runExportAsync(ctx context.Context, opts ExportOptions)
 	}
 
 	// Apply filters
-	if opts.DateRange != nil || len(opts.Authors) > 0 || opts.SearchQuery != "" {
+	if opts.hasFilters() {
 		tweets = s.filterTweets(tweets, opts)
 	}
 
@@ -1235,7 +1233,7 @@ func (s *ExportService) runDownloadExportAsync(ctx context.Context, opts ExportO
 	}
 
 	// Apply filters
-	if opts.DateRange != nil || len(opts.Authors) > 0 || opts.SearchQuery != "" {
+	if opts.hasFilters() {
 		tweets = s.filterTweets(tweets, opts)
 	}
 
@@ -1461,6 +1459,15 @@ type ExportOptions struct {
 	SearchQuery    string     // Optional search filter
 	Encrypt        bool       // Enable AES-256-GCM encryption
 	Password       string     // Password for encryption (required if Encrypt is true)
+
+	// ExcludeCredentialed skips tweets that were only visible to the user's
+	// logged-in session (protected accounts, sensitive media).
+	ExcludeCredentialed bool
+}
+
+// hasFilters reports whether any option narrows the exported tweets.
+func (o ExportOptions) hasFilters() bool {
+	return o.DateRange != nil || len(o.Authors) > 0 || o.SearchQuery != "" || o.ExcludeCredentialed
 }
 
 // DateRange filters tweets by date.
@@ -1503,7 +1510,44 @@ type ExportedTweet struct {
 	AITags        []string            `json:"ai_tags,omitempty"`
 	AIContentType string              `json:"ai_content_type,omitempty"`
 	AITopics      []string            `json:"ai_topics,omitempty"`
+	Tags          []string            `json:"tags,omitempty"`
 	ArchivePath   string              `json:"archive_path"` // Relative path for media lookup
+
+	Poll          *domain.Poll          `json:"poll,omitempty"`
+	Card          *domain.LinkCard      `json:"card,omitempty"`
+	CardImagePath string                `json:"card_image_path,omitempty"` // Relative path to the link preview image
+	CommunityNote *domain.CommunityNote `json:"community_note,omitempty"`
+
+	FetchedWithCredentials bool `json:"fetched_with_credentials,omitempty"`
+
+	// Translations keyed by language, selectable in the offline viewer
+	Translations map[string]ExportedTranslation `json:"translations,omitempty"`
+	Documents    []ExportedDocument             `json:"documents,omitempty"`
+}
+
+// ExportedDocument is a completed AI-derived document for offline viewing.
+type ExportedDocument struct {
+	Type           string   `json:"type"`
+	Label          string   `json:"label"`
+	Title          string   `json:"title"`
+	Content        string   `json:"content"` // Markdown
+	SourceTweetIDs []string `json:"source_tweet_ids,omitempty"`
+}
+
+// ExportedTranslation is a tweet's translation into one language.
+type ExportedTranslation struct {
+	Language       string `json:"language"`
+	SourceLanguage string `json:"source_language,omitempty"`
+	Text           string `json:"text,omitempty"`
+	ArticleBody    string `json:"article_body,omitempty"`
+	// Media holds translated transcripts keyed by media ID
+	Media map[string]ExportedTranslatedMedia `json:"media,omitempty"`
+}
+
+// ExportedTranslatedMedia is a translated transcript for offline viewing.
+type ExportedTranslatedMedia struct {
+	Transcript   string `json:"transcript"`
+	SubtitlePath string `json:"subtitle_path,omitempty"` // Relative path to the translated WebVTT track
 }
 
 // ExportedAuthor contains author info for offline viewing.
@@ -1521,13 +1565,19 @@ type ExportedMedia struct {
 	Type               string   `json:"type"`
 	LocalPath          string   `json:"local_path"` // Relative path from archive root
 	ThumbnailPath      string   `json:"thumbnail_path,omitempty"`
+	SubtitlePath       string   `json:"subtitle_path,omitempty"` // Relative path to WebVTT track
 	Width              int      `json:"width,omitempty"`
 	Height             int      `json:"height,omitempty"`
 	Duration           int      `json:"duration_seconds,omitempty"`
 	AICaption          string   `json:"ai_caption,omitempty"`
 	AITags             []string `json:"ai_tags,omitempty"`
+	OCRText            string   `json:"ocr_text,omitempty"`
+	AltText            string   `json:"alt_text,omitempty"` // Author's alt text, or the AI description
 	Transcript         string   `json:"transcript,omitempty"`
 	TranscriptLanguage string   `json:"transcript_language,omitempty"`
+	// Chapters of a video; ChaptersPath is the WebVTT chapters track
+	Chapters     []domain.Chapter `json:"chapters,omitempty"`
+	ChaptersPath string           `json:"chapters_path,omitempty"`
 }
 
 // ExportToUSB exports the archive to a USB drive or directory.
@@ -1595,7 +1645,7 @@ func (s *ExportService) ExportToUSB(ctx context.Context, opts ExportOptions) (*E
 	s.logger.Info("found tweets to export", "count", total)
 
 	// Filter tweets if filters are specified
-	if opts.DateRange != nil || len(opts.Authors) > 0 || opts.SearchQuery != "" {
+	if opts.hasFilters() {
 		tweets = s.filterTweets(tweets, opts)
 		s.logger.Info("filtered tweets", "count", len(tweets))
 	}
@@ -1704,6 +1754,10 @@ func (s *ExportService) filterTweets(tweets []*domain.Tweet, opts ExportOptions)
 	filtered := make([]*domain.Tweet, 0)
 
 	for _, tweet := range tweets {
+		if opts.ExcludeCredentialed && tweet.FetchedWithCredentials {
+			continue
+		}
+
 		// Date filter
 		if opts.DateRange != nil {
 			if tweet.PostedAt.Before(opts.DateRange.Start) || tweet.PostedAt.After(opts.DateRange.End) {
@@ -1761,6 +1815,11 @@ func (s *ExportService) exportTweet(ctx context.Context, tweet *domain.Tweet, da
 	var totalSize int64
 	var mediaCount int
 
+	// Media evicted to remote storage is fetched back before copying
+	if err := s.tweetSvc.EnsureLocalMedia(ctx, tweet); err != nil {
+		s.logger.Warn("failed to restore media from storage", "tweet_id", tweet.ID, "error", err)
+	}
+
 	// Copy media files in parallel for better performance
 	exportedMedia := make([]ExportedMedia, 0, len(tweet.Media))
 	if len(tweet.Media) > 0 {
@@ -1843,6 +1902,30 @@ func (s *ExportService) exportTweet(ctx context.Context, tweet *domain.Tweet, da
 		}
 	}
 
+	// Copy the link card preview image if downloaded
+	var card *domain.LinkCard
+	var cardImagePath string
+	if tweet.Card != nil {
+		c := *tweet.Card
+		if c.LocalImagePath != "" {
+			relCardPath := filepath.Join("data", relArchivePath, "media", CardImageFilename)
+			if encCtx != nil {
+				if size, err := encCtx.encryptingCopyFile(ctx, c.LocalImagePath, relCardPath); err == nil {
+					cardImagePath = relCardPath
+					totalSize += size
+				}
+			} else if size, err := copyFile(c.LocalImagePath, filepath.Join(destArchivePath, "media", CardImageFilename)); err == nil {
+				cardImagePath = relCardPath
+				totalSize += size
+			}
+			c.LocalImagePath = ""
+		}
+		card = &c
+	}
+
+	translations, size := s.exportTranslations(ctx, tweet, destArchivePath, relArchivePath, encCtx)
+	totalSize += size
+
 	// Build exported tweet
 	archivedAt := time.Now()
 	if tweet.ArchivedAt != nil {
@@ -1869,12 +1952,86 @@ func (s *ExportService) exportTweet(ctx context.Context, tweet *domain.Tweet, da
 		AITags:        tweet.AITags,
 		AIContentType: tweet.AIContentType,
 		AITopics:      tweet.AITopics,
+		Tags:          tweet.Tags,
 		ArchivePath:   filepath.Join("data", relArchivePath),
+		Poll:          tweet.Poll,
+		Card:          card,
+		CardImagePath: cardImagePath,
+		CommunityNote: tweet.CommunityNote,
+		Translations:  translations,
+		Documents:     exportDocuments(tweet),
+
+		FetchedWithCredentials: tweet.FetchedWithCredentials,
 	}
 
 	return exported, totalSize, mediaCount, nil
 }
 
+// exportTranslations converts a tweet's translations for offline viewing and
+// copies the translated WebVTT tracks. It returns the bytes copied.
+func (s *ExportService) exportTranslations(ctx context.Context, tweet *domain.Tweet, destArchivePath, relArchivePath string, encCtx *encryptionContext) (map[string]ExportedTranslation, int64) {
+	if len(tweet.Translations) == 0 {
+		return nil, 0
+	}
+
+	var totalSize int64
+	translations := make(map[string]ExportedTranslation, len(tweet.Translations))
+	for lang, tr := range tweet.Translations {
+		exported := ExportedTranslation{
+			Language:       lang,
+			SourceLanguage: tr.SourceLanguage,
+			Text:           tr.Text,
+			ArticleBody:    tr.ArticleBody,
+		}
+		for mediaID, m := range tr.Media {
+			if exported.Media == nil {
+				exported.Media = make(map[string]ExportedTranslatedMedia, len(tr.Media))
+			}
+			item := ExportedTranslatedMedia{Transcript: m.Transcript}
+
+			vttFilename := TranslatedSubtitleFilename(mediaID, lang)
+			srcVTTPath := filepath.Join(tweet.ArchivePath, "media", vttFilename)
+			if _, err := os.Stat(srcVTTPath); err == nil {
+				relVTTPath := filepath.Join("data", relArchivePath, "media", vttFilename)
+				if encCtx != nil {
+					if size, err := encCtx.encryptingCopyFile(ctx, srcVTTPath, relVTTPath); err == nil {
+						item.SubtitlePath = relVTTPath
+						totalSize += size
+					}
+				} else if size, err := copyFile(srcVTTPath, filepath.Join(destArchivePath, "media", vttFilename)); err == nil {
+					item.SubtitlePath = relVTTPath
+					totalSize += size
+				}
+			}
+			exported.Media[mediaID] = item
+		}
+		translations[lang] = exported
+	}
+	return translations, totalSize
+}
+
+// exportDocuments returns a tweet's completed documents in display order.
+func exportDocuments(tweet *domain.Tweet) []ExportedDocument {
+	var docs []ExportedDocument
+	for _, typ := range domain.DocumentTypes {
+		doc, ok := tweet.Documents[typ]
+		if !ok || doc.Status != domain.DocumentStatusCompleted {
+			continue
+		}
+		exported := ExportedDocument{
+			Type:    string(typ),
+			Label:   typ.Label(),
+			Title:   doc.Title,
+			Content: doc.Content,
+		}
+		for _, id := range doc.SourceTweetIDs {
+			exported.SourceTweetIDs = append(exported.SourceTweetIDs, id.String())
+		}
+		docs = append(docs, exported)
+	}
+	return docs
+}
+
 // exportMedia exports a single media file.
 // If encCtx is provided, files are encrypted as they're copied using streaming encryption.
 func (s *ExportService) exportMedia(ctx context.Context, media *domain.Media, srcArchivePath, destArchivePath, relArchivePath string, encCtx *encryptionContext) (*ExportedMedia, int64, error) {
@@ -1888,6 +2045,8 @@ func (s *ExportService) exportMedia(ctx context.Context, media *domain.Media, sr
 		Duration:           media.Duration,
 		AICaption:          media.AICaption,
 		AITags:             media.AITags,
+		OCRText:            media.OCRText,
+		AltText:            media.Alt(),
 		Transcript:         media.Transcript,
 		TranscriptLanguage: media.TranscriptLanguage,
 	}
@@ -1943,6 +2102,53 @@ func (s *ExportService) exportMedia(ctx context.Context, media *domain.Media, sr
 		}
 	}
 
+	// Copy WebVTT subtitles for videos with timed transcripts
+	if len(media.TranscriptSegments) > 0 {
+		vttFilename := SubtitleFilename(media.ID)
+		srcVTTPath := filepath.Join(srcArchivePath, "media", vttFilename)
+
+		if _, err := os.Stat(srcVTTPath); err == nil {
+			relVTTPath := filepath.Join("data", relArchivePath, "media", vttFilename)
+
+			if encCtx != nil {
+				if size, err := encCtx.encryptingCopyFile(ctx, srcVTTPath, relVTTPath); err == nil {
+					exported.SubtitlePath = relVTTPath
+					totalSize += size
+				}
+			} else {
+				destVTTPath := filepath.Join(destArchivePath, "media", vttFilename)
+				if size, err := copyFile(srcVTTPath, destVTTPath); err == nil {
+					exported.SubtitlePath = relVTTPath
+					totalSize += size
+				}
+			}
+		}
+	}
+
+	// Copy the WebVTT chapters track for videos with chapters
+	if len(media.Chapters) > 0 {
+		exported.Chapters = media.Chapters
+		chaptersFilename := ChaptersFilename(media.ID)
+		srcChaptersPath := filepath.Join(srcArchivePath, "media", chaptersFilename)
+
+		if _, err := os.Stat(srcChaptersPath); err == nil {
+			relChaptersPath := filepath.Join("data", relArchivePath, "media", chaptersFilename)
+
+			if encCtx != nil {
+				if size, err := encCtx.encryptingCopyFile(ctx, srcChaptersPath, relChaptersPath); err == nil {
+					exported.ChaptersPath = relChaptersPath
+					totalSize += size
+				}
+			} else {
+				destChaptersPath := filepath.Join(destArchivePath, "media", chaptersFilename)
+				if size, err := copyFile(srcChaptersPath, destChaptersPath); err == nil {
+					exported.ChaptersPath = relChaptersPath
+					totalSize += size
+				}
+			}
+		}
+	}
+
 	return exported, totalSize, nil
 }
 
@@ -2562,6 +2768,8 @@ func contentTypeForPath(path string) string {
 		return "video/mp4"
 	case ".webm":
 		return "video/webm"
+	case ".vtt":
+		return "text/vtt"
 	case ".mp3":
 		return "audio/mpeg"
 	case ".wav":
@@ -2748,6 +2956,48 @@ func generateOfflineHTML() string {
             color: #71767b;
             margin-bottom: 4px;
         }
+        .language-select {
+            font-size: 13px;
+            color: #71767b;
+            margin-bottom: 12px;
+        }
+        .language-select select {
+            margin-left: 6px;
+            background: #202327;
+            color: #e7e9ea;
+            border: 1px solid #2f3336;
+            border-radius: 4px;
+            padding: 2px 6px;
+        }
+        .chapter {
+            cursor: pointer;
+            padding: 2px 0;
+        }
+        .chapter:hover {
+            color: #1d9bf0;
+        }
+        .chapter-time {
+            color: #71767b;
+            font-variant-numeric: tabular-nums;
+            margin-right: 8px;
+        }
+        .document {
+            background: #202327;
+            padding: 12px;
+            border-radius: 8px;
+            margin-top: 12px;
+            font-size: 14px;
+        }
+        .document summary {
+            cursor: pointer;
+            color: #e7e9ea;
+        }
+        .document-content {
+            white-space: pre-wrap;
+            margin-top: 8px;
+            max-height: 300px;
+            overflow-y: auto;
+        }
     </style>
 </head>
 <body>
@@ -2810,9 +3060,9 @@ func generateOfflineHTML() string {
 
                 if (media) {
                     if (media.thumbnail_path) {
-                        mediaHtml = '<img class="tweet-media" src="' + media.thumbnail_path + '" alt="">';
+                        mediaHtml = '<img class="tweet-media" src="' + media.thumbnail_path + '" alt="' + escapeHtml(media.alt_text) + '">';
                     } else if (media.local_path && media.type === 'image') {
-                        mediaHtml = '<img class="tweet-media" src="' + media.local_path + '" alt="">';
+                        mediaHtml = '<img class="tweet-media" src="' + media.local_path + '" alt="' + escapeHtml(media.alt_text) + '">';
                     }
                 }
 
@@ -2840,7 +3090,7 @@ func generateOfflineHTML() string {
             }).join('');
         }
 
-        function openModal(index) {
+        function openModal(index, lang) {
             const tweet = filteredTweets[index];
             const modal = document.getElementById('modal');
             const title = document.getElementById('modal-title');
@@ -2849,14 +3099,29 @@ func generateOfflineHTML() string {
 
             title.textContent = tweet.ai_title || 'Tweet Details';
 
+            // Selected translation; empty shows the original
+            const translations = tweet.translations || {};
+            const languages = Object.keys(translations).sort();
+            const translation = (lang && translations[lang]) || null;
+
             // Media
             let mediaHtml = '';
             if (tweet.media && tweet.media.length > 0) {
                 const media = tweet.media[0];
                 if (media.type === 'video' || media.type === 'gif') {
-                    mediaHtml = '<video class="modal-media" controls src="' + media.local_path + '"></video>';
+                    var track = media.subtitle_path ? '<track kind="subtitles" src="' + media.subtitle_path + '" srclang="' + (media.transcript_language || 'en') + '" label="Transcript"' + (translation ? '' : ' default') + '>' : '';
+                    languages.forEach(function(l) {
+                        var tm = (translations[l].media || {})[media.id];
+                        if (tm && tm.subtitle_path) {
+                            track += '<track kind="subtitles" src="' + tm.subtitle_path + '" srclang="' + l + '" label="' + l.toUpperCase() + '"' + (l === lang ? ' default' : '') + '>';
+                        }
+                    });
+                    if (media.chapters_path) {
+                        track += '<track kind="chapters" src="' + media.chapters_path + '" srclang="' + (media.transcript_language || 'en') + '" label="Chapters">';
+                    }
+                    mediaHtml = '<video class="modal-media" controls src="' + media.local_path + '">' + track + '</video>';
                 } else if (media.type === 'image') {
-                    mediaHtml = '<img class="modal-media" src="' + media.local_path + '" alt="">';
+                    mediaHtml = '<img class="modal-media" src="' + media.local_path + '" alt="' + escapeHtml(media.alt_text) + '">';
                 }
             }
             mediaContainer.innerHTML = mediaHtml;
@@ -2870,8 +3135,17 @@ func generateOfflineHTML() string {
                     '<div class="author-name">' + escapeHtml(tweet.author.display_name) + '</div>' +
                     '<div class="author-handle">@' + escapeHtml(tweet.author.username) + '</div>' +
                 '</div>' +
-            '</div>' +
-            '<div class="full-text">' + escapeHtml(tweet.text) + '</div>';
+            '</div>';
+
+            if (languages.length > 0) {
+                bodyHtml += '<div class="language-select"><label>Language ' +
+                    '<select onchange="openModal(' + index + ', this.value)">' +
+                        '<option value="">Original</option>' +
+                        languages.map(l => '<option value="' + l + '"' + (l === lang ? ' selected' : '') + '>' + l.toUpperCase() + '</option>').join('') +
+                    '</select></label></div>';
+            }
+
+            bodyHtml += '<div class="full-text">' + escapeHtml((translation && translation.text) || tweet.text) + '</div>';
 
             if (tweet.ai_summary) {
                 bodyHtml += '<div style="color:#71767b;font-size:14px;margin-bottom:12px;">AI Summary: ' + escapeHtml(tweet.ai_summary) + '</div>';
@@ -2879,13 +3153,34 @@ func generateOfflineHTML() string {
 
             // Transcript
             const media = tweet.media && tweet.media[0];
-            if (media && media.transcript) {
+            const translatedMedia = media && translation && (translation.media || {})[media.id];
+            if (translatedMedia) {
+                bodyHtml += '<div class="transcript">' +
+                    '<div class="transcript-label">Transcript (' + lang + ', translated)</div>' +
+                    escapeHtml(translatedMedia.transcript) +
+                '</div>';
+            } else if (media && media.transcript) {
                 bodyHtml += '<div class="transcript">' +
                     '<div class="transcript-label">Transcript' + (media.transcript_language ? ' (' + media.transcript_language + ')' : '') + '</div>' +
                     escapeHtml(media.transcript) +
                 '</div>';
             }
 
+            // Video chapters; clicking one seeks the video
+            if (media && media.chapters && media.chapters.length > 0) {
+                bodyHtml += '<div class="transcript chapters"><div class="transcript-label">Chapters</div>' +
+                    media.chapters.map(ch => '<div class="chapter" onclick="seekModalVideo(' + Number(ch.start) + ')">' +
+                        '<span class="chapter-time">' + formatChapterTime(ch.start) + '</span>' + escapeHtml(ch.title) + '</div>').join('') +
+                '</div>';
+            }
+
+            // Derived documents (TL;DR, notes, FAQ, ...)
+            (tweet.documents || []).forEach(function(doc) {
+                bodyHtml += '<details class="document"><summary>' +
+                    '<span class="transcript-label">' + escapeHtml(doc.label) + '</span> ' + escapeHtml(doc.title) +
+                    '</summary><div class="document-content">' + escapeHtml(doc.content) + '</div></details>';
+            });
+
             // Tags
             const allTags = (tweet.ai_tags || []).concat(
                 (tweet.media || []).flatMap(m => m.ai_tags || [])
@@ -2906,6 +3201,20 @@ func generateOfflineHTML() string {
             modal.classList.add('active');
         }
 
+        function formatChapterTime(seconds) {
+            const total = Math.floor(seconds);
+            const m = Math.floor(total / 60);
+            const s = total % 60;
+            return String(m).padStart(2, '0') + ':' + String(s).padStart(2, '0');
+        }
+
+        function seekModalVideo(seconds) {
+            const video = document.querySelector('#modal video');
+            if (!video) return;
+            video.currentTime = seconds;
+            video.play();
+        }
+
         function closeModal() {
             const modal = document.getElementById('modal');
             modal.classList.remove('active');
@@ -2932,6 +3241,10 @@ func generateOfflineHTML() string {
                         if ((media.ai_caption || '').toLowerCase().includes(query)) return true;
                         if ((media.ai_tags || []).some(t => t.toLowerCase().includes(query))) return true;
                     }
+                    for (const tr of Object.values(tweet.translations || {})) {
+                        if ((tr.text || '').toLowerCase().includes(query)) return true;
+                        if (Object.values(tr.media || {}).some(m => (m.transcript || '').toLowerCase().includes(query))) return true;
+                    }
                     return false;
                 });
             }
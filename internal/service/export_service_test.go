@@ -10,6 +10,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/iconidentify/xgrabba/internal/domain"
 	"github.com/iconidentify/xgrabba/pkg/crypto"
 )
 
@@ -296,6 +297,60 @@ func TestExportOptions_Validation(t *testing.T) {
 	}
 }
 
+func TestExportService_FilterTweets_ExcludeCredentialed(t *testing.T) {
+	svc := &ExportService{tweetSvc: &TweetService{}}
+	tweets := []*domain.Tweet{
+		{ID: "1", Text: "public"},
+		{ID: "2", Text: "protected", FetchedWithCredentials: true},
+	}
+
+	opts := ExportOptions{ExcludeCredentialed: true}
+	if !opts.hasFilters() {
+		t.Fatal("ExcludeCredentialed should count as a filter")
+	}
+	got := svc.filterTweets(tweets, opts)
+	if len(got) != 1 || got[0].ID != "1" {
+		t.Errorf("filterTweets = %v, want only the public tweet", got)
+	}
+
+	if got := svc.filterTweets(tweets, ExportOptions{}); len(got) != 2 {
+		t.Errorf("without the option all tweets are kept, got %d", len(got))
+	}
+}
+
+func TestExportService_ExportTranslations(t *testing.T) {
+	svc := &ExportService{logger: testLogger()}
+	src := t.TempDir()
+	dest := t.TempDir()
+	os.MkdirAll(filepath.Join(src, "media"), 0755)
+	os.MkdirAll(filepath.Join(dest, "media"), 0755)
+	os.WriteFile(filepath.Join(src, "media", TranslatedSubtitleFilename("m1", "en")), []byte("WEBVTT\n"), 0644)
+
+	tweet := &domain.Tweet{
+		ArchivePath: src,
+		Translations: map[string]domain.TweetTranslation{
+			"en": {Language: "en", Text: "Cat video", Media: map[string]domain.MediaTranslation{
+				"m1": {Transcript: "meow", Segments: []domain.TranscriptSegment{{Start: 0, End: 1, Text: "meow"}}},
+			}},
+		},
+	}
+
+	got, size := svc.exportTranslations(context.Background(), tweet, dest, "2024/01/user_1", nil)
+	en := got["en"]
+	if en.Text != "Cat video" || en.Media["m1"].Transcript != "meow" {
+		t.Errorf("exported translation = %+v", en)
+	}
+	if want := filepath.Join("data", "2024/01/user_1", "media", "m1.en.vtt"); en.Media["m1"].SubtitlePath != want {
+		t.Errorf("subtitle path = %q, want %q", en.Media["m1"].SubtitlePath, want)
+	}
+	if size != int64(len("WEBVTT\n")) {
+		t.Errorf("size = %d", size)
+	}
+	if _, err := os.Stat(filepath.Join(dest, "media", "m1.en.vtt")); err != nil {
+		t.Errorf("translated subtitles not copied: %v", err)
+	}
+}
+
 func TestGetFreeDiskSpace(t *testing.T) {
 	// Test with temp directory (should have some free space)
 	tmpDir := os.TempDir()
@@ -1002,3 +1057,24 @@ func TestActiveExport_MountPointField(t *testing.T) {
 		t.Errorf("expected mount point '/Volumes/USB', got %q", ae.MountPoint)
 	}
 }
+
+func TestExportDocuments(t *testing.T) {
+	tweet := &domain.Tweet{
+		Documents: map[domain.DocumentType]domain.DerivedDocument{
+			domain.DocumentNotes: {Type: domain.DocumentNotes, Status: domain.DocumentStatusCompleted, Title: "Notes", Content: "- a"},
+			domain.DocumentTLDR:  {Type: domain.DocumentTLDR, Status: domain.DocumentStatusCompleted, Title: "Gist", Content: "short", SourceTweetIDs: []domain.TweetID{"1"}},
+			domain.DocumentFAQ:   {Type: domain.DocumentFAQ, Status: domain.DocumentStatusFailed, Error: "boom"},
+		},
+	}
+
+	got := exportDocuments(tweet)
+	if len(got) != 2 {
+		t.Fatalf("exported %d documents, want 2 completed", len(got))
+	}
+	if got[0].Type != "tldr" || got[0].Label != "TL;DR" || got[0].SourceTweetIDs[0] != "1" {
+		t.Errorf("first document = %+v", got[0])
+	}
+	if got[1].Type != "notes" || got[1].Content != "- a" {
+		t.Errorf("second document = %+v", got[1])
+	}
+}
@@ -0,0 +1,231 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/iconidentify/xgrabba/internal/config"
+	"github.com/iconidentify/xgrabba/internal/domain"
+)
+
+// ErrScrubInProgress is returned when a scrub is requested while one is running.
+var ErrScrubInProgress = errors.New("integrity scrub already in progress")
+
+// scrubStartDelay keeps the first scheduled scrub out of the startup rush
+// (disk loading, backfills, resumed archives).
+const scrubStartDelay = 10 * time.Minute
+
+// ArchiveIntegrity reports problems found in one archive.
+type ArchiveIntegrity struct {
+	TweetID      string           `json:"tweet_id"`
+	Issues       []IntegrityIssue `json:"issues"`
+	CorruptMedia []string         `json:"corrupt_media,omitempty"` // Media IDs flagged for re-download
+	RepairQueued bool             `json:"repair_queued"`
+}
+
+// ScrubReport summarizes one integrity scrub.
+type ScrubReport struct {
+	StartedAt        time.Time          `json:"started_at"`
+	FinishedAt       *time.Time         `json:"finished_at,omitempty"`
+	Archives         int                `json:"archives"`
+	FilesVerified    int                `json:"files_verified"`
+	ManifestsCreated int                `json:"manifests_created"`
+	Damaged          []ArchiveIntegrity `json:"damaged"`
+	Errors           []string           `json:"errors,omitempty"`
+}
+
+// IntegrityService periodically re-verifies archive checksums and queues
+// repairs for damaged media.
+type IntegrityService struct {
+	tweetSvc     *TweetService
+	cfg          config.IntegrityConfig
+	logger       *slog.Logger
+	eventEmitter domain.EventEmitter
+
+	mu         sync.Mutex
+	running    bool
+	lastReport *ScrubReport
+}
+
+// NewIntegrityService creates a new integrity scrubbing service.
+func NewIntegrityService(tweetSvc *TweetService, cfg config.IntegrityConfig, logger *slog.Logger, eventEmitter domain.EventEmitter) *IntegrityService {
+	return &IntegrityService{
+		tweetSvc:     tweetSvc,
+		cfg:          cfg,
+		logger:       logger,
+		eventEmitter: eventEmitter,
+	}
+}
+
+// Start runs scheduled scrubs until ctx is cancelled.
+func (s *IntegrityService) Start(ctx context.Context) {
+	if !s.cfg.ScrubEnabled || s.cfg.ScrubInterval <= 0 {
+		return
+	}
+
+	timer := time.NewTimer(scrubStartDelay)
+	defer timer.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-timer.C:
+			if _, err := s.Scrub(ctx); err != nil && !errors.Is(err, ErrScrubInProgress) {
+				s.logger.Warn("scheduled integrity scrub failed", "error", err)
+			}
+			timer.Reset(s.cfg.ScrubInterval)
+		}
+	}
+}
+
+// Running reports whether a scrub is in progress.
+func (s *IntegrityService) Running() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.running
+}
+
+// LastReport returns the most recent scrub report, or nil if none has run.
+func (s *IntegrityService) LastReport() *ScrubReport {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.lastReport
+}
+
+// Scrub verifies every completed archive against its checksums.json.
+// Archives without a manifest get one written from their current state.
+func (s *IntegrityService) Scrub(ctx context.Context) (*ScrubReport, error) {
+	s.mu.Lock()
+	if s.running {
+		s.mu.Unlock()
+		return nil, ErrScrubInProgress
+	}
+	s.running = true
+	s.mu.Unlock()
+	defer func() {
+		s.mu.Lock()
+		s.running = false
+		s.mu.Unlock()
+	}()
+
+	report := &ScrubReport{StartedAt: time.Now(), Damaged: []ArchiveIntegrity{}}
+	s.logger.Info("integrity scrub started")
+
+	tweets, _, err := s.tweetSvc.List(ctx, 0, 0)
+	if err != nil {
+		return nil, fmt.Errorf("list tweets: %w", err)
+	}
+
+	for _, tweet := range tweets {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		// Skip archives still being written or currently being regenerated
+		if tweet.Status != domain.ArchiveStatusCompleted || tweet.ArchivePath == "" ||
+			s.tweetSvc.IsAIAnalysisInProgress(tweet.ID) {
+			continue
+		}
+		report.Archives++
+
+		manifest, err := readChecksumManifest(tweet.ArchivePath)
+		if errors.Is(err, os.ErrNotExist) {
+			if err := writeChecksumManifest(tweet.ArchivePath); err != nil {
+				report.Errors = append(report.Errors, fmt.Sprintf("%s: %v", tweet.ID, err))
+			} else {
+				report.ManifestsCreated++
+			}
+			continue
+		}
+		if err != nil {
+			report.Errors = append(report.Errors, fmt.Sprintf("%s: %v", tweet.ID, err))
+			continue
+		}
+
+		report.FilesVerified += len(manifest.Files)
+		issues := verifyChecksums(tweet.ArchivePath, manifest, func(path string) int64 {
+			return s.tweetSvc.MediaFileSize(ctx, path)
+		})
+		if len(issues) > 0 {
+			report.Damaged = append(report.Damaged, s.handleDamage(tweet, issues))
+		}
+	}
+
+	now := time.Now()
+	report.FinishedAt = &now
+
+	s.mu.Lock()
+	s.lastReport = report
+	s.mu.Unlock()
+
+	s.logger.Info("integrity scrub complete",
+		"archives", report.Archives,
+		"files_verified", report.FilesVerified,
+		"manifests_created", report.ManifestsCreated,
+		"damaged", len(report.Damaged),
+		"duration", now.Sub(report.StartedAt),
+	)
+	if len(report.Damaged) == 0 && s.eventEmitter != nil {
+		s.eventEmitter.EmitSuccess(domain.EventCategoryDisk, "integrity_service",
+			fmt.Sprintf("Integrity scrub passed: %d archives, %d files verified", report.Archives, report.FilesVerified),
+			domain.EventMetadata{
+				"archives":          report.Archives,
+				"files_verified":    report.FilesVerified,
+				"manifests_created": report.ManifestsCreated,
+			})
+	}
+	return report, nil
+}
+
+// handleDamage reports a damaged archive, flags its media and queues a repair.
+func (s *IntegrityService) handleDamage(tweet *domain.Tweet, issues []IntegrityIssue) ArchiveIntegrity {
+	result := ArchiveIntegrity{TweetID: string(tweet.ID), Issues: issues}
+
+	paths := make([]string, 0, len(issues))
+	for _, issue := range issues {
+		paths = append(paths, issue.Path)
+	}
+
+	s.logger.Warn("archive failed integrity check", "tweet_id", tweet.ID, "issues", len(issues))
+	if s.eventEmitter != nil {
+		s.eventEmitter.EmitWarning(domain.EventCategoryDisk, "integrity_service",
+			fmt.Sprintf("Integrity check failed for @%s: %d damaged file(s)", tweet.Author.Username, len(issues)),
+			domain.EventMetadata{
+				"tweet_id": string(tweet.ID),
+				"files":    paths,
+				"kind":     issues[0].Kind,
+			})
+	}
+
+	flagged, err := s.tweetSvc.MarkCorruptMedia(tweet.ID, paths)
+	if err != nil {
+		s.logger.Warn("failed to flag corrupt media", "tweet_id", tweet.ID, "error", err)
+	}
+	result.CorruptMedia = flagged
+
+	// Only downloaded files can be repaired; a Resync does not restore
+	// derived files, so queuing one for them would repeat on every scrub
+	if s.cfg.AutoRepair && (len(flagged) > 0 || refetchable(paths)) {
+		if err := s.tweetSvc.StartResync(tweet.ID); err != nil {
+			s.logger.Warn("failed to queue repair", "tweet_id", tweet.ID, "error", err)
+		} else {
+			result.RepairQueued = true
+		}
+	}
+	return result
+}
+
+// refetchable reports whether any damaged path is an avatar or link card
+// image, which MarkCorruptMedia removes for Resync to fetch again.
+func refetchable(paths []string) bool {
+	for _, rel := range paths {
+		if rel == "avatar.jpg" || rel == "media/"+CardImageFilename {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,135 @@
+package service
+
+import (
+	"context"
+	"path/filepath"
+
+	"github.com/iconidentify/xgrabba/internal/blobstore"
+	"github.com/iconidentify/xgrabba/internal/domain"
+	"github.com/iconidentify/xgrabba/pkg/phash"
+)
+
+// maxHashKeyframes caps the keyframe hash sequence stored per video.
+const maxHashKeyframes = 10
+
+// computeMediaHashes fills in SHA-256 and perceptual hashes for downloaded media
+// that does not have them yet. When extractKeyframes is set, missing video
+// keyframes are extracted with ffmpeg first; otherwise only existing frames are used.
+func (s *TweetService) computeMediaHashes(ctx context.Context, tweet *domain.Tweet, extractKeyframes bool) {
+	if tweet == nil {
+		return
+	}
+	// Media evicted to remote storage is restored for hashing
+	if missingMediaHashes(tweet) || (extractKeyframes && tweet.HasVideo() && needsKeyframeHashes(tweet)) {
+		s.withLocalMedia(ctx, tweet, func() { s.hashMedia(ctx, tweet, extractKeyframes) })
+		return
+	}
+	s.hashMedia(ctx, tweet, extractKeyframes)
+}
+
+func (s *TweetService) hashMedia(ctx context.Context, tweet *domain.Tweet, extractKeyframes bool) {
+	if extractKeyframes && tweet.HasVideo() && needsKeyframeHashes(tweet) {
+		s.ensureVideoKeyframes(ctx, tweet)
+	}
+
+	for i := range tweet.Media {
+		m := &tweet.Media[i]
+		if m.LocalPath == "" || ctx.Err() != nil {
+			continue
+		}
+		logger := s.logger.With("tweet_id", tweet.ID, "media_id", m.ID)
+
+		if m.SHA256 == "" {
+			sum, err := blobstore.FileSHA256(m.LocalPath)
+			if err != nil {
+				logger.Warn("failed to hash media file", "error", err)
+				continue
+			}
+			m.SHA256 = sum
+		}
+
+		switch m.Type {
+		case domain.MediaTypeImage:
+			if m.PHash == "" {
+				setPerceptualHashes(m, m.LocalPath)
+			}
+		case domain.MediaTypeVideo, domain.MediaTypeGIF:
+			if m.PHash == "" && m.PreviewURL != "" && filepath.IsAbs(m.PreviewURL) {
+				setPerceptualHashes(m, m.PreviewURL)
+			}
+			if len(m.KeyframeHashes) == 0 {
+				frames := listKeyframes(filepath.Join(tweet.ArchivePath, "media", "keyframes_"+m.ID), maxHashKeyframes)
+				for _, f := range frames {
+					h, err := phash.FromFile(f)
+					if err != nil {
+						continue
+					}
+					m.KeyframeHashes = append(m.KeyframeHashes, phash.Format(h.PHash))
+				}
+			}
+		}
+	}
+}
+
+// needsKeyframeHashes reports whether any downloaded video lacks a keyframe hash sequence.
+func needsKeyframeHashes(tweet *domain.Tweet) bool {
+	for _, m := range tweet.Media {
+		if (m.Type == domain.MediaTypeVideo || m.Type == domain.MediaTypeGIF) && m.LocalPath != "" && len(m.KeyframeHashes) == 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// setPerceptualHashes hashes imagePath into m. Undecodable formats (e.g. WebP)
+// are left without a perceptual hash; exact duplicates still match by SHA-256.
+func setPerceptualHashes(m *domain.Media, imagePath string) {
+	h, err := phash.FromFile(imagePath)
+	if err != nil {
+		return
+	}
+	m.PHash = phash.Format(h.PHash)
+	m.DHash = phash.Format(h.DHash)
+}
+
+// BackfillMediaHashes computes hashes for archives created before hashing existed.
+// It only uses keyframes already on disk, so it never invokes ffmpeg.
+func (s *TweetService) BackfillMediaHashes(ctx context.Context) {
+	tweets, _, err := s.List(ctx, 0, 0)
+	if err != nil {
+		return
+	}
+
+	updated := 0
+	for _, snapshot := range tweets {
+		if ctx.Err() != nil {
+			return
+		}
+		s.tweetsMu.RLock()
+		tweet, ok := s.tweets[snapshot.ID]
+		s.tweetsMu.RUnlock()
+		if !ok || tweet.Status != domain.ArchiveStatusCompleted || !missingMediaHashes(tweet) {
+			continue
+		}
+
+		s.computeMediaHashes(ctx, tweet, false)
+		if err := s.saveTweetMetadata(tweet); err != nil {
+			s.logger.Warn("failed to save hashes", "tweet_id", tweet.ID, "error", err)
+			continue
+		}
+		updated++
+	}
+
+	if updated > 0 {
+		s.logger.Info("media hash backfill complete", "tweets_updated", updated)
+	}
+}
+
+func missingMediaHashes(tweet *domain.Tweet) bool {
+	for _, m := range tweet.Media {
+		if m.LocalPath != "" && m.SHA256 == "" {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,137 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/iconidentify/xgrabba/internal/config"
+	"github.com/iconidentify/xgrabba/internal/domain"
+)
+
+// SetMediaQuality sets the default media quality policy. Tweets archived with
+// their own policy keep using it.
+func (s *TweetService) SetMediaQuality(cfg config.MediaConfig) {
+	s.mediaQuality = domain.MediaQuality{
+		ImageSize:          domain.ImageSize(cfg.ImageSize),
+		MaxVideoResolution: cfg.MaxVideoResolution,
+		MaxVideoBitrate:    cfg.MaxVideoBitrate,
+		KeepAllVariants:    cfg.KeepAllVariants,
+	}
+}
+
+// mediaQualityFor returns the policy that applies to a tweet's media.
+func (s *TweetService) mediaQualityFor(tweet *domain.Tweet) domain.MediaQuality {
+	if tweet != nil && tweet.MediaQuality != nil {
+		return *tweet.MediaQuality
+	}
+	return s.mediaQuality
+}
+
+// imageExtension picks the file extension for an image URL, including the
+// ?format= style X uses for sized renditions.
+func imageExtension(rawURL string) string {
+	if u, err := url.Parse(rawURL); err == nil {
+		switch u.Query().Get("format") {
+		case "png":
+			return ".png"
+		case "webp":
+			return ".webp"
+		case "jpg", "jpeg":
+			return ".jpg"
+		}
+		rawURL = u.Path
+	}
+	switch strings.ToLower(path.Ext(rawURL)) {
+	case ".png":
+		return ".png"
+	case ".webp":
+		return ".webp"
+	}
+	return ".jpg"
+}
+
+// selectVideoVariant points the media at the rendition chosen by the policy.
+// Without caps the parser's choice (the best rendition) is kept.
+func selectVideoVariant(media *domain.Media, quality domain.MediaQuality) {
+	if quality.MaxVideoResolution == 0 && quality.MaxVideoBitrate == 0 {
+		return
+	}
+	v, ok := quality.SelectVariant(media.Variants)
+	if !ok {
+		return
+	}
+	media.URL = v.URL
+	media.Bitrate = v.Bitrate
+	if v.Width > 0 && v.Height > 0 {
+		media.Width, media.Height = v.Width, v.Height
+	}
+}
+
+// variantFilename names a kept rendition after its resolution, falling back
+// to its bitrate or position when the resolution is unknown.
+func variantFilename(mediaID string, v domain.MediaVariant, index int) string {
+	switch {
+	case v.Resolution() > 0:
+		return fmt.Sprintf("%s_%dp.mp4", mediaID, v.Resolution())
+	case v.Bitrate > 0:
+		return fmt.Sprintf("%s_%dbps.mp4", mediaID, v.Bitrate)
+	default:
+		return fmt.Sprintf("%s_v%d.mp4", mediaID, index)
+	}
+}
+
+// downloadVariants stores every rendition other than the selected one. It is
+// best-effort: the selected file is already on disk.
+func (s *TweetService) downloadVariants(ctx context.Context, media *domain.Media, archivePath string) {
+	used := make(map[string]bool)
+	for i := range media.Variants {
+		v := &media.Variants[i]
+		if v.URL == media.URL {
+			v.LocalPath = media.LocalPath
+			continue
+		}
+		name := variantFilename(media.ID, *v, i)
+		if used[name] {
+			name = variantFilename(media.ID, domain.MediaVariant{}, i)
+		}
+		used[name] = true
+		dest := filepath.Join(archivePath, "media", name)
+		if err := s.downloadToFile(ctx, v.URL, dest); err != nil {
+			s.logger.Warn("failed to download video variant", "media_id", media.ID, "url", v.URL, "error", err)
+			continue
+		}
+		v.LocalPath = dest
+	}
+}
+
+// downloadToFile downloads url to dest, replacing any existing file.
+func (s *TweetService) downloadToFile(ctx context.Context, url, dest string) error {
+	content, _, err := s.downloader.Download(ctx, url)
+	if err != nil {
+		return fmt.Errorf("download failed: %w", err)
+	}
+	defer content.Close()
+
+	// Remove any previous file first: with the blob store enabled it may be a
+	// hardlink shared with other archives, and truncating it would corrupt them.
+	if err := os.Remove(dest); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("remove old file: %w", err)
+	}
+
+	f, err := os.Create(dest)
+	if err != nil {
+		return fmt.Errorf("create file: %w", err)
+	}
+	defer f.Close()
+
+	if _, err := io.Copy(f, content); err != nil {
+		return fmt.Errorf("write file: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,98 @@
+package service
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/iconidentify/xgrabba/internal/config"
+	"github.com/iconidentify/xgrabba/internal/domain"
+	"github.com/iconidentify/xgrabba/internal/downloader"
+)
+
+func TestImageExtension(t *testing.T) {
+	tests := []struct {
+		url  string
+		want string
+	}{
+		{"https://pbs.twimg.com/media/abc.jpg", ".jpg"},
+		{"https://pbs.twimg.com/media/abc.png", ".png"},
+		{"https://pbs.twimg.com/media/abc?format=png&name=orig", ".png"},
+		{"https://pbs.twimg.com/media/abc?format=webp&name=small", ".webp"},
+		{"https://example.com/image", ".jpg"},
+	}
+
+	for _, tt := range tests {
+		if got := imageExtension(tt.url); got != tt.want {
+			t.Errorf("imageExtension(%q) = %q, want %q", tt.url, got, tt.want)
+		}
+	}
+}
+
+func TestDownloadMedia_QualityPolicy(t *testing.T) {
+	var requested []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requested = append(requested, r.URL.String())
+		_, _ = w.Write([]byte(r.URL.Path))
+	}))
+	defer srv.Close()
+
+	archive := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(archive, "media"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	svc := &TweetService{
+		downloader: downloader.NewHTTPDownloader(config.DownloadConfig{Timeout: 10 * time.Second, ReadTimeout: 10 * time.Second}),
+		logger:     slog.New(slog.NewTextHandler(io.Discard, nil)),
+	}
+
+	video := &domain.Media{
+		ID:   "v1",
+		Type: domain.MediaTypeVideo,
+		URL:  srv.URL + "/1080.mp4",
+		Variants: []domain.MediaVariant{
+			{URL: srv.URL + "/1080.mp4", Bitrate: 10368000, Width: 1920, Height: 1080},
+			{URL: srv.URL + "/720.mp4", Bitrate: 2176000, Width: 1280, Height: 720},
+			{URL: srv.URL + "/360.mp4", Bitrate: 632000, Width: 640, Height: 360},
+		},
+	}
+	quality := domain.MediaQuality{MaxVideoResolution: 720, KeepAllVariants: true}
+	if err := svc.downloadMediaWithoutAnalysis(context.Background(), video, archive, quality); err != nil {
+		t.Fatal(err)
+	}
+
+	if video.URL != srv.URL+"/720.mp4" || video.Height != 720 {
+		t.Errorf("selected %s (%dp), want 720.mp4", video.URL, video.Height)
+	}
+	data, err := os.ReadFile(filepath.Join(archive, "media", "v1.mp4"))
+	if err != nil || string(data) != "/720.mp4" {
+		t.Errorf("v1.mp4 = %q, %v; want the 720p rendition", data, err)
+	}
+	for _, name := range []string{"v1_1080p.mp4", "v1_360p.mp4"} {
+		if _, err := os.Stat(filepath.Join(archive, "media", name)); err != nil {
+			t.Errorf("kept variant %s missing: %v", name, err)
+		}
+	}
+	if video.Variants[1].LocalPath != video.LocalPath {
+		t.Errorf("selected variant local path = %q, want %q", video.Variants[1].LocalPath, video.LocalPath)
+	}
+
+	image := &domain.Media{ID: "p1", Type: domain.MediaTypeImage, URL: srv.URL + "/media/abc.png"}
+	requested = nil
+	if err := svc.downloadMediaWithoutAnalysis(context.Background(), image, archive, domain.MediaQuality{ImageSize: domain.ImageSizeOrig}); err != nil {
+		t.Fatal(err)
+	}
+	// Only pbs.twimg.com URLs are rewritten
+	if len(requested) != 1 || requested[0] != "/media/abc.png" {
+		t.Errorf("requested %v, want the original URL", requested)
+	}
+	if filepath.Base(image.LocalPath) != "p1.png" {
+		t.Errorf("image saved as %s, want p1.png", image.LocalPath)
+	}
+}
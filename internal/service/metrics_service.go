@@ -0,0 +1,279 @@
+package service
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/iconidentify/xgrabba/internal/config"
+	"github.com/iconidentify/xgrabba/internal/domain"
+)
+
+// MetricsHistoryFilename is the per-archive engagement time series, one JSON
+// sample per line.
+const MetricsHistoryFilename = "metrics.jsonl"
+
+// metricsPollTick is how often the scheduler looks for archives that are due.
+const metricsPollTick = 5 * time.Minute
+
+// metricsPollSpacing spaces out syndication requests within a cycle.
+const metricsPollSpacing = time.Second
+
+// MetricsFetcher retrieves current engagement counts for a tweet.
+type MetricsFetcher interface {
+	FetchMetrics(ctx context.Context, tweetID string) (*domain.TweetMetrics, error)
+}
+
+// MetricsService re-polls engagement metrics of archived tweets on a decaying
+// schedule and stores them as a per-archive time series.
+type MetricsService struct {
+	tweetSvc *TweetService
+	fetcher  MetricsFetcher
+	cfg      config.MetricsConfig
+	tiers    []config.MetricsTier
+	logger   *slog.Logger
+
+	mu          sync.Mutex
+	lastSampled map[domain.TweetID]time.Time // Last sample or failed attempt
+}
+
+// NewMetricsService creates a new metrics re-polling service.
+func NewMetricsService(tweetSvc *TweetService, fetcher MetricsFetcher, cfg config.MetricsConfig, logger *slog.Logger) *MetricsService {
+	tiers, err := cfg.Tiers()
+	if err != nil {
+		logger.Warn("invalid metrics poll schedule, polling disabled", "error", err)
+		tiers = nil
+	}
+	return &MetricsService{
+		tweetSvc:    tweetSvc,
+		fetcher:     fetcher,
+		cfg:         cfg,
+		tiers:       tiers,
+		logger:      logger,
+		lastSampled: make(map[domain.TweetID]time.Time),
+	}
+}
+
+// Start runs scheduled metrics polls until ctx is cancelled.
+func (s *MetricsService) Start(ctx context.Context) {
+	if !s.cfg.PollEnabled || len(s.tiers) == 0 || s.fetcher == nil {
+		return
+	}
+
+	ticker := time.NewTicker(metricsPollTick)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			polled, err := s.PollDue(ctx)
+			if err != nil && ctx.Err() == nil {
+				s.logger.Warn("metrics poll failed", "error", err)
+			}
+			if polled > 0 {
+				s.logger.Info("metrics poll complete", "sampled", polled)
+			}
+		}
+	}
+}
+
+// metricsPollDue reports whether a tweet posted at posted and last sampled at
+// last should be polled at now. Tweets older than the last tier are not polled.
+func metricsPollDue(posted, last, now time.Time, tiers []config.MetricsTier) bool {
+	age := now.Sub(posted)
+	for _, tier := range tiers {
+		if age < tier.MaxAge {
+			return !now.Before(last.Add(tier.Interval))
+		}
+	}
+	return false
+}
+
+// PollDue samples every completed archive whose next poll is due, up to
+// MaxPerCycle per call, oldest sample first. It returns the number sampled.
+func (s *MetricsService) PollDue(ctx context.Context) (int, error) {
+	tweets, _, err := s.tweetSvc.List(ctx, 0, 0)
+	if err != nil {
+		return 0, fmt.Errorf("list tweets: %w", err)
+	}
+
+	type dueTweet struct {
+		tweet *domain.Tweet
+		last  time.Time
+	}
+	now := time.Now()
+	var due []dueTweet
+	for _, tweet := range tweets {
+		if tweet.Status != domain.ArchiveStatusCompleted || tweet.ArchivePath == "" {
+			continue
+		}
+		posted := tweet.PostedAt
+		if posted.IsZero() {
+			posted = tweet.CreatedAt
+		}
+		last := s.lastSample(tweet)
+		if metricsPollDue(posted, last, now, s.tiers) {
+			due = append(due, dueTweet{tweet: tweet, last: last})
+		}
+	}
+	sort.Slice(due, func(i, j int) bool { return due[i].last.Before(due[j].last) })
+	if s.cfg.MaxPerCycle > 0 && len(due) > s.cfg.MaxPerCycle {
+		due = due[:s.cfg.MaxPerCycle]
+	}
+
+	sampled := 0
+	for i, d := range due {
+		if ctx.Err() != nil {
+			return sampled, ctx.Err()
+		}
+		if i > 0 {
+			select {
+			case <-ctx.Done():
+				return sampled, ctx.Err()
+			case <-time.After(metricsPollSpacing):
+			}
+		}
+
+		metrics, err := s.fetcher.FetchMetrics(ctx, string(d.tweet.ID))
+		if err != nil {
+			// Back off until the next interval rather than retrying every tick
+			s.setLastSampled(d.tweet.ID, time.Now())
+			s.logger.Debug("failed to fetch metrics", "tweet_id", d.tweet.ID, "error", err)
+			continue
+		}
+
+		samples := []domain.MetricsSample{domain.NewMetricsSample(time.Now().UTC(), domain.MetricsSourcePoll, *metrics)}
+		if _, err := os.Stat(filepath.Join(d.tweet.ArchivePath, MetricsHistoryFilename)); os.IsNotExist(err) {
+			// The history starts with the counts captured at archive time
+			samples = append([]domain.MetricsSample{archiveMetricsSample(d.tweet)}, samples...)
+		}
+		if err := s.appendSamples(d.tweet, samples...); err != nil {
+			s.logger.Warn("failed to record metrics sample", "tweet_id", d.tweet.ID, "error", err)
+			continue
+		}
+		sampled++
+	}
+	return sampled, nil
+}
+
+// lastSample returns when a tweet was last sampled. Archives without a history
+// file count as sampled at archive time; the file is only created once a poll
+// succeeds.
+func (s *MetricsService) lastSample(tweet *domain.Tweet) time.Time {
+	s.mu.Lock()
+	last, ok := s.lastSampled[tweet.ID]
+	s.mu.Unlock()
+	if ok {
+		return last
+	}
+
+	samples, err := readMetricsHistory(tweet.ArchivePath)
+	if err != nil && !os.IsNotExist(err) {
+		s.logger.Warn("failed to read metrics history", "tweet_id", tweet.ID, "error", err)
+	}
+	if len(samples) == 0 {
+		last = archiveMetricsSample(tweet).SampledAt
+	} else {
+		last = samples[len(samples)-1].SampledAt
+	}
+	s.setLastSampled(tweet.ID, last)
+	return last
+}
+
+func (s *MetricsService) setLastSampled(tweetID domain.TweetID, t time.Time) {
+	s.mu.Lock()
+	s.lastSampled[tweetID] = t
+	s.mu.Unlock()
+}
+
+// appendSamples adds samples to a tweet's history file and mirrors it.
+func (s *MetricsService) appendSamples(tweet *domain.Tweet, samples ...domain.MetricsSample) error {
+	var lines []byte
+	for _, sample := range samples {
+		line, err := json.Marshal(sample)
+		if err != nil {
+			return fmt.Errorf("marshal sample: %w", err)
+		}
+		lines = append(append(lines, line...), '\n')
+	}
+	path := filepath.Join(tweet.ArchivePath, MetricsHistoryFilename)
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	_, err = f.Write(lines)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return fmt.Errorf("write %s: %w", MetricsHistoryFilename, err)
+	}
+	s.setLastSampled(tweet.ID, samples[len(samples)-1].SampledAt)
+
+	if data, err := os.ReadFile(path); err == nil {
+		s.tweetSvc.mirrorMetadata(tweet, map[string][]byte{MetricsHistoryFilename: data})
+	}
+	return nil
+}
+
+// History returns a tweet's engagement samples, oldest first. Archives that
+// have not been polled yet report their archive-time metrics as one sample.
+func (s *MetricsService) History(ctx context.Context, tweetID domain.TweetID) ([]domain.MetricsSample, error) {
+	tweet, ok := s.tweetSvc.tweetSnapshot(tweetID)
+	if !ok {
+		return nil, domain.ErrVideoNotFound
+	}
+	if tweet.ArchivePath == "" {
+		return []domain.MetricsSample{archiveMetricsSample(tweet)}, nil
+	}
+
+	samples, err := readMetricsHistory(tweet.ArchivePath)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	if len(samples) == 0 {
+		return []domain.MetricsSample{archiveMetricsSample(tweet)}, nil
+	}
+	return samples, nil
+}
+
+// archiveMetricsSample is the sample implied by a tweet's stored metrics.
+func archiveMetricsSample(tweet *domain.Tweet) domain.MetricsSample {
+	at := tweet.CreatedAt
+	if tweet.ArchivedAt != nil {
+		at = *tweet.ArchivedAt
+	}
+	return domain.NewMetricsSample(at.UTC(), domain.MetricsSourceArchive, tweet.Metrics)
+}
+
+// readMetricsHistory parses metrics.jsonl. Malformed lines (e.g. a write cut
+// short by a crash) are skipped.
+func readMetricsHistory(archivePath string) ([]domain.MetricsSample, error) {
+	data, err := os.ReadFile(filepath.Join(archivePath, MetricsHistoryFilename))
+	if err != nil {
+		return nil, err
+	}
+	var samples []domain.MetricsSample
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+		var sample domain.MetricsSample
+		if json.Unmarshal(line, &sample) != nil {
+			continue
+		}
+		samples = append(samples, sample)
+	}
+	return samples, scanner.Err()
+}
@@ -0,0 +1,183 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/iconidentify/xgrabba/internal/config"
+	"github.com/iconidentify/xgrabba/internal/domain"
+)
+
+type fakeMetricsFetcher struct {
+	metrics domain.TweetMetrics
+	err     error
+	calls   int
+}
+
+func (f *fakeMetricsFetcher) FetchMetrics(ctx context.Context, tweetID string) (*domain.TweetMetrics, error) {
+	f.calls++
+	if f.err != nil {
+		return nil, f.err
+	}
+	m := f.metrics
+	return &m, nil
+}
+
+func newMetricsTestService(t *testing.T, fetcher MetricsFetcher, posted time.Time) (*MetricsService, *domain.Tweet) {
+	t.Helper()
+	archive := t.TempDir()
+	archivedAt := posted.Add(time.Minute)
+	tweet := &domain.Tweet{
+		ID:          "1",
+		Status:      domain.ArchiveStatusCompleted,
+		ArchivePath: archive,
+		PostedAt:    posted,
+		ArchivedAt:  &archivedAt,
+		Metrics:     domain.TweetMetrics{Likes: 10, Retweets: 2},
+	}
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	tweetSvc := &TweetService{
+		logger: logger,
+		tweets: map[domain.TweetID]*domain.Tweet{tweet.ID: tweet},
+	}
+	cfg := config.MetricsConfig{PollEnabled: true, Schedule: "1h@24h,24h@720h", MaxPerCycle: 10}
+	return NewMetricsService(tweetSvc, fetcher, cfg, logger), tweet
+}
+
+func TestMetricsPollDue(t *testing.T) {
+	tiers := []config.MetricsTier{
+		{Interval: time.Hour, MaxAge: 24 * time.Hour},
+		{Interval: 24 * time.Hour, MaxAge: 30 * 24 * time.Hour},
+	}
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name   string
+		posted time.Time
+		last   time.Time
+		want   bool
+	}{
+		{"first tier due", now.Add(-5 * time.Hour), now.Add(-time.Hour), true},
+		{"first tier not due", now.Add(-5 * time.Hour), now.Add(-30 * time.Minute), false},
+		{"second tier not due", now.Add(-3 * 24 * time.Hour), now.Add(-2 * time.Hour), false},
+		{"second tier due", now.Add(-3 * 24 * time.Hour), now.Add(-25 * time.Hour), true},
+		{"past last tier", now.Add(-60 * 24 * time.Hour), now.Add(-60 * 24 * time.Hour), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := metricsPollDue(tt.posted, tt.last, now, tiers); got != tt.want {
+				t.Errorf("metricsPollDue() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMetricsService_PollDue(t *testing.T) {
+	fetcher := &fakeMetricsFetcher{metrics: domain.TweetMetrics{Likes: 25, Retweets: 4, Replies: 1}}
+	svc, tweet := newMetricsTestService(t, fetcher, time.Now().Add(-3*time.Hour))
+	ctx := context.Background()
+
+	polled, err := svc.PollDue(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if polled != 1 {
+		t.Fatalf("polled = %d, want 1", polled)
+	}
+
+	samples, err := svc.History(ctx, tweet.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(samples) != 2 {
+		t.Fatalf("got %d samples, want archive seed + poll", len(samples))
+	}
+	if samples[0].Source != domain.MetricsSourceArchive || samples[0].Likes != 10 {
+		t.Errorf("seed sample = %+v", samples[0])
+	}
+	if samples[1].Source != domain.MetricsSourcePoll || samples[1].Likes != 25 || samples[1].Views != nil {
+		t.Errorf("poll sample = %+v", samples[1])
+	}
+
+	// Just sampled, so nothing is due until the next interval
+	if polled, _ := svc.PollDue(ctx); polled != 0 {
+		t.Errorf("second poll sampled %d, want 0", polled)
+	}
+	if fetcher.calls != 1 {
+		t.Errorf("fetcher called %d times, want 1", fetcher.calls)
+	}
+}
+
+func TestMetricsService_PollDueFailureBacksOff(t *testing.T) {
+	fetcher := &fakeMetricsFetcher{err: errors.New("rate limited")}
+	svc, tweet := newMetricsTestService(t, fetcher, time.Now().Add(-3*time.Hour))
+	ctx := context.Background()
+
+	if polled, _ := svc.PollDue(ctx); polled != 0 {
+		t.Errorf("polled = %d, want 0", polled)
+	}
+	if polled, _ := svc.PollDue(ctx); polled != 0 {
+		t.Errorf("polled = %d, want 0", polled)
+	}
+	if fetcher.calls != 1 {
+		t.Errorf("failed fetch should back off until next interval, got %d calls", fetcher.calls)
+	}
+
+	if _, err := os.Stat(filepath.Join(tweet.ArchivePath, MetricsHistoryFilename)); !os.IsNotExist(err) {
+		t.Error("a failed poll should not create the history file")
+	}
+}
+
+func TestMetricsService_PollDueSkipsOldArchives(t *testing.T) {
+	fetcher := &fakeMetricsFetcher{}
+	svc, tweet := newMetricsTestService(t, fetcher, time.Now().Add(-60*24*time.Hour))
+
+	if polled, _ := svc.PollDue(context.Background()); polled != 0 || fetcher.calls != 0 {
+		t.Errorf("polled = %d with %d fetches, want none", polled, fetcher.calls)
+	}
+	if _, err := os.Stat(filepath.Join(tweet.ArchivePath, MetricsHistoryFilename)); !os.IsNotExist(err) {
+		t.Error("archives that are not due should not get a history file")
+	}
+}
+
+func TestMetricsService_HistoryWithoutFile(t *testing.T) {
+	svc, tweet := newMetricsTestService(t, &fakeMetricsFetcher{}, time.Now().Add(-60*24*time.Hour))
+
+	samples, err := svc.History(context.Background(), tweet.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(samples) != 1 || samples[0].Likes != 10 || !samples[0].SampledAt.Equal(*tweet.ArchivedAt) {
+		t.Errorf("expected synthesized archive sample, got %+v", samples)
+	}
+	if _, err := os.Stat(filepath.Join(tweet.ArchivePath, MetricsHistoryFilename)); !os.IsNotExist(err) {
+		t.Error("History should not create the history file")
+	}
+
+	if _, err := svc.History(context.Background(), "missing"); !errors.Is(err, domain.ErrVideoNotFound) {
+		t.Errorf("missing tweet err = %v, want ErrVideoNotFound", err)
+	}
+}
+
+func TestReadMetricsHistory_SkipsMalformedLines(t *testing.T) {
+	dir := t.TempDir()
+	data := `{"sampled_at":"2024-01-01T00:00:00Z","source":"poll","likes":1,"retweets":0,"replies":0}
+{"sampled_at":"2024-01-01T01:00:00Z","sou
+{"sampled_at":"2024-01-01T02:00:00Z","source":"poll","likes":3,"retweets":0,"replies":0}
+`
+	os.WriteFile(filepath.Join(dir, MetricsHistoryFilename), []byte(data), 0644)
+
+	samples, err := readMetricsHistory(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(samples) != 2 || samples[1].Likes != 3 {
+		t.Errorf("got %+v", samples)
+	}
+}
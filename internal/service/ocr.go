@@ -0,0 +1,134 @@
+package service
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/iconidentify/xgrabba/internal/domain"
+	"github.com/iconidentify/xgrabba/pkg/ocr"
+)
+
+// maxOCRKeyframes caps how many keyframes per video are sent to the OCR engine.
+const maxOCRKeyframes = 10
+
+// SetOCREngine enables OCR for images and video keyframes.
+func (s *TweetService) SetOCREngine(engine ocr.Engine) {
+	s.ocrEngine = engine
+}
+
+func (s *TweetService) ocrEngineName() string {
+	if s.ocrEngine == nil {
+		return ""
+	}
+	return s.ocrEngine.Name()
+}
+
+// runOCR extracts text for every downloaded media item. Media that already
+// has OCR text is skipped unless force is set.
+func (s *TweetService) runOCR(ctx context.Context, tweet *domain.Tweet, force bool) {
+	if s.ocrEngine == nil || tweet == nil || !needsOCR(tweet, force) {
+		return
+	}
+	// Media evicted to remote storage is restored for the run
+	s.withLocalMedia(ctx, tweet, func() { s.ocrMedia(ctx, tweet, force) })
+}
+
+// needsOCR reports whether runOCR has any media to process.
+func needsOCR(tweet *domain.Tweet, force bool) bool {
+	for _, m := range tweet.Media {
+		if m.LocalPath != "" && (m.OCRText == "" || force) {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *TweetService) ocrMedia(ctx context.Context, tweet *domain.Tweet, force bool) {
+	if tweet.HasVideo() {
+		s.ensureVideoKeyframes(ctx, tweet)
+	}
+
+	for i := range tweet.Media {
+		m := &tweet.Media[i]
+		if m.LocalPath == "" || (m.OCRText != "" && !force) {
+			continue
+		}
+
+		var imagePaths []string
+		switch m.Type {
+		case domain.MediaTypeImage:
+			imagePaths = []string{m.LocalPath}
+		case domain.MediaTypeVideo, domain.MediaTypeGIF:
+			imagePaths = listKeyframes(filepath.Join(tweet.ArchivePath, "media", "keyframes_"+m.ID), maxOCRKeyframes)
+		}
+		if len(imagePaths) == 0 {
+			continue
+		}
+
+		logger := s.logger.With("tweet_id", tweet.ID, "media_id", m.ID, "engine", s.ocrEngine.Name())
+
+		var texts []string
+		for _, p := range imagePaths {
+			if ctx.Err() != nil {
+				return
+			}
+			text, err := s.ocrEngine.ExtractText(ctx, p)
+			if err != nil {
+				logger.Warn("OCR failed", "path", p, "error", err)
+				continue
+			}
+			texts = append(texts, text)
+		}
+
+		m.OCRText = mergeOCRText(texts)
+		m.OCREngine = s.ocrEngine.Name()
+		logger.Info("OCR complete", "frames", len(imagePaths), "text_len", len(m.OCRText))
+	}
+}
+
+// listKeyframes returns up to max sorted JPEG frames from a keyframes directory.
+func listKeyframes(dir string, max int) []string {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Name() < entries[j].Name() })
+
+	var paths []string
+	for _, e := range entries {
+		if e.IsDir() {
+			continue
+		}
+		ext := strings.ToLower(filepath.Ext(e.Name()))
+		if ext != ".jpg" && ext != ".jpeg" {
+			continue
+		}
+		paths = append(paths, filepath.Join(dir, e.Name()))
+		if len(paths) >= max {
+			break
+		}
+	}
+	return paths
+}
+
+// mergeOCRText joins text from several frames, dropping lines already seen.
+// Consecutive video keyframes often show the same caption or overlay.
+func mergeOCRText(texts []string) string {
+	seen := make(map[string]bool)
+	var lines []string
+	for _, text := range texts {
+		for _, line := range strings.Split(text, "\n") {
+			line = strings.TrimSpace(line)
+			key := strings.ToLower(line)
+			if line == "" || seen[key] {
+				continue
+			}
+			seen[key] = true
+			lines = append(lines, line)
+		}
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,96 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/iconidentify/xgrabba/internal/domain"
+)
+
+type fakeOCREngine struct {
+	text  map[string]string
+	calls int
+}
+
+func (f *fakeOCREngine) Name() string { return "fake" }
+
+func (f *fakeOCREngine) ExtractText(ctx context.Context, imagePath string) (string, error) {
+	f.calls++
+	text, ok := f.text[filepath.Base(imagePath)]
+	if !ok {
+		return "", fmt.Errorf("no text for %s", imagePath)
+	}
+	return text, nil
+}
+
+func TestMergeOCRText(t *testing.T) {
+	got := mergeOCRText([]string{
+		"BREAKING NEWS\nMarkets down",
+		"Breaking News\nRecovery expected",
+		"",
+	})
+	want := "BREAKING NEWS\nMarkets down\nRecovery expected"
+	if got != want {
+		t.Errorf("mergeOCRText = %q, want %q", got, want)
+	}
+}
+
+func TestRunOCR(t *testing.T) {
+	dir := t.TempDir()
+	mediaDir := filepath.Join(dir, "media")
+	framesDir := filepath.Join(mediaDir, "keyframes_v1")
+	if err := os.MkdirAll(framesDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"frame_000.jpg", "frame_001.jpg"} {
+		os.WriteFile(filepath.Join(framesDir, name), []byte("x"), 0644)
+	}
+	os.WriteFile(filepath.Join(mediaDir, "i1.png"), []byte("x"), 0644)
+
+	engine := &fakeOCREngine{text: map[string]string{
+		"i1.png":        "screenshot text",
+		"frame_000.jpg": "caption one",
+		"frame_001.jpg": "caption one\ncaption two",
+	}}
+	svc := &TweetService{
+		logger:    slog.New(slog.NewTextHandler(io.Discard, nil)),
+		ocrEngine: engine,
+	}
+
+	tweet := &domain.Tweet{
+		ID:          "1",
+		ArchivePath: dir,
+		Media: []domain.Media{
+			{ID: "i1", Type: domain.MediaTypeImage, LocalPath: filepath.Join(mediaDir, "i1.png")},
+			{ID: "v1", Type: domain.MediaTypeVideo, LocalPath: filepath.Join(mediaDir, "v1.mp4")},
+			{ID: "i2", Type: domain.MediaTypeImage}, // not downloaded
+		},
+	}
+
+	svc.runOCR(context.Background(), tweet, false)
+
+	if tweet.Media[0].OCRText != "screenshot text" || tweet.Media[0].OCREngine != "fake" {
+		t.Errorf("image OCR = %+v", tweet.Media[0])
+	}
+	if tweet.Media[1].OCRText != "caption one\ncaption two" {
+		t.Errorf("video OCR = %q", tweet.Media[1].OCRText)
+	}
+	if tweet.Media[2].OCRText != "" {
+		t.Error("undownloaded media should be skipped")
+	}
+
+	calls := engine.calls
+	svc.runOCR(context.Background(), tweet, false)
+	if engine.calls != calls {
+		t.Error("media with OCR text should be skipped without force")
+	}
+
+	if !svc.tweetMatchesQuery(tweet, "screenshot") {
+		t.Error("OCR text should be searchable")
+	}
+}
@@ -23,11 +23,13 @@ func NewPlaylistService(
 	tweetSvc *TweetService,
 	logger *slog.Logger,
 ) *PlaylistService {
-	return &PlaylistService{
+	s := &PlaylistService{
 		repo:     repo,
 		tweetSvc: tweetSvc,
 		logger:   logger,
 	}
+	s.failInterruptedDigests()
+	return s
 }
 
 // generateID creates a simple ID from timestamp and random suffix.
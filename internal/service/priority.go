@@ -0,0 +1,58 @@
+package service
+
+import (
+	"sync"
+
+	"github.com/iconidentify/xgrabba/internal/domain"
+)
+
+// prioritySemaphore limits concurrent archive processing. A freed slot goes
+// to the highest-priority waiter, oldest first.
+type prioritySemaphore struct {
+	mu      sync.Mutex
+	free    int
+	waiters [3][]chan struct{} // Indexed by ArchivePriority.Rank
+}
+
+func newPrioritySemaphore(slots int) *prioritySemaphore {
+	return &prioritySemaphore{free: slots}
+}
+
+// acquire blocks until a slot is available for the given priority.
+func (p *prioritySemaphore) acquire(priority domain.ArchivePriority) {
+	p.mu.Lock()
+	if p.free > 0 && p.waiting() == 0 {
+		p.free--
+		p.mu.Unlock()
+		return
+	}
+	ch := make(chan struct{})
+	rank := priority.Rank()
+	p.waiters[rank] = append(p.waiters[rank], ch)
+	p.mu.Unlock()
+	<-ch
+}
+
+// release hands the slot to the next waiter or returns it to the pool.
+func (p *prioritySemaphore) release() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for rank := range p.waiters {
+		if len(p.waiters[rank]) == 0 {
+			continue
+		}
+		ch := p.waiters[rank][0]
+		p.waiters[rank] = p.waiters[rank][1:]
+		close(ch)
+		return
+	}
+	p.free++
+}
+
+func (p *prioritySemaphore) waiting() int {
+	n := 0
+	for _, w := range p.waiters {
+		n += len(w)
+	}
+	return n
+}
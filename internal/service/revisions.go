@@ -0,0 +1,291 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"time"
+
+	"github.com/iconidentify/xgrabba/internal/domain"
+)
+
+// ErrRevisionNotFound is returned when a requested revision does not exist.
+var ErrRevisionNotFound = errors.New("revision not found")
+
+// maxDiffCells bounds the LCS table used for text diffs. Longer texts are
+// reported as a whole replacement.
+const maxDiffCells = 4_000_000
+
+// TextDiffOp is one span of a word-level text diff.
+type TextDiffOp struct {
+	Op   string `json:"op"` // equal, insert, delete
+	Text string `json:"text"`
+}
+
+// SetDiff lists IDs added and removed between two revisions.
+type SetDiff struct {
+	Added   []string `json:"added,omitempty"`
+	Removed []string `json:"removed,omitempty"`
+}
+
+// MetricDelta is the change of one engagement metric.
+type MetricDelta struct {
+	From  int `json:"from"`
+	To    int `json:"to"`
+	Delta int `json:"delta"`
+}
+
+// RevisionDiff compares two revisions of a tweet.
+type RevisionDiff struct {
+	TweetID      domain.TweetID         `json:"tweet_id"`
+	From         domain.TweetRevision   `json:"from"`
+	To           domain.TweetRevision   `json:"to"`
+	TextChanged  bool                   `json:"text_changed"`
+	Text         []TextDiffOp           `json:"text,omitempty"`
+	EditTweetIDs *SetDiff               `json:"edit_tweet_ids,omitempty"`
+	Metrics      map[string]MetricDelta `json:"metrics,omitempty"`
+	Media        *SetDiff               `json:"media,omitempty"`
+}
+
+// snapshotRevision captures the tracked fields of a tweet.
+func snapshotRevision(tweet *domain.Tweet) domain.TweetRevision {
+	rev := domain.TweetRevision{
+		Text:         tweet.Text,
+		EditTweetIDs: slices.Clone(tweet.EditTweetIDs),
+		Metrics:      tweet.Metrics,
+	}
+	for _, m := range tweet.Media {
+		rev.MediaIDs = append(rev.MediaIDs, m.ID)
+	}
+	return rev
+}
+
+// lastRevision returns the newest recorded revision, or the tweet's current
+// state when none has been recorded yet.
+func lastRevision(tweet *domain.Tweet) domain.TweetRevision {
+	if n := len(tweet.Revisions); n > 0 {
+		return tweet.Revisions[n-1]
+	}
+	return snapshotRevision(tweet)
+}
+
+// changedFields lists the tracked fields that differ between two snapshots.
+func changedFields(prev, cur domain.TweetRevision) []string {
+	var changed []string
+	if prev.Text != cur.Text {
+		changed = append(changed, domain.RevisionFieldText)
+	}
+	if !slices.Equal(prev.EditTweetIDs, cur.EditTweetIDs) {
+		changed = append(changed, domain.RevisionFieldEditHistory)
+	}
+	if prev.Metrics != cur.Metrics {
+		changed = append(changed, domain.RevisionFieldMetrics)
+	}
+	if !slices.Equal(prev.MediaIDs, cur.MediaIDs) {
+		changed = append(changed, domain.RevisionFieldMedia)
+	}
+	return changed
+}
+
+// newEdits reports whether the edit history grew between two snapshots.
+// Archives made before edit history was recorded have no previous IDs; for
+// those a text change alongside a multi-version history counts as an edit.
+func newEdits(prev, cur domain.TweetRevision) bool {
+	if len(cur.EditTweetIDs) <= 1 || len(cur.EditTweetIDs) <= len(prev.EditTweetIDs) {
+		return false
+	}
+	return len(prev.EditTweetIDs) > 0 || prev.Text != cur.Text
+}
+
+// recordRevision appends a revision when cur differs from the last recorded
+// state. The first time, the prior state is stored as the archive baseline.
+// It returns the new revision, or nil when nothing changed.
+func recordRevision(tweet *domain.Tweet, prev, cur domain.TweetRevision) *domain.TweetRevision {
+	changed := changedFields(prev, cur)
+	if len(changed) == 0 {
+		return nil
+	}
+
+	if len(tweet.Revisions) == 0 {
+		baseline := prev
+		baseline.Number = 1
+		baseline.Source = domain.RevisionSourceArchive
+		baseline.Changed = nil
+		baseline.Edited = false
+		baseline.RecordedAt = tweet.CreatedAt
+		if tweet.ArchivedAt != nil {
+			baseline.RecordedAt = *tweet.ArchivedAt
+		}
+		tweet.Revisions = append(tweet.Revisions, baseline)
+	}
+
+	cur.Number = len(tweet.Revisions) + 1
+	cur.RecordedAt = time.Now().UTC()
+	cur.Source = domain.RevisionSourceResync
+	cur.Changed = changed
+	cur.Edited = newEdits(prev, cur)
+	tweet.Revisions = append(tweet.Revisions, cur)
+	return &tweet.Revisions[len(tweet.Revisions)-1]
+}
+
+// ListRevisions returns a tweet's recorded revisions, oldest first.
+func (s *TweetService) ListRevisions(ctx context.Context, tweetID domain.TweetID) ([]domain.TweetRevision, error) {
+	s.tweetsMu.RLock()
+	defer s.tweetsMu.RUnlock()
+
+	tweet, ok := s.tweets[tweetID]
+	if !ok {
+		return nil, domain.ErrVideoNotFound
+	}
+	if len(tweet.Revisions) == 0 {
+		return []domain.TweetRevision{}, nil
+	}
+	return slices.Clone(tweet.Revisions), nil
+}
+
+// DiffRevisions compares revisions from and to of a tweet. A zero to selects
+// the latest revision; a zero from selects the one before to.
+func (s *TweetService) DiffRevisions(ctx context.Context, tweetID domain.TweetID, from, to int) (*RevisionDiff, error) {
+	s.tweetsMu.RLock()
+	tweet, ok := s.tweets[tweetID]
+	if !ok {
+		s.tweetsMu.RUnlock()
+		return nil, domain.ErrVideoNotFound
+	}
+	if to == 0 {
+		to = len(tweet.Revisions)
+	}
+	if from == 0 {
+		from = to - 1
+	}
+	a, b := tweet.Revision(from), tweet.Revision(to)
+	if a == nil || b == nil {
+		s.tweetsMu.RUnlock()
+		return nil, fmt.Errorf("%w: %d..%d", ErrRevisionNotFound, from, to)
+	}
+	fromRev, toRev := *a, *b
+	s.tweetsMu.RUnlock()
+
+	return diffRevisions(tweetID, fromRev, toRev), nil
+}
+
+// diffRevisions builds the field-by-field diff of two revisions.
+func diffRevisions(tweetID domain.TweetID, from, to domain.TweetRevision) *RevisionDiff {
+	diff := &RevisionDiff{TweetID: tweetID, From: from, To: to}
+
+	if from.Text != to.Text {
+		diff.TextChanged = true
+		diff.Text = diffWords(from.Text, to.Text)
+	}
+	if d := diffSets(from.EditTweetIDs, to.EditTweetIDs); d != nil {
+		diff.EditTweetIDs = d
+	}
+	if d := diffSets(from.MediaIDs, to.MediaIDs); d != nil {
+		diff.Media = d
+	}
+
+	metrics := map[string][2]int{
+		"likes":    {from.Metrics.Likes, to.Metrics.Likes},
+		"retweets": {from.Metrics.Retweets, to.Metrics.Retweets},
+		"replies":  {from.Metrics.Replies, to.Metrics.Replies},
+		"quotes":   {from.Metrics.Quotes, to.Metrics.Quotes},
+		"views":    {from.Metrics.Views, to.Metrics.Views},
+	}
+	for name, v := range metrics {
+		if v[0] == v[1] {
+			continue
+		}
+		if diff.Metrics == nil {
+			diff.Metrics = make(map[string]MetricDelta)
+		}
+		diff.Metrics[name] = MetricDelta{From: v[0], To: v[1], Delta: v[1] - v[0]}
+	}
+	return diff
+}
+
+// diffSets returns IDs added and removed, or nil when the sets are equal.
+func diffSets(from, to []string) *SetDiff {
+	var d SetDiff
+	for _, id := range to {
+		if !slices.Contains(from, id) {
+			d.Added = append(d.Added, id)
+		}
+	}
+	for _, id := range from {
+		if !slices.Contains(to, id) {
+			d.Removed = append(d.Removed, id)
+		}
+	}
+	if len(d.Added) == 0 && len(d.Removed) == 0 {
+		return nil
+	}
+	return &d
+}
+
+var diffTokenRe = regexp.MustCompile(`\s+|\S+`)
+
+// diffWords computes a word-level diff of a and b. Whitespace is kept as its
+// own token so the spans concatenate back to the original texts.
+func diffWords(a, b string) []TextDiffOp {
+	x := diffTokenRe.FindAllString(a, -1)
+	y := diffTokenRe.FindAllString(b, -1)
+
+	if len(x)*len(y) > maxDiffCells {
+		var ops []TextDiffOp
+		if a != "" {
+			ops = append(ops, TextDiffOp{Op: "delete", Text: a})
+		}
+		if b != "" {
+			ops = append(ops, TextDiffOp{Op: "insert", Text: b})
+		}
+		return ops
+	}
+
+	// lcs[i][j] is the LCS length of x[i:] and y[j:]
+	lcs := make([][]int, len(x)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(y)+1)
+	}
+	for i := len(x) - 1; i >= 0; i-- {
+		for j := len(y) - 1; j >= 0; j-- {
+			if x[i] == y[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	var ops []TextDiffOp
+	emit := func(op, text string) {
+		if n := len(ops); n > 0 && ops[n-1].Op == op {
+			ops[n-1].Text += text
+			return
+		}
+		ops = append(ops, TextDiffOp{Op: op, Text: text})
+	}
+	i, j := 0, 0
+	for i < len(x) && j < len(y) {
+		switch {
+		case x[i] == y[j]:
+			emit("equal", x[i])
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			emit("delete", x[i])
+			i++
+		default:
+			emit("insert", y[j])
+			j++
+		}
+	}
+	for ; i < len(x); i++ {
+		emit("delete", x[i])
+	}
+	for ; j < len(y); j++ {
+		emit("insert", y[j])
+	}
+	return ops
+}
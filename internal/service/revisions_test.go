@@ -0,0 +1,160 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/iconidentify/xgrabba/internal/domain"
+)
+
+func TestRecordRevision(t *testing.T) {
+	archivedAt := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+	tweet := &domain.Tweet{
+		ID:           "100",
+		Text:         "hello world",
+		EditTweetIDs: []string{"100"},
+		Metrics:      domain.TweetMetrics{Likes: 1},
+		Media:        []domain.Media{{ID: "m1"}},
+		ArchivedAt:   &archivedAt,
+	}
+
+	prev := lastRevision(tweet)
+	if rev := recordRevision(tweet, prev, snapshotRevision(tweet)); rev != nil {
+		t.Fatalf("unchanged tweet recorded revision %+v", rev)
+	}
+
+	// X edit: new version in the history, new text, more likes
+	tweet.Text = "hello brave world"
+	tweet.EditTweetIDs = []string{"100", "101"}
+	tweet.Metrics.Likes = 5
+	rev := recordRevision(tweet, prev, snapshotRevision(tweet))
+	if rev == nil {
+		t.Fatal("expected a revision")
+	}
+	if len(tweet.Revisions) != 2 {
+		t.Fatalf("revisions = %d, want baseline + 1", len(tweet.Revisions))
+	}
+	baseline := tweet.Revisions[0]
+	if baseline.Number != 1 || baseline.Source != domain.RevisionSourceArchive || baseline.Text != "hello world" || !baseline.RecordedAt.Equal(archivedAt) {
+		t.Errorf("unexpected baseline %+v", baseline)
+	}
+	if rev.Number != 2 || !rev.Edited {
+		t.Errorf("revision = %+v, want number 2 and edited", rev)
+	}
+	want := []string{domain.RevisionFieldText, domain.RevisionFieldEditHistory, domain.RevisionFieldMetrics}
+	if strings.Join(rev.Changed, ",") != strings.Join(want, ",") {
+		t.Errorf("changed = %v, want %v", rev.Changed, want)
+	}
+	if !tweet.EditedAfterArchive() {
+		t.Error("EditedAfterArchive should be true")
+	}
+
+	// Metrics-only change is not an edit
+	prev = lastRevision(tweet)
+	tweet.Metrics.Likes = 9
+	rev = recordRevision(tweet, prev, snapshotRevision(tweet))
+	if rev == nil || rev.Number != 3 || rev.Edited {
+		t.Errorf("metrics revision = %+v", rev)
+	}
+}
+
+func TestNewEdits_LegacyArchive(t *testing.T) {
+	// Archived before edit history was stored
+	prev := domain.TweetRevision{Text: "typo"}
+	if newEdits(prev, domain.TweetRevision{Text: "typo", EditTweetIDs: []string{"1", "2"}}) {
+		t.Error("history without a text change should not count as a new edit")
+	}
+	if !newEdits(prev, domain.TweetRevision{Text: "fixed", EditTweetIDs: []string{"1", "2"}}) {
+		t.Error("history with a text change should count as a new edit")
+	}
+	if newEdits(prev, domain.TweetRevision{Text: "fixed", EditTweetIDs: []string{"1"}}) {
+		t.Error("single-version history is not an edit")
+	}
+}
+
+func TestDiffWords(t *testing.T) {
+	ops := diffWords("the quick fox", "the slow brown fox")
+
+	var from, to strings.Builder
+	for _, op := range ops {
+		if op.Op != "insert" {
+			from.WriteString(op.Text)
+		}
+		if op.Op != "delete" {
+			to.WriteString(op.Text)
+		}
+	}
+	if from.String() != "the quick fox" || to.String() != "the slow brown fox" {
+		t.Errorf("ops do not reconstruct inputs: %+v", ops)
+	}
+
+	var deleted, inserted []string
+	for _, op := range ops {
+		switch op.Op {
+		case "delete":
+			deleted = append(deleted, strings.TrimSpace(op.Text))
+		case "insert":
+			inserted = append(inserted, strings.TrimSpace(op.Text))
+		}
+	}
+	if strings.Join(deleted, " ") != "quick" || strings.Join(inserted, " ") != "slow brown" {
+		t.Errorf("deleted = %q, inserted = %q", deleted, inserted)
+	}
+}
+
+func TestDiffRevisions(t *testing.T) {
+	tweet := &domain.Tweet{
+		ID: "1",
+		Revisions: []domain.TweetRevision{
+			{Number: 1, Text: "a", EditTweetIDs: []string{"1"}, MediaIDs: []string{"m1"}, Metrics: domain.TweetMetrics{Likes: 1, Views: 10}},
+			{Number: 2, Text: "a", EditTweetIDs: []string{"1"}, MediaIDs: []string{"m1"}, Metrics: domain.TweetMetrics{Likes: 3, Views: 10}},
+			{Number: 3, Text: "b", EditTweetIDs: []string{"1", "2"}, MediaIDs: []string{"m2"}, Metrics: domain.TweetMetrics{Likes: 3, Views: 10}},
+		},
+	}
+	svc := &TweetService{tweets: map[domain.TweetID]*domain.Tweet{tweet.ID: tweet}}
+	ctx := context.Background()
+
+	// Defaults to the latest two revisions
+	diff, err := svc.DiffRevisions(ctx, tweet.ID, 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff.From.Number != 2 || diff.To.Number != 3 {
+		t.Errorf("compared %d..%d, want 2..3", diff.From.Number, diff.To.Number)
+	}
+	if !diff.TextChanged || diff.Metrics != nil {
+		t.Errorf("text_changed = %v, metrics = %v", diff.TextChanged, diff.Metrics)
+	}
+	if diff.EditTweetIDs == nil || strings.Join(diff.EditTweetIDs.Added, ",") != "2" {
+		t.Errorf("edit IDs diff = %+v", diff.EditTweetIDs)
+	}
+	if diff.Media == nil || diff.Media.Added[0] != "m2" || diff.Media.Removed[0] != "m1" {
+		t.Errorf("media diff = %+v", diff.Media)
+	}
+
+	diff, err = svc.DiffRevisions(ctx, tweet.ID, 1, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff.TextChanged || diff.Metrics["likes"].Delta != 2 || len(diff.Metrics) != 1 {
+		t.Errorf("diff 1..2 = %+v", diff)
+	}
+
+	if _, err := svc.DiffRevisions(ctx, tweet.ID, 1, 7); !errors.Is(err, ErrRevisionNotFound) {
+		t.Errorf("err = %v, want ErrRevisionNotFound", err)
+	}
+}
+
+func TestBuildMarkdownSummary_EditedNote(t *testing.T) {
+	tweet := &domain.Tweet{ID: "1", Text: "hello", AITitle: "title"}
+	if strings.Contains(buildMarkdownSummary(tweet), "**Edited:**") {
+		t.Error("unedited tweet should not have an edit note")
+	}
+	tweet.Revisions = []domain.TweetRevision{{Number: 1}, {Number: 2, Edited: true}}
+	if !strings.Contains(buildMarkdownSummary(tweet), "**Edited:**") {
+		t.Error("edited tweet should have an edit note")
+	}
+}
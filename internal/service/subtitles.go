@@ -0,0 +1,212 @@
+package service
+
+import (
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/iconidentify/xgrabba/internal/domain"
+	"github.com/iconidentify/xgrabba/pkg/whisper"
+)
+
+// TranscriptMatch is a search hit inside a timed video transcript.
+type TranscriptMatch struct {
+	MediaID    string  `json:"media_id"`
+	MediaIndex int     `json:"media_index"`
+	Start      float64 `json:"start"`
+	End        float64 `json:"end"`
+	Text       string  `json:"text"`
+	Language   string  `json:"language,omitempty"` // Set when the hit is in a translated transcript
+}
+
+// SubtitleFilename returns the WebVTT subtitle filename for a media item.
+func SubtitleFilename(mediaID string) string {
+	return mediaID + ".vtt"
+}
+
+// SRTFilename returns the SRT subtitle filename for a media item.
+func SRTFilename(mediaID string) string {
+	return mediaID + ".srt"
+}
+
+// TranslatedSubtitleFilename returns the WebVTT filename for a media item's
+// transcript translated into lang.
+func TranslatedSubtitleFilename(mediaID, lang string) string {
+	return mediaID + "." + lang + ".vtt"
+}
+
+// TranslatedSRTFilename returns the SRT filename for a media item's
+// transcript translated into lang.
+func TranslatedSRTFilename(mediaID, lang string) string {
+	return mediaID + "." + lang + ".srt"
+}
+
+// transcriptFiles returns the archive-relative transcript and subtitle files
+// of a media item, for the checksum manifest.
+func transcriptFiles(mediaID string) []string {
+	return []string{
+		"media/" + mediaID + "_transcript.txt",
+		"media/" + SubtitleFilename(mediaID),
+		"media/" + SRTFilename(mediaID),
+	}
+}
+
+// translatedSubtitleFiles returns the archive-relative translated subtitle
+// files of a media item, for the checksum manifest.
+func translatedSubtitleFiles(mediaID, lang string) []string {
+	return []string{
+		"media/" + TranslatedSubtitleFilename(mediaID, lang),
+		"media/" + TranslatedSRTFilename(mediaID, lang),
+	}
+}
+
+// segmentsFromWhisper converts Whisper segments to domain transcript segments,
+// dropping empty text.
+func segmentsFromWhisper(segments []whisper.TranscriptionSegment) []domain.TranscriptSegment {
+	if len(segments) == 0 {
+		return nil
+	}
+	result := make([]domain.TranscriptSegment, 0, len(segments))
+	for _, seg := range segments {
+		text := strings.TrimSpace(seg.Text)
+		if text == "" {
+			continue
+		}
+		result = append(result, domain.TranscriptSegment{
+			Start: seg.Start,
+			End:   seg.End,
+			Text:  text,
+		})
+	}
+	return result
+}
+
+// formatSubtitleTimestamp formats seconds as HH:MM:SS<sep>mmm.
+// WebVTT uses "." as the millisecond separator, SRT uses ",".
+func formatSubtitleTimestamp(seconds float64, sep string) string {
+	if seconds < 0 {
+		seconds = 0
+	}
+	totalMs := int64(math.Round(seconds * 1000))
+	h := totalMs / 3600000
+	m := (totalMs % 3600000) / 60000
+	s := (totalMs % 60000) / 1000
+	ms := totalMs % 1000
+	return fmt.Sprintf("%02d:%02d:%02d%s%03d", h, m, s, sep, ms)
+}
+
+// buildWebVTT renders transcript segments as a WebVTT document.
+func buildWebVTT(segments []domain.TranscriptSegment) string {
+	var sb strings.Builder
+	sb.WriteString("WEBVTT\n\n")
+	for i, seg := range segments {
+		sb.WriteString(fmt.Sprintf("%d\n", i+1))
+		sb.WriteString(fmt.Sprintf("%s --> %s\n", formatSubtitleTimestamp(seg.Start, "."), formatSubtitleTimestamp(seg.End, ".")))
+		// "-->" is not allowed inside WebVTT cue text
+		sb.WriteString(strings.ReplaceAll(seg.Text, "-->", "->"))
+		sb.WriteString("\n\n")
+	}
+	return sb.String()
+}
+
+// buildSRT renders transcript segments as a SubRip document.
+func buildSRT(segments []domain.TranscriptSegment) string {
+	var sb strings.Builder
+	for i, seg := range segments {
+		sb.WriteString(fmt.Sprintf("%d\n", i+1))
+		sb.WriteString(fmt.Sprintf("%s --> %s\n", formatSubtitleTimestamp(seg.Start, ","), formatSubtitleTimestamp(seg.End, ",")))
+		sb.WriteString(seg.Text)
+		sb.WriteString("\n\n")
+	}
+	return sb.String()
+}
+
+// writeSubtitleFiles writes {mediaID}.vtt and {mediaID}.srt next to the video.
+func writeSubtitleFiles(archivePath string, media *domain.Media) error {
+	return writeSubtitlePair(archivePath, SubtitleFilename(media.ID), SRTFilename(media.ID), media.TranscriptSegments)
+}
+
+// writeTranslatedSubtitleFiles writes {mediaID}.{lang}.vtt and
+// {mediaID}.{lang}.srt next to the video.
+func writeTranslatedSubtitleFiles(archivePath, mediaID, lang string, segments []domain.TranscriptSegment) error {
+	return writeSubtitlePair(archivePath, TranslatedSubtitleFilename(mediaID, lang), TranslatedSRTFilename(mediaID, lang), segments)
+}
+
+func writeSubtitlePair(archivePath, vttName, srtName string, segments []domain.TranscriptSegment) error {
+	if len(segments) == 0 {
+		return nil
+	}
+	mediaDir := filepath.Join(archivePath, "media")
+	if err := os.MkdirAll(mediaDir, 0755); err != nil {
+		return fmt.Errorf("create media directory: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(mediaDir, vttName), []byte(buildWebVTT(segments)), 0644); err != nil {
+		return fmt.Errorf("write vtt: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(mediaDir, srtName), []byte(buildSRT(segments)), 0644); err != nil {
+		return fmt.Errorf("write srt: %w", err)
+	}
+	return nil
+}
+
+// removeSubtitleFiles deletes subtitle files for a media item, ignoring missing files.
+func removeSubtitleFiles(archivePath string, media *domain.Media) {
+	mediaDir := filepath.Join(archivePath, "media")
+	_ = os.Remove(filepath.Join(mediaDir, SubtitleFilename(media.ID)))
+	_ = os.Remove(filepath.Join(mediaDir, SRTFilename(media.ID)))
+}
+
+// removeTranslatedSubtitleFiles deletes the translated subtitle files for a
+// media item, ignoring missing files.
+func removeTranslatedSubtitleFiles(archivePath, mediaID, lang string) {
+	mediaDir := filepath.Join(archivePath, "media")
+	_ = os.Remove(filepath.Join(mediaDir, TranslatedSubtitleFilename(mediaID, lang)))
+	_ = os.Remove(filepath.Join(mediaDir, TranslatedSRTFilename(mediaID, lang)))
+}
+
+// findTranscriptMatches returns timed transcript segments containing the
+// (already lowercased) query, including segments of translated transcripts.
+func findTranscriptMatches(t *domain.Tweet, query string) []TranscriptMatch {
+	if query == "" {
+		return nil
+	}
+	var matches []TranscriptMatch
+	for i, m := range t.Media {
+		for _, seg := range m.TranscriptSegments {
+			if strings.Contains(strings.ToLower(seg.Text), query) {
+				matches = append(matches, TranscriptMatch{
+					MediaID:    m.ID,
+					MediaIndex: i,
+					Start:      seg.Start,
+					End:        seg.End,
+					Text:       seg.Text,
+				})
+			}
+		}
+		for _, lang := range t.TranslationLanguages() {
+			for _, seg := range t.Translations[lang].Media[m.ID].Segments {
+				if strings.Contains(strings.ToLower(seg.Text), query) {
+					matches = append(matches, TranscriptMatch{
+						MediaID:    m.ID,
+						MediaIndex: i,
+						Start:      seg.Start,
+						End:        seg.End,
+						Text:       seg.Text,
+						Language:   lang,
+					})
+				}
+			}
+		}
+	}
+	return matches
+}
+
+// TranscriptMatches returns transcript timestamps in the tweet matching the query.
+func (s *TweetService) TranscriptMatches(t *domain.Tweet, query string) []TranscriptMatch {
+	query, _ = parseSearchQuery(query)
+	s.tweetsMu.RLock()
+	defer s.tweetsMu.RUnlock()
+	return findTranscriptMatches(t, query)
+}
@@ -0,0 +1,174 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/iconidentify/xgrabba/internal/domain"
+	"github.com/iconidentify/xgrabba/pkg/whisper"
+)
+
+func TestFormatSubtitleTimestamp(t *testing.T) {
+	tests := []struct {
+		name    string
+		seconds float64
+		sep     string
+		want    string
+	}{
+		{"zero", 0, ".", "00:00:00.000"},
+		{"negative clamps", -3, ".", "00:00:00.000"},
+		{"fractional", 1.5, ".", "00:00:01.500"},
+		{"srt separator", 61.25, ",", "00:01:01,250"},
+		{"hours", 3725.004, ".", "01:02:05.004"},
+		{"rounds", 2.9996, ".", "00:00:03.000"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := formatSubtitleTimestamp(tt.seconds, tt.sep); got != tt.want {
+				t.Errorf("formatSubtitleTimestamp(%v, %q) = %q, want %q", tt.seconds, tt.sep, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSegmentsFromWhisper(t *testing.T) {
+	segs := segmentsFromWhisper([]whisper.TranscriptionSegment{
+		{ID: 0, Start: 0, End: 2, Text: "  Hello there "},
+		{ID: 1, Start: 2, End: 3, Text: "   "},
+		{ID: 2, Start: 3, End: 5, Text: "General Kenobi"},
+	})
+
+	if len(segs) != 2 {
+		t.Fatalf("len = %d, want 2", len(segs))
+	}
+	if segs[0].Text != "Hello there" {
+		t.Errorf("text = %q, want trimmed", segs[0].Text)
+	}
+	if segs[1].Start != 3 || segs[1].End != 5 {
+		t.Errorf("timing = %v-%v, want 3-5", segs[1].Start, segs[1].End)
+	}
+	if segmentsFromWhisper(nil) != nil {
+		t.Error("nil input should return nil")
+	}
+}
+
+func TestSegmentsFromWhisper_ChunkOffsets(t *testing.T) {
+	dir := t.TempDir()
+	chunks := []string{filepath.Join(dir, "chunk_000.mp3"), filepath.Join(dir, "chunk_001.mp3")}
+	for _, chunk := range chunks {
+		if err := os.WriteFile(chunk, []byte("audio"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Every chunk is timed from zero; the second must be shifted by the
+	// first chunk's duration
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		json.NewEncoder(w).Encode(whisper.TranscriptionResponse{
+			Text:     fmt.Sprintf("chunk %d", calls),
+			Duration: 300,
+			Segments: []whisper.TranscriptionSegment{
+				{Start: 0, End: 2, Text: fmt.Sprintf("chunk %d start", calls)},
+				{Start: 2.5, End: 4, Text: fmt.Sprintf("chunk %d end", calls)},
+			},
+		})
+	}))
+	defer server.Close()
+
+	client := whisper.NewClient(whisper.Config{APIKey: "test", BaseURL: server.URL})
+	result, err := client.TranscribeChunks(context.Background(), chunks, whisper.TranscriptionOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	segs := segmentsFromWhisper(result.Segments)
+	want := []domain.TranscriptSegment{
+		{Start: 0, End: 2, Text: "chunk 1 start"},
+		{Start: 2.5, End: 4, Text: "chunk 1 end"},
+		{Start: 300, End: 302, Text: "chunk 2 start"},
+		{Start: 302.5, End: 304, Text: "chunk 2 end"},
+	}
+	if !reflect.DeepEqual(segs, want) {
+		t.Errorf("segments = %+v, want %+v", segs, want)
+	}
+}
+
+func TestBuildWebVTTAndSRT(t *testing.T) {
+	segs := []domain.TranscriptSegment{
+		{Start: 0, End: 1.5, Text: "first --> line"},
+		{Start: 1.5, End: 4, Text: "second"},
+	}
+
+	vtt := buildWebVTT(segs)
+	if !strings.HasPrefix(vtt, "WEBVTT\n\n") {
+		t.Errorf("vtt missing header: %q", vtt)
+	}
+	if !strings.Contains(vtt, "00:00:00.000 --> 00:00:01.500\nfirst -> line\n") {
+		t.Errorf("vtt cue malformed: %q", vtt)
+	}
+
+	srt := buildSRT(segs)
+	if !strings.HasPrefix(srt, "1\n00:00:00,000 --> 00:00:01,500\n") {
+		t.Errorf("srt cue malformed: %q", srt)
+	}
+	if !strings.Contains(srt, "2\n00:00:01,500 --> 00:00:04,000\nsecond\n") {
+		t.Errorf("srt second cue malformed: %q", srt)
+	}
+}
+
+func TestWriteSubtitleFiles(t *testing.T) {
+	dir := t.TempDir()
+	media := &domain.Media{
+		ID:                 "m1",
+		TranscriptSegments: []domain.TranscriptSegment{{Start: 0, End: 1, Text: "hi"}},
+	}
+
+	if err := writeSubtitleFiles(dir, media); err != nil {
+		t.Fatalf("writeSubtitleFiles: %v", err)
+	}
+	for _, name := range []string{"m1.vtt", "m1.srt"} {
+		if _, err := os.Stat(filepath.Join(dir, "media", name)); err != nil {
+			t.Errorf("expected %s: %v", name, err)
+		}
+	}
+
+	removeSubtitleFiles(dir, media)
+	if _, err := os.Stat(filepath.Join(dir, "media", "m1.vtt")); !os.IsNotExist(err) {
+		t.Error("vtt should be removed")
+	}
+}
+
+func TestFindTranscriptMatches(t *testing.T) {
+	tweet := &domain.Tweet{
+		Media: []domain.Media{
+			{ID: "img"},
+			{ID: "vid", TranscriptSegments: []domain.TranscriptSegment{
+				{Start: 0, End: 5, Text: "Welcome to the show"},
+				{Start: 5, End: 9, Text: "today we talk about Rockets"},
+				{Start: 9, End: 12, Text: "rockets are loud"},
+			}},
+		},
+	}
+
+	matches := findTranscriptMatches(tweet, "rockets")
+	if len(matches) != 2 {
+		t.Fatalf("len = %d, want 2", len(matches))
+	}
+	if matches[0].MediaID != "vid" || matches[0].MediaIndex != 1 || matches[0].Start != 5 {
+		t.Errorf("unexpected first match: %+v", matches[0])
+	}
+	if findTranscriptMatches(tweet, "") != nil {
+		t.Error("empty query should return nil")
+	}
+}
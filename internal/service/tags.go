@@ -0,0 +1,325 @@
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/iconidentify/xgrabba/internal/domain"
+)
+
+// TagRegistryFilename is the tag registry under the storage base path.
+const TagRegistryFilename = ".tags.json"
+
+// ErrNoTagRegistry is returned when the tag registry is changed on a service
+// without one.
+var ErrNoTagRegistry = errors.New("tag registry not configured")
+
+// TagRegistryService stores the registry that curates AI tags and topics:
+// canonical tags, aliases and a blocklist.
+type TagRegistryService struct {
+	path   string
+	logger *slog.Logger
+	now    func() time.Time
+
+	mu       sync.RWMutex
+	registry domain.TagRegistry
+}
+
+// NewTagRegistryService creates a tag registry backed by the file in
+// basePath and loads the registry saved so far.
+func NewTagRegistryService(basePath string, logger *slog.Logger) *TagRegistryService {
+	s := &TagRegistryService{
+		path:   filepath.Join(basePath, TagRegistryFilename),
+		logger: logger,
+		now:    time.Now,
+	}
+	registry, err := readTagRegistry(s.path)
+	if err != nil && !os.IsNotExist(err) {
+		logger.Warn("failed to load tag registry", "path", s.path, "error", err)
+	}
+	s.registry = registry
+	return s
+}
+
+// readTagRegistry parses the registry file. The canonical and blocked lists
+// are sorted, since the file may have been edited by hand.
+func readTagRegistry(path string) (domain.TagRegistry, error) {
+	var registry domain.TagRegistry
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return registry, err
+	}
+	if err := json.Unmarshal(data, &registry); err != nil {
+		return domain.TagRegistry{}, fmt.Errorf("parse %s: %w", TagRegistryFilename, err)
+	}
+	sort.Strings(registry.Canonical)
+	sort.Strings(registry.Blocked)
+	return registry, nil
+}
+
+// Registry returns a copy of the current registry.
+func (s *TagRegistryService) Registry() domain.TagRegistry {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.registry.Clone()
+}
+
+// Apply curates a list of AI tags or topics with the current registry.
+func (s *TagRegistryService) Apply(tags []string) []string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.registry.Apply(tags)
+}
+
+// Update changes the registry with fn and saves it. The registry is left
+// unchanged when fn or the save fails.
+func (s *TagRegistryService) Update(fn func(*domain.TagRegistry) error) (domain.TagRegistry, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	next := s.registry.Clone()
+	if err := fn(&next); err != nil {
+		return s.registry.Clone(), err
+	}
+	next.UpdatedAt = s.now().UTC()
+
+	data, err := jsonMarshalIndent(next)
+	if err != nil {
+		return s.registry.Clone(), fmt.Errorf("marshal tag registry: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(s.path), 0755); err != nil {
+		return s.registry.Clone(), fmt.Errorf("create storage directory: %w", err)
+	}
+	tempPath := s.path + ".tmp"
+	if err := os.WriteFile(tempPath, data, 0644); err != nil {
+		return s.registry.Clone(), fmt.Errorf("write %s: %w", TagRegistryFilename, err)
+	}
+	if err := os.Rename(tempPath, s.path); err != nil {
+		return s.registry.Clone(), fmt.Errorf("write %s: %w", TagRegistryFilename, err)
+	}
+
+	s.registry = next
+	return next.Clone(), nil
+}
+
+// SetTagRegistry sets the registry that curates AI tags and topics.
+// Without one, tags are only normalized.
+func (s *TweetService) SetTagRegistry(tags *TagRegistryService) {
+	s.tags = tags
+}
+
+// curateTags normalizes AI tags or topics and applies the tag registry.
+func (s *TweetService) curateTags(tags []string) []string {
+	if s.tags == nil {
+		return (*domain.TagRegistry)(nil).Apply(tags)
+	}
+	return s.tags.Apply(tags)
+}
+
+// TagRegistry returns a copy of the current tag registry.
+func (s *TweetService) TagRegistry() domain.TagRegistry {
+	if s.tags == nil {
+		return domain.TagRegistry{}
+	}
+	return s.tags.Registry()
+}
+
+// TagApplyStatus reports the background application of the tag registry to
+// archived tweets.
+type TagApplyStatus struct {
+	Running    bool       `json:"running"`
+	Updated    int        `json:"updated"` // Tweets changed by the last finished run
+	FinishedAt *time.Time `json:"finished_at,omitempty"`
+}
+
+// UpdateTagRegistry changes the tag registry with fn and starts applying the
+// result to every archived tweet in the background. It returns the new
+// registry and the status of the application.
+func (s *TweetService) UpdateTagRegistry(fn func(*domain.TagRegistry) error) (domain.TagRegistry, TagApplyStatus, error) {
+	if s.tags == nil {
+		return domain.TagRegistry{}, s.TagApplyStatus(), ErrNoTagRegistry
+	}
+	registry, err := s.tags.Update(fn)
+	if err != nil {
+		return registry, s.TagApplyStatus(), err
+	}
+	return registry, s.StartTagRegistryApply(), nil
+}
+
+// TagApplyStatus returns the status of the background tag registry
+// application.
+func (s *TweetService) TagApplyStatus() TagApplyStatus {
+	s.tagApplyMu.Lock()
+	defer s.tagApplyMu.Unlock()
+	return s.tagApply
+}
+
+// StartTagRegistryApply applies the tag registry to every archived tweet in
+// the background. A change made while a run is in progress is picked up by
+// one more run once it finishes.
+func (s *TweetService) StartTagRegistryApply() TagApplyStatus {
+	s.tagApplyMu.Lock()
+	defer s.tagApplyMu.Unlock()
+	if s.tagApply.Running {
+		s.tagApplyAgain = true
+		return s.tagApply
+	}
+	s.tagApply.Running = true
+	go s.runTagRegistryApply()
+	return s.tagApply
+}
+
+func (s *TweetService) runTagRegistryApply() {
+	for {
+		updated := s.ApplyTagRegistry()
+
+		s.tagApplyMu.Lock()
+		finishedAt := time.Now().UTC()
+		s.tagApply.Updated = updated
+		s.tagApply.FinishedAt = &finishedAt
+		if !s.tagApplyAgain {
+			s.tagApply.Running = false
+			s.tagApplyMu.Unlock()
+			return
+		}
+		s.tagApplyAgain = false
+		s.tagApplyMu.Unlock()
+	}
+}
+
+// ApplyTagRegistry curates the AI tags and topics of every archived tweet
+// and its media with the current registry, saving the tweets that change,
+// and returns how many did. Curation starts from the raw AI tags, so tags
+// dropped by a blocklist or merge come back once the registry no longer
+// drops them. Each tweet is locked only while it is curated.
+func (s *TweetService) ApplyTagRegistry() int {
+	s.tweetsMu.RLock()
+	tweets := make([]*domain.Tweet, 0, len(s.tweets))
+	for _, tweet := range s.tweets {
+		tweets = append(tweets, tweet)
+	}
+	s.tweetsMu.RUnlock()
+
+	updated := 0
+	for _, tweet := range tweets {
+		s.tweetsMu.Lock()
+		changed := s.curateTweetTags(tweet)
+		s.tweetsMu.Unlock()
+		if !changed {
+			continue
+		}
+		updated++
+		if tweet.ArchivePath == "" {
+			continue
+		}
+		if err := s.saveTweetMetadata(tweet); err != nil {
+			s.logger.Warn("failed to save curated tags", "tweet_id", tweet.ID, "error", err)
+		}
+	}
+	if updated > 0 {
+		s.logger.Info("tag registry applied", "tweets_updated", updated)
+	}
+	return updated
+}
+
+// curateTweetTags derives a tweet's curated AI tags and topics from the raw
+// ones and reports whether any changed. Tweets curated before raw tags were
+// kept take their current tags as raw the first time curation changes them.
+// The caller holds tweetsMu.
+func (s *TweetService) curateTweetTags(tweet *domain.Tweet) bool {
+	changed := false
+	curate := func(raw, tags *[]string) {
+		source := *raw
+		if source == nil {
+			source = *tags
+		}
+		if len(source) == 0 {
+			return
+		}
+		curated := s.curateTags(source)
+		if reflect.DeepEqual(curated, *tags) {
+			return
+		}
+		if *raw == nil {
+			*raw = append([]string(nil), *tags...)
+		}
+		*tags = curated
+		changed = true
+	}
+	curate(&tweet.AIRawTags, &tweet.AITags)
+	curate(&tweet.AIRawTopics, &tweet.AITopics)
+	for i := range tweet.Media {
+		curate(&tweet.Media[i].AIRawTags, &tweet.Media[i].AITags)
+		curate(&tweet.Media[i].AIRawTopics, &tweet.Media[i].AITopics)
+	}
+	return changed
+}
+
+// rawAITags returns the raw AI tags or topics behind a curated list.
+func rawAITags(raw, tags []string) []string {
+	if raw != nil {
+		return raw
+	}
+	return tags
+}
+
+// TagCounts returns the curated AI tags and topics in the archive with the
+// number of tweets carrying each, most used first. Canonical tags are
+// included even when unused. query, when set, keeps tags containing it.
+func (s *TweetService) TagCounts(query string) []domain.TagCount {
+	registry := s.TagRegistry()
+	counts := make(map[string]int)
+	for _, tag := range registry.Canonical {
+		counts[tag] = 0
+	}
+
+	s.tweetsMu.RLock()
+	for _, tweet := range s.tweets {
+		seen := make(map[string]bool)
+		add := func(tags []string) {
+			for _, tag := range registry.Apply(tags) {
+				if !seen[tag] {
+					seen[tag] = true
+					counts[tag]++
+				}
+			}
+		}
+		add(rawAITags(tweet.AIRawTags, tweet.AITags))
+		add(rawAITags(tweet.AIRawTopics, tweet.AITopics))
+		for _, m := range tweet.Media {
+			add(rawAITags(m.AIRawTags, m.AITags))
+			add(rawAITags(m.AIRawTopics, m.AITopics))
+		}
+	}
+	s.tweetsMu.RUnlock()
+
+	query = domain.NormalizeTag(query)
+	result := make([]domain.TagCount, 0, len(counts))
+	for tag, count := range counts {
+		if query != "" && !strings.Contains(tag, query) {
+			continue
+		}
+		result = append(result, domain.TagCount{
+			Tag:       tag,
+			Count:     count,
+			Canonical: registry.IsCanonical(tag),
+			Aliases:   registry.AliasesOf(tag),
+		})
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Count != result[j].Count {
+			return result[i].Count > result[j].Count
+		}
+		return result[i].Tag < result[j].Tag
+	})
+	return result
+}
@@ -0,0 +1,134 @@
+package service
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/iconidentify/xgrabba/internal/domain"
+)
+
+func TestTagRegistryService_Persists(t *testing.T) {
+	base := t.TempDir()
+	reg := NewTagRegistryService(base, testLogger())
+
+	if _, err := reg.Update(func(r *domain.TagRegistry) error { return r.Merge("ai", "artificial intelligence") }); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := reg.Update(func(r *domain.TagRegistry) error { return r.Merge("", "x") }); err == nil {
+		t.Error("failed update should return its error")
+	}
+
+	reloaded := NewTagRegistryService(base, testLogger()).Registry()
+	if reloaded.Aliases["artificial intelligence"] != "ai" || reloaded.UpdatedAt.IsZero() {
+		t.Errorf("reloaded registry = %+v", reloaded)
+	}
+}
+
+func TestApplyTagRegistry(t *testing.T) {
+	svc, tweet := newIntegrityTestService(t)
+	tweet.AITags = []string{"AI", "Cats", "viral"}
+	tweet.AITopics = []string{"Artificial-Intelligence"}
+	tweet.Media[1].AITags = []string{"cats", "#AI"}
+	other := &domain.Tweet{ID: "2", AITags: []string{"ai"}}
+	svc.tweets[other.ID] = other
+
+	if n := svc.ApplyTagRegistry(); n != 1 {
+		t.Errorf("normalization updated %d tweets, want 1", n)
+	}
+	if !reflect.DeepEqual(tweet.AITags, []string{"ai", "cats", "viral"}) {
+		t.Errorf("normalized tags = %v", tweet.AITags)
+	}
+
+	svc.SetTagRegistry(NewTagRegistryService(t.TempDir(), testLogger()))
+	registry, _, err := svc.UpdateTagRegistry(func(r *domain.TagRegistry) error {
+		if err := r.Merge("artificial intelligence", "ai"); err != nil {
+			return err
+		}
+		return r.Block("viral")
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if status := waitTagApply(t, svc); status.Updated != 2 || !registry.IsBlocked("viral") {
+		t.Errorf("apply = %+v, registry = %+v", status, registry)
+	}
+	if !reflect.DeepEqual(tweet.AITags, []string{"artificial intelligence", "cats"}) ||
+		!reflect.DeepEqual(tweet.Media[1].AITags, []string{"cats", "artificial intelligence"}) {
+		t.Errorf("curated tags = %v, media %v", tweet.AITags, tweet.Media[1].AITags)
+	}
+
+	data, err := os.ReadFile(filepath.Join(tweet.ArchivePath, "tweet.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var stored domain.StoredTweet
+	if err := json.Unmarshal(data, &stored); err != nil {
+		t.Fatal(err)
+	}
+	if strings.Join(stored.AITags, ",") != "artificial intelligence,cats" ||
+		strings.Join(stored.AIRawTags, ",") != "AI,Cats,viral" {
+		t.Errorf("saved tags = %v, raw %v", stored.AITags, stored.AIRawTags)
+	}
+
+	counts := svc.TagCounts("")
+	want := []domain.TagCount{
+		{Tag: "artificial intelligence", Count: 2, Canonical: true, Aliases: []string{"ai"}},
+		{Tag: "cats", Count: 1},
+	}
+	if !reflect.DeepEqual(counts, want) {
+		t.Errorf("TagCounts() = %+v, want %+v", counts, want)
+	}
+	if counts := svc.TagCounts("CAT"); len(counts) != 1 || counts[0].Tag != "cats" {
+		t.Errorf("TagCounts(CAT) = %+v", counts)
+	}
+}
+
+func TestApplyTagRegistry_RestoresFromRawTags(t *testing.T) {
+	svc, tweet := newIntegrityTestService(t)
+	svc.SetTagRegistry(NewTagRegistryService(t.TempDir(), testLogger()))
+	tweet.AIRawTags = []string{"AI", "viral"}
+	tweet.AITags = []string{"ai", "viral"}
+
+	if _, _, err := svc.UpdateTagRegistry(func(r *domain.TagRegistry) error { return r.Block("viral") }); err != nil {
+		t.Fatal(err)
+	}
+	waitTagApply(t, svc)
+	if !reflect.DeepEqual(tweet.AITags, []string{"ai"}) {
+		t.Fatalf("blocked tags = %v", tweet.AITags)
+	}
+
+	if _, _, err := svc.UpdateTagRegistry(func(r *domain.TagRegistry) error {
+		r.Unblock("viral")
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if status := waitTagApply(t, svc); status.Updated != 1 {
+		t.Errorf("apply = %+v", status)
+	}
+	if !reflect.DeepEqual(tweet.AITags, []string{"ai", "viral"}) ||
+		!reflect.DeepEqual(tweet.AIRawTags, []string{"AI", "viral"}) {
+		t.Errorf("unblocked tags = %v, raw %v", tweet.AITags, tweet.AIRawTags)
+	}
+}
+
+// waitTagApply waits for the background tag registry application to finish.
+func waitTagApply(t *testing.T, svc *TweetService) TagApplyStatus {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		status := svc.TagApplyStatus()
+		if !status.Running {
+			return status
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("tag registry application did not finish")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
@@ -0,0 +1,428 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/iconidentify/xgrabba/internal/config"
+	"github.com/iconidentify/xgrabba/internal/domain"
+	"github.com/iconidentify/xgrabba/pkg/grok"
+)
+
+var (
+	// ErrInvalidLanguage is returned for a target language that is not an
+	// ISO-639-1 code.
+	ErrInvalidLanguage = errors.New("language must be a two-letter ISO-639-1 code")
+
+	// ErrNothingToTranslate is returned for a tweet without text, article
+	// body or transcripts.
+	ErrNothingToTranslate = errors.New("tweet has no text or transcript to translate")
+
+	// ErrTranslationNotFound is returned when a tweet has no translation into
+	// the requested language.
+	ErrTranslationNotFound = errors.New("translation not found")
+
+	// ErrTranslationInProgress is returned when the same translation is
+	// already running.
+	ErrTranslationInProgress = errors.New("translation already in progress")
+)
+
+// defaultTranslationBatchSize is the number of transcript segments sent per
+// translation request when AIConfig.TranslationBatchSize is unset.
+const defaultTranslationBatchSize = 50
+
+// normalizeLanguage lowercases lang and checks that it is an ISO-639-1 code.
+func normalizeLanguage(lang string) (string, error) {
+	lang = strings.ToLower(strings.TrimSpace(lang))
+	if !config.ValidLanguageCode(lang) {
+		return "", fmt.Errorf("%w: %q", ErrInvalidLanguage, lang)
+	}
+	return lang, nil
+}
+
+// translationSource is a snapshot of the tweet fields that get translated.
+type translationSource struct {
+	archivePath string
+	text        string
+	articleBody string
+	media       []domain.Media
+}
+
+func (src translationSource) empty() bool {
+	if src.text != "" || src.articleBody != "" {
+		return false
+	}
+	for _, m := range src.media {
+		if m.Transcript != "" {
+			return false
+		}
+	}
+	return true
+}
+
+func (s *TweetService) translationSource(tweetID domain.TweetID) (translationSource, error) {
+	s.tweetsMu.RLock()
+	defer s.tweetsMu.RUnlock()
+	tweet, ok := s.tweets[tweetID]
+	if !ok {
+		return translationSource{}, domain.ErrVideoNotFound
+	}
+	src := translationSource{
+		archivePath: tweet.ArchivePath,
+		text:        strings.TrimSpace(tweet.Text),
+		articleBody: strings.TrimSpace(tweet.ArticleBody),
+	}
+	for _, m := range tweet.Media {
+		if m.Transcript == "" {
+			continue
+		}
+		m.TranscriptSegments = append([]domain.TranscriptSegment(nil), m.TranscriptSegments...)
+		src.media = append(src.media, m)
+	}
+	return src, nil
+}
+
+// Translate translates a tweet's text, article body and video transcripts
+// into lang and stores the result with the tweet, replacing any earlier
+// translation into lang. Timed transcript segments are translated in
+// batches and keep their timings; they are also written as
+// {mediaID}.{lang}.vtt and .srt subtitles. Text already in lang is not
+// stored, nor are transcripts whose detected language is lang.
+func (s *TweetService) Translate(ctx context.Context, tweetID domain.TweetID, lang string) (*domain.TweetTranslation, error) {
+	lang, err := normalizeLanguage(lang)
+	if err != nil {
+		return nil, err
+	}
+	src, err := s.translationSource(tweetID)
+	if err != nil {
+		return nil, err
+	}
+	if src.empty() {
+		return nil, ErrNothingToTranslate
+	}
+
+	ctx = withUsageTweet(ctx, tweetID)
+	translation := domain.TweetTranslation{
+		Language:      lang,
+		PromptVersion: s.promptSet().Version(),
+	}
+
+	// Text and article body go in one request so the model sees them together
+	var texts []string
+	if src.text != "" {
+		texts = append(texts, src.text)
+	}
+	if src.articleBody != "" {
+		texts = append(texts, src.articleBody)
+	}
+	if len(texts) > 0 {
+		resp, err := s.grokClient.Translate(ctx, grok.TranslationRequest{TargetLanguage: lang, Texts: texts})
+		if err != nil {
+			return nil, fmt.Errorf("translate text: %w", err)
+		}
+		translation.SourceLanguage = resp.SourceLanguage
+		if resp.SourceLanguage != lang {
+			out := resp.Texts
+			if src.text != "" {
+				translation.Text, out = out[0], out[1:]
+			}
+			if src.articleBody != "" {
+				translation.ArticleBody = out[0]
+			}
+		}
+	}
+
+	for _, m := range src.media {
+		if m.TranscriptLanguage == lang {
+			continue
+		}
+		mt, err := s.translateTranscript(ctx, m, lang)
+		if err != nil {
+			return nil, fmt.Errorf("translate transcript of media %s: %w", m.ID, err)
+		}
+		if translation.Media == nil {
+			translation.Media = make(map[string]domain.MediaTranslation)
+		}
+		translation.Media[m.ID] = mt
+	}
+	translation.TranslatedAt = time.Now()
+
+	s.tweetsMu.Lock()
+	tweet, ok := s.tweets[tweetID]
+	if !ok {
+		s.tweetsMu.Unlock()
+		return nil, domain.ErrVideoNotFound
+	}
+	if previous, ok := tweet.Translations[lang]; ok {
+		for mediaID := range previous.Media {
+			removeTranslatedSubtitleFiles(src.archivePath, mediaID, lang)
+		}
+	}
+	if tweet.Translations == nil {
+		tweet.Translations = make(map[string]domain.TweetTranslation)
+	}
+	tweet.Translations[lang] = translation
+	s.tweetsMu.Unlock()
+
+	for mediaID, mt := range translation.Media {
+		if err := writeTranslatedSubtitleFiles(src.archivePath, mediaID, lang, mt.Segments); err != nil {
+			s.logger.Warn("failed to write translated subtitles", "tweet_id", tweetID, "media_id", mediaID, "language", lang, "error", err)
+		}
+	}
+
+	s.logger.Info("tweet translated",
+		"tweet_id", tweetID,
+		"language", lang,
+		"source_language", translation.SourceLanguage,
+		"transcripts", len(translation.Media))
+
+	return &translation, nil
+}
+
+// translateTranscript translates a media transcript. Timed segments are
+// sent in batches so long videos fit in one request each and the translated
+// segments line up with the originals.
+func (s *TweetService) translateTranscript(ctx context.Context, m domain.Media, lang string) (domain.MediaTranslation, error) {
+	if len(m.TranscriptSegments) == 0 {
+		resp, err := s.grokClient.Translate(ctx, grok.TranslationRequest{
+			TargetLanguage: lang,
+			SourceLanguage: m.TranscriptLanguage,
+			Texts:          []string{m.Transcript},
+		})
+		if err != nil {
+			return domain.MediaTranslation{}, err
+		}
+		return domain.MediaTranslation{Transcript: resp.Texts[0]}, nil
+	}
+
+	batchSize := s.aiCfg.TranslationBatchSize
+	if batchSize <= 0 {
+		batchSize = defaultTranslationBatchSize
+	}
+	segments := make([]domain.TranscriptSegment, 0, len(m.TranscriptSegments))
+	for start := 0; start < len(m.TranscriptSegments); start += batchSize {
+		batch := m.TranscriptSegments[start:min(start+batchSize, len(m.TranscriptSegments))]
+		texts := make([]string, len(batch))
+		for i, seg := range batch {
+			texts[i] = seg.Text
+		}
+		resp, err := s.grokClient.Translate(ctx, grok.TranslationRequest{
+			TargetLanguage: lang,
+			SourceLanguage: m.TranscriptLanguage,
+			Texts:          texts,
+		})
+		if err != nil {
+			return domain.MediaTranslation{}, err
+		}
+		for i, seg := range batch {
+			segments = append(segments, domain.TranscriptSegment{
+				Start: seg.Start,
+				End:   seg.End,
+				Text:  strings.TrimSpace(resp.Texts[i]),
+			})
+		}
+	}
+
+	texts := make([]string, len(segments))
+	for i, seg := range segments {
+		texts[i] = seg.Text
+	}
+	return domain.MediaTranslation{
+		Transcript: strings.Join(texts, " "),
+		Segments:   segments,
+	}, nil
+}
+
+// GetTranslation returns a tweet's translation into lang.
+func (s *TweetService) GetTranslation(tweetID domain.TweetID, lang string) (*domain.TweetTranslation, error) {
+	lang, err := normalizeLanguage(lang)
+	if err != nil {
+		return nil, err
+	}
+	s.tweetsMu.RLock()
+	defer s.tweetsMu.RUnlock()
+	tweet, ok := s.tweets[tweetID]
+	if !ok {
+		return nil, domain.ErrVideoNotFound
+	}
+	translation, ok := tweet.Translations[lang]
+	if !ok {
+		return nil, ErrTranslationNotFound
+	}
+	return &translation, nil
+}
+
+// DeleteTranslation removes a tweet's translation into lang along with its
+// translated subtitle files.
+func (s *TweetService) DeleteTranslation(ctx context.Context, tweetID domain.TweetID, lang string) error {
+	lang, err := normalizeLanguage(lang)
+	if err != nil {
+		return err
+	}
+	s.tweetsMu.Lock()
+	tweet, ok := s.tweets[tweetID]
+	if !ok {
+		s.tweetsMu.Unlock()
+		return domain.ErrVideoNotFound
+	}
+	translation, ok := tweet.Translations[lang]
+	if !ok {
+		s.tweetsMu.Unlock()
+		return ErrTranslationNotFound
+	}
+	delete(tweet.Translations, lang)
+	if len(tweet.Translations) == 0 {
+		tweet.Translations = nil
+	}
+	s.tweetsMu.Unlock()
+
+	var removed []string
+	for mediaID := range translation.Media {
+		removeTranslatedSubtitleFiles(tweet.ArchivePath, mediaID, lang)
+		removed = append(removed, translatedSubtitleFiles(mediaID, lang)...)
+	}
+	if err := s.saveTweetMetadata(tweet); err != nil {
+		return fmt.Errorf("failed to save tweet metadata: %w", err)
+	}
+	s.writeChecksums(tweet, removed...)
+	if err := s.syncArchiveToStorage(ctx, tweet); err != nil {
+		s.logger.Warn("failed to mirror archive to storage", "tweet_id", tweetID, "error", err)
+	}
+
+	s.logger.Info("translation deleted", "tweet_id", tweetID, "language", lang)
+	return nil
+}
+
+// StartTranslate translates a tweet into lang in the background and saves the
+// archive when done.
+func (s *TweetService) StartTranslate(tweetID domain.TweetID, lang string) error {
+	lang, err := normalizeLanguage(lang)
+	if err != nil {
+		return err
+	}
+	src, err := s.translationSource(tweetID)
+	if err != nil {
+		return err
+	}
+	if src.empty() {
+		return ErrNothingToTranslate
+	}
+	if err := s.aiBudgetExceeded(); err != nil {
+		return err
+	}
+
+	key := string(tweetID) + "/" + lang
+	s.aiAnalysisLock.Lock()
+	if s.translating[key] {
+		s.aiAnalysisLock.Unlock()
+		return ErrTranslationInProgress
+	}
+	if s.translating == nil {
+		s.translating = make(map[string]bool)
+	}
+	s.translating[key] = true
+	s.aiAnalysisLock.Unlock()
+
+	go func() {
+		defer func() {
+			s.aiAnalysisLock.Lock()
+			delete(s.translating, key)
+			s.aiAnalysisLock.Unlock()
+		}()
+
+		ctx := context.Background()
+		if s.aiCfg.RegenerateTimeout > 0 {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, s.aiCfg.RegenerateTimeout)
+			defer cancel()
+		}
+		if err := s.translateAndSave(ctx, tweetID, lang); err != nil {
+			s.logger.Error("background translation failed", "tweet_id", tweetID, "language", lang, "error", err)
+		}
+	}()
+	return nil
+}
+
+// IsTranslating reports whether a background translation of the tweet into
+// lang is running.
+func (s *TweetService) IsTranslating(tweetID domain.TweetID, lang string) bool {
+	s.aiAnalysisLock.Lock()
+	defer s.aiAnalysisLock.Unlock()
+	return s.translating[string(tweetID)+"/"+strings.ToLower(lang)]
+}
+
+// translateAndSave runs Translate and persists the archive.
+func (s *TweetService) translateAndSave(ctx context.Context, tweetID domain.TweetID, lang string) error {
+	translation, err := s.Translate(ctx, tweetID, lang)
+	if err != nil {
+		return err
+	}
+
+	s.tweetsMu.RLock()
+	tweet, ok := s.tweets[tweetID]
+	s.tweetsMu.RUnlock()
+	if !ok {
+		return domain.ErrVideoNotFound
+	}
+	if err := s.saveTweetMetadata(tweet); err != nil {
+		return fmt.Errorf("failed to save tweet metadata: %w", err)
+	}
+	var rewritten []string
+	for mediaID := range translation.Media {
+		rewritten = append(rewritten, translatedSubtitleFiles(mediaID, lang)...)
+	}
+	s.writeChecksums(tweet, rewritten...)
+	if err := s.syncArchiveToStorage(ctx, tweet); err != nil {
+		s.logger.Warn("failed to mirror archive to storage", "tweet_id", tweetID, "error", err)
+	}
+
+	s.emitEvent(domain.EventSeverityInfo, domain.EventCategoryAI, "Tweet translated",
+		domain.EventMetadata{
+			"tweet_id":    string(tweetID),
+			"language":    lang,
+			"transcripts": len(translation.Media),
+		})
+	return nil
+}
+
+// runTranslations translates a tweet being archived into each configured
+// target language it has no translation for yet. The caller saves the tweet.
+func (s *TweetService) runTranslations(ctx context.Context, tweet *domain.Tweet) {
+	for _, lang := range s.aiCfg.TranslationTargets() {
+		s.tweetsMu.RLock()
+		_, done := tweet.Translations[lang]
+		s.tweetsMu.RUnlock()
+		if done {
+			continue
+		}
+		if err := s.aiBudgetExceeded(); err != nil {
+			s.logger.Warn("translation paused", "tweet_id", tweet.ID, "error", err)
+			return
+		}
+		if _, err := s.Translate(ctx, tweet.ID, lang); err != nil && !errors.Is(err, ErrNothingToTranslate) {
+			s.logger.Warn("translation failed", "tweet_id", tweet.ID, "language", lang, "error", err)
+		}
+	}
+}
+
+// dropTranscriptTranslations removes the translations of a media item's
+// transcript, e.g. before it is transcribed again, and returns the removed
+// subtitle files relative to the archive.
+func (s *TweetService) dropTranscriptTranslations(tweet *domain.Tweet, mediaID string) []string {
+	s.tweetsMu.Lock()
+	defer s.tweetsMu.Unlock()
+	var removed []string
+	for lang, translation := range tweet.Translations {
+		if _, ok := translation.Media[mediaID]; !ok {
+			continue
+		}
+		delete(translation.Media, mediaID)
+		tweet.Translations[lang] = translation
+		removeTranslatedSubtitleFiles(tweet.ArchivePath, mediaID, lang)
+		removed = append(removed, translatedSubtitleFiles(mediaID, lang)...)
+	}
+	return removed
+}
@@ -0,0 +1,156 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/iconidentify/xgrabba/internal/config"
+	"github.com/iconidentify/xgrabba/internal/domain"
+	"github.com/iconidentify/xgrabba/pkg/grok"
+)
+
+// translateStub "translates" by prefixing each text with the target language
+// and records the size of each batch.
+type translateStub struct {
+	analysisStub
+	source  string
+	batches []int
+}
+
+func (s *translateStub) Translate(ctx context.Context, req grok.TranslationRequest) (*grok.TranslationResponse, error) {
+	s.batches = append(s.batches, len(req.Texts))
+	out := make([]string, len(req.Texts))
+	for i, text := range req.Texts {
+		out[i] = "[" + req.TargetLanguage + "] " + text
+	}
+	return &grok.TranslationResponse{SourceLanguage: s.source, Texts: out}, nil
+}
+
+func newTranslationTestService(t *testing.T) (*TweetService, *domain.Tweet, *translateStub) {
+	t.Helper()
+	svc, tweet := newIntegrityTestService(t)
+	stub := &translateStub{source: "ja"}
+	svc.grokClient = stub
+	svc.aiCfg = config.AIConfig{TranslationBatchSize: 2}
+	tweet.Text = "猫の動画"
+	tweet.Media[0].Transcript = "にゃー にゃー にゃー"
+	tweet.Media[0].TranscriptLanguage = "ja"
+	tweet.Media[0].TranscriptSegments = []domain.TranscriptSegment{
+		{Start: 0, End: 1.5, Text: "にゃー"},
+		{Start: 1.5, End: 3, Text: "にゃー"},
+		{Start: 3, End: 4.25, Text: "にゃー"},
+	}
+	return svc, tweet, stub
+}
+
+func TestTranslate_KeepsSegmentTimings(t *testing.T) {
+	svc, tweet, stub := newTranslationTestService(t)
+
+	got, err := svc.Translate(context.Background(), tweet.ID, "EN")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Language != "en" || got.SourceLanguage != "ja" || got.Text != "[en] 猫の動画" {
+		t.Errorf("translation = %+v", got)
+	}
+	if fmt.Sprint(stub.batches) != "[1 2 1]" {
+		t.Errorf("batches = %v, want text then segments in batches of 2", stub.batches)
+	}
+
+	mt, ok := got.Media["m1"]
+	if !ok {
+		t.Fatal("missing transcript translation for m1")
+	}
+	if len(mt.Segments) != 3 || mt.Segments[2].Start != 3 || mt.Segments[2].End != 4.25 || mt.Segments[2].Text != "[en] にゃー" {
+		t.Errorf("segments = %+v", mt.Segments)
+	}
+	if _, ok := got.Media["m2"]; ok {
+		t.Error("media without a transcript should not be translated")
+	}
+
+	vtt, err := os.ReadFile(filepath.Join(tweet.ArchivePath, "media", TranslatedSubtitleFilename("m1", "en")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(vtt), "00:00:03.000 --> 00:00:04.250\n[en] にゃー") {
+		t.Errorf("translated vtt = %q", vtt)
+	}
+
+	if matches := findTranscriptMatches(tweet, "[en] にゃー"); len(matches) != 3 || matches[0].Language != "en" {
+		t.Errorf("translated transcript matches = %+v", matches)
+	}
+	if !svc.tweetMatchesQuery(tweet, "[en] 猫") {
+		t.Error("search should match translated text")
+	}
+}
+
+func TestTranslate_SkipsTextAlreadyInTarget(t *testing.T) {
+	svc, tweet, stub := newTranslationTestService(t)
+	stub.source = "en"
+	tweet.Media[0].TranscriptLanguage = "en"
+
+	got, err := svc.Translate(context.Background(), tweet.ID, "en")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Text != "" || len(got.Media) != 0 {
+		t.Errorf("translation of English content into English = %+v", got)
+	}
+	if len(stub.batches) != 1 {
+		t.Errorf("transcript already in target language should not be sent, batches = %v", stub.batches)
+	}
+}
+
+func TestTranslation_StoreAndDelete(t *testing.T) {
+	svc, tweet, _ := newTranslationTestService(t)
+	ctx := context.Background()
+
+	if err := svc.translateAndSave(ctx, tweet.ID, "es"); err != nil {
+		t.Fatal(err)
+	}
+	stored := tweet.ToStoredTweet()
+	if loaded := svc.storedTweetToTweet(&stored, ""); loaded.Translations["es"].Text != "[es] 猫の動画" {
+		t.Errorf("translation not stored: %+v", loaded.Translations)
+	}
+	if got, err := svc.GetTranslation(tweet.ID, "es"); err != nil || got.Media["m1"].Transcript != "[es] にゃー [es] にゃー [es] にゃー" {
+		t.Errorf("GetTranslation = %+v, %v", got, err)
+	}
+
+	if err := svc.DeleteTranslation(ctx, tweet.ID, "es"); err != nil {
+		t.Fatal(err)
+	}
+	if tweet.Translations != nil {
+		t.Errorf("translations after delete = %+v", tweet.Translations)
+	}
+	if _, err := os.Stat(filepath.Join(tweet.ArchivePath, "media", TranslatedSubtitleFilename("m1", "es"))); !os.IsNotExist(err) {
+		t.Error("translated subtitles should be removed")
+	}
+	if _, err := svc.GetTranslation(tweet.ID, "es"); !errors.Is(err, ErrTranslationNotFound) {
+		t.Errorf("GetTranslation after delete: err = %v", err)
+	}
+}
+
+func TestTranslate_Errors(t *testing.T) {
+	svc, tweet, _ := newTranslationTestService(t)
+	ctx := context.Background()
+
+	for _, lang := range []string{"", "eng", "e1"} {
+		if _, err := svc.Translate(ctx, tweet.ID, lang); !errors.Is(err, ErrInvalidLanguage) {
+			t.Errorf("Translate(%q): err = %v, want ErrInvalidLanguage", lang, err)
+		}
+	}
+	if _, err := svc.Translate(ctx, "missing", "en"); !errors.Is(err, domain.ErrVideoNotFound) {
+		t.Errorf("missing tweet: err = %v", err)
+	}
+
+	tweet.Text = ""
+	tweet.Media[0].Transcript = ""
+	if err := svc.StartTranslate(tweet.ID, "en"); !errors.Is(err, ErrNothingToTranslate) {
+		t.Errorf("empty tweet: err = %v, want ErrNothingToTranslate", err)
+	}
+}
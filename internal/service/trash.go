@@ -0,0 +1,327 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/iconidentify/xgrabba/internal/domain"
+)
+
+// TrashDirName is the directory under BasePath holding deleted archives.
+const TrashDirName = ".trash"
+
+// trashRecordFilename sits next to tweet.json in a trashed archive and
+// records where it came from.
+const trashRecordFilename = "trash.json"
+
+// trashPurgeInterval is how often expired trash is purged.
+const trashPurgeInterval = time.Hour
+
+// trashRecord is the on-disk record of a trashed archive.
+type trashRecord struct {
+	TweetID      domain.TweetID `json:"tweet_id"`
+	OriginalPath string         `json:"original_path"` // Relative to BasePath
+	DeletedAt    time.Time      `json:"deleted_at"`
+}
+
+// TrashEntry describes a deleted archive that can still be restored.
+type TrashEntry struct {
+	TweetID      domain.TweetID `json:"tweet_id"`
+	OriginalPath string         `json:"original_path"`
+	DeletedAt    time.Time      `json:"deleted_at"`
+	ExpiresAt    time.Time      `json:"expires_at"`
+	Author       string         `json:"author,omitempty"`
+	Text         string         `json:"text,omitempty"`
+	MediaCount   int            `json:"media_count"`
+	SizeBytes    int64          `json:"size_bytes"`
+}
+
+// trashPath returns the trash directory for a tweet.
+func (s *TweetService) trashPath(tweetID domain.TweetID) string {
+	return filepath.Join(s.cfg.BasePath, TrashDirName, string(tweetID))
+}
+
+// moveToTrash moves an archive directory into the trash. Remote metadata is
+// removed so RestoreFromStorage does not bring the archive back; remote media
+// is kept until the trash entry is purged.
+func (s *TweetService) moveToTrash(ctx context.Context, tweetID domain.TweetID, archivePath string) error {
+	if _, err := os.Stat(archivePath); os.IsNotExist(err) {
+		return nil
+	}
+	rel, err := filepath.Rel(s.cfg.BasePath, archivePath)
+	if err != nil {
+		return fmt.Errorf("resolve archive path: %w", err)
+	}
+
+	s.trashMu.Lock()
+	defer s.trashMu.Unlock()
+
+	dest := s.trashPath(tweetID)
+	// An older deletion of the same tweet is superseded by this one
+	if _, err := os.Stat(dest); err == nil {
+		s.purgeTrashLocked(ctx, tweetID)
+	}
+	if err := os.MkdirAll(filepath.Dir(dest), 0755); err != nil {
+		return fmt.Errorf("create trash directory: %w", err)
+	}
+	if err := os.Rename(archivePath, dest); err != nil {
+		return fmt.Errorf("move archive to trash: %w", err)
+	}
+
+	record := trashRecord{TweetID: tweetID, OriginalPath: filepath.ToSlash(rel), DeletedAt: time.Now().UTC()}
+	data, err := jsonMarshalIndent(record)
+	if err != nil {
+		return fmt.Errorf("marshal trash record: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(dest, trashRecordFilename), data, 0644); err != nil {
+		return fmt.Errorf("write trash record: %w", err)
+	}
+
+	if s.remoteStorage() {
+		for _, name := range []string{"tweet.json", "README.md", "avatar.jpg", MetricsHistoryFilename} {
+			key, err := s.storageKey(filepath.Join(archivePath, name))
+			if err != nil {
+				break
+			}
+			s.forgetRemote(key)
+			if err := s.store.Delete(ctx, key); err != nil {
+				s.logger.Warn("failed to remove trashed metadata from storage", "tweet_id", tweetID, "file", name, "error", err)
+			}
+		}
+	}
+	return nil
+}
+
+// readTrashRecord loads the trash record of a trashed archive directory.
+func readTrashRecord(dir string) (*trashRecord, error) {
+	data, err := os.ReadFile(filepath.Join(dir, trashRecordFilename))
+	if err != nil {
+		return nil, err
+	}
+	var record trashRecord
+	if err := json.Unmarshal(data, &record); err != nil {
+		return nil, fmt.Errorf("parse trash record: %w", err)
+	}
+	return &record, nil
+}
+
+// ListTrash returns deleted archives, most recently deleted first.
+func (s *TweetService) ListTrash(ctx context.Context) ([]TrashEntry, error) {
+	s.trashMu.Lock()
+	defer s.trashMu.Unlock()
+
+	dirs, err := os.ReadDir(filepath.Join(s.cfg.BasePath, TrashDirName))
+	if os.IsNotExist(err) {
+		return []TrashEntry{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read trash: %w", err)
+	}
+
+	entries := make([]TrashEntry, 0, len(dirs))
+	for _, d := range dirs {
+		if !d.IsDir() {
+			continue
+		}
+		dir := filepath.Join(s.cfg.BasePath, TrashDirName, d.Name())
+		record, err := readTrashRecord(dir)
+		if err != nil {
+			s.logger.Warn("skipping unreadable trash entry", "path", dir, "error", err)
+			continue
+		}
+
+		entry := TrashEntry{
+			TweetID:      record.TweetID,
+			OriginalPath: record.OriginalPath,
+			DeletedAt:    record.DeletedAt,
+			ExpiresAt:    record.DeletedAt.Add(s.cfg.TrashRetention),
+		}
+		if data, err := os.ReadFile(filepath.Join(dir, "tweet.json")); err == nil {
+			var stored domain.StoredTweet
+			if json.Unmarshal(data, &stored) == nil {
+				entry.Author = stored.Author.Username
+				entry.Text = truncateText(stored.Text, 200)
+				entry.MediaCount = len(stored.Media)
+			}
+		}
+		filepath.Walk(dir, func(_ string, info os.FileInfo, err error) error {
+			if err == nil && !info.IsDir() {
+				entry.SizeBytes += info.Size()
+			}
+			return nil
+		})
+		entries = append(entries, entry)
+	}
+
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].DeletedAt.After(entries[j].DeletedAt)
+	})
+	return entries, nil
+}
+
+// RestoreFromTrash moves a deleted archive back to its original location and
+// re-indexes it. It fails with domain.ErrDuplicateVideo if the tweet has been
+// archived again since it was deleted.
+func (s *TweetService) RestoreFromTrash(ctx context.Context, tweetID domain.TweetID) (*domain.Tweet, error) {
+	s.trashMu.Lock()
+	defer s.trashMu.Unlock()
+
+	dir := s.trashPath(tweetID)
+	record, err := readTrashRecord(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, domain.ErrVideoNotFound
+		}
+		return nil, err
+	}
+
+	s.tweetsMu.RLock()
+	_, exists := s.tweets[tweetID]
+	s.tweetsMu.RUnlock()
+	archivePath := filepath.Join(s.cfg.BasePath, filepath.FromSlash(record.OriginalPath))
+	if _, err := os.Stat(archivePath); exists || err == nil {
+		return nil, domain.ErrDuplicateVideo
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, "tweet.json"))
+	if err != nil {
+		return nil, fmt.Errorf("read tweet.json: %w", err)
+	}
+	var stored domain.StoredTweet
+	if err := json.Unmarshal(data, &stored); err != nil {
+		return nil, fmt.Errorf("parse tweet.json: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(archivePath), 0755); err != nil {
+		return nil, fmt.Errorf("create archive directory: %w", err)
+	}
+	if err := os.Rename(dir, archivePath); err != nil {
+		return nil, fmt.Errorf("restore archive: %w", err)
+	}
+	os.Remove(filepath.Join(archivePath, trashRecordFilename))
+
+	tweet := s.storedTweetToTweet(&stored, archivePath)
+	s.tweetsMu.Lock()
+	s.tweets[tweet.ID] = tweet
+	s.tweetsMu.Unlock()
+
+	if err := s.syncArchiveToStorage(ctx, tweet); err != nil {
+		s.logger.Warn("failed to mirror restored archive", "tweet_id", tweetID, "error", err)
+	}
+
+	s.logger.Info("tweet restored from trash", "tweet_id", tweetID)
+	return tweet, nil
+}
+
+// PurgeTrash permanently deletes one archive from the trash.
+func (s *TweetService) PurgeTrash(ctx context.Context, tweetID domain.TweetID) error {
+	s.trashMu.Lock()
+	defer s.trashMu.Unlock()
+
+	if _, err := os.Stat(s.trashPath(tweetID)); os.IsNotExist(err) {
+		return domain.ErrVideoNotFound
+	}
+	s.purgeTrashLocked(ctx, tweetID)
+	return nil
+}
+
+// EmptyTrash permanently deletes every archive in the trash.
+func (s *TweetService) EmptyTrash(ctx context.Context) (int, error) {
+	return s.purgeTrash(ctx, func(*trashRecord) bool { return true })
+}
+
+// PurgeExpiredTrash permanently deletes archives that have been in the trash
+// longer than the retention period.
+func (s *TweetService) PurgeExpiredTrash(ctx context.Context) (int, error) {
+	cutoff := time.Now().Add(-s.cfg.TrashRetention)
+	return s.purgeTrash(ctx, func(r *trashRecord) bool { return r.DeletedAt.Before(cutoff) })
+}
+
+// purgeTrash deletes trash entries matching expired.
+func (s *TweetService) purgeTrash(ctx context.Context, expired func(*trashRecord) bool) (int, error) {
+	s.trashMu.Lock()
+	defer s.trashMu.Unlock()
+
+	dirs, err := os.ReadDir(filepath.Join(s.cfg.BasePath, TrashDirName))
+	if os.IsNotExist(err) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("read trash: %w", err)
+	}
+
+	purged := 0
+	for _, d := range dirs {
+		if ctx.Err() != nil {
+			return purged, ctx.Err()
+		}
+		if !d.IsDir() {
+			continue
+		}
+		record, err := readTrashRecord(filepath.Join(s.cfg.BasePath, TrashDirName, d.Name()))
+		if err != nil || !expired(record) {
+			continue
+		}
+		s.purgeTrashLocked(ctx, record.TweetID)
+		purged++
+	}
+	return purged, nil
+}
+
+// purgeTrashLocked removes a trashed archive, its blob references and its
+// remote copy. Remote objects are kept if the tweet was archived again at the
+// same path. Callers must hold trashMu.
+func (s *TweetService) purgeTrashLocked(ctx context.Context, tweetID domain.TweetID) {
+	dir := s.trashPath(tweetID)
+	record, err := readTrashRecord(dir)
+
+	if err := os.RemoveAll(dir); err != nil {
+		s.logger.Warn("failed to purge trashed archive", "tweet_id", tweetID, "path", dir, "error", err)
+		return
+	}
+	s.releaseMediaBlobs(tweetID, dir)
+
+	if err == nil {
+		archivePath := filepath.Join(s.cfg.BasePath, filepath.FromSlash(record.OriginalPath))
+		if _, statErr := os.Stat(archivePath); os.IsNotExist(statErr) {
+			s.releaseMediaBlobs(tweetID, archivePath)
+			s.deleteFromStorage(ctx, tweetID, archivePath)
+		}
+	}
+
+	s.logger.Info("trashed archive purged", "tweet_id", tweetID)
+}
+
+// RunTrashRetention purges expired trash periodically until ctx is cancelled.
+func (s *TweetService) RunTrashRetention(ctx context.Context) {
+	if s.cfg.TrashRetention <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(trashPurgeInterval)
+	defer ticker.Stop()
+	for {
+		purged, err := s.PurgeExpiredTrash(ctx)
+		if err != nil && !errors.Is(err, context.Canceled) {
+			s.logger.Warn("failed to purge expired trash", "error", err)
+		}
+		if purged > 0 {
+			s.logger.Info("purged expired trash", "count", purged)
+			s.emitEvent(domain.EventSeverityInfo, domain.EventCategoryDisk,
+				fmt.Sprintf("Purged %d expired archive(s) from trash", purged),
+				domain.EventMetadata{"purged": fmt.Sprintf("%d", purged)})
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
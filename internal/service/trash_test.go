@@ -0,0 +1,157 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/iconidentify/xgrabba/internal/config"
+	"github.com/iconidentify/xgrabba/internal/domain"
+)
+
+func newTrashTestService(t *testing.T) (*TweetService, *domain.Tweet) {
+	t.Helper()
+	base := t.TempDir()
+	archive := filepath.Join(base, "2024", "01", "user_2024-01-02_1")
+	mediaPath := filepath.Join(archive, "media", "m1.jpg")
+	if err := os.MkdirAll(filepath.Dir(mediaPath), 0755); err != nil {
+		t.Fatal(err)
+	}
+	os.WriteFile(mediaPath, []byte("image-bytes"), 0644)
+
+	tweet := &domain.Tweet{
+		ID:          "1",
+		Text:        "hello",
+		Author:      domain.Author{Username: "user"},
+		Status:      domain.ArchiveStatusCompleted,
+		ArchivePath: archive,
+		Media:       []domain.Media{{ID: "m1", Type: domain.MediaTypeImage, LocalPath: mediaPath, Downloaded: true}},
+	}
+	svc := &TweetService{
+		cfg:    config.StorageConfig{BasePath: base, TrashRetention: 24 * time.Hour},
+		logger: slog.New(slog.NewTextHandler(io.Discard, nil)),
+		tweets: map[domain.TweetID]*domain.Tweet{tweet.ID: tweet},
+	}
+	if err := svc.saveTweetMetadata(tweet); err != nil {
+		t.Fatal(err)
+	}
+	return svc, tweet
+}
+
+func TestDelete_MovesToTrashAndRestores(t *testing.T) {
+	svc, tweet := newTrashTestService(t)
+	ctx := context.Background()
+	archive := tweet.ArchivePath
+
+	if err := svc.Delete(ctx, tweet.ID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(archive); !os.IsNotExist(err) {
+		t.Fatal("archive should be moved out of its original location")
+	}
+	if _, err := svc.GetArchivePath(ctx, tweet.ID); !errors.Is(err, domain.ErrVideoNotFound) {
+		t.Errorf("trashed tweet should not be listed, got err=%v", err)
+	}
+
+	// Trashed archives are not picked up on reload
+	if err := svc.LoadFromDisk(); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := svc.tweets[tweet.ID]; ok {
+		t.Error("LoadFromDisk should skip the trash")
+	}
+
+	entries, err := svc.ListTrash(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 {
+		t.Fatalf("trash entries = %d, want 1", len(entries))
+	}
+	e := entries[0]
+	if e.TweetID != tweet.ID || e.Author != "user" || e.MediaCount != 1 || e.OriginalPath != "2024/01/user_2024-01-02_1" {
+		t.Errorf("unexpected entry %+v", e)
+	}
+	if !e.ExpiresAt.Equal(e.DeletedAt.Add(24 * time.Hour)) {
+		t.Errorf("expires_at = %v, want deleted_at + retention", e.ExpiresAt)
+	}
+
+	restored, err := svc.RestoreFromTrash(ctx, tweet.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if restored.ArchivePath != archive {
+		t.Errorf("restored path = %q, want %q", restored.ArchivePath, archive)
+	}
+	if _, err := os.Stat(filepath.Join(archive, "media", "m1.jpg")); err != nil {
+		t.Error("media should be back in place")
+	}
+	if _, err := os.Stat(filepath.Join(archive, trashRecordFilename)); !os.IsNotExist(err) {
+		t.Error("trash record should be removed on restore")
+	}
+	if _, err := svc.GetArchivePath(ctx, tweet.ID); err != nil {
+		t.Errorf("restored tweet not indexed: %v", err)
+	}
+	if _, err := svc.RestoreFromTrash(ctx, tweet.ID); !errors.Is(err, domain.ErrVideoNotFound) {
+		t.Errorf("second restore err = %v, want ErrVideoNotFound", err)
+	}
+}
+
+func TestRestoreFromTrash_Conflict(t *testing.T) {
+	svc, tweet := newTrashTestService(t)
+	ctx := context.Background()
+
+	if err := svc.Delete(ctx, tweet.ID); err != nil {
+		t.Fatal(err)
+	}
+	// Archived again after deletion
+	svc.tweets[tweet.ID] = tweet
+	if _, err := svc.RestoreFromTrash(ctx, tweet.ID); !errors.Is(err, domain.ErrDuplicateVideo) {
+		t.Errorf("err = %v, want ErrDuplicateVideo", err)
+	}
+}
+
+func TestPurgeTrash(t *testing.T) {
+	svc, tweet := newTrashTestService(t)
+	ctx := context.Background()
+
+	if err := svc.Delete(ctx, tweet.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	// Not yet expired
+	if n, err := svc.PurgeExpiredTrash(ctx); err != nil || n != 0 {
+		t.Fatalf("PurgeExpiredTrash = %d, %v; want 0", n, err)
+	}
+
+	svc.cfg.TrashRetention = time.Nanosecond
+	if n, err := svc.PurgeExpiredTrash(ctx); err != nil || n != 1 {
+		t.Fatalf("PurgeExpiredTrash = %d, %v; want 1", n, err)
+	}
+	if _, err := os.Stat(svc.trashPath(tweet.ID)); !os.IsNotExist(err) {
+		t.Error("expired archive should be removed")
+	}
+	if err := svc.PurgeTrash(ctx, tweet.ID); !errors.Is(err, domain.ErrVideoNotFound) {
+		t.Errorf("PurgeTrash on missing entry err = %v", err)
+	}
+}
+
+func TestDelete_ZeroRetentionRemovesImmediately(t *testing.T) {
+	svc, tweet := newTrashTestService(t)
+	svc.cfg.TrashRetention = 0
+
+	if err := svc.Delete(context.Background(), tweet.ID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(tweet.ArchivePath); !os.IsNotExist(err) {
+		t.Error("archive should be removed")
+	}
+	if _, err := os.Stat(filepath.Join(svc.cfg.BasePath, TrashDirName)); !os.IsNotExist(err) {
+		t.Error("trash should not be used with zero retention")
+	}
+}
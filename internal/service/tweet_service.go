@@ -14,11 +14,14 @@ import (
 	"sync"
 	"time"
 
+	"github.com/iconidentify/xgrabba/internal/blobstore"
 	"github.com/iconidentify/xgrabba/internal/config"
 	"github.com/iconidentify/xgrabba/internal/domain"
 	"github.com/iconidentify/xgrabba/internal/downloader"
+	"github.com/iconidentify/xgrabba/internal/storage"
 	"github.com/iconidentify/xgrabba/pkg/ffmpeg"
 	"github.com/iconidentify/xgrabba/pkg/grok"
+	"github.com/iconidentify/xgrabba/pkg/ocr"
 	"github.com/iconidentify/xgrabba/pkg/twitter"
 	"github.com/iconidentify/xgrabba/pkg/whisper"
 )
@@ -38,6 +41,7 @@ var permanentFailurePatterns = []string{
 }
 
 // isPermanentTweetFailure checks if an error message indicates a permanent failure.
+// It is only consulted for failures recorded before FailureReason existed.
 func isPermanentTweetFailure(errMsg string) bool {
 	if errMsg == "" {
 		return false
@@ -61,6 +65,9 @@ type TweetService struct {
 	cfg            config.StorageConfig
 	aiCfg          config.AIConfig
 	whisperEnabled bool
+	ocrEngine      ocr.Engine
+	blobs          *blobstore.Store
+	store          storage.Storage
 	logger         *slog.Logger
 	eventEmitter   domain.EventEmitter
 
@@ -73,17 +80,63 @@ type TweetService struct {
 	aiAnalysisLock sync.Mutex
 	processingAI   map[domain.TweetID]bool // Track which tweets are currently being analyzed
 
-	// Semaphore to limit concurrent video processing
-	processingSem chan struct{}
+	// Serializes moves into, out of and within the trash
+	trashMu sync.Mutex
+
+	// Default media quality policy; see SetMediaQuality
+	mediaQuality domain.MediaQuality
+
+	// Limits concurrent archive processing; high-priority requests go first
+	processingSem *prioritySemaphore
+
+	// Playlists named in archive requests; see SetPlaylistService
+	playlists *PlaylistService
+
+	// AI usage ledger and budgets; see SetAIUsage
+	usage *AIUsageService
+
+	// AI response cache, reported in diagnostics; see SetAICache
+	aiCache *grok.ResponseCache
+
+	// Prompt templates, for versioning AI results and previews; see SetPrompts
+	prompts         *grok.Prompts
+	promptRerunning bool // Protected by aiAnalysisLock
+
+	// Background translations keyed by "<tweet id>/<language>"; protected by aiAnalysisLock
+	translating map[string]bool
+
+	// Curates AI tags and topics; see SetTagRegistry
+	tags *TagRegistryService
+
+	// Background application of the tag registry; see StartTagRegistryApply
+	tagApplyMu    sync.Mutex
+	tagApply      TagApplyStatus
+	tagApplyAgain bool // The registry changed during a run
+
+	// Objects known to be in the remote backend, keyed by storage key, so
+	// serving and syncing do not stat them on every request
+	remoteMu      sync.Mutex
+	remoteObjects map[string]storage.ObjectInfo
+
+	// tweet.json and README.md uploads keyed by storage key; see mirrorMetadata
+	metadataMu        sync.Mutex
+	metadataQueue     map[string]metadataUpload
+	metadataMirroring bool   // runMetadataMirror is draining the queue
+	metadataInFlight  string // Key being uploaded
+	metadataCanceled  bool   // metadataInFlight was deleted during its upload
 }
 
 // PipelineDiagnostics captures high-level runtime capabilities/config.
 type PipelineDiagnostics struct {
-	FFmpegAvailable    bool   `json:"ffmpeg_available"`
-	FFmpegVersion      string `json:"ffmpeg_version,omitempty"`
-	VideoProcessorInit bool   `json:"video_processor_initialized"`
-	WhisperEnabled     bool   `json:"whisper_enabled"`
-	WhisperClientInit  bool   `json:"whisper_client_initialized"`
+	FFmpegAvailable    bool             `json:"ffmpeg_available"`
+	FFmpegVersion      string           `json:"ffmpeg_version,omitempty"`
+	VideoProcessorInit bool             `json:"video_processor_initialized"`
+	WhisperEnabled     bool             `json:"whisper_enabled"`
+	WhisperClientInit  bool             `json:"whisper_client_initialized"`
+	OCREngine          string           `json:"ocr_engine,omitempty"`
+	BlobStore          *blobstore.Stats `json:"blob_store,omitempty"`
+	StorageBackend     string           `json:"storage_backend,omitempty"`
+	AICache            *grok.CacheStats `json:"ai_cache,omitempty"`
 }
 
 // NewTweetService creates a new tweet service.
@@ -119,13 +172,15 @@ func NewTweetService(
 		videoProcessor: videoProc,
 		downloader:     dl,
 		cfg:            storageCfg,
+		store:          storage.NewLocal(storageCfg.BasePath),
 		aiCfg:          aiCfg,
 		whisperEnabled: whisperEnabled && whisperClient != nil && videoProc != nil,
 		logger:         logger,
 		eventEmitter:   eventEmitter,
 		tweets:         make(map[domain.TweetID]*domain.Tweet),
 		processingAI:   make(map[domain.TweetID]bool),
-		processingSem:  make(chan struct{}, 2), // Allow 2 concurrent video processes
+		translating:    make(map[string]bool),
+		processingSem:  newPrioritySemaphore(2), // Allow 2 concurrent video processes
 	}
 
 	// Load existing tweets from disk on startup
@@ -157,6 +212,18 @@ func (s *TweetService) GetPipelineDiagnostics() PipelineDiagnostics {
 		VideoProcessorInit: s.videoProcessor != nil,
 		WhisperEnabled:     s.whisperEnabled,
 		WhisperClientInit:  s.whisperClient != nil,
+		OCREngine:          s.ocrEngineName(),
+	}
+	if s.blobs != nil {
+		stats := s.blobs.Stats()
+		diag.BlobStore = &stats
+	}
+	if s.store != nil {
+		diag.StorageBackend = s.store.Name()
+	}
+	if s.aiCache != nil {
+		stats := s.aiCache.Stats()
+		diag.AICache = &stats
 	}
 	if diag.FFmpegAvailable {
 		if v, err := ffmpeg.GetVersion(); err == nil {
@@ -175,6 +242,9 @@ func (s *TweetService) LoadFromDisk() error {
 		if err != nil {
 			return nil // Skip errors, continue walking
 		}
+		if info.IsDir() && (info.Name() == blobstore.DirName || info.Name() == TrashDirName) {
+			return filepath.SkipDir
+		}
 		if info.IsDir() || info.Name() != "tweet.json" {
 			return nil
 		}
@@ -220,6 +290,9 @@ func (s *TweetService) RecoverOrphanedArchives(ctx context.Context) {
 		if !info.IsDir() {
 			return nil
 		}
+		if info.Name() == blobstore.DirName || info.Name() == TrashDirName {
+			return filepath.SkipDir
+		}
 
 		// Check if this looks like a tweet directory (has temp_processing but no tweet.json)
 		tempPath := filepath.Join(path, "temp_processing")
@@ -302,8 +375,8 @@ func (s *TweetService) ResumeIncompleteArchives(ctx context.Context) {
 		// Determine which phase to resume from
 		go func(t *domain.Tweet) {
 			// Acquire semaphore for processing
-			s.processingSem <- struct{}{}
-			defer func() { <-s.processingSem }()
+			s.processingSem.acquire(t.Options.Priority)
+			defer s.processingSem.release()
 
 			switch t.Status {
 			case domain.ArchiveStatusPending, domain.ArchiveStatusFetching:
@@ -354,10 +427,13 @@ func (s *TweetService) storedTweetToTweet(stored *domain.StoredTweet, archivePat
 		Media:           stored.Media,
 		Metrics:         stored.Metrics,
 		Status:          status,
+		Error:           stored.Error,
+		FailureReason:   domain.FailureReason(stored.FailureReason),
 		ArchivePath:     archivePath,
 		FetchedAt:       stored.FetchedAt,
 		DownloadedAt:    stored.DownloadedAt,
 		AnalyzedAt:      stored.AnalyzedAt,
+		AIPending:       stored.AIPending,
 		MediaDownloaded: stored.MediaDownloaded,
 		MediaTotal:      stored.MediaTotal,
 		AITitle:         stored.AITitle,
@@ -365,8 +441,33 @@ func (s *TweetService) storedTweetToTweet(stored *domain.StoredTweet, archivePat
 		AITags:          stored.AITags,
 		AIContentType:   stored.AIContentType,
 		AITopics:        stored.AITopics,
+		AIRawTags:       stored.AIRawTags,
+		AIRawTopics:     stored.AIRawTopics,
+		AIPromptVersion: stored.AIPromptVersion,
 		CreatedAt:       createdAt,
 		ArchivedAt:      &stored.ArchivedAt,
+		ContentType:     domain.ContentType(stored.ContentType),
+		ArticleTitle:    stored.ArticleTitle,
+		ArticleHTML:     stored.ArticleHTML,
+		ArticleBody:     stored.ArticleBody,
+		ArticleImages:   stored.ArticleImages,
+		WordCount:       stored.WordCount,
+		ReadingMinutes:  stored.ReadingMinutes,
+		Translations:    stored.Translations,
+		Documents:       interruptedDocumentsFailed(stored.Documents),
+		Poll:            stored.Poll,
+		Card:            stored.Card,
+		CommunityNote:   stored.CommunityNote,
+		EditTweetIDs:    stored.EditTweetIDs,
+		Revisions:       stored.Revisions,
+
+		FetchedWithCredentials: stored.FetchedWithCredentials,
+		MediaQuality:           stored.MediaQuality,
+		Tags:                   stored.Tags,
+
+		UpstreamStatus:    domain.UpstreamStatus(stored.UpstreamStatus),
+		UpstreamCheckedAt: stored.UpstreamCheckedAt,
+		UpstreamChangedAt: stored.UpstreamChangedAt,
 	}
 
 	// If MediaTotal wasn't stored, infer from media array
@@ -382,6 +483,9 @@ func (s *TweetService) storedTweetToTweet(stored *domain.StoredTweet, archivePat
 		quoted := domain.TweetID(stored.QuotedTweet)
 		tweet.QuotedTweet = &quoted
 	}
+	if stored.Options != nil {
+		tweet.Options = *stored.Options
+	}
 
 	return tweet
 }
@@ -392,8 +496,9 @@ func (s *TweetService) BackfillAIMetadata(ctx context.Context) {
 	var needsBackfill []*domain.Tweet
 
 	for _, tweet := range s.tweets {
-		// Check if tweet is missing AI metadata
-		if len(tweet.AITags) == 0 && tweet.AISummary == "" {
+		// Check if tweet is missing AI metadata (and didn't opt out of it).
+		// Tweets paused by the AI budget are resumed by RunPausedAnalysis.
+		if len(tweet.AITags) == 0 && tweet.AISummary == "" && !tweet.Options.SkipsAI() && !tweet.AIPending {
 			needsBackfill = append(needsBackfill, tweet)
 		}
 	}
@@ -413,13 +518,19 @@ func (s *TweetService) BackfillAIMetadata(ctx context.Context) {
 		default:
 		}
 
+		if err := s.aiBudgetExceeded(); err != nil {
+			s.logger.Warn("backfill paused", "processed", i, "error", err)
+			return
+		}
+
 		s.logger.Info("backfilling AI metadata", "tweet_id", tweet.ID, "progress", fmt.Sprintf("%d/%d", i+1, len(needsBackfill)))
+		tweetCtx := withUsageTweet(ctx, tweet.ID)
 
 		// Per-media analysis first (so each media item gets its own caption/tags)
-		s.runPerMediaAnalysis(ctx, tweet)
+		s.runPerMediaAnalysis(tweetCtx, tweet)
 
 		// Tweet-level vision analysis (will fall back to text if no media)
-		s.runVisionAnalysis(ctx, tweet)
+		s.runVisionAnalysis(tweetCtx, tweet)
 
 		// Save updated metadata to disk
 		if err := s.saveTweetMetadata(tweet); err != nil {
@@ -443,26 +554,34 @@ type ArchiveRequest struct {
 	AuthorAvatarURL   string
 	AuthorDisplayName string
 	AuthorUsername    string
+	// Quality overrides the default media quality policy for this tweet
+	Quality *domain.MediaQuality
+	// Options skip pipeline stages, add the tweet to playlists and set its
+	// queue priority. Tags are the user's own labels for the tweet.
+	Options domain.ArchiveOptions
+	Tags    []string
 }
 
 // ArchiveResponse is returned after submitting an archive request.
 type ArchiveResponse struct {
-	TweetID domain.TweetID
-	Status  domain.ArchiveStatus
-	Message string
+	TweetID       domain.TweetID
+	Status        domain.ArchiveStatus
+	Message       string
+	FailureReason domain.FailureReason // Set when Status is failed
 }
 
 // TweetStatusResponse contains the current status of a tweet archive.
 type TweetStatusResponse struct {
-	TweetID     domain.TweetID
-	Status      domain.ArchiveStatus
-	Author      string
-	Text        string
-	MediaCount  int
-	AITitle     string
-	ArchivePath string
-	Error       string
-	CreatedAt   time.Time
+	TweetID       domain.TweetID
+	Status        domain.ArchiveStatus
+	Author        string
+	Text          string
+	MediaCount    int
+	AITitle       string
+	ArchivePath   string
+	Error         string
+	FailureReason domain.FailureReason
+	CreatedAt     time.Time
 }
 
 // Archive submits a tweet URL for archiving.
@@ -474,6 +593,9 @@ func (s *TweetService) Archive(ctx context.Context, req ArchiveRequest) (*Archiv
 	if tweetID == "" {
 		return nil, domain.ErrInvalidTweetURL
 	}
+	if err := s.validatePlaylists(ctx, req.Options.PlaylistIDs); err != nil {
+		return nil, err
+	}
 
 	// Check if already archived or in progress - no-op for duplicates
 	s.tweetsMu.Lock()
@@ -499,13 +621,15 @@ func (s *TweetService) Archive(ctx context.Context, req ArchiveRequest) (*Archiv
 			}, nil
 		case domain.ArchiveStatusFailed:
 			// Check if the failure is permanent (account suspended, deleted, etc.)
-			if isPermanentTweetFailure(existing.Error) {
+			if existing.FailureReason.Permanent() ||
+				(existing.FailureReason == "" && isPermanentTweetFailure(existing.Error)) {
 				s.tweetsMu.Unlock()
-				s.logger.Info("not re-queueing permanently failed tweet", "tweet_id", tweetID, "error", existing.Error)
+				s.logger.Info("not re-queueing permanently failed tweet", "tweet_id", tweetID, "error", existing.Error, "reason", existing.FailureReason)
 				return &ArchiveResponse{
-					TweetID: existing.ID,
-					Status:  existing.Status,
-					Message: "Tweet permanently unavailable: " + existing.Error,
+					TweetID:       existing.ID,
+					Status:        existing.Status,
+					Message:       "Tweet permanently unavailable: " + existing.Error,
+					FailureReason: existing.FailureReason,
 				}, nil
 			}
 			// Transient failures can be re-queued - delete the old one and try again
@@ -521,6 +645,12 @@ func (s *TweetService) Archive(ctx context.Context, req ArchiveRequest) (*Archiv
 		Status:    domain.ArchiveStatusPending,
 		CreatedAt: time.Now(),
 	}
+	if req.Quality != nil {
+		quality := *req.Quality
+		tweet.MediaQuality = &quality
+	}
+	tweet.Options = req.Options
+	tweet.Tags = domain.NormalizeTags(req.Tags)
 
 	// Store extension-provided author hints for fallback
 	if req.AuthorAvatarURL != "" || req.AuthorDisplayName != "" || req.AuthorUsername != "" {
@@ -545,8 +675,8 @@ func (s *TweetService) Archive(ctx context.Context, req ArchiveRequest) (*Archiv
 
 	// Process asynchronously with concurrency limit
 	go func() {
-		s.processingSem <- struct{}{}        // Acquire semaphore
-		defer func() { <-s.processingSem }() // Release semaphore
+		s.processingSem.acquire(tweet.Options.Priority) // Acquire semaphore
+		defer s.processingSem.release()                 // Release semaphore
 		s.processTweet(context.Background(), tweet)
 	}()
 
@@ -563,12 +693,14 @@ func (s *TweetService) Archive(ctx context.Context, req ArchiveRequest) (*Archiv
 // Phase 3: AI analysis (async, runs in background)
 func (s *TweetService) processTweet(ctx context.Context, tweet *domain.Tweet) {
 	logger := s.logger.With("tweet_id", tweet.ID)
+	ctx = withUsageTweet(ctx, tweet.ID)
 
 	// Phase 1: Quick fetch - get metadata, generate AI title, save first checkpoint
 	if err := s.processPhase1Fetch(ctx, tweet); err != nil {
-		logger.Error("phase 1 failed", "error", err)
 		tweet.Status = domain.ArchiveStatusFailed
 		tweet.Error = err.Error()
+		tweet.FailureReason = domain.FailureReasonFor(err)
+		logger.Error("phase 1 failed", "error", err, "reason", tweet.FailureReason)
 		if saveErr := s.saveTweetMetadata(tweet); saveErr != nil {
 			logger.Warn("failed to save failure state", "error", saveErr)
 		}
@@ -576,7 +708,7 @@ func (s *TweetService) processTweet(ctx context.Context, tweet *domain.Tweet) {
 		// Emit error event
 		s.emitEvent(domain.EventSeverityError, domain.EventCategoryTweet,
 			fmt.Sprintf("Tweet archive failed: %s", err.Error()),
-			domain.EventMetadata{"tweet_id": string(tweet.ID), "phase": "fetch", "error": err.Error()})
+			domain.EventMetadata{"tweet_id": string(tweet.ID), "phase": "fetch", "error": err.Error(), "failure_reason": string(tweet.FailureReason)})
 		return
 	}
 
@@ -598,7 +730,7 @@ func (s *TweetService) processTweet(ctx context.Context, tweet *domain.Tweet) {
 	// Phase 3: AI analysis runs asynchronously
 	// Use separate goroutine so we don't block the processing semaphore
 	go func() {
-		s.processPhase3Analyze(context.Background(), tweet)
+		s.processPhase3Analyze(withUsageTweet(context.Background(), tweet.ID), tweet)
 	}()
 }
 
@@ -637,6 +769,14 @@ func (s *TweetService) processPhase1Fetch(ctx context.Context, tweet *domain.Twe
 	tweet.ReplyTo = fetchedTweet.ReplyTo
 	tweet.QuotedTweet = fetchedTweet.QuotedTweet
 	tweet.MediaTotal = len(fetchedTweet.Media)
+	if tweet.Options.TextOnly {
+		tweet.MediaTotal = 0
+	}
+	tweet.EditTweetIDs = fetchedTweet.EditTweetIDs
+	tweet.FetchedWithCredentials = fetchedTweet.FetchedWithCredentials
+	tweet.Poll = fetchedTweet.Poll
+	tweet.Card = fetchedTweet.Card
+	tweet.CommunityNote = fetchedTweet.CommunityNote
 
 	// Merge article fields if this is an article
 	if fetchedTweet.ContentType == domain.ContentTypeArticle {
@@ -687,7 +827,13 @@ func (s *TweetService) processPhase1Fetch(ctx context.Context, tweet *domain.Twe
 func (s *TweetService) processPhase2Download(ctx context.Context, tweet *domain.Tweet) error {
 	logger := s.logger.With("tweet_id", tweet.ID)
 
-	if len(tweet.Media) == 0 && tweet.Author.AvatarURL == "" {
+	// Text-only archives keep media metadata but not the files
+	media := tweet.Media
+	if tweet.Options.TextOnly {
+		media = nil
+	}
+
+	if len(media) == 0 && tweet.Author.AvatarURL == "" {
 		// Nothing to download, skip to phase 3
 		now := time.Now()
 		tweet.DownloadedAt = &now
@@ -699,7 +845,7 @@ func (s *TweetService) processPhase2Download(ctx context.Context, tweet *domain.
 		return nil
 	}
 
-	logger.Info("phase 2: downloading media", "count", len(tweet.Media))
+	logger.Info("phase 2: downloading media", "count", len(media))
 	tweet.Status = domain.ArchiveStatusDownloading
 	if err := s.saveTweetMetadata(tweet); err != nil {
 		logger.Warn("failed to save metadata", "error", err)
@@ -708,17 +854,17 @@ func (s *TweetService) processPhase2Download(ctx context.Context, tweet *domain.
 	var downloadErrors []string
 
 	// Download each media item with incremental saves
-	for i := range tweet.Media {
-		media := &tweet.Media[i]
-		if err := s.downloadMediaWithoutAnalysis(ctx, media, tweet.ArchivePath); err != nil {
-			logger.Warn("failed to download media", "media_id", media.ID, "error", err)
-			downloadErrors = append(downloadErrors, fmt.Sprintf("%s: %v", media.ID, err))
+	for i := range media {
+		m := &media[i]
+		if err := s.downloadMediaWithoutAnalysis(ctx, m, tweet.ArchivePath, s.mediaQualityFor(tweet)); err != nil {
+			logger.Warn("failed to download media", "media_id", m.ID, "error", err)
+			downloadErrors = append(downloadErrors, fmt.Sprintf("%s: %v", m.ID, err))
 			continue
 		}
 
 		tweet.MediaDownloaded++
 		logger.Info("media downloaded",
-			"media_id", media.ID,
+			"media_id", m.ID,
 			"progress", fmt.Sprintf("%d/%d", tweet.MediaDownloaded, tweet.MediaTotal),
 		)
 
@@ -728,6 +874,10 @@ func (s *TweetService) processPhase2Download(ctx context.Context, tweet *domain.
 		}
 	}
 
+	// Content and perceptual hashes for duplicate detection
+	s.computeMediaHashes(ctx, tweet, true)
+	s.ingestMediaBlobs(tweet)
+
 	// Download author avatar
 	if tweet.Author.AvatarURL != "" {
 		avatarPath := filepath.Join(tweet.ArchivePath, "avatar.jpg")
@@ -738,6 +888,10 @@ func (s *TweetService) processPhase2Download(ctx context.Context, tweet *domain.
 		}
 	}
 
+	if err := s.downloadCardImage(ctx, tweet); err != nil {
+		logger.Warn("failed to download link card image", "error", err)
+	}
+
 	// Mark phase 2 complete
 	now := time.Now()
 	tweet.DownloadedAt = &now
@@ -776,21 +930,18 @@ func (s *TweetService) processPhase3Analyze(ctx context.Context, tweet *domain.T
 		logger.Warn("failed to save metadata", "error", err)
 	}
 
-	// Run Whisper transcription for videos (if enabled)
-	if s.whisperEnabled && tweet.HasVideo() {
-		for i := range tweet.Media {
-			media := &tweet.Media[i]
-			if (media.Type == domain.MediaTypeVideo || media.Type == domain.MediaTypeGIF) && media.LocalPath != "" {
-				s.processVideoForTranscription(ctx, media, tweet.ArchivePath)
-			}
-		}
+	// Extract text from images and video keyframes
+	if !tweet.Options.SkipsOCR() {
+		s.runOCR(ctx, tweet, false)
 	}
 
-	// Run per-media analysis (each media gets caption/tags)
-	s.runPerMediaAnalysis(ctx, tweet)
-
-	// Run tweet-level vision analysis
-	s.runVisionAnalysis(ctx, tweet)
+	// Paid AI phases wait for the budget to reset; RunPausedAnalysis resumes them
+	if err := s.aiBudgetExceeded(); err != nil {
+		logger.Warn("AI phases paused until the budget resets", "error", err)
+		tweet.AIPending = true
+	} else {
+		s.runAIPhases(ctx, tweet)
+	}
 
 	// Mark complete
 	now := time.Now()
@@ -807,6 +958,17 @@ func (s *TweetService) processPhase3Analyze(ctx context.Context, tweet *domain.T
 		return
 	}
 
+	// Record checksums for integrity scrubbing, then mirror to remote storage
+	s.writeChecksums(tweet)
+	if err := s.syncArchiveToStorage(ctx, tweet); err != nil {
+		logger.Warn("failed to mirror archive to storage", "error", err)
+		s.emitEvent(domain.EventSeverityWarning, domain.EventCategoryDisk,
+			fmt.Sprintf("Failed to upload archive to storage: %s", err.Error()),
+			domain.EventMetadata{"tweet_id": string(tweet.ID), "error": err.Error()})
+	}
+
+	s.addToRequestedPlaylists(ctx, tweet)
+
 	// Emit success event for completed archive
 	s.emitEvent(domain.EventSeveritySuccess, domain.EventCategoryTweet,
 		fmt.Sprintf("Tweet archived: @%s - %s", tweet.Author.Username, tweet.AITitle),
@@ -824,47 +986,162 @@ func (s *TweetService) processPhase3Analyze(ctx context.Context, tweet *domain.T
 	)
 }
 
+// runAIPhases runs the paid phases of analysis: Whisper transcription, then
+// per-media and tweet-level analysis, translations and chapters.
+func (s *TweetService) runAIPhases(ctx context.Context, tweet *domain.Tweet) {
+	// Run Whisper transcription for videos (if enabled)
+	if s.whisperEnabled && tweet.HasVideo() && !tweet.Options.SkipsTranscription() {
+		for i := range tweet.Media {
+			media := &tweet.Media[i]
+			if (media.Type == domain.MediaTypeVideo || media.Type == domain.MediaTypeGIF) && media.LocalPath != "" {
+				s.processVideoForTranscription(ctx, media, tweet.ArchivePath)
+			}
+		}
+	}
+
+	if tweet.Options.SkipsAI() {
+		s.logger.Info("AI analysis skipped by archive options", "tweet_id", tweet.ID)
+		return
+	}
+
+	// Run per-media analysis (each media gets caption/tags)
+	s.runPerMediaAnalysis(ctx, tweet)
+
+	// Run tweet-level vision analysis
+	s.runVisionAnalysis(ctx, tweet)
+
+	// Translate text and transcripts into the configured languages
+	s.runTranslations(ctx, tweet)
+
+	// Split long transcribed videos into chapters
+	s.runChapters(ctx, tweet)
+}
+
+// pausedAnalysisInterval is how often archives paused by the AI budget are
+// retried.
+const pausedAnalysisInterval = 15 * time.Minute
+
+// RunPausedAnalysis runs the AI phases of archives that completed while an
+// AI budget was exceeded, at startup and then periodically until ctx is
+// cancelled. Each pass stops as soon as the budget is exceeded again.
+func (s *TweetService) RunPausedAnalysis(ctx context.Context) {
+	if s.usage == nil {
+		return
+	}
+
+	ticker := time.NewTicker(pausedAnalysisInterval)
+	defer ticker.Stop()
+	for {
+		if resumed := s.ResumePausedAnalysis(ctx); resumed > 0 {
+			s.logger.Info("resumed paused AI analysis", "count", resumed)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// ResumePausedAnalysis runs the AI phases of archives marked AIPending while
+// the budget allows it and returns how many finished.
+func (s *TweetService) ResumePausedAnalysis(ctx context.Context) int {
+	var pending []*domain.Tweet
+	s.tweetsMu.RLock()
+	for _, tweet := range s.tweets {
+		if tweet.AIPending && tweet.Status == domain.ArchiveStatusCompleted {
+			pending = append(pending, tweet)
+		}
+	}
+	s.tweetsMu.RUnlock()
+	sort.Slice(pending, func(i, j int) bool { return pending[i].CreatedAt.Before(pending[j].CreatedAt) })
+
+	resumed := 0
+	for _, tweet := range pending {
+		if ctx.Err() != nil {
+			return resumed
+		}
+		if err := s.aiBudgetExceeded(); err != nil {
+			s.logger.Info("paused AI analysis waits for the budget to reset", "remaining", len(pending)-resumed, "error", err)
+			return resumed
+		}
+
+		s.aiAnalysisLock.Lock()
+		busy := s.processingAI[tweet.ID]
+		s.processingAI[tweet.ID] = true
+		s.aiAnalysisLock.Unlock()
+		if busy {
+			continue
+		}
+
+		tweetCtx := withUsageTweet(ctx, tweet.ID)
+		// The archive may have been mirrored and its media evicted meanwhile
+		s.withLocalMedia(tweetCtx, tweet, func() { s.runAIPhases(tweetCtx, tweet) })
+		if !tweet.Options.SkipsAI() {
+			// The title fell back to the author and text while paused
+			if title, err := s.grokClient.GenerateFilename(tweetCtx, s.filenameRequest(tweet)); err == nil {
+				tweet.AITitle = title
+			}
+		}
+		tweet.AIPending = false
+		now := time.Now()
+		tweet.AnalyzedAt = &now
+
+		s.aiAnalysisLock.Lock()
+		delete(s.processingAI, tweet.ID)
+		s.aiAnalysisLock.Unlock()
+
+		if err := s.saveTweetMetadata(tweet); err != nil {
+			s.logger.Warn("failed to save resumed analysis", "tweet_id", tweet.ID, "error", err)
+			continue
+		}
+		s.writeChecksums(tweet)
+		if err := s.syncArchiveToStorage(ctx, tweet); err != nil {
+			s.logger.Warn("failed to mirror archive to storage", "tweet_id", tweet.ID, "error", err)
+		}
+		resumed++
+	}
+	return resumed
+}
+
 // downloadMediaWithoutAnalysis downloads a single media file without running per-media analysis.
 // Analysis is deferred to Phase 3 to avoid blocking the download pipeline.
-func (s *TweetService) downloadMediaWithoutAnalysis(ctx context.Context, media *domain.Media, archivePath string) error {
-	// Determine filename
+func (s *TweetService) downloadMediaWithoutAnalysis(ctx context.Context, media *domain.Media, archivePath string, quality domain.MediaQuality) error {
+	// Determine filename and the rendition to fetch
 	var filename string
+	downloadURL := media.URL
 	switch media.Type {
 	case domain.MediaTypeImage:
-		ext := ".jpg"
-		if strings.Contains(media.URL, ".png") {
-			ext = ".png"
-		} else if strings.Contains(media.URL, ".webp") {
-			ext = ".webp"
-		}
-		filename = fmt.Sprintf("%s%s", media.ID, ext)
+		filename = fmt.Sprintf("%s%s", media.ID, imageExtension(media.URL))
+		downloadURL = quality.ImageURL(media.URL)
 	case domain.MediaTypeVideo, domain.MediaTypeGIF:
 		filename = fmt.Sprintf("%s.mp4", media.ID)
+		selectVideoVariant(media, quality)
+		downloadURL = media.URL
 	}
 
 	localPath := filepath.Join(archivePath, "media", filename)
 
 	// Download main media file
-	content, _, err := s.downloader.Download(ctx, media.URL)
-	if err != nil {
-		return fmt.Errorf("download failed: %w", err)
+	err := s.downloadToFile(ctx, downloadURL, localPath)
+	if err != nil && downloadURL != media.URL {
+		// Not every image has every size; fall back to the URL X returned
+		s.logger.Warn("sized image download failed, using original URL", "media_id", media.ID, "error", err)
+		err = s.downloadToFile(ctx, media.URL, localPath)
 	}
-	defer content.Close()
-
-	// Save to file
-	f, err := os.Create(localPath)
 	if err != nil {
-		return fmt.Errorf("create file: %w", err)
-	}
-	defer f.Close()
-
-	if _, err := io.Copy(f, content); err != nil {
-		return fmt.Errorf("write file: %w", err)
+		return err
 	}
+	media.SHA256, media.PHash, media.DHash, media.KeyframeHashes = "", "", "", nil
 
 	media.LocalPath = localPath
 	media.Downloaded = true
 
+	if quality.KeepAllVariants && len(media.Variants) > 1 {
+		s.downloadVariants(ctx, media, archivePath)
+	}
+
 	// For videos, also download the thumbnail/preview image
 	if (media.Type == domain.MediaTypeVideo || media.Type == domain.MediaTypeGIF) && media.PreviewURL != "" {
 		thumbPath := filepath.Join(archivePath, "media", fmt.Sprintf("%s_thumb.jpg", media.ID))
@@ -895,6 +1172,12 @@ func (s *TweetService) runPerMediaAnalysis(ctx context.Context, tweet *domain.Tw
 	}
 }
 
+// needsAltText reports whether the vision pass should describe media for
+// screen readers: images and GIFs the author gave no alt text.
+func needsAltText(media *domain.Media) bool {
+	return media.AltText == "" && (media.Type == domain.MediaTypeImage || media.Type == domain.MediaTypeGIF)
+}
+
 func (s *TweetService) analyzeMedia(ctx context.Context, tweet *domain.Tweet, media *domain.Media) {
 	if tweet == nil || media == nil || media.LocalPath == "" {
 		return
@@ -968,6 +1251,7 @@ func (s *TweetService) analyzeMedia(ctx context.Context, tweet *domain.Tweet, me
 		VideoThumbPath: videoThumb,
 		HasVideo:       media.Type == domain.MediaTypeVideo || media.Type == domain.MediaTypeGIF,
 		VideoDuration:  media.Duration,
+		WantAltText:    needsAltText(media),
 	}
 
 	analysis, err := s.grokClient.AnalyzeContentWithVision(ctx, req)
@@ -977,9 +1261,12 @@ func (s *TweetService) analyzeMedia(ctx context.Context, tweet *domain.Tweet, me
 	}
 
 	media.AICaption = analysis.Summary
-	media.AITags = analysis.Tags
+	media.AIRawTags, media.AITags = analysis.Tags, s.curateTags(analysis.Tags)
 	media.AIContentType = analysis.ContentType
-	media.AITopics = analysis.Topics
+	media.AIRawTopics, media.AITopics = analysis.Topics, s.curateTags(analysis.Topics)
+	if req.WantAltText {
+		media.AIAltText = strings.TrimSpace(analysis.AltText)
+	}
 
 	logger.Info("per-media analysis complete",
 		"tags_count", len(analysis.Tags),
@@ -989,17 +1276,11 @@ func (s *TweetService) analyzeMedia(ctx context.Context, tweet *domain.Tweet, me
 }
 
 func (s *TweetService) generateAIMetadata(ctx context.Context, tweet *domain.Tweet) (string, string) {
-	// Build prompt for Grok
-	prompt := buildTweetPrompt(tweet)
-
-	title, err := s.grokClient.GenerateFilename(ctx, grok.FilenameRequest{
-		TweetText:      prompt,
-		AuthorUsername: tweet.Author.Username,
-		AuthorName:     tweet.Author.DisplayName,
-		PostedAt:       tweet.PostedAt.Format("2006-01-02"),
-		Duration:       getTotalVideoDuration(tweet),
-	})
+	if tweet.Options.SkipsAI() || s.aiBudgetExceeded() != nil {
+		return grok.FallbackFilename(tweet.Author.Username, tweet.PostedAt, tweet.Text), ""
+	}
 
+	title, err := s.grokClient.GenerateFilename(ctx, s.filenameRequest(tweet))
 	if err != nil {
 		s.logger.Warn("AI title generation failed, using fallback", "error", err)
 		// Fallback: use author + first few words
@@ -1097,6 +1378,45 @@ func (s *TweetService) runVisionAnalysis(ctx context.Context, tweet *domain.Twee
 	// This ensures multi-frame context is available for vision analysis.
 	s.ensureVideoKeyframes(ctx, tweet)
 
+	req, keyframes, images, ok := visionRequest(tweet)
+	if !ok {
+		// No media available, use text-only analysis
+		s.runTextAnalysis(ctx, tweet)
+		return
+	}
+
+	s.logger.Info("running vision analysis",
+		"tweet_id", tweet.ID,
+		"keyframes", keyframes,
+		"images", images,
+		"vision_images_used", len(req.ImagePaths),
+		"using_thumbnail", req.VideoThumbPath != "",
+	)
+
+	analysis, err := s.grokClient.AnalyzeContentWithVision(ctx, req)
+	if err != nil {
+		s.logger.Warn("vision analysis failed, falling back to text analysis", "error", err)
+		// Fall back to text-only analysis
+		s.runTextAnalysis(ctx, tweet)
+		return
+	}
+
+	tweet.AISummary = analysis.Summary
+	tweet.AIRawTags, tweet.AITags = analysis.Tags, s.curateTags(analysis.Tags)
+	tweet.AIContentType = analysis.ContentType
+	tweet.AIRawTopics, tweet.AITopics = analysis.Topics, s.curateTags(analysis.Topics)
+	tweet.AIPromptVersion = s.promptSet().Version()
+	s.logger.Info("vision analysis complete",
+		"tags_count", len(analysis.Tags),
+		"content_type", analysis.ContentType,
+	)
+}
+
+// visionRequest builds the vision analysis request for a tweet from its local
+// images, video thumbnail and extracted keyframes, along with how many
+// keyframes and images were found. ok is false when the tweet has no local
+// media to analyze.
+func visionRequest(tweet *domain.Tweet) (req grok.VisionAnalysisRequest, keyframes, images int, ok bool) {
 	// Collect local image paths, video thumbnails, and extracted keyframes
 	var imagePaths []string // images + (optionally) keyframes
 	var keyframePaths []string
@@ -1131,120 +1451,97 @@ func (s *TweetService) runVisionAnalysis(ctx context.Context, tweet *domain.Twee
 		}
 	}
 
-	// If we have images or video thumbnail, use vision analysis
-	if len(imagePaths) > 0 || len(keyframePaths) > 0 || videoThumbPath != "" {
-		// Build transcript context (if available) so the summary reflects the whole video, not just frames.
-		// Keep it bounded to avoid blowing up token limits.
-		var transcriptSnippet string
-		for _, m := range tweet.Media {
-			if m.Transcript == "" {
-				continue
-			}
-			// Prefer full transcript if it's short; otherwise take a head+tail excerpt.
-			const max = 3000
-			t := strings.TrimSpace(m.Transcript)
-			if len(t) <= max {
-				transcriptSnippet = t
-			} else {
-				head := t[:2000]
-				tail := t[len(t)-800:]
-				transcriptSnippet = head + "\n...\n" + tail
-			}
-			break
-		}
-
-		tweetTextForVision := tweet.Text
-		if transcriptSnippet != "" {
-			tweetTextForVision = tweetTextForVision + "\n\n[Video transcript excerpt]\n" + transcriptSnippet
-		}
+	if len(imagePaths) == 0 && len(keyframePaths) == 0 && videoThumbPath == "" {
+		return grok.VisionAnalysisRequest{}, 0, 0, false
+	}
 
-		// Prefer multiple keyframes over a single thumbnail: pass keyframes as ImagePaths
-		// and only include VideoThumbPath if we have no keyframes.
-		maxImages := 4
-		visionPaths := make([]string, 0, maxImages)
-		for _, p := range keyframePaths {
-			if len(visionPaths) >= maxImages {
-				break
-			}
-			visionPaths = append(visionPaths, p)
-		}
-		for _, p := range imagePaths {
-			if len(visionPaths) >= maxImages {
-				break
-			}
-			visionPaths = append(visionPaths, p)
+	// Build transcript context (if available) so the summary reflects the whole video, not just frames.
+	// Keep it bounded to avoid blowing up token limits.
+	var transcriptSnippet string
+	for _, m := range tweet.Media {
+		if m.Transcript == "" {
+			continue
 		}
-
-		thumb := videoThumbPath
-		if len(keyframePaths) > 0 {
-			thumb = "" // don't let thumbnail steal a slot when we have real multi-frame context
+		// Prefer full transcript if it's short; otherwise take a head+tail excerpt.
+		const max = 3000
+		t := strings.TrimSpace(m.Transcript)
+		if len(t) <= max {
+			transcriptSnippet = t
+		} else {
+			head := t[:2000]
+			tail := t[len(t)-800:]
+			transcriptSnippet = head + "\n...\n" + tail
 		}
+		break
+	}
 
-		s.logger.Info("running vision analysis",
-			"tweet_id", tweet.ID,
-			"keyframes", len(keyframePaths),
-			"images", len(imagePaths),
-			"vision_images_used", len(visionPaths),
-			"using_thumbnail", thumb != "",
-		)
-
-		analysis, err := s.grokClient.AnalyzeContentWithVision(ctx, grok.VisionAnalysisRequest{
-			TweetText:      tweetTextForVision,
-			AuthorUsername: tweet.Author.Username,
-			ImagePaths:     visionPaths,
-			VideoThumbPath: thumb,
-			HasVideo:       tweet.HasVideo(),
-			VideoDuration:  getTotalVideoDuration(tweet),
-		})
+	tweetTextForVision := tweet.Text
+	if transcriptSnippet != "" {
+		tweetTextForVision = tweetTextForVision + "\n\n[Video transcript excerpt]\n" + transcriptSnippet
+	}
 
-		if err != nil {
-			s.logger.Warn("vision analysis failed, falling back to text analysis", "error", err)
-			// Fall back to text-only analysis
-			s.runTextAnalysis(ctx, tweet)
-			return
+	// Prefer multiple keyframes over a single thumbnail: pass keyframes as ImagePaths
+	// and only include VideoThumbPath if we have no keyframes.
+	maxImages := 4
+	visionPaths := make([]string, 0, maxImages)
+	for _, p := range keyframePaths {
+		if len(visionPaths) >= maxImages {
+			break
 		}
-
-		tweet.AISummary = analysis.Summary
-		tweet.AITags = analysis.Tags
-		tweet.AIContentType = analysis.ContentType
-		tweet.AITopics = analysis.Topics
-		s.logger.Info("vision analysis complete",
-			"tags_count", len(analysis.Tags),
-			"content_type", analysis.ContentType,
-		)
-		return
+		visionPaths = append(visionPaths, p)
+	}
+	for _, p := range imagePaths {
+		if len(visionPaths) >= maxImages {
+			break
+		}
+		visionPaths = append(visionPaths, p)
 	}
 
-	// No media available, use text-only analysis
-	s.runTextAnalysis(ctx, tweet)
-}
+	thumb := videoThumbPath
+	if len(keyframePaths) > 0 {
+		thumb = "" // don't let thumbnail steal a slot when we have real multi-frame context
+	}
 
-// runTextAnalysis performs text-only AI analysis (no vision).
-func (s *TweetService) runTextAnalysis(ctx context.Context, tweet *domain.Tweet) {
-	analysis, err := s.grokClient.AnalyzeContent(ctx, grok.ContentAnalysisRequest{
-		TweetText:      tweet.Text,
+	return grok.VisionAnalysisRequest{
+		TweetText:      tweetTextForVision,
 		AuthorUsername: tweet.Author.Username,
+		ImagePaths:     visionPaths,
+		VideoThumbPath: thumb,
 		HasVideo:       tweet.HasVideo(),
-		HasImages:      tweet.HasImages(),
-		ImageCount:     countImages(tweet),
 		VideoDuration:  getTotalVideoDuration(tweet),
-	})
+	}, len(keyframePaths), len(imagePaths), true
+}
 
+// runTextAnalysis performs text-only AI analysis (no vision).
+func (s *TweetService) runTextAnalysis(ctx context.Context, tweet *domain.Tweet) {
+	analysis, err := s.grokClient.AnalyzeContent(ctx, textAnalysisRequest(tweet))
 	if err != nil {
 		s.logger.Warn("AI content analysis failed", "error", err)
 		return
 	}
 
 	tweet.AISummary = analysis.Summary
-	tweet.AITags = analysis.Tags
+	tweet.AIRawTags, tweet.AITags = analysis.Tags, s.curateTags(analysis.Tags)
 	tweet.AIContentType = analysis.ContentType
-	tweet.AITopics = analysis.Topics
+	tweet.AIRawTopics, tweet.AITopics = analysis.Topics, s.curateTags(analysis.Topics)
+	tweet.AIPromptVersion = s.promptSet().Version()
 	s.logger.Info("text analysis complete",
 		"tags_count", len(analysis.Tags),
 		"content_type", analysis.ContentType,
 	)
 }
 
+func textAnalysisRequest(tweet *domain.Tweet) grok.ContentAnalysisRequest {
+	return grok.ContentAnalysisRequest{
+		TweetText:      tweet.Text,
+		AuthorUsername: tweet.Author.Username,
+		HasVideo:       tweet.HasVideo(),
+		HasImages:      tweet.HasImages(),
+		ImageCount:     countImages(tweet),
+		VideoDuration:  getTotalVideoDuration(tweet),
+	}
+}
+
 // RegenerateAIMetadata re-runs AI analysis on a tweet and updates its metadata.
 // This is useful when the AI algorithm is improved or to get better results.
 // Returns an error if analysis is already in progress for this tweet.
@@ -1275,24 +1572,20 @@ func (s *TweetService) regenerateAIMetadata(ctx context.Context, tweetID domain.
 	if !ok {
 		return domain.ErrVideoNotFound
 	}
+	if err := s.aiBudgetExceeded(); err != nil {
+		return err
+	}
+	ctx = withUsageTweet(ctx, tweetID)
 
 	s.logger.Info("regenerating AI metadata", "tweet_id", tweetID)
 
-	// Clear existing AI metadata
-	tweet.AISummary = ""
-	tweet.AITags = nil
-	tweet.AIContentType = ""
-	tweet.AITopics = nil
-
-	// Clear per-media AI metadata so we can re-run the new paradigm
-	for i := range tweet.Media {
-		tweet.Media[i].AICaption = ""
-		tweet.Media[i].AITags = nil
-		tweet.Media[i].AIContentType = ""
-		tweet.Media[i].AITopics = nil
+	// Media may only exist in remote storage
+	if err := s.EnsureLocalMedia(ctx, tweet); err != nil {
+		s.logger.Warn("failed to restore media from storage", "tweet_id", tweetID, "error", err)
 	}
 
 	// Re-run transcription for videos if Whisper is enabled
+	var rewritten []string
 	if s.whisperEnabled && tweet.HasVideo() {
 		s.logger.Info("re-running video transcription", "tweet_id", tweetID)
 		for i := range tweet.Media {
@@ -1301,12 +1594,45 @@ func (s *TweetService) regenerateAIMetadata(ctx context.Context, tweetID domain.
 				// Clear existing transcript
 				media.Transcript = ""
 				media.TranscriptLanguage = ""
+				media.TranscriptSegments = nil
+				removeSubtitleFiles(tweet.ArchivePath, media)
+				rewritten = append(rewritten, transcriptFiles(media.ID)...)
+				rewritten = append(rewritten, s.dropTranscriptTranslations(tweet, media.ID)...)
 				// Re-run transcription
 				s.processVideoForTranscription(ctx, media, tweet.ArchivePath)
 			}
 		}
 	}
 
+	// Re-run OCR (engine may have changed since the original archive)
+	s.runOCR(ctx, tweet, true)
+
+	return s.reanalyzeTweet(ctx, tweet, rewritten...)
+}
+
+// reanalyzeTweet replaces a tweet's AI analysis and title using its current
+// transcripts and OCR text, then saves the result. rewritten lists archive
+// files the caller replaced, for the checksum manifest.
+func (s *TweetService) reanalyzeTweet(ctx context.Context, tweet *domain.Tweet, rewritten ...string) error {
+	// Clear existing AI metadata
+	tweet.AISummary = ""
+	tweet.AITags = nil
+	tweet.AIContentType = ""
+	tweet.AITopics = nil
+	tweet.AIRawTags = nil
+	tweet.AIRawTopics = nil
+
+	// Clear per-media AI metadata so we can re-run the new paradigm
+	for i := range tweet.Media {
+		tweet.Media[i].AICaption = ""
+		tweet.Media[i].AITags = nil
+		tweet.Media[i].AIContentType = ""
+		tweet.Media[i].AITopics = nil
+		tweet.Media[i].AIRawTags = nil
+		tweet.Media[i].AIRawTopics = nil
+		tweet.Media[i].AIAltText = ""
+	}
+
 	// Re-run per-media analysis (uses transcript/keyframes when available)
 	s.runPerMediaAnalysis(ctx, tweet)
 
@@ -1314,14 +1640,7 @@ func (s *TweetService) regenerateAIMetadata(ctx context.Context, tweetID domain.
 	s.runVisionAnalysis(ctx, tweet)
 
 	// Also regenerate the title
-	prompt := buildTweetPrompt(tweet)
-	title, err := s.grokClient.GenerateFilename(ctx, grok.FilenameRequest{
-		TweetText:      prompt,
-		AuthorUsername: tweet.Author.Username,
-		AuthorName:     tweet.Author.DisplayName,
-		PostedAt:       tweet.PostedAt.Format("2006-01-02"),
-		Duration:       getTotalVideoDuration(tweet),
-	})
+	title, err := s.grokClient.GenerateFilename(ctx, s.filenameRequest(tweet))
 	if err == nil {
 		tweet.AITitle = title
 	}
@@ -1330,9 +1649,13 @@ func (s *TweetService) regenerateAIMetadata(ctx context.Context, tweetID domain.
 	if err := s.saveTweetMetadata(tweet); err != nil {
 		return fmt.Errorf("save metadata: %w", err)
 	}
+	s.writeChecksums(tweet, rewritten...)
+	if err := s.syncArchiveToStorage(ctx, tweet); err != nil {
+		s.logger.Warn("failed to mirror archive to storage", "tweet_id", tweet.ID, "error", err)
+	}
 
 	s.logger.Info("AI metadata regenerated",
-		"tweet_id", tweetID,
+		"tweet_id", tweet.ID,
 		"tags_count", len(tweet.AITags),
 		"summary_len", len(tweet.AISummary),
 	)
@@ -1366,6 +1689,20 @@ func (s *TweetService) Resync(ctx context.Context, tweetID domain.TweetID) error
 		return fmt.Errorf("resync fetch failed: %w", err)
 	}
 
+	// The original post ID returns the first version of an edited post;
+	// follow the edit history so the archive reflects the current text
+	if latest := fetchedTweet.LatestEditID(); latest != "" && latest != string(tweetID) {
+		latestURL := fmt.Sprintf("https://x.com/%s/status/%s", fetchedTweet.Author.Username, latest)
+		if edited, err := s.twitterClient.FetchTweet(ctx, latestURL); err != nil {
+			s.logger.Warn("failed to fetch latest edit", "tweet_id", tweetID, "edit_id", latest, "error", err)
+		} else {
+			fetchedTweet.Text = edited.Text
+		}
+	}
+
+	// Keep the pre-resync state so changes are recorded as a revision
+	prevRevision := lastRevision(tweet)
+
 	// Track what changed
 	textChanged := tweet.Text != fetchedTweet.Text
 	oldTextLen := len(tweet.Text)
@@ -1375,6 +1712,14 @@ func (s *TweetService) Resync(ctx context.Context, tweetID domain.TweetID) error
 	tweet.Text = fetchedTweet.Text
 	tweet.PostedAt = fetchedTweet.PostedAt
 	tweet.Metrics = fetchedTweet.Metrics
+	if len(fetchedTweet.EditTweetIDs) > 0 {
+		tweet.EditTweetIDs = fetchedTweet.EditTweetIDs
+	}
+	// Content kept from a credentialed fetch stays credentialed even if the
+	// tweet is public now
+	if fetchedTweet.FetchedWithCredentials {
+		tweet.FetchedWithCredentials = true
+	}
 
 	// Update author but preserve LocalAvatarURL (local copy of downloaded avatar)
 	existingLocalAvatar := tweet.Author.LocalAvatarURL
@@ -1387,6 +1732,7 @@ func (s *TweetService) Resync(ctx context.Context, tweetID domain.TweetID) error
 	// Smart resync: do NOT re-download media. Only refresh avatar metadata/picture if needed.
 	// - If we already have a local avatar, keep it.
 	// - If the author avatar URL changed or we have none, attempt to download the avatar.jpg (small).
+	var refetched []string
 	if tweet.ArchivePath != "" {
 		avatarPath := filepath.Join(tweet.ArchivePath, "avatar.jpg")
 		needsAvatar := tweet.Author.LocalAvatarURL == ""
@@ -1404,6 +1750,7 @@ func (s *TweetService) Resync(ctx context.Context, tweetID domain.TweetID) error
 				s.logger.Debug("avatar refresh failed", "tweet_id", tweetID, "error", err)
 			} else {
 				tweet.Author.LocalAvatarURL = avatarPath
+				refetched = append(refetched, "avatar.jpg")
 			}
 		}
 	}
@@ -1414,6 +1761,21 @@ func (s *TweetService) Resync(ctx context.Context, tweetID domain.TweetID) error
 		tweet.MediaTotal = len(fetchedTweet.Media)
 	}
 
+	// Polls keep counting and Community Notes can appear after archiving
+	mergeAttachments(tweet, fetchedTweet)
+	if tweet.Card != nil && tweet.Card.LocalImagePath == "" {
+		if err := s.downloadCardImage(ctx, tweet); err != nil {
+			s.logger.Debug("link card image download failed", "tweet_id", tweetID, "error", err)
+		} else if tweet.Card.LocalImagePath != "" {
+			refetched = append(refetched, "media/"+CardImageFilename)
+		}
+	}
+
+	// Re-download media flagged as damaged by the integrity scrubber
+	if repaired := s.repairCorruptMedia(ctx, tweet, fetchedTweet, refetched...); repaired > 0 {
+		s.logger.Info("repaired corrupt media", "tweet_id", tweetID, "count", repaired)
+	}
+
 	// Merge article fields if this is an article
 	if fetchedTweet.ContentType == domain.ContentTypeArticle {
 		tweet.ContentType = fetchedTweet.ContentType
@@ -1432,6 +1794,24 @@ func (s *TweetService) Resync(ctx context.Context, tweetID domain.TweetID) error
 		"new_text_len", newTextLen,
 	)
 
+	// Record a revision. Media already on disk is kept, so the media set is
+	// taken from the fetched post to capture upstream changes.
+	curRevision := snapshotRevision(tweet)
+	if len(fetchedTweet.Media) > 0 {
+		curRevision.MediaIDs = snapshotRevision(fetchedTweet).MediaIDs
+	}
+	if rev := recordRevision(tweet, prevRevision, curRevision); rev != nil {
+		s.logger.Info("tweet revision recorded", "tweet_id", tweetID, "revision", rev.Number, "changed", rev.Changed)
+		if rev.Edited {
+			s.emitEvent(domain.EventSeverityInfo, domain.EventCategoryTweet,
+				fmt.Sprintf("@%s edited a tweet after it was archived", tweet.Author.Username),
+				domain.EventMetadata{
+					"tweet_id": string(tweetID),
+					"revision": fmt.Sprintf("%d", rev.Number),
+				})
+		}
+	}
+
 	// Re-generate AI title with new text
 	aiTitle, aiSummary := s.generateAIMetadata(ctx, tweet)
 	tweet.AITitle = aiTitle
@@ -1508,6 +1888,10 @@ func (s *TweetService) StartRegenerateAIMetadata(tweetID domain.TweetID) error {
 		s.aiAnalysisLock.Unlock()
 		return domain.ErrVideoNotFound
 	}
+	if err := s.aiBudgetExceeded(); err != nil {
+		s.aiAnalysisLock.Unlock()
+		return err
+	}
 	s.processingAI[tweetID] = true
 	s.aiAnalysisLock.Unlock()
 
@@ -1536,18 +1920,30 @@ func (s *TweetService) StartRegenerateAIMetadata(tweetID domain.TweetID) error {
 	return nil
 }
 
-func buildTweetPrompt(tweet *domain.Tweet) string {
-	var sb strings.Builder
-	sb.WriteString(tweet.Text)
-
-	if tweet.HasVideo() {
-		sb.WriteString("\n[Contains video]")
+// filenameRequest builds the title generation request for a tweet. If the
+// tweet template fails to render, the plain tweet text is used instead.
+func (s *TweetService) filenameRequest(tweet *domain.Tweet) grok.FilenameRequest {
+	prompt, err := buildTweetPrompt(s.promptSet(), tweet)
+	if err != nil {
+		s.logger.Warn("tweet prompt template failed, using tweet text", "tweet_id", tweet.ID, "error", err)
+		prompt = tweet.Text
 	}
-	if tweet.HasImages() {
-		sb.WriteString(fmt.Sprintf("\n[Contains %d images]", countImages(tweet)))
+	return grok.FilenameRequest{
+		TweetText:      prompt,
+		AuthorUsername: tweet.Author.Username,
+		AuthorName:     tweet.Author.DisplayName,
+		PostedAt:       tweet.PostedAt.Format("2006-01-02"),
+		Duration:       getTotalVideoDuration(tweet),
 	}
+}
 
-	return sb.String()
+func buildTweetPrompt(prompts *grok.Prompts, tweet *domain.Tweet) (string, error) {
+	return prompts.RenderTweet(grok.TweetPromptData{
+		Text:       tweet.Text,
+		HasVideo:   tweet.HasVideo(),
+		HasImages:  tweet.HasImages(),
+		ImageCount: countImages(tweet),
+	})
 }
 
 func getTotalVideoDuration(tweet *domain.Tweet) int {
@@ -1711,13 +2107,23 @@ func (s *TweetService) processVideoForTranscription(ctx context.Context, media *
 		}
 	}
 
+	if s.usage != nil {
+		seconds := transcription.Duration
+		if seconds <= 0 {
+			seconds = float64(media.Duration)
+		}
+		s.usage.RecordTranscription(ctx, s.whisperClient.Model(), seconds)
+	}
+
 	// Store transcript in media
 	media.Transcript = transcription.Text
 	media.TranscriptLanguage = transcription.Language
+	media.TranscriptSegments = segmentsFromWhisper(transcription.Segments)
 
 	logger.Info("transcription complete",
 		"transcript_length", len(transcription.Text),
 		"language", transcription.Language,
+		"segments", len(media.TranscriptSegments),
 	)
 
 	// Save transcript to file as well
@@ -1725,6 +2131,11 @@ func (s *TweetService) processVideoForTranscription(ctx context.Context, media *
 	if err := os.WriteFile(transcriptPath, []byte(transcription.Text), 0644); err != nil {
 		logger.Warn("failed to save transcript file", "error", err)
 	}
+
+	// Write timed subtitles next to the video for <track> playback
+	if err := writeSubtitleFiles(archivePath, media); err != nil {
+		logger.Warn("failed to save subtitle files", "error", err)
+	}
 }
 
 // downloadThumbnail downloads a thumbnail image to the specified path.
@@ -1769,9 +2180,21 @@ func (s *TweetService) saveTweetMetadata(tweet *domain.Tweet) error {
 		return fmt.Errorf("write markdown: %w", err)
 	}
 
+	s.mirrorMetadata(tweet, map[string][]byte{"tweet.json": data, "README.md": []byte(md)})
+
 	return nil
 }
 
+// markdownAlt makes alt text safe inside a Markdown image, falling back to
+// "Image" when there is none.
+func markdownAlt(alt string) string {
+	alt = strings.Join(strings.Fields(alt), " ")
+	if alt == "" {
+		return "Image"
+	}
+	return strings.NewReplacer(`\`, `\\`, "[", `\[`, "]", `\]`).Replace(alt)
+}
+
 func buildMarkdownSummary(tweet *domain.Tweet) string {
 	// Check if this is an Article
 	if tweet.IsArticle() {
@@ -1783,7 +2206,12 @@ func buildMarkdownSummary(tweet *domain.Tweet) string {
 	sb.WriteString(fmt.Sprintf("# %s\n\n", tweet.AITitle))
 	sb.WriteString(fmt.Sprintf("**Author:** @%s (%s)\n\n", tweet.Author.Username, tweet.Author.DisplayName))
 	sb.WriteString(fmt.Sprintf("**Posted:** %s\n\n", tweet.PostedAt.Format("January 2, 2006 at 3:04 PM")))
+	if tweet.EditedAfterArchive() {
+		sb.WriteString(fmt.Sprintf("**Edited:** This post was edited on X after it was archived (%d revisions recorded)\n\n", len(tweet.Revisions)))
+	}
+	sb.WriteString(upstreamMarkdown(tweet))
 	sb.WriteString(fmt.Sprintf("**Original URL:** %s\n\n", tweet.URL))
+	sb.WriteString(tagsMarkdown(tweet))
 	sb.WriteString("---\n\n")
 	sb.WriteString(fmt.Sprintf("%s\n\n", tweet.Text))
 
@@ -1793,14 +2221,24 @@ func buildMarkdownSummary(tweet *domain.Tweet) string {
 			if m.LocalPath != "" {
 				relPath := filepath.Base(m.LocalPath)
 				if m.Type == domain.MediaTypeImage {
-					sb.WriteString(fmt.Sprintf("![Image](media/%s)\n\n", relPath))
+					sb.WriteString(fmt.Sprintf("![%s](media/%s)\n\n", markdownAlt(m.Alt()), relPath))
 				} else {
 					sb.WriteString(fmt.Sprintf("- [Video: %s](media/%s)\n", relPath, relPath))
+					sb.WriteString(markdownChapters(m.Chapters))
+				}
+				if m.OCRText != "" {
+					sb.WriteString("> **Text in media:**\n")
+					for _, line := range strings.Split(m.OCRText, "\n") {
+						sb.WriteString(fmt.Sprintf("> %s\n", line))
+					}
+					sb.WriteString("\n")
 				}
 			}
 		}
 	}
 
+	sb.WriteString(attachmentsMarkdown(tweet))
+
 	sb.WriteString("\n---\n\n## Metrics\n\n")
 	sb.WriteString(fmt.Sprintf("- Likes: %d\n", tweet.Metrics.Likes))
 	sb.WriteString(fmt.Sprintf("- Retweets: %d\n", tweet.Metrics.Retweets))
@@ -1831,6 +2269,10 @@ func buildArticleMarkdownSummary(tweet *domain.Tweet) string {
 	sb.WriteString(fmt.Sprintf("# %s\n\n", title))
 	sb.WriteString(fmt.Sprintf("**Author:** @%s (%s)\n\n", tweet.Author.Username, tweet.Author.DisplayName))
 	sb.WriteString(fmt.Sprintf("**Posted:** %s\n\n", tweet.PostedAt.Format("January 2, 2006 at 3:04 PM")))
+	if tweet.EditedAfterArchive() {
+		sb.WriteString(fmt.Sprintf("**Edited:** This post was edited on X after it was archived (%d revisions recorded)\n\n", len(tweet.Revisions)))
+	}
+	sb.WriteString(upstreamMarkdown(tweet))
 
 	// Article-specific metadata
 	if tweet.WordCount > 0 {
@@ -1841,6 +2283,7 @@ func buildArticleMarkdownSummary(tweet *domain.Tweet) string {
 	}
 
 	sb.WriteString(fmt.Sprintf("**Original URL:** %s\n\n", tweet.URL))
+	sb.WriteString(tagsMarkdown(tweet))
 	sb.WriteString("**Type:** Article\n\n")
 	sb.WriteString("---\n\n")
 
@@ -1858,9 +2301,10 @@ func buildArticleMarkdownSummary(tweet *domain.Tweet) string {
 			if m.LocalPath != "" {
 				relPath := filepath.Base(m.LocalPath)
 				if m.Type == domain.MediaTypeImage {
-					sb.WriteString(fmt.Sprintf("![Image](media/%s)\n\n", relPath))
+					sb.WriteString(fmt.Sprintf("![%s](media/%s)\n\n", markdownAlt(m.Alt()), relPath))
 				} else {
 					sb.WriteString(fmt.Sprintf("- [Video: %s](media/%s)\n", relPath, relPath))
+					sb.WriteString(markdownChapters(m.Chapters))
 				}
 			}
 		}
@@ -1885,6 +2329,8 @@ func buildArticleMarkdownSummary(tweet *domain.Tweet) string {
 		}
 	}
 
+	sb.WriteString(attachmentsMarkdown(tweet))
+
 	sb.WriteString("\n---\n\n## Metrics\n\n")
 	sb.WriteString(fmt.Sprintf("- Likes: %d\n", tweet.Metrics.Likes))
 	sb.WriteString(fmt.Sprintf("- Retweets: %d\n", tweet.Metrics.Retweets))
@@ -1912,15 +2358,16 @@ func (s *TweetService) GetStatus(ctx context.Context, tweetID domain.TweetID) (*
 	}
 	// Copy fields while holding lock
 	resp := &TweetStatusResponse{
-		TweetID:     tweet.ID,
-		Status:      tweet.Status,
-		Author:      tweet.Author.Username,
-		Text:        truncateText(tweet.Text, 100),
-		MediaCount:  len(tweet.Media),
-		AITitle:     tweet.AITitle,
-		ArchivePath: tweet.ArchivePath,
-		Error:       tweet.Error,
-		CreatedAt:   tweet.CreatedAt,
+		TweetID:       tweet.ID,
+		Status:        tweet.Status,
+		Author:        tweet.Author.Username,
+		Text:          truncateText(tweet.Text, 100),
+		MediaCount:    len(tweet.Media),
+		AITitle:       tweet.AITitle,
+		ArchivePath:   tweet.ArchivePath,
+		Error:         tweet.Error,
+		FailureReason: tweet.FailureReason,
+		CreatedAt:     tweet.CreatedAt,
 	}
 	s.tweetsMu.RUnlock()
 	return resp, nil
@@ -1958,17 +2405,77 @@ func (s *TweetService) List(ctx context.Context, limit, offset int) ([]*domain.T
 	return result, total, nil
 }
 
+// tweetSnapshot returns a shallow copy of a tweet so callers can read it
+// without holding tweetsMu.
+func (s *TweetService) tweetSnapshot(tweetID domain.TweetID) (*domain.Tweet, bool) {
+	s.tweetsMu.RLock()
+	defer s.tweetsMu.RUnlock()
+	tweet, ok := s.tweets[tweetID]
+	if !ok {
+		return nil, false
+	}
+	tweetCopy := *tweet
+	return &tweetCopy, true
+}
+
+// upstreamFilterPrefix is the search operator restricting results by upstream
+// status, e.g. "upstream:deleted". "upstream:gone" matches any tweet no longer
+// visible on X and "upstream:unchecked" matches tweets not yet checked.
+const upstreamFilterPrefix = "upstream:"
+
+// parseSearchQuery lowercases a search query and splits out its upstream
+// filters. The remaining free text is returned trimmed.
+func parseSearchQuery(query string) (string, []string) {
+	var words, upstream []string
+	for _, word := range strings.Fields(strings.ToLower(query)) {
+		if v, ok := strings.CutPrefix(word, upstreamFilterPrefix); ok && v != "" {
+			upstream = append(upstream, v)
+			continue
+		}
+		words = append(words, word)
+	}
+	return strings.Join(words, " "), upstream
+}
+
+// matchesUpstreamFilter reports whether a tweet matches any of the upstream
+// filters. An empty filter list matches everything.
+func matchesUpstreamFilter(t *domain.Tweet, filters []string) bool {
+	if len(filters) == 0 {
+		return true
+	}
+	for _, f := range filters {
+		switch f {
+		case "gone":
+			if t.UpstreamStatus.Gone() {
+				return true
+			}
+		case "unchecked":
+			if t.UpstreamStatus == "" {
+				return true
+			}
+		default:
+			if string(t.UpstreamStatus) == f {
+				return true
+			}
+		}
+	}
+	return false
+}
+
 // Search returns tweets matching the query, sorted by date (newest first).
-// Searches across: text, author, ai_title, ai_summary, ai_tags, ai_topics, transcripts, media tags/captions.
+// Searches across: text, author, tags, ai_title, ai_summary, ai_tags, ai_topics, transcripts, media tags/captions, OCR text.
 func (s *TweetService) Search(ctx context.Context, query string, limit, offset int) ([]*domain.Tweet, int, error) {
-	query = strings.ToLower(strings.TrimSpace(query))
-	if query == "" {
+	query, upstream := parseSearchQuery(query)
+	if query == "" && len(upstream) == 0 {
 		return s.List(ctx, limit, offset)
 	}
 
 	var matched []*domain.Tweet
 	for _, tweet := range s.tweets {
-		if s.tweetMatchesQuery(tweet, query) {
+		if !matchesUpstreamFilter(tweet, upstream) {
+			continue
+		}
+		if query == "" || s.tweetMatchesQuery(tweet, query) {
 			matched = append(matched, tweet)
 		}
 	}
@@ -2007,6 +2514,21 @@ func (s *TweetService) tweetMatchesQuery(t *domain.Tweet, query string) bool {
 		return true
 	}
 
+	// Translated text and transcripts
+	for _, tr := range t.Translations {
+		if strings.Contains(strings.ToLower(tr.Text), query) {
+			return true
+		}
+		if strings.Contains(strings.ToLower(tr.ArticleBody), query) {
+			return true
+		}
+		for _, m := range tr.Media {
+			if strings.Contains(strings.ToLower(m.Transcript), query) {
+				return true
+			}
+		}
+	}
+
 	// AI metadata
 	if strings.Contains(strings.ToLower(t.AITitle), query) {
 		return true
@@ -2018,6 +2540,13 @@ func (s *TweetService) tweetMatchesQuery(t *domain.Tweet, query string) bool {
 		return true
 	}
 
+	// User tags
+	for _, tag := range t.Tags {
+		if strings.Contains(tag, query) {
+			return true
+		}
+	}
+
 	// AI tags
 	for _, tag := range t.AITags {
 		if strings.Contains(strings.ToLower(tag), query) {
@@ -2032,7 +2561,7 @@ func (s *TweetService) tweetMatchesQuery(t *domain.Tweet, query string) bool {
 		}
 	}
 
-	// Media: transcripts, tags, captions
+	// Media: transcripts, tags, captions, OCR text
 	for _, m := range t.Media {
 		if strings.Contains(strings.ToLower(m.Transcript), query) {
 			return true
@@ -2040,6 +2569,9 @@ func (s *TweetService) tweetMatchesQuery(t *domain.Tweet, query string) bool {
 		if strings.Contains(strings.ToLower(m.AICaption), query) {
 			return true
 		}
+		if strings.Contains(strings.ToLower(m.OCRText), query) {
+			return true
+		}
 		for _, tag := range m.AITags {
 			if strings.Contains(strings.ToLower(tag), query) {
 				return true
@@ -2050,7 +2582,8 @@ func (s *TweetService) tweetMatchesQuery(t *domain.Tweet, query string) bool {
 	return false
 }
 
-// Delete removes a tweet archive including all files.
+// Delete removes a tweet archive. With a trash retention configured the
+// archive is moved to the trash; otherwise all files are removed immediately.
 func (s *TweetService) Delete(ctx context.Context, tweetID domain.TweetID) error {
 	s.tweetsMu.Lock()
 	tweet, ok := s.tweets[tweetID]
@@ -2063,6 +2596,18 @@ func (s *TweetService) Delete(ctx context.Context, tweetID domain.TweetID) error
 	delete(s.tweets, tweetID)
 	s.tweetsMu.Unlock()
 
+	// Move the archive to the trash so it can be restored until retention expires
+	if archivePath != "" && s.cfg.TrashRetention > 0 {
+		if err := s.moveToTrash(ctx, tweetID, archivePath); err != nil {
+			s.tweetsMu.Lock()
+			s.tweets[tweetID] = tweet
+			s.tweetsMu.Unlock()
+			return fmt.Errorf("move to trash: %w", err)
+		}
+		s.logger.Info("tweet moved to trash", "tweet_id", tweetID)
+		return nil
+	}
+
 	// Delete the archive directory if it exists (outside lock to avoid blocking)
 	if archivePath != "" {
 		if err := os.RemoveAll(archivePath); err != nil {
@@ -2072,6 +2617,8 @@ func (s *TweetService) Delete(ctx context.Context, tweetID domain.TweetID) error
 				"error", err,
 			)
 		}
+		s.releaseMediaBlobs(tweetID, archivePath)
+		s.deleteFromStorage(ctx, tweetID, archivePath)
 	}
 
 	s.logger.Info("tweet deleted", "tweet_id", tweetID)
@@ -2210,31 +2757,6 @@ func (s *TweetService) GetArchivePath(ctx context.Context, tweetID domain.TweetI
 	return archivePath, nil
 }
 
-// GetAvatarPath returns the path to the locally stored avatar for a tweet's author.
-func (s *TweetService) GetAvatarPath(ctx context.Context, tweetID domain.TweetID) (string, error) {
-	s.tweetsMu.RLock()
-	tweet, ok := s.tweets[tweetID]
-	if !ok {
-		s.tweetsMu.RUnlock()
-		return "", domain.ErrVideoNotFound
-	}
-	localAvatarURL := tweet.Author.LocalAvatarURL
-	archivePath := tweet.ArchivePath
-	s.tweetsMu.RUnlock()
-
-	if localAvatarURL != "" {
-		return localAvatarURL, nil
-	}
-
-	// Fallback: try avatar.jpg in archive path
-	avatarPath := filepath.Join(archivePath, "avatar.jpg")
-	if _, err := os.Stat(avatarPath); err == nil {
-		return avatarPath, nil
-	}
-
-	return "", domain.ErrMediaNotFound
-}
-
 func getMediaType(filename string) string {
 	ext := strings.ToLower(filepath.Ext(filename))
 	switch ext {
@@ -2242,6 +2764,8 @@ func getMediaType(filename string) string {
 		return "image"
 	case ".mp4", ".webm", ".mov":
 		return "video"
+	case ".vtt", ".srt":
+		return "subtitle"
 	default:
 		return "unknown"
 	}
@@ -2264,6 +2788,10 @@ func getContentType(filename string) string {
 		return "video/webm"
 	case ".mov":
 		return "video/quicktime"
+	case ".vtt":
+		return "text/vtt; charset=utf-8"
+	case ".srt":
+		return "application/x-subrip; charset=utf-8"
 	default:
 		return "application/octet-stream"
 	}
@@ -2332,7 +2860,7 @@ func (s *TweetService) GenerateEssay(ctx context.Context, tweetID domain.TweetID
 		"transcript_length", len(media.Transcript))
 
 	// Call Grok to generate the essay
-	essayResp, err := s.grokClient.GenerateEssay(ctx, grok.EssayRequest{
+	essayResp, err := s.grokClient.GenerateEssay(withUsageTweet(ctx, tweetID), grok.EssayRequest{
 		Transcript:  media.Transcript,
 		ContentType: tweet.AIContentType,
 		Style:       style,
@@ -2360,6 +2888,7 @@ func (s *TweetService) GenerateEssay(ctx context.Context, tweetID domain.TweetID
 	media.Essay = essayResp.Essay
 	media.EssayTitle = essayResp.Title
 	media.EssayWordCount = essayResp.WordCount
+	media.EssayPromptVersion = s.promptSet().Version()
 	media.EssayStatus = "completed"
 	media.EssayError = ""
 	s.tweetsMu.Unlock()
@@ -2376,6 +2905,10 @@ func (s *TweetService) GenerateEssay(ctx context.Context, tweetID domain.TweetID
 	if err := os.WriteFile(essayPath, []byte(essayContent), 0644); err != nil {
 		s.logger.Warn("failed to save essay file", "path", essayPath, "error", err)
 	}
+	s.writeChecksums(tweet, essayFilename)
+	if err := s.syncArchiveToStorage(ctx, tweet); err != nil {
+		s.logger.Warn("failed to mirror archive to storage", "tweet_id", tweetID, "error", err)
+	}
 
 	s.logger.Info("essay generation completed",
 		"tweet_id", tweetID,
@@ -2430,14 +2963,14 @@ func (s *TweetService) GetEssay(ctx context.Context, tweetID domain.TweetID, med
 // DeleteEssay removes an essay from a media item.
 func (s *TweetService) DeleteEssay(ctx context.Context, tweetID domain.TweetID, mediaIndex int) error {
 	s.tweetsMu.Lock()
-	defer s.tweetsMu.Unlock()
-
 	tweet, ok := s.tweets[tweetID]
 	if !ok {
+		s.tweetsMu.Unlock()
 		return domain.ErrVideoNotFound
 	}
 
 	if mediaIndex < 0 || mediaIndex >= len(tweet.Media) {
+		s.tweetsMu.Unlock()
 		return fmt.Errorf("invalid media index: %d", mediaIndex)
 	}
 
@@ -2447,6 +2980,7 @@ func (s *TweetService) DeleteEssay(ctx context.Context, tweetID domain.TweetID,
 	media.Essay = ""
 	media.EssayTitle = ""
 	media.EssayWordCount = 0
+	media.EssayPromptVersion = ""
 	media.EssayStatus = ""
 	media.EssayError = ""
 
@@ -2459,9 +2993,15 @@ func (s *TweetService) DeleteEssay(ctx context.Context, tweetID domain.TweetID,
 	}
 
 	// Save updated metadata
-	if err := s.saveTweetMetadata(tweet); err != nil {
+	err := s.saveTweetMetadata(tweet)
+	s.tweetsMu.Unlock()
+	if err != nil {
 		return fmt.Errorf("failed to save tweet metadata: %w", err)
 	}
+	s.writeChecksums(tweet, essayFilename)
+	if err := s.syncArchiveToStorage(ctx, tweet); err != nil {
+		s.logger.Warn("failed to mirror archive to storage", "tweet_id", tweetID, "error", err)
+	}
 
 	s.logger.Info("essay deleted",
 		"tweet_id", tweetID,
@@ -2501,6 +3041,10 @@ func (s *TweetService) StartGenerateEssay(tweetID domain.TweetID, mediaIndex int
 	}
 	s.tweetsMu.RUnlock()
 
+	if err := s.aiBudgetExceeded(); err != nil {
+		return err
+	}
+
 	// Run in background
 	go func() {
 		ctx := context.Background()
@@ -1,9 +1,14 @@
 package service
 
 import (
+	"context"
+	"io"
+	"log/slog"
+	"strings"
 	"testing"
 
 	"github.com/iconidentify/xgrabba/internal/domain"
+	"github.com/iconidentify/xgrabba/pkg/grok"
 )
 
 func TestIsPermanentTweetFailure(t *testing.T) {
@@ -84,6 +89,28 @@ func TestIsPermanentTweetFailure(t *testing.T) {
 	}
 }
 
+func TestArchive_PermanentFailureReasonNotRequeued(t *testing.T) {
+	svc := &TweetService{
+		logger: slog.New(slog.NewTextHandler(io.Discard, nil)),
+		tweets: map[domain.TweetID]*domain.Tweet{
+			"123": {
+				ID:            "123",
+				Status:        domain.ArchiveStatusFailed,
+				Error:         "fetch tweet: syndication: x: tweet has been deleted",
+				FailureReason: domain.FailureTweetDeleted,
+			},
+		},
+	}
+
+	resp, err := svc.Archive(context.Background(), ArchiveRequest{TweetURL: "https://x.com/user/status/123"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.Status != domain.ArchiveStatusFailed || resp.FailureReason != domain.FailureTweetDeleted {
+		t.Errorf("resp = %+v, want failed with tweet_deleted", resp)
+	}
+}
+
 func TestBuildTweetPrompt(t *testing.T) {
 	tests := []struct {
 		name  string
@@ -135,7 +162,10 @@ func TestBuildTweetPrompt(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got := buildTweetPrompt(tt.tweet)
+			got, err := buildTweetPrompt(grok.DefaultPrompts(), tt.tweet)
+			if err != nil {
+				t.Fatal(err)
+			}
 			if got != tt.want {
 				t.Errorf("buildTweetPrompt() = %q, want %q", got, tt.want)
 			}
@@ -331,3 +361,62 @@ func TestErrAIAlreadyInProgress(t *testing.T) {
 		t.Errorf("unexpected error message: %s", ErrAIAlreadyInProgress.Error())
 	}
 }
+
+// altTextStub answers vision analysis with an alt text and records whether
+// alt text was requested.
+type altTextStub struct {
+	analysisStub
+	wantAltText []bool
+}
+
+func (s *altTextStub) AnalyzeContentWithVision(ctx context.Context, req grok.VisionAnalysisRequest) (*grok.ContentAnalysisResponse, error) {
+	s.wantAltText = append(s.wantAltText, req.WantAltText)
+	return &grok.ContentAnalysisResponse{Summary: "cats", AltText: " A [grey] cat\nasleep "}, nil
+}
+
+func TestRunPerMediaAnalysis_AltText(t *testing.T) {
+	svc, tweet := newIntegrityTestService(t)
+	stub := &altTextStub{}
+	svc.grokClient = stub
+	tweet.Media = append(tweet.Media, domain.Media{
+		ID: "m3", Type: domain.MediaTypeImage, LocalPath: tweet.Media[1].LocalPath, AltText: "My cat",
+	})
+	tweet.Media = tweet.Media[1:]
+
+	svc.runPerMediaAnalysis(context.Background(), tweet)
+
+	if len(stub.wantAltText) != 2 || !stub.wantAltText[0] || stub.wantAltText[1] {
+		t.Fatalf("alt text requested = %v, want only for the image without alt text", stub.wantAltText)
+	}
+	if got := tweet.Media[0].AIAltText; got != "A [grey] cat\nasleep" {
+		t.Errorf("AIAltText = %q", got)
+	}
+	if tweet.Media[1].AIAltText != "" || tweet.Media[1].Alt() != "My cat" {
+		t.Errorf("author alt text should be kept: %+v", tweet.Media[1])
+	}
+
+	md := buildMarkdownSummary(tweet)
+	if !strings.Contains(md, `![A \[grey\] cat asleep](media/m2.jpg)`) || !strings.Contains(md, "![My cat](media/m2.jpg)") {
+		t.Errorf("markdown alt text missing:\n%s", md)
+	}
+}
+
+func TestStoredTweetToTweet_ArticleFields(t *testing.T) {
+	svc := &TweetService{logger: testLogger()}
+	tweet := &domain.Tweet{
+		ID:             "1",
+		ContentType:    domain.ContentTypeArticle,
+		ArticleTitle:   "Cats",
+		ArticleBody:    "Cats purr.",
+		ArticleImages:  []domain.ArticleImage{{URL: "https://example.com/cat.jpg"}},
+		WordCount:      2,
+		ReadingMinutes: 1,
+	}
+	stored := tweet.ToStoredTweet()
+	loaded := svc.storedTweetToTweet(&stored, "")
+	if loaded.ContentType != domain.ContentTypeArticle || loaded.ArticleTitle != "Cats" ||
+		loaded.ArticleBody != "Cats purr." || len(loaded.ArticleImages) != 1 ||
+		loaded.WordCount != 2 || loaded.ReadingMinutes != 1 {
+		t.Errorf("loaded article = %+v", loaded)
+	}
+}
@@ -0,0 +1,208 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sort"
+	"time"
+
+	"github.com/iconidentify/xgrabba/internal/config"
+	"github.com/iconidentify/xgrabba/internal/domain"
+)
+
+// upstreamCheckTick is how often the checker looks for tweets that are due.
+const upstreamCheckTick = 15 * time.Minute
+
+// upstreamCheckSpacing spaces out syndication requests within a cycle.
+const upstreamCheckSpacing = 2 * time.Second
+
+// UpstreamChecker probes whether a tweet still exists on X.
+type UpstreamChecker interface {
+	CheckAvailability(ctx context.Context, tweetID string) (domain.UpstreamStatus, error)
+}
+
+// UpstreamService periodically checks archived tweets against X and records
+// which ones have been deleted, withheld or hidden since they were archived.
+type UpstreamService struct {
+	tweetSvc     *TweetService
+	checker      UpstreamChecker
+	cfg          config.UpstreamConfig
+	logger       *slog.Logger
+	eventEmitter domain.EventEmitter
+}
+
+// NewUpstreamService creates a new upstream availability checker.
+func NewUpstreamService(tweetSvc *TweetService, checker UpstreamChecker, cfg config.UpstreamConfig, logger *slog.Logger, eventEmitter domain.EventEmitter) *UpstreamService {
+	return &UpstreamService{
+		tweetSvc:     tweetSvc,
+		checker:      checker,
+		cfg:          cfg,
+		logger:       logger,
+		eventEmitter: eventEmitter,
+	}
+}
+
+// Start runs scheduled checks until ctx is cancelled.
+func (s *UpstreamService) Start(ctx context.Context) {
+	if !s.cfg.CheckEnabled || s.cfg.CheckInterval <= 0 || s.checker == nil {
+		return
+	}
+
+	ticker := time.NewTicker(upstreamCheckTick)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			checked, err := s.CheckDue(ctx)
+			if err != nil && ctx.Err() == nil {
+				s.logger.Warn("upstream check failed", "error", err)
+			}
+			if checked > 0 {
+				s.logger.Info("upstream check complete", "checked", checked)
+			}
+		}
+	}
+}
+
+// CheckDue checks completed archives not checked within CheckInterval, least
+// recently checked first, up to MaxPerCycle per call. It returns the number
+// of tweets checked.
+func (s *UpstreamService) CheckDue(ctx context.Context) (int, error) {
+	tweets, _, err := s.tweetSvc.List(ctx, 0, 0)
+	if err != nil {
+		return 0, fmt.Errorf("list tweets: %w", err)
+	}
+
+	cutoff := time.Now().Add(-s.cfg.CheckInterval)
+	var due []*domain.Tweet
+	for _, tweet := range tweets {
+		if tweet.Status != domain.ArchiveStatusCompleted {
+			continue
+		}
+		if tweet.UpstreamCheckedAt == nil || tweet.UpstreamCheckedAt.Before(cutoff) {
+			due = append(due, tweet)
+		}
+	}
+	sort.Slice(due, func(i, j int) bool {
+		a, b := due[i].UpstreamCheckedAt, due[j].UpstreamCheckedAt
+		if a == nil || b == nil {
+			return a == nil && b != nil
+		}
+		return a.Before(*b)
+	})
+	if s.cfg.MaxPerCycle > 0 && len(due) > s.cfg.MaxPerCycle {
+		due = due[:s.cfg.MaxPerCycle]
+	}
+
+	checked := 0
+	for i, tweet := range due {
+		if ctx.Err() != nil {
+			return checked, ctx.Err()
+		}
+		if i > 0 {
+			select {
+			case <-ctx.Done():
+				return checked, ctx.Err()
+			case <-time.After(upstreamCheckSpacing):
+			}
+		}
+		if err := s.Check(ctx, tweet.ID); err != nil {
+			s.logger.Debug("upstream check failed", "tweet_id", tweet.ID, "error", err)
+			continue
+		}
+		checked++
+	}
+	return checked, nil
+}
+
+// Check probes one tweet and records the result, emitting an event when the
+// tweet disappears or comes back.
+func (s *UpstreamService) Check(ctx context.Context, tweetID domain.TweetID) error {
+	status, err := s.checker.CheckAvailability(ctx, string(tweetID))
+	if err != nil {
+		return err
+	}
+	prev, changed, err := s.tweetSvc.setUpstreamStatus(tweetID, status, time.Now().UTC())
+	if err != nil {
+		return err
+	}
+	if changed {
+		s.reportChange(tweetID, prev, status)
+	}
+	return nil
+}
+
+// reportChange logs and emits an event for a status transition. The first
+// check of a tweet that is still available is not worth reporting.
+func (s *UpstreamService) reportChange(tweetID domain.TweetID, prev, status domain.UpstreamStatus) {
+	if prev == "" && !status.Gone() {
+		return
+	}
+
+	author := ""
+	if tweet, ok := s.tweetSvc.tweetSnapshot(tweetID); ok {
+		author = tweet.Author.Username
+	}
+	meta := domain.EventMetadata{
+		"tweet_id":        string(tweetID),
+		"author":          author,
+		"upstream_status": string(status),
+		"previous_status": string(prev),
+	}
+
+	s.logger.Info("upstream status changed", "tweet_id", tweetID, "from", prev, "to", status)
+	if s.eventEmitter == nil {
+		return
+	}
+	switch {
+	case status.Gone() && !prev.Gone():
+		s.eventEmitter.EmitWarning(domain.EventCategoryTweet, "upstream_service",
+			fmt.Sprintf("Tweet by @%s is no longer available on X (%s)", author, status), meta)
+	case !status.Gone():
+		s.eventEmitter.EmitInfo(domain.EventCategoryTweet, "upstream_service",
+			fmt.Sprintf("Tweet by @%s is available on X again", author), meta)
+	default:
+		s.eventEmitter.EmitInfo(domain.EventCategoryTweet, "upstream_service",
+			fmt.Sprintf("Tweet by @%s changed from %s to %s on X", author, prev, status), meta)
+	}
+}
+
+// setUpstreamStatus records a check result. It returns the previous status
+// and whether it changed.
+func (s *TweetService) setUpstreamStatus(tweetID domain.TweetID, status domain.UpstreamStatus, checkedAt time.Time) (domain.UpstreamStatus, bool, error) {
+	s.tweetsMu.Lock()
+	tweet, ok := s.tweets[tweetID]
+	if !ok {
+		s.tweetsMu.Unlock()
+		return "", false, domain.ErrVideoNotFound
+	}
+	prev := tweet.UpstreamStatus
+	changed := prev != status
+	tweet.UpstreamStatus = status
+	tweet.UpstreamCheckedAt = &checkedAt
+	if changed {
+		tweet.UpstreamChangedAt = &checkedAt
+	}
+	s.tweetsMu.Unlock()
+
+	if err := s.saveTweetMetadata(tweet); err != nil {
+		return prev, changed, fmt.Errorf("save metadata: %w", err)
+	}
+	return prev, changed, nil
+}
+
+// upstreamMarkdown returns the README.md line noting a tweet that is no longer
+// on X, or "" while it is still available.
+func upstreamMarkdown(tweet *domain.Tweet) string {
+	if !tweet.UpstreamStatus.Gone() {
+		return ""
+	}
+	since := ""
+	if tweet.UpstreamChangedAt != nil {
+		since = fmt.Sprintf(" (detected %s)", tweet.UpstreamChangedAt.Format("January 2, 2006"))
+	}
+	return fmt.Sprintf("**Upstream:** No longer available on X: %s%s\n\n", tweet.UpstreamStatus, since)
+}
@@ -0,0 +1,156 @@
+package service
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/iconidentify/xgrabba/internal/config"
+	"github.com/iconidentify/xgrabba/internal/domain"
+)
+
+type fakeUpstreamChecker struct {
+	status domain.UpstreamStatus
+	calls  int
+}
+
+func (f *fakeUpstreamChecker) CheckAvailability(ctx context.Context, tweetID string) (domain.UpstreamStatus, error) {
+	f.calls++
+	return f.status, nil
+}
+
+type recordingEmitter struct {
+	events []domain.Event
+}
+
+func (e *recordingEmitter) Emit(event domain.Event) { e.events = append(e.events, event) }
+
+func (e *recordingEmitter) EmitInfo(category domain.EventCategory, source, message string, metadata domain.EventMetadata) {
+	e.Emit(domain.Event{Severity: domain.EventSeverityInfo, Category: category, Source: source, Message: message})
+}
+
+func (e *recordingEmitter) EmitWarning(category domain.EventCategory, source, message string, metadata domain.EventMetadata) {
+	e.Emit(domain.Event{Severity: domain.EventSeverityWarning, Category: category, Source: source, Message: message})
+}
+
+func (e *recordingEmitter) EmitError(category domain.EventCategory, source, message string, metadata domain.EventMetadata) {
+	e.Emit(domain.Event{Severity: domain.EventSeverityError, Category: category, Source: source, Message: message})
+}
+
+func (e *recordingEmitter) EmitSuccess(category domain.EventCategory, source, message string, metadata domain.EventMetadata) {
+	e.Emit(domain.Event{Severity: domain.EventSeveritySuccess, Category: category, Source: source, Message: message})
+}
+
+func newUpstreamTestService(t *testing.T) (*UpstreamService, *fakeUpstreamChecker, *recordingEmitter, *domain.Tweet) {
+	t.Helper()
+	tweet := &domain.Tweet{
+		ID:          "1",
+		Text:        "hello",
+		Author:      domain.Author{Username: "user"},
+		Status:      domain.ArchiveStatusCompleted,
+		ArchivePath: t.TempDir(),
+	}
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	tweetSvc := &TweetService{
+		logger: logger,
+		tweets: map[domain.TweetID]*domain.Tweet{tweet.ID: tweet},
+	}
+	checker := &fakeUpstreamChecker{status: domain.UpstreamStatusAvailable}
+	emitter := &recordingEmitter{}
+	cfg := config.UpstreamConfig{CheckEnabled: true, CheckInterval: time.Hour, MaxPerCycle: 10}
+	return NewUpstreamService(tweetSvc, checker, cfg, logger, emitter), checker, emitter, tweet
+}
+
+func TestUpstreamService_CheckDue(t *testing.T) {
+	svc, checker, emitter, tweet := newUpstreamTestService(t)
+	ctx := context.Background()
+
+	if n, err := svc.CheckDue(ctx); err != nil || n != 1 {
+		t.Fatalf("CheckDue() = %d, %v; want 1", n, err)
+	}
+	if tweet.UpstreamStatus != domain.UpstreamStatusAvailable || tweet.UpstreamCheckedAt == nil {
+		t.Fatalf("status not recorded: %q %v", tweet.UpstreamStatus, tweet.UpstreamCheckedAt)
+	}
+	if len(emitter.events) != 0 {
+		t.Errorf("first check of an available tweet should not emit, got %+v", emitter.events)
+	}
+
+	// Checked within the interval, so not due again
+	if n, _ := svc.CheckDue(ctx); n != 0 || checker.calls != 1 {
+		t.Errorf("second CheckDue() = %d with %d calls, want 0 and 1", n, checker.calls)
+	}
+}
+
+func TestUpstreamService_CheckEmitsOnDisappearance(t *testing.T) {
+	svc, checker, emitter, tweet := newUpstreamTestService(t)
+	ctx := context.Background()
+
+	if err := svc.Check(ctx, tweet.ID); err != nil {
+		t.Fatal(err)
+	}
+	checker.status = domain.UpstreamStatusDeleted
+	if err := svc.Check(ctx, tweet.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	if tweet.UpstreamStatus != domain.UpstreamStatusDeleted || tweet.UpstreamChangedAt == nil {
+		t.Fatalf("status = %q, changed_at = %v", tweet.UpstreamStatus, tweet.UpstreamChangedAt)
+	}
+	if len(emitter.events) != 1 || emitter.events[0].Severity != domain.EventSeverityWarning {
+		t.Fatalf("expected one warning event, got %+v", emitter.events)
+	}
+	if !strings.Contains(emitter.events[0].Message, "deleted") {
+		t.Errorf("event message = %q", emitter.events[0].Message)
+	}
+
+	// Re-checking an unchanged status is silent
+	if err := svc.Check(ctx, tweet.ID); err != nil {
+		t.Fatal(err)
+	}
+	if len(emitter.events) != 1 {
+		t.Errorf("unchanged status should not emit, got %d events", len(emitter.events))
+	}
+
+	stored, err := svc.tweetSvc.GetFullTweet(ctx, tweet.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stored.UpstreamStatus != string(domain.UpstreamStatusDeleted) {
+		t.Errorf("tweet.json upstream_status = %q", stored.UpstreamStatus)
+	}
+}
+
+func TestSearch_UpstreamFilter(t *testing.T) {
+	now := time.Now()
+	svc := &TweetService{tweets: map[domain.TweetID]*domain.Tweet{
+		"1": {ID: "1", Text: "cats", CreatedAt: now, UpstreamStatus: domain.UpstreamStatusDeleted},
+		"2": {ID: "2", Text: "cats", CreatedAt: now, UpstreamStatus: domain.UpstreamStatusAvailable},
+		"3": {ID: "3", Text: "dogs", CreatedAt: now, UpstreamStatus: domain.UpstreamStatusSuspended},
+		"4": {ID: "4", Text: "cats", CreatedAt: now},
+	}}
+	ctx := context.Background()
+
+	tests := []struct {
+		query string
+		want  int
+	}{
+		{"upstream:deleted", 1},
+		{"upstream:gone", 2},
+		{"cats upstream:gone", 1},
+		{"upstream:unchecked", 1},
+		{"upstream:deleted upstream:suspended", 2},
+		{"cats", 3},
+	}
+	for _, tt := range tests {
+		_, total, err := svc.Search(ctx, tt.query, 0, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if total != tt.want {
+			t.Errorf("Search(%q) total = %d, want %d", tt.query, total, tt.want)
+		}
+	}
+}
@@ -0,0 +1,192 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Local stores objects as files under a root directory.
+type Local struct {
+	root string
+}
+
+// NewLocal creates a local filesystem backend rooted at root.
+func NewLocal(root string) *Local {
+	return &Local{root: root}
+}
+
+// Root returns the directory backing the store.
+func (l *Local) Root() string {
+	return l.root
+}
+
+// Name implements Storage.
+func (l *Local) Name() string {
+	return BackendLocal
+}
+
+// path maps a key onto the filesystem, rejecting keys that escape the root.
+func (l *Local) path(key string) (string, error) {
+	clean := Key(key)
+	if clean == "" || clean == "." || clean == ".." || strings.HasPrefix(clean, "../") {
+		return "", fmt.Errorf("invalid key %q", key)
+	}
+	return filepath.Join(l.root, filepath.FromSlash(clean)), nil
+}
+
+// Put implements Storage. The file is written to a temp name and renamed so
+// readers never see partial content and existing hardlinks are not modified.
+func (l *Local) Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error {
+	p, err := l.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(p), 0755); err != nil {
+		return fmt.Errorf("create directory: %w", err)
+	}
+
+	tmp := p + ".tmp"
+	f, err := os.Create(tmp)
+	if err != nil {
+		return fmt.Errorf("create file: %w", err)
+	}
+	written, err := io.Copy(f, r)
+	if err != nil {
+		f.Close()
+		os.Remove(tmp)
+		return fmt.Errorf("write file: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		os.Remove(tmp)
+		return fmt.Errorf("close file: %w", err)
+	}
+	if size >= 0 && written != size {
+		os.Remove(tmp)
+		return fmt.Errorf("size mismatch: wrote %d bytes, expected %d", written, size)
+	}
+	if err := os.Rename(tmp, p); err != nil {
+		os.Remove(tmp)
+		return fmt.Errorf("rename file: %w", err)
+	}
+	return nil
+}
+
+// Get implements Storage.
+func (l *Local) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	f, _, err := l.open(key)
+	return f, err
+}
+
+// GetRange implements Storage.
+func (l *Local) GetRange(ctx context.Context, key string, offset, length int64) (io.ReadCloser, error) {
+	f, _, err := l.open(key)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := f.Seek(offset, io.SeekStart); err != nil {
+		f.Close()
+		return nil, fmt.Errorf("seek: %w", err)
+	}
+	if length < 0 {
+		return f, nil
+	}
+	return struct {
+		io.Reader
+		io.Closer
+	}{io.LimitReader(f, length), f}, nil
+}
+
+// Stat implements Storage.
+func (l *Local) Stat(ctx context.Context, key string) (ObjectInfo, error) {
+	p, err := l.path(key)
+	if err != nil {
+		return ObjectInfo{}, err
+	}
+	info, err := os.Stat(p)
+	if os.IsNotExist(err) || (err == nil && info.IsDir()) {
+		return ObjectInfo{}, ErrNotFound
+	}
+	if err != nil {
+		return ObjectInfo{}, fmt.Errorf("stat: %w", err)
+	}
+	return ObjectInfo{Key: Key(key), Size: info.Size(), ModTime: info.ModTime()}, nil
+}
+
+// List implements Storage. Only the directory containing the prefix is walked.
+func (l *Local) List(ctx context.Context, prefix string) ([]ObjectInfo, error) {
+	dir := l.root
+	if i := strings.LastIndex(prefix, "/"); i >= 0 {
+		dir = filepath.Join(l.root, filepath.FromSlash(prefix[:i]))
+	}
+
+	var objects []ObjectInfo
+	err := filepath.Walk(dir, func(p string, info os.FileInfo, err error) error {
+		if err != nil {
+			if os.IsNotExist(err) {
+				return nil
+			}
+			return err
+		}
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if info.IsDir() {
+			return nil
+		}
+		rel, err := filepath.Rel(l.root, p)
+		if err != nil {
+			return nil
+		}
+		key := filepath.ToSlash(rel)
+		if strings.HasPrefix(key, prefix) {
+			objects = append(objects, ObjectInfo{Key: key, Size: info.Size(), ModTime: info.ModTime()})
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("list: %w", err)
+	}
+	sort.Slice(objects, func(i, j int) bool { return objects[i].Key < objects[j].Key })
+	return objects, nil
+}
+
+// Delete implements Storage.
+func (l *Local) Delete(ctx context.Context, key string) error {
+	p, err := l.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(p); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("delete: %w", err)
+	}
+	return nil
+}
+
+func (l *Local) open(key string) (*os.File, ObjectInfo, error) {
+	p, err := l.path(key)
+	if err != nil {
+		return nil, ObjectInfo{}, err
+	}
+	f, err := os.Open(p)
+	if os.IsNotExist(err) {
+		return nil, ObjectInfo{}, ErrNotFound
+	}
+	if err != nil {
+		return nil, ObjectInfo{}, fmt.Errorf("open: %w", err)
+	}
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return nil, ObjectInfo{}, fmt.Errorf("stat: %w", err)
+	}
+	if info.IsDir() {
+		f.Close()
+		return nil, ObjectInfo{}, ErrNotFound
+	}
+	return f, ObjectInfo{Key: Key(key), Size: info.Size(), ModTime: info.ModTime()}, nil
+}
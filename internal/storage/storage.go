@@ -0,0 +1,222 @@
+// Package storage abstracts where archive files live.
+//
+// Keys are slash-separated paths relative to the archive root, e.g.
+// "2024/01/user_2024-01-02_123/media/456.mp4". The local implementation maps
+// keys onto a directory; the S3 implementation maps them onto objects in a
+// bucket under an optional prefix.
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/iconidentify/xgrabba/internal/config"
+)
+
+// Backend names accepted by New.
+const (
+	BackendLocal = "local"
+	BackendS3    = "s3"
+)
+
+// ErrNotFound is returned when a key does not exist.
+var ErrNotFound = errors.New("object not found")
+
+// ObjectInfo describes a stored object.
+type ObjectInfo struct {
+	Key         string    `json:"key"`
+	Size        int64     `json:"size"`
+	ModTime     time.Time `json:"mod_time"`
+	ContentType string    `json:"content_type,omitempty"`
+}
+
+// Storage is the archive file backend.
+type Storage interface {
+	// Put stores size bytes from r under key, replacing any existing object.
+	// size may be -1 when unknown.
+	Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error
+	// Get opens the object for reading.
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+	// GetRange opens length bytes starting at offset. A negative length reads to the end.
+	GetRange(ctx context.Context, key string, offset, length int64) (io.ReadCloser, error)
+	// Stat returns object metadata, or ErrNotFound.
+	Stat(ctx context.Context, key string) (ObjectInfo, error)
+	// List returns all objects whose key starts with prefix.
+	List(ctx context.Context, prefix string) ([]ObjectInfo, error)
+	// Delete removes the object. Deleting a missing key is not an error.
+	Delete(ctx context.Context, key string) error
+	// Name identifies the backend in logs and diagnostics.
+	Name() string
+}
+
+// Presigner is implemented by backends that can hand out time-limited URLs
+// so clients fetch objects directly instead of through the server.
+type Presigner interface {
+	PresignGet(key string, expiry time.Duration) (string, error)
+}
+
+// Key joins path elements into a clean storage key.
+func Key(elem ...string) string {
+	return strings.TrimPrefix(path.Join(elem...), "/")
+}
+
+// PutFile uploads a local file under key.
+func PutFile(ctx context.Context, st Storage, key, filePath, contentType string) error {
+	f, err := os.Open(filePath)
+	if err != nil {
+		return fmt.Errorf("open file: %w", err)
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return fmt.Errorf("stat file: %w", err)
+	}
+	return st.Put(ctx, key, f, info.Size(), contentType)
+}
+
+// GetFile downloads key into a local file, replacing it atomically.
+func GetFile(ctx context.Context, st Storage, key, filePath string) error {
+	rc, err := st.Get(ctx, key)
+	if err != nil {
+		return err
+	}
+	defer rc.Close()
+
+	tmp := filePath + ".download"
+	f, err := os.Create(tmp)
+	if err != nil {
+		return fmt.Errorf("create file: %w", err)
+	}
+	if _, err := io.Copy(f, rc); err != nil {
+		f.Close()
+		os.Remove(tmp)
+		return fmt.Errorf("write file: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		os.Remove(tmp)
+		return fmt.Errorf("close file: %w", err)
+	}
+	if err := os.Rename(tmp, filePath); err != nil {
+		os.Remove(tmp)
+		return fmt.Errorf("rename file: %w", err)
+	}
+	return nil
+}
+
+// DeletePrefix removes every object under prefix and returns the number removed.
+func DeletePrefix(ctx context.Context, st Storage, prefix string) (int, error) {
+	objects, err := st.List(ctx, prefix)
+	if err != nil {
+		return 0, err
+	}
+	deleted := 0
+	for _, obj := range objects {
+		if err := st.Delete(ctx, obj.Key); err != nil {
+			return deleted, fmt.Errorf("delete %s: %w", obj.Key, err)
+		}
+		deleted++
+	}
+	return deleted, nil
+}
+
+// Open returns a seekable reader for key, suitable for http.ServeContent.
+// Local files are opened directly; other backends are read with ranged requests.
+func Open(ctx context.Context, st Storage, key string) (io.ReadSeekCloser, ObjectInfo, error) {
+	if l, ok := st.(*Local); ok {
+		f, info, err := l.open(key)
+		if err != nil {
+			return nil, ObjectInfo{}, err
+		}
+		return f, info, nil
+	}
+	info, err := st.Stat(ctx, key)
+	if err != nil {
+		return nil, ObjectInfo{}, err
+	}
+	return &rangeReader{ctx: ctx, st: st, key: key, size: info.Size}, info, nil
+}
+
+// rangeReader adapts GetRange to io.ReadSeeker. Each Seek drops the current
+// body; the next Read opens a new range from the new offset to the end.
+type rangeReader struct {
+	ctx    context.Context
+	st     Storage
+	key    string
+	size   int64
+	offset int64
+	body   io.ReadCloser
+}
+
+func (r *rangeReader) Read(p []byte) (int, error) {
+	if r.offset >= r.size {
+		return 0, io.EOF
+	}
+	if r.body == nil {
+		body, err := r.st.GetRange(r.ctx, r.key, r.offset, -1)
+		if err != nil {
+			return 0, err
+		}
+		r.body = body
+	}
+	n, err := r.body.Read(p)
+	r.offset += int64(n)
+	return n, err
+}
+
+func (r *rangeReader) Seek(offset int64, whence int) (int64, error) {
+	var abs int64
+	switch whence {
+	case io.SeekStart:
+		abs = offset
+	case io.SeekCurrent:
+		abs = r.offset + offset
+	case io.SeekEnd:
+		abs = r.size + offset
+	default:
+		return 0, fmt.Errorf("invalid whence %d", whence)
+	}
+	if abs < 0 {
+		return 0, fmt.Errorf("negative position")
+	}
+	if abs != r.offset && r.body != nil {
+		r.body.Close()
+		r.body = nil
+	}
+	r.offset = abs
+	return abs, nil
+}
+
+func (r *rangeReader) Close() error {
+	if r.body != nil {
+		return r.body.Close()
+	}
+	return nil
+}
+
+// New creates the backend selected by cfg.Backend. The local backend is
+// rooted at cfg.BasePath.
+func New(cfg config.StorageConfig) (Storage, error) {
+	switch cfg.Backend {
+	case "", BackendLocal:
+		return NewLocal(cfg.BasePath), nil
+	case BackendS3:
+		return NewS3(S3Config{
+			Endpoint:  cfg.S3.Endpoint,
+			Region:    cfg.S3.Region,
+			Bucket:    cfg.S3.Bucket,
+			AccessKey: cfg.S3.AccessKey,
+			SecretKey: cfg.S3.SecretKey,
+			Prefix:    cfg.S3.Prefix,
+			PathStyle: cfg.S3.PathStyle,
+		})
+	default:
+		return nil, fmt.Errorf("unknown storage backend %q", cfg.Backend)
+	}
+}
@@ -0,0 +1,150 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// exerciseStorage runs the same contract checks against any backend.
+func exerciseStorage(t *testing.T, st Storage) {
+	t.Helper()
+	ctx := context.Background()
+
+	if err := st.Put(ctx, "2024/01/a/media/1.mp4", strings.NewReader("0123456789"), 10, "video/mp4"); err != nil {
+		t.Fatalf("put: %v", err)
+	}
+	if err := st.Put(ctx, "2024/01/a/tweet.json", strings.NewReader(`{}`), -1, "application/json"); err != nil {
+		t.Fatalf("put unknown size: %v", err)
+	}
+	if err := st.Put(ctx, "2024/01/b/tweet.json", strings.NewReader(`{}`), 2, ""); err != nil {
+		t.Fatalf("put: %v", err)
+	}
+
+	info, err := st.Stat(ctx, "2024/01/a/media/1.mp4")
+	if err != nil || info.Size != 10 {
+		t.Fatalf("stat = %+v, %v", info, err)
+	}
+	if _, err := st.Stat(ctx, "missing"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("stat missing = %v, want ErrNotFound", err)
+	}
+	if _, err := st.Get(ctx, "missing"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("get missing = %v, want ErrNotFound", err)
+	}
+
+	rc, err := st.Get(ctx, "2024/01/a/media/1.mp4")
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, _ := io.ReadAll(rc)
+	rc.Close()
+	if string(data) != "0123456789" {
+		t.Errorf("get = %q", data)
+	}
+
+	rc, err = st.GetRange(ctx, "2024/01/a/media/1.mp4", 3, 4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, _ = io.ReadAll(rc)
+	rc.Close()
+	if string(data) != "3456" {
+		t.Errorf("range = %q, want 3456", data)
+	}
+
+	objects, err := st.List(ctx, "2024/01/a/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(objects) != 2 || objects[0].Key != "2024/01/a/media/1.mp4" || objects[1].Key != "2024/01/a/tweet.json" {
+		t.Errorf("list = %+v", objects)
+	}
+
+	n, err := DeletePrefix(ctx, st, "2024/01/a/")
+	if err != nil || n != 2 {
+		t.Fatalf("delete prefix = %d, %v", n, err)
+	}
+	if _, err := st.Stat(ctx, "2024/01/a/tweet.json"); !errors.Is(err, ErrNotFound) {
+		t.Error("object should be deleted")
+	}
+	if _, err := st.Stat(ctx, "2024/01/b/tweet.json"); err != nil {
+		t.Error("other archive should be kept")
+	}
+	if err := st.Delete(ctx, "2024/01/a/tweet.json"); err != nil {
+		t.Errorf("deleting missing key should succeed: %v", err)
+	}
+}
+
+func TestLocal(t *testing.T) {
+	exerciseStorage(t, NewLocal(t.TempDir()))
+}
+
+func TestLocal_RejectsTraversal(t *testing.T) {
+	st := NewLocal(t.TempDir())
+	if err := st.Put(context.Background(), "../escape", strings.NewReader("x"), 1, ""); err == nil {
+		t.Error("expected error for key outside root")
+	}
+}
+
+func TestLocal_PutDoesNotModifyHardlinks(t *testing.T) {
+	root := t.TempDir()
+	st := NewLocal(root)
+	original := filepath.Join(root, "a")
+	os.WriteFile(original, []byte("old"), 0644)
+	os.Link(original, filepath.Join(root, "b"))
+
+	if err := st.Put(context.Background(), "b", strings.NewReader("new"), 3, ""); err != nil {
+		t.Fatal(err)
+	}
+	data, _ := os.ReadFile(original)
+	if string(data) != "old" {
+		t.Errorf("hardlinked file changed to %q", data)
+	}
+}
+
+func TestOpen_ServeContentRanges(t *testing.T) {
+	fake := newFakeS3(t)
+	st := fake.storage(t)
+	ctx := context.Background()
+	if err := st.Put(ctx, "v.mp4", strings.NewReader("abcdefghij"), 10, "video/mp4"); err != nil {
+		t.Fatal(err)
+	}
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		f, info, err := Open(r.Context(), st, "v.mp4")
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		defer f.Close()
+		http.ServeContent(w, r, "v.mp4", info.ModTime, f)
+	}))
+	defer srv.Close()
+
+	req, _ := http.NewRequest(http.MethodGet, srv.URL, nil)
+	req.Header.Set("Range", "bytes=2-5")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode != http.StatusPartialContent || string(body) != "cdef" {
+		t.Errorf("status %d body %q", resp.StatusCode, body)
+	}
+}
+
+func TestKey(t *testing.T) {
+	if got := Key("2024", "01", "a/media", "1.jpg"); got != "2024/01/a/media/1.jpg" {
+		t.Errorf("Key = %q", got)
+	}
+	if got := Key("/a//b/"); got != "a/b" {
+		t.Errorf("Key = %q", got)
+	}
+}
@@ -0,0 +1,104 @@
+package ffmpeg
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// Chapter is a titled section of a video. Start and End are in seconds.
+type Chapter struct {
+	Start float64
+	End   float64
+	Title string
+}
+
+// EmbedChapters writes a copy of videoPath to outPath with chapters in its
+// container metadata so players show a chapter menu; no chapters removes any
+// existing ones. Streams are copied, not re-encoded, and videoPath is left
+// untouched. outPath is replaced only once the remux succeeds.
+func (p *VideoProcessor) EmbedChapters(ctx context.Context, videoPath, outPath string, chapters []Chapter) error {
+	dir := filepath.Dir(outPath)
+	args := []string{"-i", videoPath}
+
+	if len(chapters) > 0 {
+		meta, err := os.CreateTemp(dir, ".chapters-*.txt")
+		if err != nil {
+			return fmt.Errorf("create chapter metadata: %w", err)
+		}
+		defer os.Remove(meta.Name())
+		if _, err := meta.WriteString(buildFFMetadata(chapters)); err != nil {
+			meta.Close()
+			return fmt.Errorf("write chapter metadata: %w", err)
+		}
+		if err := meta.Close(); err != nil {
+			return fmt.Errorf("write chapter metadata: %w", err)
+		}
+		args = append(args, "-f", "ffmetadata", "-i", meta.Name(), "-map_chapters", "1")
+	} else {
+		args = append(args, "-map_chapters", "-1")
+	}
+
+	out, err := os.CreateTemp(dir, ".chapters-*"+filepath.Ext(outPath))
+	if err != nil {
+		return fmt.Errorf("create output file: %w", err)
+	}
+	out.Close()
+	defer os.Remove(out.Name())
+
+	args = append(args,
+		"-map", "0",
+		"-map_metadata", "0",
+		"-codec", "copy",
+		"-y", out.Name(),
+	)
+	cmd := exec.CommandContext(ctx, p.ffmpegPath, args...)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("embed chapters: %w: %s", err, lastLine(string(output)))
+	}
+
+	if err := os.Rename(out.Name(), outPath); err != nil {
+		return fmt.Errorf("write chapters video: %w", err)
+	}
+	return nil
+}
+
+// buildFFMetadata renders chapters in ffmpeg's FFMETADATA1 format with
+// millisecond timestamps.
+func buildFFMetadata(chapters []Chapter) string {
+	var sb strings.Builder
+	sb.WriteString(";FFMETADATA1\n")
+	for _, ch := range chapters {
+		sb.WriteString("\n[CHAPTER]\nTIMEBASE=1/1000\n")
+		fmt.Fprintf(&sb, "START=%d\n", int64(math.Round(ch.Start*1000)))
+		fmt.Fprintf(&sb, "END=%d\n", int64(math.Round(ch.End*1000)))
+		fmt.Fprintf(&sb, "title=%s\n", escapeFFMetadata(ch.Title))
+	}
+	return sb.String()
+}
+
+// escapeFFMetadata escapes the characters FFMETADATA treats as special.
+func escapeFFMetadata(s string) string {
+	var sb strings.Builder
+	for _, r := range s {
+		switch r {
+		case '=', ';', '#', '\\':
+			sb.WriteRune('\\')
+		case '\n', '\r':
+			r = ' '
+		}
+		sb.WriteRune(r)
+	}
+	return sb.String()
+}
+
+// lastLine returns the last non-empty line of ffmpeg output, which usually
+// holds the error.
+func lastLine(output string) string {
+	lines := strings.Split(strings.TrimSpace(output), "\n")
+	return lines[len(lines)-1]
+}
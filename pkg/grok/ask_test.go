@@ -0,0 +1,110 @@
+package grok
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/iconidentify/xgrabba/internal/config"
+)
+
+func TestDefaultPrompts_RenderAsk(t *testing.T) {
+	got, err := DefaultPrompts().RenderAsk(AskRequest{
+		Question: "Why do cats purr?",
+		Sources: []AskSource{
+			{TweetID: "1", Author: "alice", PostedAt: "2024-05-01", Text: "Cats purr when calm."},
+			{TweetID: "2", Author: "bob", Text: "[01:15] Purring heals bones."},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "Question: Why do cats purr?\n\nArchived posts, most relevant first:\n" +
+		"\n=== Post 1 by @alice on 2024-05-01 ===\nCats purr when calm.\n" +
+		"\n=== Post 2 by @bob ===\n[01:15] Purring heals bones.\n"
+	if got.User != want {
+		t.Errorf("ask user prompt = %q, want %q", got.User, want)
+	}
+	if !strings.Contains(got.System, "[tweet:ID@mm:ss]") || got.Task != config.AITaskAsk {
+		t.Errorf("ask prompt = %+v", got)
+	}
+}
+
+// streamServer replies to chat requests with an SSE stream of chunks and
+// records whether streaming was requested.
+func streamServer(t *testing.T, chunks []string, stream *bool) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req chatRequest
+		json.NewDecoder(r.Body).Decode(&req)
+		*stream = req.Stream
+		w.Header().Set("Content-Type", "text/event-stream")
+		for _, c := range chunks {
+			data, _ := json.Marshal(map[string]interface{}{
+				"choices": []map[string]interface{}{{"delta": map[string]string{"content": c}}},
+			})
+			fmt.Fprintf(w, "data: %s\n\n", data)
+		}
+		fmt.Fprint(w, "data: {\"choices\":[],\"usage\":{\"prompt_tokens\":50,\"completion_tokens\":4}}\n\n")
+		fmt.Fprint(w, "data: [DONE]\n\n")
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestHTTPClient_AskStreams(t *testing.T) {
+	var stream bool
+	server := streamServer(t, []string{"Cats purr ", "[tweet:1]."}, &stream)
+	var log usageLog
+	client := &HTTPClient{model: "grok-3", baseURL: server.URL, httpClient: &http.Client{Timeout: 5 * time.Second}}
+	client.SetUsageRecorder(&log)
+	client.SetCache(NewResponseCache(t.TempDir(), 0))
+	req := AskRequest{Question: "Why?", Sources: []AskSource{{TweetID: "1", Author: "alice", Text: "Cats purr."}}}
+
+	var deltas []string
+	resp, err := client.Ask(context.Background(), req, func(text string) { deltas = append(deltas, text) })
+	if err != nil {
+		t.Fatalf("Ask() error = %v", err)
+	}
+	if !stream || len(deltas) != 2 || resp.Answer != "Cats purr [tweet:1]." {
+		t.Errorf("stream=%v deltas=%q answer=%q", stream, deltas, resp.Answer)
+	}
+	want := Usage{Provider: "grok", Model: "grok-3", Task: config.AITaskAsk, PromptTokens: 50, CompletionTokens: 4}
+	if len(log) != 1 || log[0] != want {
+		t.Errorf("usage = %+v, want [%+v]", log, want)
+	}
+
+	// A repeated question is answered from the cache in one piece
+	server.Close()
+	deltas = nil
+	resp, err = client.Ask(context.Background(), req, func(text string) { deltas = append(deltas, text) })
+	if err != nil || len(deltas) != 1 || deltas[0] != "Cats purr [tweet:1]." {
+		t.Errorf("cached Ask() = %+v, %v with deltas %q", resp, err, deltas)
+	}
+}
+
+func TestHTTPClient_AskWithoutStreaming(t *testing.T) {
+	var user string
+	server := translationServer(t, "Cats purr [tweet:1].", &user)
+	client := &HTTPClient{baseURL: server.URL, httpClient: &http.Client{Timeout: 5 * time.Second}}
+
+	resp, err := client.Ask(context.Background(), AskRequest{
+		Question: "Why?",
+		Sources:  []AskSource{{TweetID: "1", Author: "alice", Text: "Cats purr."}},
+	}, nil)
+	if err != nil || resp.Answer != "Cats purr [tweet:1]." {
+		t.Errorf("Ask() = %+v, %v", resp, err)
+	}
+	if !strings.Contains(user, "=== Post 1 by @alice ===") {
+		t.Errorf("sources not sent: %q", user)
+	}
+
+	if _, err := client.Ask(context.Background(), AskRequest{Question: "Why?"}, nil); err == nil {
+		t.Error("Ask() without sources should fail")
+	}
+}
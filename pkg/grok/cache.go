@@ -0,0 +1,275 @@
+package grok
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// PromptVersion is mixed into every cache key. Bump it when response parsing
+// changes so responses cached for the old parser are not reused. Template
+// changes need no bump, since the rendered prompt is part of the key.
+const PromptVersion = 1
+
+// ResponseCache stores successful chat responses on disk, keyed by a hash of
+// the prompt version, provider, task, model and the full request, which
+// includes the prompt text and the bytes of any attached images.
+type ResponseCache struct {
+	dir string
+	ttl time.Duration // Zero keeps entries until invalidated
+	now func() time.Time
+
+	mu     sync.Mutex
+	counts map[string]*CacheCounts // Keyed by task
+}
+
+// CacheCounts are lookups since startup.
+type CacheCounts struct {
+	Hits   int `json:"hits"`
+	Misses int `json:"misses"`
+}
+
+// HitRate returns the fraction of lookups served from the cache.
+func (c CacheCounts) HitRate() float64 {
+	if c.Hits+c.Misses == 0 {
+		return 0
+	}
+	return float64(c.Hits) / float64(c.Hits+c.Misses)
+}
+
+// CacheStats describes the cache contents and its hit rate since startup.
+type CacheStats struct {
+	Entries int                    `json:"entries"`
+	Bytes   int64                  `json:"bytes"`
+	TTL     string                 `json:"ttl,omitempty"`
+	Hits    int                    `json:"hits"`
+	Misses  int                    `json:"misses"`
+	HitRate float64                `json:"hit_rate"`
+	ByTask  map[string]CacheCounts `json:"by_task,omitempty"`
+}
+
+// CacheFilter selects cache entries to invalidate. Empty fields match everything.
+type CacheFilter struct {
+	Task      string
+	Model     string
+	OlderThan time.Duration
+}
+
+// cacheEntry is the on-disk form of a cached response.
+type cacheEntry struct {
+	CreatedAt time.Time `json:"created_at"`
+	Provider  string    `json:"provider"`
+	Task      string    `json:"task"`
+	Model     string    `json:"model"`
+	Response  string    `json:"response"`
+}
+
+// NewResponseCache creates a cache stored under dir.
+func NewResponseCache(dir string, ttl time.Duration) *ResponseCache {
+	return &ResponseCache{
+		dir:    dir,
+		ttl:    ttl,
+		now:    time.Now,
+		counts: make(map[string]*CacheCounts),
+	}
+}
+
+// SetCache serves repeated requests from cache instead of the API.
+func (c *HTTPClient) SetCache(cache *ResponseCache) {
+	c.cache = cache
+}
+
+// SetCache enables the response cache on every routed provider that supports it.
+func (r *Router) SetCache(cache *ResponseCache) {
+	for _, providers := range r.routes {
+		for _, p := range providers {
+			if c, ok := p.Client.(interface{ SetCache(*ResponseCache) }); ok {
+				c.SetCache(cache)
+			}
+		}
+	}
+}
+
+// cacheKey hashes everything that determines a response.
+func cacheKey(provider, task string, req chatRequest) (string, error) {
+	body, err := json.Marshal(req)
+	if err != nil {
+		return "", err
+	}
+	h := sha256.New()
+	fmt.Fprintf(h, "v%d\x00%s\x00%s\x00", PromptVersion, provider, task)
+	h.Write(body)
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+func (c *ResponseCache) path(key string) string {
+	return filepath.Join(c.dir, key[:2], key+".json")
+}
+
+// get returns the cached response for key, counting the lookup against task.
+func (c *ResponseCache) get(key, task string) (string, bool) {
+	entry, err := readCacheEntry(c.path(key))
+	hit := err == nil && !c.expired(entry)
+	if err == nil && !hit {
+		os.Remove(c.path(key))
+	}
+
+	c.mu.Lock()
+	counts, ok := c.counts[task]
+	if !ok {
+		counts = &CacheCounts{}
+		c.counts[task] = counts
+	}
+	if hit {
+		counts.Hits++
+	} else {
+		counts.Misses++
+	}
+	c.mu.Unlock()
+
+	if !hit {
+		return "", false
+	}
+	return entry.Response, true
+}
+
+// put stores a response. Failures only cost a future cache miss, so they are
+// not reported.
+func (c *ResponseCache) put(key string, entry cacheEntry) {
+	entry.CreatedAt = c.now().UTC()
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return
+	}
+	path := c.path(key)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		os.Remove(tmp)
+	}
+}
+
+// drop deletes the entry for key, e.g. a reply that failed validation.
+func (c *ResponseCache) drop(key string) {
+	os.Remove(c.path(key))
+}
+
+func (c *ResponseCache) expired(entry *cacheEntry) bool {
+	return c.ttl > 0 && c.now().Sub(entry.CreatedAt) > c.ttl
+}
+
+// Invalidate deletes the entries matching filter and returns how many were removed.
+func (c *ResponseCache) Invalidate(filter CacheFilter) (int, error) {
+	now := c.now()
+	return c.remove(func(entry *cacheEntry) bool {
+		if filter.Task != "" && entry.Task != filter.Task {
+			return false
+		}
+		if filter.Model != "" && entry.Model != filter.Model {
+			return false
+		}
+		return filter.OlderThan <= 0 || now.Sub(entry.CreatedAt) > filter.OlderThan
+	})
+}
+
+// Prune deletes expired entries and returns how many were removed.
+func (c *ResponseCache) Prune() (int, error) {
+	if c.ttl <= 0 {
+		return 0, nil
+	}
+	return c.remove(c.expired)
+}
+
+// remove deletes entries for which match returns true. Unreadable entries are
+// always removed.
+func (c *ResponseCache) remove(match func(*cacheEntry) bool) (int, error) {
+	removed := 0
+	err := c.walk(func(path string, entry *cacheEntry, _ int64) {
+		if entry == nil || match(entry) {
+			if os.Remove(path) == nil {
+				removed++
+			}
+		}
+	})
+	return removed, err
+}
+
+// walk calls fn for every entry file; entry is nil when the file is unreadable.
+func (c *ResponseCache) walk(fn func(path string, entry *cacheEntry, size int64)) error {
+	err := filepath.WalkDir(c.dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || !strings.HasSuffix(path, ".json") {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return nil
+		}
+		entry, err := readCacheEntry(path)
+		if err != nil {
+			entry = nil
+		}
+		fn(path, entry, info.Size())
+		return nil
+	})
+	if os.IsNotExist(err) {
+		return nil
+	}
+	return err
+}
+
+// Stats reports the cache size and hit rates since startup.
+func (c *ResponseCache) Stats() CacheStats {
+	var stats CacheStats
+	c.walk(func(_ string, entry *cacheEntry, size int64) {
+		if entry != nil && !c.expired(entry) {
+			stats.Entries++
+			stats.Bytes += size
+		}
+	})
+	if c.ttl > 0 {
+		stats.TTL = c.ttl.String()
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if len(c.counts) > 0 {
+		stats.ByTask = make(map[string]CacheCounts, len(c.counts))
+	}
+	var total CacheCounts
+	for task, counts := range c.counts {
+		stats.ByTask[task] = *counts
+		total.Hits += counts.Hits
+		total.Misses += counts.Misses
+	}
+	stats.Hits = total.Hits
+	stats.Misses = total.Misses
+	stats.HitRate = total.HitRate()
+	return stats
+}
+
+func readCacheEntry(path string) (*cacheEntry, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var entry cacheEntry
+	if err := json.Unmarshal(data, &entry); err != nil {
+		return nil, err
+	}
+	return &entry, nil
+}
@@ -0,0 +1,160 @@
+package grok
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/iconidentify/xgrabba/internal/config"
+)
+
+// newCachedTestClient returns a client backed by a server that counts requests.
+func newCachedTestClient(t *testing.T, ttl time.Duration) (*HTTPClient, *ResponseCache, *int) {
+	t.Helper()
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"choices": []map[string]interface{}{
+				{"message": map[string]string{"content": `{"summary":"cats","tags":["cats"]}`}},
+			},
+		})
+	}))
+	t.Cleanup(server.Close)
+
+	cache := NewResponseCache(t.TempDir(), ttl)
+	client := &HTTPClient{
+		apiKey:     "test-key",
+		model:      "grok-2",
+		baseURL:    server.URL,
+		httpClient: &http.Client{Timeout: 5 * time.Second},
+	}
+	client.SetCache(cache)
+	return client, cache, &calls
+}
+
+func TestResponseCache_ReusesIdenticalRequests(t *testing.T) {
+	client, cache, calls := newCachedTestClient(t, 0)
+	ctx := context.Background()
+	req := ContentAnalysisRequest{TweetText: "a cat", AuthorUsername: "someone"}
+
+	for i := 0; i < 2; i++ {
+		resp, err := client.AnalyzeContent(ctx, req)
+		if err != nil {
+			t.Fatalf("AnalyzeContent() error = %v", err)
+		}
+		if resp.Summary != "cats" {
+			t.Errorf("summary = %q, want cats", resp.Summary)
+		}
+	}
+	if *calls != 1 {
+		t.Errorf("API calls = %d, want 1", *calls)
+	}
+
+	req.TweetText = "a dog"
+	if _, err := client.AnalyzeContent(ctx, req); err != nil {
+		t.Fatal(err)
+	}
+	if *calls != 2 {
+		t.Errorf("API calls after a new prompt = %d, want 2", *calls)
+	}
+
+	stats := cache.Stats()
+	if stats.Entries != 2 || stats.Hits != 1 || stats.Misses != 2 {
+		t.Errorf("stats = %+v, want 2 entries, 1 hit, 2 misses", stats)
+	}
+	if got := stats.ByTask[config.AITaskAnalysis].HitRate(); got < 0.33 || got > 0.34 {
+		t.Errorf("analysis hit rate = %f, want 1/3", got)
+	}
+}
+
+func TestResponseCache_KeyIncludesImageBytes(t *testing.T) {
+	client, _, calls := newCachedTestClient(t, 0)
+	img := filepath.Join(t.TempDir(), "photo.jpg")
+	req := VisionAnalysisRequest{TweetText: "look", ImagePaths: []string{img}}
+
+	os.WriteFile(img, []byte("first image"), 0644)
+	client.AnalyzeContentWithVision(context.Background(), req)
+	client.AnalyzeContentWithVision(context.Background(), req)
+	os.WriteFile(img, []byte("second image"), 0644)
+	client.AnalyzeContentWithVision(context.Background(), req)
+
+	if *calls != 2 {
+		t.Errorf("API calls = %d, want 2 (one per distinct image)", *calls)
+	}
+}
+
+func TestResponseCache_TTLAndInvalidate(t *testing.T) {
+	client, cache, calls := newCachedTestClient(t, time.Hour)
+	now := time.Date(2024, 3, 10, 12, 0, 0, 0, time.UTC)
+	cache.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	client.AnalyzeContent(ctx, ContentAnalysisRequest{TweetText: "one"})
+	client.GenerateFilename(ctx, FilenameRequest{TweetText: "one"})
+
+	now = now.Add(2 * time.Hour)
+	client.AnalyzeContent(ctx, ContentAnalysisRequest{TweetText: "one"})
+	if *calls != 3 {
+		t.Errorf("API calls = %d, want 3 after the entry expired", *calls)
+	}
+	if n, err := cache.Prune(); err != nil || n != 1 {
+		t.Errorf("Prune() = %d, %v; want the expired filename entry removed", n, err)
+	}
+
+	client.GenerateFilename(ctx, FilenameRequest{TweetText: "one"})
+	if n, err := cache.Invalidate(CacheFilter{Task: config.AITaskFilename}); err != nil || n != 1 {
+		t.Errorf("Invalidate(filename) = %d, %v; want 1", n, err)
+	}
+	if got := cache.Stats().Entries; got != 1 {
+		t.Errorf("entries = %d, want only the analysis entry", got)
+	}
+	if n, _ := cache.Invalidate(CacheFilter{}); n != 1 {
+		t.Errorf("Invalidate(all) = %d, want 1", n)
+	}
+}
+
+func TestResponseCache_SkipsInvalidReplies(t *testing.T) {
+	replies := []string{`not json`, `{"source_language":"es","translations":["hola"]}`}
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		reply := replies[calls]
+		calls++
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"choices": []map[string]interface{}{
+				{"message": map[string]string{"content": reply}},
+			},
+		})
+	}))
+	defer server.Close()
+
+	client := &HTTPClient{
+		apiKey:     "test-key",
+		model:      "grok-2",
+		baseURL:    server.URL,
+		httpClient: &http.Client{Timeout: 5 * time.Second},
+	}
+	client.SetCache(NewResponseCache(t.TempDir(), 0))
+	req := TranslationRequest{TargetLanguage: "en", Texts: []string{"hola"}}
+
+	if _, err := client.Translate(context.Background(), req); err == nil {
+		t.Fatal("expected an error for an unparseable reply")
+	}
+	// The bad reply was not cached, so the retry reaches the API
+	resp, err := client.Translate(context.Background(), req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if calls != 2 || resp.Texts[0] != "hola" {
+		t.Errorf("calls = %d, texts = %v", calls, resp.Texts)
+	}
+	// The good reply is served from the cache
+	if _, err := client.Translate(context.Background(), req); err != nil || calls != 2 {
+		t.Errorf("cached retry: err = %v, calls = %d", err, calls)
+	}
+}
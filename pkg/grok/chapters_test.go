@@ -0,0 +1,45 @@
+package grok
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestHTTPClient_GenerateChapters(t *testing.T) {
+	var user string
+	server := translationServer(t, "```json\n{\"chapters\":[{\"start\":\"00:00\",\"title\":\"Intro\"},{\"start\":\"1:02:05\",\"title\":\" Wrap up \"},{\"start\":90.5,\"title\":\"Cats\"}]}\n```", &user)
+	client := &HTTPClient{baseURL: server.URL, httpClient: &http.Client{Timeout: 5 * time.Second}}
+
+	result, err := client.GenerateChapters(context.Background(), ChaptersRequest{
+		Transcript: "[00:00] Hello\n[01:30] Cats",
+		Duration:   "62:10",
+	})
+	if err != nil {
+		t.Fatalf("GenerateChapters failed: %v", err)
+	}
+	want := []ChapterItem{{0, "Intro"}, {3725, "Wrap up"}, {90.5, "Cats"}}
+	if len(result.Chapters) != len(want) {
+		t.Fatalf("chapters = %+v", result.Chapters)
+	}
+	for i, ch := range result.Chapters {
+		if ch != want[i] {
+			t.Errorf("chapter %d = %+v, want %+v", i, ch, want[i])
+		}
+	}
+	if !strings.Contains(user, "Video length: 62:10") || !strings.Contains(user, "[01:30] Cats") {
+		t.Errorf("user prompt = %q", user)
+	}
+
+	bad := translationServer(t, `{"chapters":[{"start":"soon","title":"Intro"}]}`, nil)
+	client.baseURL = bad.URL
+	if _, err := client.GenerateChapters(context.Background(), ChaptersRequest{Transcript: "[00:00] Hello"}); err == nil {
+		t.Error("invalid chapter start should fail")
+	}
+
+	if _, err := client.GenerateChapters(context.Background(), ChaptersRequest{}); err == nil {
+		t.Error("GenerateChapters without a transcript should fail")
+	}
+}
@@ -10,13 +10,15 @@ import (
 	"net/http"
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/iconidentify/xgrabba/internal/config"
 )
 
-// Client interfaces with Grok AI for filename generation and content analysis.
+// Client generates filenames, content analysis, essays and translations, and
+// answers questions about the archive, with an AI backend.
 type Client interface {
 	// GenerateFilename creates a descriptive filename based on video metadata.
 	GenerateFilename(ctx context.Context, req FilenameRequest) (string, error)
@@ -26,6 +28,16 @@ type Client interface {
 	AnalyzeContentWithVision(ctx context.Context, req VisionAnalysisRequest) (*ContentAnalysisResponse, error)
 	// GenerateEssay creates a high-quality markdown essay from a video transcript.
 	GenerateEssay(ctx context.Context, req EssayRequest) (*EssayResponse, error)
+	// Translate translates a batch of texts into another language.
+	Translate(ctx context.Context, req TranslationRequest) (*TranslationResponse, error)
+	// GenerateDocument creates a derived markdown document, such as notes or
+	// flashcards, from one or more tweets.
+	GenerateDocument(ctx context.Context, req DocumentRequest) (*DocumentResponse, error)
+	// GenerateChapters splits a timed video transcript into titled chapters.
+	GenerateChapters(ctx context.Context, req ChaptersRequest) (*ChaptersResponse, error)
+	// Ask answers a question from archived tweets. When onDelta is set, the
+	// answer is streamed to it as it is generated.
+	Ask(ctx context.Context, req AskRequest, onDelta func(string)) (*AskResponse, error)
 }
 
 // ContentAnalysisRequest contains information for analyzing tweet content.
@@ -46,6 +58,7 @@ type VisionAnalysisRequest struct {
 	VideoThumbPath string   // Video thumbnail path for video analysis
 	HasVideo       bool
 	VideoDuration  int
+	WantAltText    bool // Also describe the media for screen readers (alt_text)
 }
 
 // ContentAnalysisResponse contains AI-generated analysis of tweet content.
@@ -54,6 +67,7 @@ type ContentAnalysisResponse struct {
 	Tags        []string // Searchable keywords/tags
 	ContentType string   // e.g., "documentary", "news", "comedy", "sports", etc.
 	Topics      []string // Main topics discussed or shown
+	AltText     string   `json:"alt_text"` // Accessibility description, when requested
 }
 
 // EssayRequest contains information for generating an essay from a transcript.
@@ -70,6 +84,87 @@ type EssayResponse struct {
 	WordCount int    // Word count of the essay
 }
 
+// TranslationRequest contains a batch of texts to translate.
+type TranslationRequest struct {
+	TargetLanguage string   // ISO-639-1 code to translate into
+	SourceLanguage string   // ISO-639-1 code of the texts - optional hint
+	Texts          []string // Translated independently, in order
+}
+
+// TranslationResponse contains the translated texts.
+type TranslationResponse struct {
+	SourceLanguage string   // Language the model detected (ISO-639-1)
+	Texts          []string // One per request text, in the same order
+}
+
+// Derived document types. Each has its own document_<type>_system prompt.
+const (
+	DocumentTLDR       = "tldr"       // A few sentences with the gist
+	DocumentNotes      = "notes"      // Bullet notes of the key points
+	DocumentFAQ        = "faq"        // Questions and answers
+	DocumentFlashcards = "flashcards" // Study flashcards
+	DocumentOutline    = "outline"    // Chapter outline with timestamps
+	DocumentDigest     = "digest"     // Combined digest of several tweets
+)
+
+// DocumentRequest contains the sources for generating a derived document.
+type DocumentRequest struct {
+	Type    string           // One of the Document* types
+	Title   string           // Name of the thread or playlist - optional
+	Sources []DocumentSource // Tweets to derive the document from, in order
+}
+
+// DocumentSource is one tweet a document is derived from.
+type DocumentSource struct {
+	Author     string
+	PostedAt   string // Date the tweet was posted (YYYY-MM-DD) - optional
+	Text       string // Tweet text or article body
+	Transcript string // Video transcript, one "[mm:ss] text" line per segment when timed
+}
+
+// DocumentResponse contains the generated document.
+type DocumentResponse struct {
+	Title     string // Document title
+	Content   string // Markdown document
+	WordCount int    // Word count of the content
+}
+
+// ChaptersRequest contains a timed transcript to split into chapters.
+type ChaptersRequest struct {
+	Transcript string // One "[mm:ss] text" line per transcript segment
+	Duration   string // Length of the video as mm:ss
+}
+
+// ChaptersResponse contains the generated chapters in order.
+type ChaptersResponse struct {
+	Chapters []ChapterItem
+}
+
+// ChapterItem is one chapter of a video.
+type ChapterItem struct {
+	Start float64 // Offset in seconds where the chapter begins
+	Title string
+}
+
+// AskRequest contains a question and the archived tweets to answer it from.
+type AskRequest struct {
+	Question string
+	Sources  []AskSource // Most relevant first
+}
+
+// AskSource is one archived tweet offered as evidence for an answer.
+type AskSource struct {
+	TweetID  string
+	Author   string
+	PostedAt string // Date the tweet was posted (YYYY-MM-DD) - optional
+	Text     string // Relevant excerpts; transcript lines start with "[mm:ss]"
+}
+
+// AskResponse contains the answer.
+type AskResponse struct {
+	Answer string // Markdown citing tweets as [tweet:ID] or [tweet:ID@mm:ss]
+}
+
 // FilenameRequest contains information for generating a filename.
 type FilenameRequest struct {
 	TweetText      string
@@ -79,12 +174,19 @@ type FilenameRequest struct {
 	Duration       int
 }
 
-// HTTPClient implements Client using HTTP requests to the Grok API.
+// HTTPClient implements Client against an OpenAI-compatible chat completions
+// API: the Grok API itself, or local servers such as Ollama, llama.cpp and vLLM.
 type HTTPClient struct {
-	apiKey     string
-	baseURL    string
-	model      string
-	httpClient *http.Client
+	name        string // Provider name used in errors; "Grok" when empty
+	apiKey      string
+	baseURL     string
+	model       string
+	visionModel string // Empty selects the Grok vision default
+	essayModel  string // Empty selects the Grok essay default
+	httpClient  *http.Client
+	usage       UsageRecorder  // Optional; see SetUsageRecorder
+	cache       *ResponseCache // Optional; see SetCache
+	prompts     *Prompts       // Nil uses DefaultPrompts; see SetPrompts
 }
 
 // NewClient creates a new Grok API client.
@@ -99,10 +201,36 @@ func NewClient(cfg config.GrokConfig) *HTTPClient {
 	}
 }
 
-// chatRequest is the request body for the Grok chat API.
+// NewOpenAIClient creates a client for any OpenAI-compatible chat backend.
+// The vision and essay models default to the main model.
+func NewOpenAIClient(cfg config.OpenAIConfig) *HTTPClient {
+	visionModel := cfg.VisionModel
+	if visionModel == "" {
+		visionModel = cfg.Model
+	}
+	essayModel := cfg.EssayModel
+	if essayModel == "" {
+		essayModel = cfg.Model
+	}
+	return &HTTPClient{
+		name:        "openai",
+		apiKey:      cfg.APIKey,
+		baseURL:     strings.TrimSuffix(cfg.BaseURL, "/"),
+		model:       cfg.Model,
+		visionModel: visionModel,
+		essayModel:  essayModel,
+		httpClient: &http.Client{
+			Timeout: cfg.Timeout,
+		},
+	}
+}
+
+// chatRequest is the request body for the chat completions API.
 type chatRequest struct {
-	Model    string        `json:"model"`
-	Messages []chatMessage `json:"messages"`
+	Model         string         `json:"model"`
+	Messages      []chatMessage  `json:"messages"`
+	Stream        bool           `json:"stream,omitempty"`
+	StreamOptions *streamOptions `json:"stream_options,omitempty"`
 }
 
 type chatMessage struct {
@@ -122,35 +250,40 @@ type imageURL struct {
 	Detail string `json:"detail,omitempty"` // "low", "high", or "auto"
 }
 
-// chatResponse is the response from the Grok chat API.
+// chatResponse is the response from the chat completions API.
 type chatResponse struct {
 	Choices []struct {
 		Message struct {
 			Content string `json:"content"`
 		} `json:"message"`
 	} `json:"choices"`
+	Usage *struct {
+		PromptTokens     int `json:"prompt_tokens"`
+		CompletionTokens int `json:"completion_tokens"`
+	} `json:"usage,omitempty"`
 	Error *struct {
 		Message string `json:"message"`
 		Type    string `json:"type"`
 	} `json:"error,omitempty"`
 }
 
-// GenerateFilename creates a descriptive filename based on video metadata.
-func (c *HTTPClient) GenerateFilename(ctx context.Context, req FilenameRequest) (string, error) {
-	prompt := buildFilenamePrompt(req)
-
-	chatReq := chatRequest{
-		Model: c.model,
-		Messages: []chatMessage{
-			{
-				Role:    "system",
-				Content: "You are a helpful assistant that generates concise, descriptive filenames for archived videos. Return ONLY the filename without any extension, explanation, or surrounding text. Use underscores instead of spaces. Keep filenames under 50 characters.",
-			},
-			{
-				Role:    "user",
-				Content: prompt,
-			},
-		},
+// complete sends a chat request for task and returns the content of the first
+// choice. Token usage is reported to the usage recorder when one is set, and
+// repeated requests are answered from the response cache when one is set.
+// validate, when set, checks a reply before it is returned or cached, so a
+// reply the caller cannot use is never served again from the cache.
+func (c *HTTPClient) complete(ctx context.Context, task string, chatReq chatRequest, validate func(reply string) error) (string, error) {
+	var key string
+	if c.cache != nil {
+		if k, err := cacheKey(c.providerName(), task, chatReq); err == nil {
+			if reply, ok := c.cache.get(k, task); ok {
+				if validate == nil || validate(reply) == nil {
+					return reply, nil
+				}
+				c.cache.drop(k)
+			}
+			key = k
+		}
 	}
 
 	body, err := json.Marshal(chatReq)
@@ -164,7 +297,10 @@ func (c *HTTPClient) GenerateFilename(ctx context.Context, req FilenameRequest)
 	}
 
 	httpReq.Header.Set("Content-Type", "application/json")
-	httpReq.Header.Set("Authorization", "Bearer "+c.apiKey)
+	// Local servers usually run without authentication
+	if c.apiKey != "" {
+		httpReq.Header.Set("Authorization", "Bearer "+c.apiKey)
+	}
 
 	resp, err := c.httpClient.Do(httpReq)
 	if err != nil {
@@ -191,29 +327,118 @@ func (c *HTTPClient) GenerateFilename(ctx context.Context, req FilenameRequest)
 	}
 
 	if len(chatResp.Choices) == 0 {
-		return "", fmt.Errorf("no response from Grok")
+		return "", fmt.Errorf("no response from %s", c.providerName())
 	}
 
-	filename := sanitizeFilename(chatResp.Choices[0].Message.Content)
-	return filename, nil
+	if chatResp.Usage != nil {
+		c.recordUsage(ctx, task, chatReq.Model, chatResp.Usage.PromptTokens, chatResp.Usage.CompletionTokens)
+	}
+
+	reply := chatResp.Choices[0].Message.Content
+	if validate != nil {
+		if err := validate(reply); err != nil {
+			return "", err
+		}
+	}
+	c.cachePut(key, task, chatReq.Model, reply)
+	return reply, nil
 }
 
-func buildFilenamePrompt(req FilenameRequest) string {
-	var sb strings.Builder
-	sb.WriteString("Generate a concise, descriptive filename for this archived video:\n\n")
-	sb.WriteString(fmt.Sprintf("Author: @%s (%s)\n", req.AuthorUsername, req.AuthorName))
-	sb.WriteString(fmt.Sprintf("Date: %s\n", req.PostedAt))
+// recordUsage reports token usage to the usage recorder when one is set.
+func (c *HTTPClient) recordUsage(ctx context.Context, task, model string, promptTokens, completionTokens int) {
+	if c.usage == nil {
+		return
+	}
+	c.usage.RecordAIUsage(ctx, Usage{
+		Provider:         strings.ToLower(c.providerName()),
+		Model:            model,
+		Task:             task,
+		PromptTokens:     promptTokens,
+		CompletionTokens: completionTokens,
+	})
+}
 
-	if req.Duration > 0 {
-		sb.WriteString(fmt.Sprintf("Duration: %d seconds\n", req.Duration))
+// cachePut stores a reply under key. An empty key means caching is off.
+func (c *HTTPClient) cachePut(key, task, model, reply string) {
+	if key == "" {
+		return
 	}
+	c.cache.put(key, cacheEntry{
+		Provider: strings.ToLower(c.providerName()),
+		Task:     task,
+		Model:    model,
+		Response: reply,
+	})
+}
 
-	sb.WriteString(fmt.Sprintf("Tweet text: \"%s\"\n\n", req.TweetText))
-	sb.WriteString("If this appears to be from a known documentary, show, movie, or media source, include that context in the filename.\n")
-	sb.WriteString("Format: author_date_description (e.g., elonmusk_2024-01-15_starship_launch_test)\n")
-	sb.WriteString("Return ONLY the filename, no extension, no quotes, no explanation.")
+func (c *HTTPClient) providerName() string {
+	if c.name == "" {
+		return "Grok"
+	}
+	return c.name
+}
 
-	return sb.String()
+// visionModelName returns the model used for image analysis. Grok clients
+// switch to a vision model unless the configured one already is.
+func (c *HTTPClient) visionModelName() string {
+	if c.visionModel != "" {
+		return c.visionModel
+	}
+	if strings.Contains(c.model, "vision") {
+		return c.model
+	}
+	return "grok-2-vision-1212"
+}
+
+// essayModelName returns the model used for essays. Grok clients use the most
+// capable text model unless a vision model is configured.
+func (c *HTTPClient) essayModelName() string {
+	if c.essayModel != "" {
+		return c.essayModel
+	}
+	if strings.Contains(c.model, "vision") {
+		return c.model
+	}
+	return "grok-3"
+}
+
+// stripCodeFence trims whitespace and a surrounding markdown code block from a reply.
+func stripCodeFence(content string) string {
+	content = strings.TrimSpace(content)
+	content = strings.TrimPrefix(content, "```json")
+	content = strings.TrimPrefix(content, "```")
+	content = strings.TrimSuffix(content, "```")
+	return strings.TrimSpace(content)
+}
+
+// GenerateFilename creates a descriptive filename based on video metadata.
+func (c *HTTPClient) GenerateFilename(ctx context.Context, req FilenameRequest) (string, error) {
+	prompt, err := c.promptSet().RenderFilename(req)
+	if err != nil {
+		return "", err
+	}
+
+	chatReq := chatRequest{
+		Model: c.model,
+		Messages: []chatMessage{
+			{
+				Role:    "system",
+				Content: prompt.System,
+			},
+			{
+				Role:    "user",
+				Content: prompt.User,
+			},
+		},
+	}
+
+	reply, err := c.complete(ctx, config.AITaskFilename, chatReq, nil)
+	if err != nil {
+		return "", err
+	}
+
+	filename := sanitizeFilename(reply)
+	return filename, nil
 }
 
 func sanitizeFilename(s string) string {
@@ -251,80 +476,32 @@ func sanitizeFilename(s string) string {
 
 // AnalyzeContent generates searchable tags and description for media content.
 func (c *HTTPClient) AnalyzeContent(ctx context.Context, req ContentAnalysisRequest) (*ContentAnalysisResponse, error) {
-	prompt := buildContentAnalysisPrompt(req)
+	prompt, err := c.promptSet().RenderAnalysis(req)
+	if err != nil {
+		return nil, err
+	}
 
 	chatReq := chatRequest{
 		Model: c.model,
 		Messages: []chatMessage{
 			{
-				Role: "system",
-				Content: `You are a content analyzer that extracts searchable metadata from tweets.
-Return your analysis as JSON with these fields:
-- summary: 1-2 sentence description of what the content shows/discusses
-- tags: array of 5-15 searchable keywords (people, places, objects, events, concepts)
-- content_type: category like "documentary", "news", "comedy", "sports", "music", "politics", "science", "tutorial", "meme", "personal", "promotional"
-- topics: array of 2-5 main topics
-
-Example output:
-{"summary":"Historical footage of World War 2 showing tank battles in North Africa","tags":["ww2","world war 2","tanks","north africa","rommel","desert fox","history","military","1942"],"content_type":"documentary","topics":["World War 2","Military History","North Africa Campaign"]}
-
-Return ONLY valid JSON, no markdown, no explanation.`,
+				Role:    "system",
+				Content: prompt.System,
 			},
 			{
 				Role:    "user",
-				Content: prompt,
+				Content: prompt.User,
 			},
 		},
 	}
 
-	body, err := json.Marshal(chatReq)
+	reply, err := c.complete(ctx, config.AITaskAnalysis, chatReq, nil)
 	if err != nil {
-		return nil, fmt.Errorf("marshal request: %w", err)
-	}
-
-	httpReq, err := http.NewRequestWithContext(ctx, "POST", c.baseURL+"/chat/completions", bytes.NewReader(body))
-	if err != nil {
-		return nil, fmt.Errorf("create request: %w", err)
-	}
-
-	httpReq.Header.Set("Content-Type", "application/json")
-	httpReq.Header.Set("Authorization", "Bearer "+c.apiKey)
-
-	resp, err := c.httpClient.Do(httpReq)
-	if err != nil {
-		return nil, fmt.Errorf("send request: %w", err)
-	}
-	defer resp.Body.Close()
-
-	respBody, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, fmt.Errorf("read response: %w", err)
-	}
-
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("API error (status %d): %s", resp.StatusCode, string(respBody))
-	}
-
-	var chatResp chatResponse
-	if err := json.Unmarshal(respBody, &chatResp); err != nil {
-		return nil, fmt.Errorf("unmarshal response: %w", err)
-	}
-
-	if chatResp.Error != nil {
-		return nil, fmt.Errorf("API error: %s", chatResp.Error.Message)
-	}
-
-	if len(chatResp.Choices) == 0 {
-		return nil, fmt.Errorf("no response from Grok")
+		return nil, err
 	}
 
 	// Parse the JSON response
-	content := strings.TrimSpace(chatResp.Choices[0].Message.Content)
-	// Clean up potential markdown code blocks
-	content = strings.TrimPrefix(content, "```json")
-	content = strings.TrimPrefix(content, "```")
-	content = strings.TrimSuffix(content, "```")
-	content = strings.TrimSpace(content)
+	content := stripCodeFence(reply)
 
 	var result ContentAnalysisResponse
 	if err := json.Unmarshal([]byte(content), &result); err != nil {
@@ -345,10 +522,13 @@ func (c *HTTPClient) AnalyzeContentWithVision(ctx context.Context, req VisionAna
 	var contentParts []contentPart
 
 	// Add text prompt first
-	promptText := buildVisionPrompt(req)
+	prompt, err := c.promptSet().RenderVision(req)
+	if err != nil {
+		return nil, err
+	}
 	contentParts = append(contentParts, contentPart{
 		Type: "text",
-		Text: promptText,
+		Text: prompt.User,
 	})
 
 	// Add images (up to 4 to stay within limits)
@@ -391,37 +571,12 @@ func (c *HTTPClient) AnalyzeContentWithVision(ctx context.Context, req VisionAna
 		})
 	}
 
-	// Use vision model (grok-2-vision or similar)
-	visionModel := c.model
-	if !strings.Contains(visionModel, "vision") {
-		visionModel = "grok-2-vision-1212" // Default vision model
-	}
-
 	chatReq := chatRequest{
-		Model: visionModel,
+		Model: c.visionModelName(),
 		Messages: []chatMessage{
 			{
-				Role: "system",
-				Content: `You are an expert content analyzer with vision capabilities. Analyze the provided images/video thumbnail along with the tweet text.
-
-IMPORTANT: Extract EVERYTHING visible in the images:
-- All text, captions, memes text, watermarks, titles
-- People (identify if recognizable public figures)
-- Objects, brands, logos
-- Locations, landmarks
-- Historical context, time periods
-- Cultural references, symbols
-- Actions, events happening
-
-Return your analysis as JSON with these fields:
-- summary: 2-3 sentence detailed description of what the content shows, including any text visible in images
-- tags: array of 15-30 searchable keywords (extract ALL relevant terms - people, places, objects, text from images, events, concepts, brands)
-- content_type: category like "meme", "documentary", "news", "comedy", "sports", "music", "politics", "science", "tutorial", "personal", "promotional", "historical"
-- topics: array of 3-7 main topics
-
-Be thorough - if there's text in a meme about the "Talmud", include "talmud" in tags. If there's a brand logo, include the brand. If there's a historical figure, include their name.
-
-Return ONLY valid JSON, no markdown, no explanation.`,
+				Role:    "system",
+				Content: prompt.System,
 			},
 			{
 				Role:    "user",
@@ -430,53 +585,13 @@ Return ONLY valid JSON, no markdown, no explanation.`,
 		},
 	}
 
-	body, err := json.Marshal(chatReq)
-	if err != nil {
-		return nil, fmt.Errorf("marshal request: %w", err)
-	}
-
-	httpReq, err := http.NewRequestWithContext(ctx, "POST", c.baseURL+"/chat/completions", bytes.NewReader(body))
+	reply, err := c.complete(ctx, config.AITaskVision, chatReq, nil)
 	if err != nil {
-		return nil, fmt.Errorf("create request: %w", err)
-	}
-
-	httpReq.Header.Set("Content-Type", "application/json")
-	httpReq.Header.Set("Authorization", "Bearer "+c.apiKey)
-
-	resp, err := c.httpClient.Do(httpReq)
-	if err != nil {
-		return nil, fmt.Errorf("send request: %w", err)
-	}
-	defer resp.Body.Close()
-
-	respBody, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, fmt.Errorf("read response: %w", err)
-	}
-
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("API error (status %d): %s", resp.StatusCode, string(respBody))
-	}
-
-	var chatResp chatResponse
-	if err := json.Unmarshal(respBody, &chatResp); err != nil {
-		return nil, fmt.Errorf("unmarshal response: %w", err)
-	}
-
-	if chatResp.Error != nil {
-		return nil, fmt.Errorf("API error: %s", chatResp.Error.Message)
-	}
-
-	if len(chatResp.Choices) == 0 {
-		return nil, fmt.Errorf("no response from Grok")
+		return nil, err
 	}
 
 	// Parse the JSON response
-	content := strings.TrimSpace(chatResp.Choices[0].Message.Content)
-	content = strings.TrimPrefix(content, "```json")
-	content = strings.TrimPrefix(content, "```")
-	content = strings.TrimSuffix(content, "```")
-	content = strings.TrimSpace(content)
+	content := stripCodeFence(reply)
 
 	var result ContentAnalysisResponse
 	if err := json.Unmarshal([]byte(content), &result); err != nil {
@@ -516,58 +631,6 @@ func encodeImageToBase64(path string) (string, string, error) {
 	return encoded, mimeType, nil
 }
 
-func buildVisionPrompt(req VisionAnalysisRequest) string {
-	var sb strings.Builder
-	sb.WriteString("Analyze this tweet and its media content. Extract ALL searchable metadata:\n\n")
-	sb.WriteString(fmt.Sprintf("Author: @%s\n", req.AuthorUsername))
-
-	if req.TweetText != "" {
-		sb.WriteString(fmt.Sprintf("Tweet text: \"%s\"\n", req.TweetText))
-	} else {
-		sb.WriteString("Tweet text: (no text, media only)\n")
-	}
-
-	if req.HasVideo {
-		sb.WriteString(fmt.Sprintf("Media type: Video (%d seconds) - thumbnail provided\n", req.VideoDuration))
-	} else if len(req.ImagePaths) > 0 {
-		sb.WriteString(fmt.Sprintf("Media type: %d images\n", len(req.ImagePaths)))
-	}
-
-	sb.WriteString("\nCarefully examine the image(s) and extract:")
-	sb.WriteString("\n- Any text visible (memes, captions, signs, watermarks)")
-	sb.WriteString("\n- People (especially public figures)")
-	sb.WriteString("\n- Objects, products, brands")
-	sb.WriteString("\n- Locations, settings")
-	sb.WriteString("\n- Historical or cultural context")
-	sb.WriteString("\n- The overall topic and meaning")
-
-	return sb.String()
-}
-
-func buildContentAnalysisPrompt(req ContentAnalysisRequest) string {
-	var sb strings.Builder
-	sb.WriteString("Analyze this tweet and extract searchable metadata:\n\n")
-	sb.WriteString(fmt.Sprintf("Author: @%s\n", req.AuthorUsername))
-
-	if req.TweetText != "" {
-		sb.WriteString(fmt.Sprintf("Tweet text: \"%s\"\n", req.TweetText))
-	} else {
-		sb.WriteString("Tweet text: (no text, media only)\n")
-	}
-
-	if req.HasVideo {
-		sb.WriteString(fmt.Sprintf("Media: Video (%d seconds)\n", req.VideoDuration))
-	} else if req.HasImages {
-		sb.WriteString(fmt.Sprintf("Media: %d images\n", req.ImageCount))
-	}
-
-	sb.WriteString("\nBased on the author, text, and media type, infer what this content likely shows or discusses. ")
-	sb.WriteString("If it's from a known account (news, documentary, sports, etc.), use that context. ")
-	sb.WriteString("Generate comprehensive tags that someone might search for to find this content.")
-
-	return sb.String()
-}
-
 func extractBasicTags(text string) []string {
 	// Simple fallback: extract words that could be tags
 	words := strings.Fields(strings.ToLower(text))
@@ -642,177 +705,253 @@ func (c *HTTPClient) GenerateEssay(ctx context.Context, req EssayRequest) (*Essa
 		return nil, fmt.Errorf("transcript is required for essay generation")
 	}
 
-	// Use the best available model for essay generation
-	model := c.model
-	if !strings.Contains(model, "vision") {
-		// For essay generation, we want the most capable text model
-		model = "grok-3" // Use Grok 3 for highest quality essays
-	}
-
-	// Default to academic style if not specified
-	style := req.Style
-	if style == "" {
-		style = "academic"
-	}
-
-	var systemPrompt string
-	if style == "magazine" {
-		systemPrompt = `You are an expert magazine writer tasked with transforming a raw transcript into an engaging, article-style piece.
-
-CRITICAL REQUIREMENTS:
-1. Transform the transcript into a well-structured magazine article with an engaging hook, body, and conclusion
-2. Faithfully cover ALL key points, arguments, facts, and narratives from the transcript
-3. Use a casual, accessible tone suitable for a general magazine audience
-4. Write in an engaging, narrative style that draws readers in
-5. NEVER mention or reference the transcript, video, source, or the fact this is derived from any source
-6. Present it as an original standalone article on the topic
-7. You may use a more conversational tone and engaging storytelling techniques
-8. Feel free to use vivid descriptions and narrative flow to make the content compelling
-9. The goal is to relay the documentary content in an accessible, magazine-style format
-
-STRICT SOURCE RESTRICTIONS:
-- You MUST base the article EXCLUSIVELY on the transcript provided below
-- Do NOT fetch, search for, or reference any external sources, websites, or web content
-- Do NOT use any information from your training data beyond general writing knowledge
-- Do NOT add facts, statistics, or details that are not present in the transcript
-- Do NOT reference external articles, studies, or sources
-- If the transcript mentions something without full context, work with what is provided—do not supplement with external knowledge
-- The article should be a faithful transformation of ONLY the transcript content into magazine article form
-
-OUTPUT FORMAT:
-Return your response as JSON with exactly two fields:
-{
-  "title": "An Engaging Article Title Based on the Content",
-  "essay": "The full markdown article content here..."
-}
-
-ARTICLE FORMATTING:
-- Use markdown for structure (## for section headings, **bold** for emphasis)
-- Write in clear paragraphs with engaging narrative flow
-- Include smooth transitions between sections
-- Use a casual, accessible tone throughout
-- Keep the title catchy and engaging (5-12 words typically)
-
-Return ONLY valid JSON, no markdown code blocks, no explanation.`
-	} else {
-		// Academic style (default)
-		systemPrompt = `You are an expert academic writer tasked with transforming a raw transcript into a cohesive, original essay.
-
-CRITICAL REQUIREMENTS:
-1. Transform the transcript into a well-structured essay with introduction, body paragraphs, and conclusion
-2. Faithfully cover ALL key points, arguments, facts, and narratives from the transcript
-3. Use formal, academic language with smooth transitions and objective analysis
-4. Maintain objectivity and neutrality throughout—present information without bias
-5. NEVER mention or reference the transcript, video, source, or the fact this is derived from any source
-6. Present it as an original standalone academic essay on the topic
-7. Do NOT inject any personal opinions, commentary, or value judgments about the content
-8. Report the information objectively and neutrally, even if the content is controversial
-9. Let the facts and arguments speak for themselves without editorial framing
-10. Use academic conventions: clear thesis, evidence-based arguments, logical structure
-
-STRICT SOURCE RESTRICTIONS:
-- You MUST base the essay EXCLUSIVELY on the transcript provided below
-- Do NOT fetch, search for, or reference any external sources, websites, or web content
-- Do NOT use any information from your training data beyond general writing knowledge
-- Do NOT add facts, statistics, or details that are not present in the transcript
-- Do NOT reference external articles, studies, or sources
-- If the transcript mentions something without full context, work with what is provided—do not supplement with external knowledge
-- The essay should be a faithful transformation of ONLY the transcript content into academic essay form
-
-OUTPUT FORMAT:
-Return your response as JSON with exactly two fields:
-{
-  "title": "An Appropriate Essay Title Based on the Content",
-  "essay": "The full markdown essay content here..."
-}
-
-ESSAY FORMATTING:
-- Use markdown for structure (## for section headings, **bold** for emphasis)
-- Write in clear paragraphs with logical flow
-- Include smooth transitions between sections
-- Aim for comprehensive coverage - the essay should be thorough
-- Keep the title concise but descriptive (5-12 words typically)
-- Maintain formal academic tone throughout
-
-Return ONLY valid JSON, no markdown code blocks, no explanation.`
-	}
-
-	userPrompt := fmt.Sprintf("Transform this transcript into a polished essay:\n\n%s", req.Transcript)
+	prompt, err := c.promptSet().RenderEssay(req)
+	if err != nil {
+		return nil, err
+	}
 
 	chatReq := chatRequest{
-		Model: model,
+		Model: c.essayModelName(),
 		Messages: []chatMessage{
-			{Role: "system", Content: systemPrompt},
-			{Role: "user", Content: userPrompt},
+			{Role: "system", Content: prompt.System},
+			{Role: "user", Content: prompt.User},
 		},
 	}
 
-	body, err := json.Marshal(chatReq)
+	reply, err := c.complete(ctx, config.AITaskEssay, chatReq, nil)
 	if err != nil {
-		return nil, fmt.Errorf("marshal request: %w", err)
+		return nil, err
 	}
 
-	httpReq, err := http.NewRequestWithContext(ctx, "POST", c.baseURL+"/chat/completions", bytes.NewReader(body))
+	// Parse the JSON response
+	content := stripCodeFence(reply)
+
+	var result struct {
+		Title string `json:"title"`
+		Essay string `json:"essay"`
+	}
+	if err := json.Unmarshal([]byte(content), &result); err != nil {
+		// If JSON parsing fails, try to extract content directly
+		// This handles cases where the model returns plain text
+		return &EssayResponse{
+			Title:     "Essay",
+			Essay:     content,
+			WordCount: len(strings.Fields(content)),
+		}, nil
+	}
+
+	return &EssayResponse{
+		Title:     result.Title,
+		Essay:     result.Essay,
+		WordCount: len(strings.Fields(result.Essay)),
+	}, nil
+}
+
+// GenerateDocument creates a derived markdown document from one or more
+// tweets. Documents are long-form like essays, so they use the essay model
+// and route.
+func (c *HTTPClient) GenerateDocument(ctx context.Context, req DocumentRequest) (*DocumentResponse, error) {
+	if len(req.Sources) == 0 {
+		return nil, fmt.Errorf("at least one source is required for document generation")
+	}
+
+	prompt, err := c.promptSet().RenderDocument(req)
 	if err != nil {
-		return nil, fmt.Errorf("create request: %w", err)
+		return nil, err
 	}
 
-	httpReq.Header.Set("Content-Type", "application/json")
-	httpReq.Header.Set("Authorization", "Bearer "+c.apiKey)
+	chatReq := chatRequest{
+		Model: c.essayModelName(),
+		Messages: []chatMessage{
+			{Role: "system", Content: prompt.System},
+			{Role: "user", Content: prompt.User},
+		},
+	}
 
-	resp, err := c.httpClient.Do(httpReq)
+	reply, err := c.complete(ctx, config.AITaskDocument, chatReq, nil)
 	if err != nil {
-		return nil, fmt.Errorf("send request: %w", err)
+		return nil, err
 	}
-	defer resp.Body.Close()
 
-	respBody, err := io.ReadAll(resp.Body)
+	content := stripCodeFence(reply)
+
+	var result struct {
+		Title   string `json:"title"`
+		Content string `json:"content"`
+	}
+	if err := json.Unmarshal([]byte(content), &result); err != nil || result.Content == "" {
+		// Models sometimes return the markdown itself rather than JSON
+		return &DocumentResponse{
+			Content:   content,
+			WordCount: len(strings.Fields(content)),
+		}, nil
+	}
+
+	return &DocumentResponse{
+		Title:     result.Title,
+		Content:   result.Content,
+		WordCount: len(strings.Fields(result.Content)),
+	}, nil
+}
+
+// GenerateChapters splits a timed video transcript into titled chapters.
+// Chapters read the whole transcript like essays, so they use the essay model
+// and route.
+func (c *HTTPClient) GenerateChapters(ctx context.Context, req ChaptersRequest) (*ChaptersResponse, error) {
+	if req.Transcript == "" {
+		return nil, fmt.Errorf("transcript is required for chapter generation")
+	}
+
+	prompt, err := c.promptSet().RenderChapters(req)
 	if err != nil {
-		return nil, fmt.Errorf("read response: %w", err)
+		return nil, err
 	}
 
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("API error (status %d): %s", resp.StatusCode, string(respBody))
+	chatReq := chatRequest{
+		Model: c.essayModelName(),
+		Messages: []chatMessage{
+			{Role: "system", Content: prompt.System},
+			{Role: "user", Content: prompt.User},
+		},
 	}
 
-	var chatResp chatResponse
-	if err := json.Unmarshal(respBody, &chatResp); err != nil {
-		return nil, fmt.Errorf("unmarshal response: %w", err)
+	var resp *ChaptersResponse
+	parse := func(reply string) error {
+		var err error
+		resp, err = parseChapters(reply)
+		return err
+	}
+	if _, err := c.complete(ctx, config.AITaskChapters, chatReq, parse); err != nil {
+		return nil, err
 	}
+	return resp, nil
+}
 
-	if chatResp.Error != nil {
-		return nil, fmt.Errorf("API error: %s", chatResp.Error.Message)
+// parseChapters parses a chapters reply.
+func parseChapters(reply string) (*ChaptersResponse, error) {
+	var result struct {
+		Chapters []struct {
+			Start json.RawMessage `json:"start"`
+			Title string          `json:"title"`
+		} `json:"chapters"`
+	}
+	if err := json.Unmarshal([]byte(stripCodeFence(reply)), &result); err != nil {
+		return nil, fmt.Errorf("parse chapters response: %w", err)
 	}
 
-	if len(chatResp.Choices) == 0 {
-		return nil, fmt.Errorf("no response from Grok")
+	resp := &ChaptersResponse{}
+	for _, ch := range result.Chapters {
+		start, err := parseChapterStart(ch.Start)
+		if err != nil {
+			return nil, fmt.Errorf("parse chapter %q: %w", ch.Title, err)
+		}
+		resp.Chapters = append(resp.Chapters, ChapterItem{Start: start, Title: strings.TrimSpace(ch.Title)})
 	}
+	return resp, nil
+}
 
-	// Parse the JSON response
-	content := strings.TrimSpace(chatResp.Choices[0].Message.Content)
-	// Clean up potential markdown code blocks
-	content = strings.TrimPrefix(content, "```json")
-	content = strings.TrimPrefix(content, "```")
-	content = strings.TrimSuffix(content, "```")
-	content = strings.TrimSpace(content)
+// parseChapterStart accepts a chapter start given either as seconds or as an
+// "mm:ss" or "hh:mm:ss" string.
+func parseChapterStart(raw json.RawMessage) (float64, error) {
+	var seconds float64
+	if err := json.Unmarshal(raw, &seconds); err == nil {
+		return seconds, nil
+	}
+	var stamp string
+	if err := json.Unmarshal(raw, &stamp); err != nil {
+		return 0, fmt.Errorf("invalid start %s", raw)
+	}
+	stamp = strings.Trim(strings.TrimSpace(stamp), "[]")
+	seconds = 0
+	for _, part := range strings.Split(stamp, ":") {
+		n, err := strconv.ParseFloat(part, 64)
+		if err != nil {
+			return 0, fmt.Errorf("invalid start %q", stamp)
+		}
+		seconds = seconds*60 + n
+	}
+	return seconds, nil
+}
+
+// Translate translates a batch of texts into the target language. The reply
+// must contain exactly one translation per text so that callers can map them
+// back, e.g. onto timed transcript segments.
+func (c *HTTPClient) Translate(ctx context.Context, req TranslationRequest) (*TranslationResponse, error) {
+	if req.TargetLanguage == "" {
+		return nil, fmt.Errorf("target language is required for translation")
+	}
+	if len(req.Texts) == 0 {
+		return &TranslationResponse{SourceLanguage: req.SourceLanguage}, nil
+	}
+
+	prompt, err := c.promptSet().RenderTranslation(req)
+	if err != nil {
+		return nil, err
+	}
+
+	chatReq := chatRequest{
+		Model: c.model,
+		Messages: []chatMessage{
+			{Role: "system", Content: prompt.System},
+			{Role: "user", Content: prompt.User},
+		},
+	}
 
 	var result struct {
-		Title string `json:"title"`
-		Essay string `json:"essay"`
+		SourceLanguage string   `json:"source_language"`
+		Translations   []string `json:"translations"`
 	}
-	if err := json.Unmarshal([]byte(content), &result); err != nil {
-		// If JSON parsing fails, try to extract content directly
-		// This handles cases where the model returns plain text
-		return &EssayResponse{
-			Title:     "Essay",
-			Essay:     content,
-			WordCount: len(strings.Fields(content)),
-		}, nil
+	parse := func(reply string) error {
+		result.SourceLanguage, result.Translations = "", nil
+		if err := json.Unmarshal([]byte(stripCodeFence(reply)), &result); err != nil {
+			return fmt.Errorf("parse translation: %w", err)
+		}
+		if len(result.Translations) != len(req.Texts) {
+			return fmt.Errorf("translation returned %d texts, want %d", len(result.Translations), len(req.Texts))
+		}
+		return nil
+	}
+	if _, err := c.complete(ctx, config.AITaskTranslation, chatReq, parse); err != nil {
+		return nil, err
 	}
 
-	return &EssayResponse{
-		Title:     result.Title,
-		Essay:     result.Essay,
-		WordCount: len(strings.Fields(result.Essay)),
+	return &TranslationResponse{
+		SourceLanguage: strings.ToLower(strings.TrimSpace(result.SourceLanguage)),
+		Texts:          result.Translations,
 	}, nil
 }
+
+// Ask answers a question using only the given archived tweets, citing them
+// as [tweet:ID] or [tweet:ID@mm:ss] for transcript lines.
+func (c *HTTPClient) Ask(ctx context.Context, req AskRequest, onDelta func(string)) (*AskResponse, error) {
+	if strings.TrimSpace(req.Question) == "" {
+		return nil, fmt.Errorf("question is required")
+	}
+	if len(req.Sources) == 0 {
+		return nil, fmt.Errorf("at least one source is required to answer a question")
+	}
+
+	prompt, err := c.promptSet().RenderAsk(req)
+	if err != nil {
+		return nil, err
+	}
+
+	chatReq := chatRequest{
+		Model: c.model,
+		Messages: []chatMessage{
+			{Role: "system", Content: prompt.System},
+			{Role: "user", Content: prompt.User},
+		},
+	}
+
+	var reply string
+	if onDelta == nil {
+		reply, err = c.complete(ctx, config.AITaskAsk, chatReq, nil)
+	} else {
+		reply, err = c.completeStream(ctx, config.AITaskAsk, chatReq, onDelta)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &AskResponse{Answer: strings.TrimSpace(reply)}, nil
+}
@@ -7,6 +7,7 @@ import (
 	"net/http/httptest"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 
@@ -224,7 +225,11 @@ func TestBuildFilenamePrompt(t *testing.T) {
 		Duration:       120,
 	}
 
-	prompt := buildFilenamePrompt(req)
+	rendered, err := DefaultPrompts().RenderFilename(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	prompt := rendered.User
 
 	// Check that prompt contains expected elements
 	if !containsAll(prompt,
@@ -246,7 +251,11 @@ func TestBuildContentAnalysisPrompt(t *testing.T) {
 		VideoDuration:  300,
 	}
 
-	prompt := buildContentAnalysisPrompt(req)
+	rendered, err := DefaultPrompts().RenderAnalysis(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	prompt := rendered.User
 
 	if !containsAll(prompt,
 		"@historyChannel",
@@ -262,7 +271,11 @@ func TestBuildContentAnalysisPrompt(t *testing.T) {
 		HasImages:      true,
 		ImageCount:     3,
 	}
-	prompt2 := buildContentAnalysisPrompt(req2)
+	rendered2, err := DefaultPrompts().RenderAnalysis(req2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	prompt2 := rendered2.User
 	if !containsAll(prompt2, "@test", "Image test") {
 		t.Errorf("buildContentAnalysisPrompt with images missing expected content: %s", prompt2)
 	}
@@ -775,6 +788,50 @@ func TestHTTPClient_AnalyzeContentWithVision_Success(t *testing.T) {
 	}
 }
 
+func TestHTTPClient_AnalyzeContentWithVision_AltText(t *testing.T) {
+	var system string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Messages []struct {
+				Content json.RawMessage `json:"content"`
+			} `json:"messages"`
+		}
+		json.NewDecoder(r.Body).Decode(&req)
+		json.Unmarshal(req.Messages[0].Content, &system)
+		analysisJSON := `{"summary":"Big sale on cat beds!","tags":["cat"],"alt_text":"A grey cat asleep in a round bed"}`
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"choices": []map[string]interface{}{
+				{"message": map[string]string{"content": analysisJSON}},
+			},
+		})
+	}))
+	defer server.Close()
+
+	client := &HTTPClient{baseURL: server.URL, httpClient: &http.Client{Timeout: 5 * time.Second}}
+	imgPath := filepath.Join(t.TempDir(), "cat.jpg")
+	os.WriteFile(imgPath, []byte("fake image data"), 0644)
+
+	req := VisionAnalysisRequest{AuthorUsername: "test", ImagePaths: []string{imgPath}}
+	if _, err := client.AnalyzeContentWithVision(context.Background(), req); err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(system, "alt_text") {
+		t.Error("alt text should only be requested when WantAltText is set")
+	}
+
+	req.WantAltText = true
+	result, err := client.AnalyzeContentWithVision(context.Background(), req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(system, "- alt_text:") {
+		t.Errorf("system prompt does not ask for alt text:\n%s", system)
+	}
+	if result.AltText != "A grey cat asleep in a round bed" || result.Summary != "Big sale on cat beds!" {
+		t.Errorf("result = %+v", result)
+	}
+}
+
 func TestHTTPClient_AnalyzeContentWithVision_ImageNotFound(t *testing.T) {
 	client := &HTTPClient{
 		apiKey:     "test-key",
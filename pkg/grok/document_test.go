@@ -0,0 +1,79 @@
+package grok
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDefaultPrompts_RenderDocument(t *testing.T) {
+	p := DefaultPrompts()
+	req := DocumentRequest{
+		Type:  DocumentDigest,
+		Title: "Cats",
+		Sources: []DocumentSource{
+			{Author: "alice", PostedAt: "2024-05-01", Text: "Cats sleep a lot."},
+			{Author: "bob", Transcript: "[00:05] They also purr."},
+		},
+	}
+	got, err := p.RenderDocument(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "Collection: Cats\n\n" +
+		"=== Post by @alice on 2024-05-01 ===\nCats sleep a lot.\n\n" +
+		"=== Post by @bob ===\n\nVideo transcript:\n[00:05] They also purr.\n"
+	if got.User != want {
+		t.Errorf("document user prompt = %q, want %q", got.User, want)
+	}
+	if !strings.Contains(got.System, `collected as "Cats"`) || got.Task != "document" {
+		t.Errorf("digest prompt = %+v", got)
+	}
+
+	for _, typ := range []string{DocumentTLDR, DocumentNotes, DocumentFAQ, DocumentFlashcards, DocumentOutline} {
+		req.Type = typ
+		if _, err := p.RenderDocument(req); err != nil {
+			t.Errorf("RenderDocument(%s): %v", typ, err)
+		}
+	}
+	req.Type = "poem"
+	if _, err := p.RenderDocument(req); err == nil {
+		t.Error("unknown document type should fail")
+	}
+}
+
+func TestHTTPClient_GenerateDocument(t *testing.T) {
+	var user string
+	server := translationServer(t, "```json\n{\"title\":\"Cat facts\",\"content\":\"- Cats sleep\\n- Cats purr\"}\n```", &user)
+	client := &HTTPClient{baseURL: server.URL, httpClient: &http.Client{Timeout: 5 * time.Second}}
+
+	result, err := client.GenerateDocument(context.Background(), DocumentRequest{
+		Type:    DocumentNotes,
+		Sources: []DocumentSource{{Author: "alice", Text: "Cats sleep and purr."}},
+	})
+	if err != nil {
+		t.Fatalf("GenerateDocument failed: %v", err)
+	}
+	if result.Title != "Cat facts" || result.Content != "- Cats sleep\n- Cats purr" || result.WordCount != 6 {
+		t.Errorf("result = %+v", result)
+	}
+	if !strings.Contains(user, "Cats sleep and purr.") {
+		t.Errorf("source not sent: %q", user)
+	}
+
+	plain := translationServer(t, "## Notes\n- Cats sleep", nil)
+	client.baseURL = plain.URL
+	result, err = client.GenerateDocument(context.Background(), DocumentRequest{
+		Type:    DocumentNotes,
+		Sources: []DocumentSource{{Author: "alice", Text: "Cats sleep."}},
+	})
+	if err != nil || result.Content != "## Notes\n- Cats sleep" {
+		t.Errorf("plain markdown reply = %+v, %v", result, err)
+	}
+
+	if _, err := client.GenerateDocument(context.Background(), DocumentRequest{Type: DocumentNotes}); err == nil {
+		t.Error("GenerateDocument without sources should fail")
+	}
+}
@@ -0,0 +1,343 @@
+package grok
+
+import (
+	"crypto/sha256"
+	"embed"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"text/template"
+
+	"github.com/iconidentify/xgrabba/internal/config"
+)
+
+// defaultPromptFiles are the built-in prompt templates, one <name>.tmpl per prompt.
+//
+//go:embed prompts/*.tmpl
+var defaultPromptFiles embed.FS
+
+// Prompt template names. A prompts directory overrides a template with a
+// file named <name>.tmpl.
+const (
+	PromptTweet               = "tweet"
+	PromptFilenameSystem      = "filename_system"
+	PromptFilename            = "filename"
+	PromptAnalysisSystem      = "analysis_system"
+	PromptAnalysis            = "analysis"
+	PromptVisionSystem        = "vision_system"
+	PromptVision              = "vision"
+	PromptEssayAcademicSystem = "essay_academic_system"
+	PromptEssayMagazineSystem = "essay_magazine_system"
+	PromptEssay               = "essay"
+	PromptTranslationSystem   = "translation_system"
+	PromptTranslation         = "translation"
+	PromptTLDRSystem          = "document_tldr_system"
+	PromptNotesSystem         = "document_notes_system"
+	PromptFAQSystem           = "document_faq_system"
+	PromptFlashcardsSystem    = "document_flashcards_system"
+	PromptOutlineSystem       = "document_outline_system"
+	PromptDigestSystem        = "document_digest_system"
+	PromptDocument            = "document"
+	PromptChaptersSystem      = "chapters_system"
+	PromptChapters            = "chapters"
+	PromptAskSystem           = "ask_system"
+	PromptAsk                 = "ask"
+)
+
+// documentSystemPrompts maps each document type to its system prompt.
+var documentSystemPrompts = map[string]string{
+	DocumentTLDR:       PromptTLDRSystem,
+	DocumentNotes:      PromptNotesSystem,
+	DocumentFAQ:        PromptFAQSystem,
+	DocumentFlashcards: PromptFlashcardsSystem,
+	DocumentOutline:    PromptOutlineSystem,
+	DocumentDigest:     PromptDigestSystem,
+}
+
+// promptData holds the data type each template is rendered with. Templates
+// are test-rendered with the zero value when loaded, so references to fields
+// that don't exist fail at startup rather than on the first AI call.
+var promptData = map[string]any{
+	PromptTweet:               TweetPromptData{},
+	PromptFilenameSystem:      FilenameRequest{},
+	PromptFilename:            FilenameRequest{},
+	PromptAnalysisSystem:      ContentAnalysisRequest{},
+	PromptAnalysis:            ContentAnalysisRequest{},
+	PromptVisionSystem:        VisionAnalysisRequest{},
+	PromptVision:              VisionAnalysisRequest{},
+	PromptEssayAcademicSystem: EssayRequest{},
+	PromptEssayMagazineSystem: EssayRequest{},
+	PromptEssay:               EssayRequest{},
+	PromptTranslationSystem:   TranslationRequest{},
+	PromptTranslation:         TranslationRequest{},
+	PromptTLDRSystem:          DocumentRequest{},
+	PromptNotesSystem:         DocumentRequest{},
+	PromptFAQSystem:           DocumentRequest{},
+	PromptFlashcardsSystem:    DocumentRequest{},
+	PromptOutlineSystem:       DocumentRequest{},
+	PromptDigestSystem:        DocumentRequest{},
+	PromptDocument:            DocumentRequest{},
+	PromptChaptersSystem:      ChaptersRequest{},
+	PromptChapters:            ChaptersRequest{},
+	PromptAskSystem:           AskRequest{},
+	PromptAsk:                 AskRequest{},
+}
+
+// promptNames lists the templates in a fixed order for listing and versioning.
+var promptNames = []string{
+	PromptTweet,
+	PromptFilenameSystem,
+	PromptFilename,
+	PromptAnalysisSystem,
+	PromptAnalysis,
+	PromptVisionSystem,
+	PromptVision,
+	PromptEssayAcademicSystem,
+	PromptEssayMagazineSystem,
+	PromptEssay,
+	PromptTranslationSystem,
+	PromptTranslation,
+	PromptTLDRSystem,
+	PromptNotesSystem,
+	PromptFAQSystem,
+	PromptFlashcardsSystem,
+	PromptOutlineSystem,
+	PromptDigestSystem,
+	PromptDocument,
+	PromptChaptersSystem,
+	PromptChapters,
+	PromptAskSystem,
+	PromptAsk,
+}
+
+// promptFuncs are the functions available to templates in addition to the
+// text/template builtins.
+var promptFuncs = template.FuncMap{
+	// json renders a value as JSON, e.g. a list of texts to translate.
+	"json": func(v any) (string, error) {
+		data, err := json.Marshal(v)
+		return string(data), err
+	},
+}
+
+// TweetPromptData is the data for the tweet template, which describes a
+// tweet as the text passed to filename generation.
+type TweetPromptData struct {
+	Text       string
+	HasVideo   bool
+	HasImages  bool
+	ImageCount int
+}
+
+// Prompts is a parsed set of prompt templates.
+type Prompts struct {
+	templates  map[string]*template.Template
+	overridden map[string]bool
+	version    string
+}
+
+// PromptInfo describes one template in a prompt set.
+type PromptInfo struct {
+	Name       string `json:"name"`
+	Overridden bool   `json:"overridden"` // Loaded from the prompts directory rather than built in
+}
+
+// RenderedPrompt is the system and user prompt sent for one AI task.
+type RenderedPrompt struct {
+	Task    string `json:"task"`
+	Version string `json:"version"`
+	System  string `json:"system"`
+	User    string `json:"user"`
+}
+
+var defaultPrompts = sync.OnceValue(func() *Prompts {
+	p, err := parsePrompts(nil)
+	if err != nil {
+		panic(fmt.Sprintf("built-in prompt templates: %v", err))
+	}
+	return p
+})
+
+// DefaultPrompts returns the built-in prompt templates.
+func DefaultPrompts() *Prompts {
+	return defaultPrompts()
+}
+
+// LoadPrompts loads the built-in templates, overriding each one that has a
+// <name>.tmpl file in dir. Files with other extensions are ignored. An empty
+// dir returns the built-in templates.
+func LoadPrompts(dir string) (*Prompts, error) {
+	if dir == "" {
+		return DefaultPrompts(), nil
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("read prompts dir: %w", err)
+	}
+	overrides := make(map[string]string)
+	for _, entry := range entries {
+		if entry.IsDir() || filepath.Ext(entry.Name()) != ".tmpl" {
+			continue
+		}
+		name := strings.TrimSuffix(entry.Name(), ".tmpl")
+		if _, ok := promptData[name]; !ok {
+			return nil, fmt.Errorf("unknown prompt template %q", entry.Name())
+		}
+		data, err := os.ReadFile(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("read prompt template: %w", err)
+		}
+		overrides[name] = string(data)
+	}
+	return parsePrompts(overrides)
+}
+
+func parsePrompts(overrides map[string]string) (*Prompts, error) {
+	p := &Prompts{
+		templates:  make(map[string]*template.Template, len(promptNames)),
+		overridden: make(map[string]bool),
+	}
+	h := sha256.New()
+	for _, name := range promptNames {
+		src, ok := overrides[name]
+		if ok {
+			p.overridden[name] = true
+		} else {
+			data, err := defaultPromptFiles.ReadFile("prompts/" + name + ".tmpl")
+			if err != nil {
+				return nil, err
+			}
+			src = string(data)
+		}
+		// Files end with a newline that isn't part of the prompt
+		src = strings.TrimSuffix(strings.TrimSuffix(src, "\n"), "\r")
+
+		tmpl, err := template.New(name).Funcs(promptFuncs).Parse(src)
+		if err != nil {
+			return nil, fmt.Errorf("parse prompt template %s: %w", name, err)
+		}
+		if err := tmpl.Execute(&strings.Builder{}, promptData[name]); err != nil {
+			return nil, fmt.Errorf("prompt template %s: %w", name, err)
+		}
+		p.templates[name] = tmpl
+		fmt.Fprintf(h, "%s\x00%s\x00", name, src)
+	}
+	p.version = hex.EncodeToString(h.Sum(nil))[:12]
+	return p, nil
+}
+
+// Version identifies the template contents. It changes whenever any template
+// does, so results can be traced to the prompts that produced them.
+func (p *Prompts) Version() string {
+	return p.version
+}
+
+// List describes every template in the set.
+func (p *Prompts) List() []PromptInfo {
+	infos := make([]PromptInfo, 0, len(promptNames))
+	for _, name := range promptNames {
+		infos = append(infos, PromptInfo{Name: name, Overridden: p.overridden[name]})
+	}
+	return infos
+}
+
+func (p *Prompts) render(name string, data any) (string, error) {
+	var sb strings.Builder
+	if err := p.templates[name].Execute(&sb, data); err != nil {
+		return "", fmt.Errorf("render prompt %s: %w", name, err)
+	}
+	return sb.String(), nil
+}
+
+func (p *Prompts) renderTask(task, systemName, userName string, data any) (RenderedPrompt, error) {
+	system, err := p.render(systemName, data)
+	if err != nil {
+		return RenderedPrompt{}, err
+	}
+	user, err := p.render(userName, data)
+	if err != nil {
+		return RenderedPrompt{}, err
+	}
+	return RenderedPrompt{Task: task, Version: p.version, System: system, User: user}, nil
+}
+
+// RenderTweet renders the tweet description used as filename request text.
+func (p *Prompts) RenderTweet(data TweetPromptData) (string, error) {
+	return p.render(PromptTweet, data)
+}
+
+// RenderFilename renders the prompts for filename generation.
+func (p *Prompts) RenderFilename(req FilenameRequest) (RenderedPrompt, error) {
+	return p.renderTask(config.AITaskFilename, PromptFilenameSystem, PromptFilename, req)
+}
+
+// RenderAnalysis renders the prompts for text-only content analysis.
+func (p *Prompts) RenderAnalysis(req ContentAnalysisRequest) (RenderedPrompt, error) {
+	return p.renderTask(config.AITaskAnalysis, PromptAnalysisSystem, PromptAnalysis, req)
+}
+
+// RenderVision renders the prompts for vision analysis. The images are sent
+// alongside the user prompt.
+func (p *Prompts) RenderVision(req VisionAnalysisRequest) (RenderedPrompt, error) {
+	return p.renderTask(config.AITaskVision, PromptVisionSystem, PromptVision, req)
+}
+
+// RenderEssay renders the prompts for essay generation in the requested style.
+func (p *Prompts) RenderEssay(req EssayRequest) (RenderedPrompt, error) {
+	system := PromptEssayAcademicSystem
+	if req.Style == "magazine" {
+		system = PromptEssayMagazineSystem
+	}
+	return p.renderTask(config.AITaskEssay, system, PromptEssay, req)
+}
+
+// RenderTranslation renders the prompts for translating a batch of texts.
+func (p *Prompts) RenderTranslation(req TranslationRequest) (RenderedPrompt, error) {
+	return p.renderTask(config.AITaskTranslation, PromptTranslationSystem, PromptTranslation, req)
+}
+
+// RenderDocument renders the prompts for a derived document of req.Type.
+func (p *Prompts) RenderDocument(req DocumentRequest) (RenderedPrompt, error) {
+	system, ok := documentSystemPrompts[req.Type]
+	if !ok {
+		return RenderedPrompt{}, fmt.Errorf("unknown document type %q", req.Type)
+	}
+	return p.renderTask(config.AITaskDocument, system, PromptDocument, req)
+}
+
+// RenderChapters renders the prompts for splitting a transcript into chapters.
+func (p *Prompts) RenderChapters(req ChaptersRequest) (RenderedPrompt, error) {
+	return p.renderTask(config.AITaskChapters, PromptChaptersSystem, PromptChapters, req)
+}
+
+// RenderAsk renders the prompts for answering a question from archived tweets.
+func (p *Prompts) RenderAsk(req AskRequest) (RenderedPrompt, error) {
+	return p.renderTask(config.AITaskAsk, PromptAskSystem, PromptAsk, req)
+}
+
+// SetPrompts replaces the built-in prompt templates.
+func (c *HTTPClient) SetPrompts(prompts *Prompts) {
+	c.prompts = prompts
+}
+
+func (c *HTTPClient) promptSet() *Prompts {
+	if c.prompts == nil {
+		return DefaultPrompts()
+	}
+	return c.prompts
+}
+
+// SetPrompts sets the prompt templates on every routed provider that supports them.
+func (r *Router) SetPrompts(prompts *Prompts) {
+	for _, providers := range r.routes {
+		for _, p := range providers {
+			if c, ok := p.Client.(interface{ SetPrompts(*Prompts) }); ok {
+				c.SetPrompts(prompts)
+			}
+		}
+	}
+}
@@ -0,0 +1,149 @@
+package grok
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func writePrompt(t *testing.T, dir, name, src string) {
+	t.Helper()
+	if err := os.WriteFile(filepath.Join(dir, name), []byte(src), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestDefaultPrompts_RenderTweet(t *testing.T) {
+	got, err := DefaultPrompts().RenderTweet(TweetPromptData{Text: "Mixed media", HasVideo: true, HasImages: true, ImageCount: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "Mixed media\n[Contains video]\n[Contains 2 images]"; got != want {
+		t.Errorf("RenderTweet() = %q, want %q", got, want)
+	}
+}
+
+func TestDefaultPrompts_RenderEssayStyle(t *testing.T) {
+	p := DefaultPrompts()
+	academic, err := p.RenderEssay(EssayRequest{Transcript: "words"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	magazine, err := p.RenderEssay(EssayRequest{Transcript: "words", Style: "magazine"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(academic.System, "academic writer") || !strings.Contains(magazine.System, "magazine writer") {
+		t.Errorf("essay system prompts not selected by style")
+	}
+	if academic.User != "Transform this transcript into a polished essay:\n\nwords" {
+		t.Errorf("essay user prompt = %q", academic.User)
+	}
+	if academic.Task != "essay" || academic.Version != p.Version() {
+		t.Errorf("rendered = %+v", academic)
+	}
+}
+
+func TestLoadPrompts_Overrides(t *testing.T) {
+	dir := t.TempDir()
+	writePrompt(t, dir, "analysis.tmpl", "Tag tweets from @{{.AuthorUsername}} for our lab.\n")
+	writePrompt(t, dir, "README.md", "ignored")
+
+	p, err := LoadPrompts(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.Version() == DefaultPrompts().Version() || len(p.Version()) != 12 {
+		t.Errorf("version = %q, want a new 12 character version", p.Version())
+	}
+	for _, info := range p.List() {
+		if info.Overridden != (info.Name == PromptAnalysis) {
+			t.Errorf("%s overridden = %v", info.Name, info.Overridden)
+		}
+	}
+
+	rendered, err := p.RenderAnalysis(ContentAnalysisRequest{AuthorUsername: "someone"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rendered.User != "Tag tweets from @someone for our lab." {
+		t.Errorf("user prompt = %q", rendered.User)
+	}
+	if def, _ := DefaultPrompts().RenderAnalysis(ContentAnalysisRequest{}); rendered.System != def.System {
+		t.Error("system prompt should keep the built-in template")
+	}
+
+	if again, _ := LoadPrompts(dir); again.Version() != p.Version() {
+		t.Error("version is not stable across loads")
+	}
+}
+
+func TestLoadPrompts_Errors(t *testing.T) {
+	tests := []struct {
+		name string
+		file string
+		src  string
+	}{
+		{"unknown template", "summary.tmpl", "hello"},
+		{"parse error", "vision.tmpl", "{{if .HasVideo}}unclosed"},
+		{"missing field", "filename.tmpl", "{{.Handle}}"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			writePrompt(t, dir, tt.file, tt.src)
+			if _, err := LoadPrompts(dir); err == nil {
+				t.Error("LoadPrompts() succeeded, want error")
+			}
+		})
+	}
+
+	if _, err := LoadPrompts(filepath.Join(t.TempDir(), "missing")); err == nil {
+		t.Error("missing dir: LoadPrompts() succeeded, want error")
+	}
+}
+
+func TestHTTPClient_UsesPrompts(t *testing.T) {
+	var system string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Messages []struct {
+				Role    string `json:"role"`
+				Content string `json:"content"`
+			} `json:"messages"`
+		}
+		json.NewDecoder(r.Body).Decode(&req)
+		system = req.Messages[0].Content
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"choices": []map[string]interface{}{
+				{"message": map[string]string{"content": "cats_video"}},
+			},
+		})
+	}))
+	defer server.Close()
+
+	dir := t.TempDir()
+	writePrompt(t, dir, "filename_system.tmpl", "Name files for @{{.AuthorUsername}}.")
+	prompts, err := LoadPrompts(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	router := NewRouter()
+	client := &HTTPClient{model: "grok-2", baseURL: server.URL, httpClient: &http.Client{Timeout: 5 * time.Second}}
+	router.SetRoute("filename", Provider{Name: "grok", Client: client})
+	router.SetPrompts(prompts)
+
+	if _, err := router.GenerateFilename(context.Background(), FilenameRequest{AuthorUsername: "someone"}); err != nil {
+		t.Fatal(err)
+	}
+	if system != "Name files for @someone." {
+		t.Errorf("system prompt = %q", system)
+	}
+}
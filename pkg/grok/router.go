@@ -0,0 +1,165 @@
+package grok
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/iconidentify/xgrabba/internal/config"
+)
+
+// Provider is a named AI backend that a Router can send tasks to.
+type Provider struct {
+	Name   string
+	Client Client
+}
+
+// Router implements Client by sending each task to its routed provider and
+// retrying on the next provider in the route when one fails.
+type Router struct {
+	routes map[string][]Provider // Keyed by config.AITask*
+}
+
+// NewRouter creates a router with no routes. Unrouted tasks return an error.
+func NewRouter() *Router {
+	return &Router{routes: make(map[string][]Provider)}
+}
+
+// SetRoute sets the providers tried, in order, for a task.
+func (r *Router) SetRoute(task string, providers ...Provider) {
+	r.routes[task] = providers
+}
+
+// NewRouterFromConfig builds the providers named in the AI config and routes
+// each task to its provider, followed by the fallback provider when set.
+// Tasks routed to "none" have no provider and fail, so callers use their
+// non-AI fallbacks.
+func NewRouterFromConfig(grokCfg config.GrokConfig, aiCfg config.AIConfig) *Router {
+	clients := make(map[string]Client)
+	provider := func(name string) (Provider, bool) {
+		if name == "" || name == "none" {
+			return Provider{}, false
+		}
+		client, ok := clients[name]
+		if !ok {
+			switch name {
+			case "grok":
+				client = NewClient(grokCfg)
+			case "openai":
+				client = NewOpenAIClient(aiCfg.OpenAI)
+			default:
+				return Provider{}, false
+			}
+			clients[name] = client
+		}
+		return Provider{Name: name, Client: client}, true
+	}
+
+	router := NewRouter()
+	for _, task := range config.AITasks {
+		var providers []Provider
+		primary := aiCfg.ProviderFor(task)
+		if p, ok := provider(primary); ok {
+			providers = append(providers, p)
+		}
+		if aiCfg.FallbackProvider != primary {
+			if p, ok := provider(aiCfg.FallbackProvider); ok {
+				providers = append(providers, p)
+			}
+		}
+		router.SetRoute(task, providers...)
+	}
+	return router
+}
+
+// GenerateFilename creates a descriptive filename based on video metadata.
+func (r *Router) GenerateFilename(ctx context.Context, req FilenameRequest) (string, error) {
+	return route(ctx, r, config.AITaskFilename, func(c Client) (string, error) {
+		return c.GenerateFilename(ctx, req)
+	})
+}
+
+// AnalyzeContent generates searchable tags and description for media content.
+func (r *Router) AnalyzeContent(ctx context.Context, req ContentAnalysisRequest) (*ContentAnalysisResponse, error) {
+	return route(ctx, r, config.AITaskAnalysis, func(c Client) (*ContentAnalysisResponse, error) {
+		return c.AnalyzeContent(ctx, req)
+	})
+}
+
+// AnalyzeContentWithVision analyzes content including actual images for rich metadata.
+func (r *Router) AnalyzeContentWithVision(ctx context.Context, req VisionAnalysisRequest) (*ContentAnalysisResponse, error) {
+	return route(ctx, r, config.AITaskVision, func(c Client) (*ContentAnalysisResponse, error) {
+		return c.AnalyzeContentWithVision(ctx, req)
+	})
+}
+
+// GenerateEssay creates a high-quality markdown essay from a video transcript.
+func (r *Router) GenerateEssay(ctx context.Context, req EssayRequest) (*EssayResponse, error) {
+	return route(ctx, r, config.AITaskEssay, func(c Client) (*EssayResponse, error) {
+		return c.GenerateEssay(ctx, req)
+	})
+}
+
+// Translate translates a batch of texts into another language.
+func (r *Router) Translate(ctx context.Context, req TranslationRequest) (*TranslationResponse, error) {
+	return route(ctx, r, config.AITaskTranslation, func(c Client) (*TranslationResponse, error) {
+		return c.Translate(ctx, req)
+	})
+}
+
+// GenerateDocument creates a derived markdown document.
+func (r *Router) GenerateDocument(ctx context.Context, req DocumentRequest) (*DocumentResponse, error) {
+	return route(ctx, r, config.AITaskDocument, func(c Client) (*DocumentResponse, error) {
+		return c.GenerateDocument(ctx, req)
+	})
+}
+
+// GenerateChapters splits a timed video transcript into titled chapters.
+func (r *Router) GenerateChapters(ctx context.Context, req ChaptersRequest) (*ChaptersResponse, error) {
+	return route(ctx, r, config.AITaskChapters, func(c Client) (*ChaptersResponse, error) {
+		return c.GenerateChapters(ctx, req)
+	})
+}
+
+// Ask answers a question from archived tweets. Once part of an answer has
+// been streamed, a failure is not retried on the next provider, which would
+// stream a second answer after the partial first one.
+func (r *Router) Ask(ctx context.Context, req AskRequest, onDelta func(string)) (*AskResponse, error) {
+	streamed := false
+	var delta func(string)
+	if onDelta != nil {
+		delta = func(text string) {
+			streamed = true
+			onDelta(text)
+		}
+	}
+	return route(ctx, r, config.AITaskAsk, func(c Client) (*AskResponse, error) {
+		if streamed {
+			return nil, errors.New("skipped after a partially streamed answer")
+		}
+		return c.Ask(ctx, req, delta)
+	})
+}
+
+// route calls each provider for task in order until one succeeds. It stops
+// early when the context is done, since later providers would fail too.
+func route[T any](ctx context.Context, r *Router, task string, call func(Client) (T, error)) (T, error) {
+	var zero T
+	providers := r.routes[task]
+	if len(providers) == 0 {
+		return zero, fmt.Errorf("no AI provider configured for %s", task)
+	}
+
+	var errs []error
+	for _, p := range providers {
+		out, err := call(p.Client)
+		if err == nil {
+			return out, nil
+		}
+		errs = append(errs, fmt.Errorf("%s: %w", p.Name, err))
+		if ctx.Err() != nil {
+			break
+		}
+	}
+	return zero, fmt.Errorf("%s failed: %w", task, errors.Join(errs...))
+}
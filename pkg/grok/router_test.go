@@ -0,0 +1,264 @@
+package grok
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/iconidentify/xgrabba/internal/config"
+)
+
+// stubClient is a Client that returns a fixed filename or error.
+type stubClient struct {
+	filename string
+	err      error
+	calls    int
+}
+
+func (s *stubClient) GenerateFilename(ctx context.Context, req FilenameRequest) (string, error) {
+	s.calls++
+	return s.filename, s.err
+}
+
+func (s *stubClient) AnalyzeContent(ctx context.Context, req ContentAnalysisRequest) (*ContentAnalysisResponse, error) {
+	s.calls++
+	return &ContentAnalysisResponse{Summary: s.filename}, s.err
+}
+
+func (s *stubClient) AnalyzeContentWithVision(ctx context.Context, req VisionAnalysisRequest) (*ContentAnalysisResponse, error) {
+	s.calls++
+	return &ContentAnalysisResponse{Summary: s.filename}, s.err
+}
+
+func (s *stubClient) GenerateEssay(ctx context.Context, req EssayRequest) (*EssayResponse, error) {
+	s.calls++
+	return &EssayResponse{Title: s.filename}, s.err
+}
+
+func (s *stubClient) Translate(ctx context.Context, req TranslationRequest) (*TranslationResponse, error) {
+	s.calls++
+	return &TranslationResponse{Texts: req.Texts}, s.err
+}
+
+func (s *stubClient) GenerateDocument(ctx context.Context, req DocumentRequest) (*DocumentResponse, error) {
+	s.calls++
+	return &DocumentResponse{Title: s.filename}, s.err
+}
+
+func (s *stubClient) GenerateChapters(ctx context.Context, req ChaptersRequest) (*ChaptersResponse, error) {
+	s.calls++
+	return &ChaptersResponse{Chapters: []ChapterItem{{Title: s.filename}}}, s.err
+}
+
+// Ask streams the fixed filename as the answer before returning the error.
+func (s *stubClient) Ask(ctx context.Context, req AskRequest, onDelta func(string)) (*AskResponse, error) {
+	s.calls++
+	if onDelta != nil && s.filename != "" {
+		onDelta(s.filename)
+	}
+	return &AskResponse{Answer: s.filename}, s.err
+}
+
+func TestRouter_FallbackOnError(t *testing.T) {
+	primary := &stubClient{err: errors.New("model overloaded")}
+	fallback := &stubClient{filename: "from_fallback"}
+	router := NewRouter()
+	router.SetRoute(config.AITaskFilename, Provider{"openai", primary}, Provider{"grok", fallback})
+
+	got, err := router.GenerateFilename(context.Background(), FilenameRequest{})
+	if err != nil {
+		t.Fatalf("GenerateFilename() error = %v", err)
+	}
+	if got != "from_fallback" || primary.calls != 1 || fallback.calls != 1 {
+		t.Errorf("got %q with calls %d/%d; want fallback result after one primary call", got, primary.calls, fallback.calls)
+	}
+}
+
+func TestRouter_AskNoFallbackAfterPartialStream(t *testing.T) {
+	router := NewRouter()
+	fallback := &stubClient{filename: "second answer"}
+	router.SetRoute(config.AITaskAsk,
+		Provider{"openai", &stubClient{filename: "Cats ", err: errors.New("connection reset")}},
+		Provider{"grok", fallback},
+	)
+
+	var streamed string
+	if _, err := router.Ask(context.Background(), AskRequest{Question: "cats?"}, func(text string) { streamed += text }); err == nil {
+		t.Fatal("Ask() should fail when the stream breaks")
+	}
+	if streamed != "Cats " || fallback.calls != 0 {
+		t.Errorf("streamed %q with %d fallback calls; want only the partial answer", streamed, fallback.calls)
+	}
+
+	// Without streaming the fallback answers as usual
+	got, err := router.Ask(context.Background(), AskRequest{Question: "cats?"}, nil)
+	if err != nil || got.Answer != "second answer" {
+		t.Errorf("Ask() without streaming = %+v, %v", got, err)
+	}
+}
+
+func TestRouter_AllProvidersFail(t *testing.T) {
+	router := NewRouter()
+	router.SetRoute(config.AITaskEssay,
+		Provider{"openai", &stubClient{err: errors.New("connection refused")}},
+		Provider{"grok", &stubClient{err: errors.New("rate limited")}},
+	)
+
+	_, err := router.GenerateEssay(context.Background(), EssayRequest{Transcript: "text"})
+	if err == nil {
+		t.Fatal("GenerateEssay() should fail when every provider fails")
+	}
+	for _, want := range []string{"openai: connection refused", "grok: rate limited"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("error %q missing %q", err, want)
+		}
+	}
+}
+
+func TestRouter_StopsWhenContextDone(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	fallback := &stubClient{filename: "unused"}
+	router := NewRouter()
+	router.SetRoute(config.AITaskAnalysis, Provider{"openai", &stubClient{err: context.Canceled}}, Provider{"grok", fallback})
+
+	if _, err := router.AnalyzeContent(ctx, ContentAnalysisRequest{}); !errors.Is(err, context.Canceled) {
+		t.Errorf("AnalyzeContent() error = %v, want context.Canceled", err)
+	}
+	if fallback.calls != 0 {
+		t.Errorf("fallback called %d times after cancellation", fallback.calls)
+	}
+}
+
+func TestRouter_Unrouted(t *testing.T) {
+	if _, err := NewRouter().AnalyzeContentWithVision(context.Background(), VisionAnalysisRequest{}); err == nil {
+		t.Error("AnalyzeContentWithVision() should fail without a route")
+	}
+}
+
+func TestNewRouterFromConfig(t *testing.T) {
+	router := NewRouterFromConfig(config.GrokConfig{APIKey: "key"}, config.AIConfig{
+		Provider:         "openai",
+		FallbackProvider: "grok",
+		EssayProvider:    "grok",
+		VisionProvider:   "none",
+		OpenAI:           config.OpenAIConfig{BaseURL: "http://localhost:11434/v1", Model: "llama3.2"},
+	})
+
+	names := func(task string) []string {
+		var out []string
+		for _, p := range router.routes[task] {
+			out = append(out, p.Name)
+		}
+		return out
+	}
+	if got := strings.Join(names(config.AITaskFilename), ","); got != "openai,grok" {
+		t.Errorf("filename route = %s, want openai,grok", got)
+	}
+	if got := strings.Join(names(config.AITaskEssay), ","); got != "grok" {
+		t.Errorf("essay route = %s, want grok", got)
+	}
+	if got := strings.Join(names(config.AITaskVision), ","); got != "grok" {
+		t.Errorf("vision route = %s, want grok (fallback only)", got)
+	}
+	if router.routes[config.AITaskFilename][0].Client != router.routes[config.AITaskAnalysis][0].Client {
+		t.Error("tasks on the same provider should share one client")
+	}
+}
+
+func TestOpenAIClient_LocalBackend(t *testing.T) {
+	var models []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if auth := r.Header.Get("Authorization"); auth != "" {
+			t.Errorf("Authorization = %q, want none without an API key", auth)
+		}
+		var req chatRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Fatalf("decode request: %v", err)
+		}
+		models = append(models, req.Model)
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"choices": []map[string]interface{}{
+				{"message": map[string]string{"content": `{"title":"Local","essay":"Body text"}`}},
+			},
+		})
+	}))
+	defer server.Close()
+
+	client := NewOpenAIClient(config.OpenAIConfig{
+		BaseURL:    server.URL + "/",
+		Model:      "llama3.2",
+		EssayModel: "qwen2.5:14b",
+		Timeout:    5 * time.Second,
+	})
+
+	if _, err := client.GenerateFilename(context.Background(), FilenameRequest{AuthorUsername: "someone"}); err != nil {
+		t.Fatalf("GenerateFilename() error = %v", err)
+	}
+	essay, err := client.GenerateEssay(context.Background(), EssayRequest{Transcript: "hello"})
+	if err != nil {
+		t.Fatalf("GenerateEssay() error = %v", err)
+	}
+	if essay.Title != "Local" {
+		t.Errorf("essay title = %q, want Local", essay.Title)
+	}
+	if strings.Join(models, ",") != "llama3.2,qwen2.5:14b" {
+		t.Errorf("models = %v, want [llama3.2 qwen2.5:14b]", models)
+	}
+	if client.visionModelName() != "llama3.2" {
+		t.Errorf("vision model = %q, want the main model", client.visionModelName())
+	}
+}
+
+type usageLog []Usage
+
+func (u *usageLog) RecordAIUsage(ctx context.Context, usage Usage) {
+	*u = append(*u, usage)
+}
+
+func TestHTTPClient_RecordsUsage(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"choices": []map[string]interface{}{
+				{"message": map[string]string{"content": "some_title"}},
+			},
+			"usage": map[string]int{"prompt_tokens": 120, "completion_tokens": 8, "total_tokens": 128},
+		})
+	}))
+	defer server.Close()
+
+	var log usageLog
+	router := NewRouterFromConfig(config.GrokConfig{APIKey: "key", BaseURL: server.URL, Model: "grok-3", Timeout: 5 * time.Second}, config.AIConfig{})
+	router.SetUsageRecorder(&log)
+
+	if _, err := router.GenerateFilename(context.Background(), FilenameRequest{}); err != nil {
+		t.Fatalf("GenerateFilename() error = %v", err)
+	}
+	want := Usage{Provider: "grok", Model: "grok-3", Task: config.AITaskFilename, PromptTokens: 120, CompletionTokens: 8}
+	if len(log) != 1 || log[0] != want {
+		t.Errorf("usage = %+v, want [%+v]", log, want)
+	}
+}
+
+func TestEstimateCost(t *testing.T) {
+	tests := []struct {
+		model string
+		want  float64
+	}{
+		{"grok-3", 3.00 + 15.00},
+		{"grok-3-mini", 0.30 + 0.50},
+		{"grok-2-vision-1212", 2.00 + 10.00},
+		{"gpt-4o-mini", 0.15 + 0.60},
+		{"llama3.2", 0},
+	}
+	for _, tt := range tests {
+		if got := EstimateCost(tt.model, 1_000_000, 1_000_000); got != tt.want {
+			t.Errorf("EstimateCost(%q) = %f, want %f", tt.model, got, tt.want)
+		}
+	}
+}
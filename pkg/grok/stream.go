@@ -0,0 +1,121 @@
+package grok
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// streamOptions asks for token usage in the final chunk of a streamed reply.
+type streamOptions struct {
+	IncludeUsage bool `json:"include_usage"`
+}
+
+// streamChunk is one server-sent event of a streamed chat completion.
+type streamChunk struct {
+	Choices []struct {
+		Delta struct {
+			Content string `json:"content"`
+		} `json:"delta"`
+	} `json:"choices"`
+	Usage *struct {
+		PromptTokens     int `json:"prompt_tokens"`
+		CompletionTokens int `json:"completion_tokens"`
+	} `json:"usage,omitempty"`
+	Error *struct {
+		Message string `json:"message"`
+	} `json:"error,omitempty"`
+}
+
+// completeStream is like complete, but streams the reply to onDelta as it
+// is generated. A cached reply is passed to onDelta in one piece. Streamed
+// and non-streamed requests share cache entries.
+func (c *HTTPClient) completeStream(ctx context.Context, task string, chatReq chatRequest, onDelta func(string)) (string, error) {
+	var key string
+	if c.cache != nil {
+		if k, err := cacheKey(c.providerName(), task, chatReq); err == nil {
+			if reply, ok := c.cache.get(k, task); ok {
+				onDelta(reply)
+				return reply, nil
+			}
+			key = k
+		}
+	}
+
+	chatReq.Stream = true
+	chatReq.StreamOptions = &streamOptions{IncludeUsage: true}
+	body, err := json.Marshal(chatReq)
+	if err != nil {
+		return "", fmt.Errorf("marshal request: %w", err)
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, "POST", c.baseURL+"/chat/completions", bytes.NewReader(body))
+	if err != nil {
+		return "", fmt.Errorf("create request: %w", err)
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set("Accept", "text/event-stream")
+	if c.apiKey != "" {
+		httpReq.Header.Set("Authorization", "Bearer "+c.apiKey)
+	}
+
+	resp, err := c.httpClient.Do(httpReq)
+	if err != nil {
+		return "", fmt.Errorf("send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		respBody, _ := io.ReadAll(resp.Body)
+		return "", fmt.Errorf("API error (status %d): %s", resp.StatusCode, string(respBody))
+	}
+
+	var reply strings.Builder
+	var promptTokens, completionTokens int
+	scanner := bufio.NewScanner(resp.Body)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		data, ok := strings.CutPrefix(scanner.Text(), "data:")
+		if !ok {
+			continue // Blank separators, comments and other SSE fields
+		}
+		data = strings.TrimSpace(data)
+		if data == "[DONE]" {
+			break
+		}
+
+		var chunk streamChunk
+		if err := json.Unmarshal([]byte(data), &chunk); err != nil {
+			return "", fmt.Errorf("unmarshal stream chunk: %w", err)
+		}
+		if chunk.Error != nil {
+			return "", fmt.Errorf("API error: %s", chunk.Error.Message)
+		}
+		if chunk.Usage != nil {
+			promptTokens, completionTokens = chunk.Usage.PromptTokens, chunk.Usage.CompletionTokens
+		}
+		for _, choice := range chunk.Choices {
+			if choice.Delta.Content != "" {
+				reply.WriteString(choice.Delta.Content)
+				onDelta(choice.Delta.Content)
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return "", fmt.Errorf("read stream: %w", err)
+	}
+	if reply.Len() == 0 {
+		return "", fmt.Errorf("no response from %s", c.providerName())
+	}
+
+	if promptTokens > 0 || completionTokens > 0 {
+		c.recordUsage(ctx, task, chatReq.Model, promptTokens, completionTokens)
+	}
+	c.cachePut(key, task, chatReq.Model, reply.String())
+	return reply.String(), nil
+}
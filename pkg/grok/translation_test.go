@@ -0,0 +1,81 @@
+package grok
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func translationServer(t *testing.T, reply string, user *string) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Messages []struct {
+				Content string `json:"content"`
+			} `json:"messages"`
+		}
+		json.NewDecoder(r.Body).Decode(&req)
+		if user != nil {
+			*user = req.Messages[1].Content
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"choices": []map[string]interface{}{
+				{"message": map[string]string{"content": reply}},
+			},
+		})
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestHTTPClient_Translate_Success(t *testing.T) {
+	var user string
+	server := translationServer(t, "```json\n{\"source_language\":\"JA\",\"translations\":[\"Hello\",\"Good night\"]}\n```", &user)
+	client := &HTTPClient{baseURL: server.URL, httpClient: &http.Client{Timeout: 5 * time.Second}}
+
+	result, err := client.Translate(context.Background(), TranslationRequest{
+		TargetLanguage: "en",
+		Texts:          []string{"こんにちは", "おやすみ \"なさい\""},
+	})
+	if err != nil {
+		t.Fatalf("Translate failed: %v", err)
+	}
+	if result.SourceLanguage != "ja" {
+		t.Errorf("SourceLanguage = %q, want ja", result.SourceLanguage)
+	}
+	if strings.Join(result.Texts, "|") != "Hello|Good night" {
+		t.Errorf("Texts = %v", result.Texts)
+	}
+	if !strings.Contains(user, `["こんにちは","おやすみ \"なさい\""]`) {
+		t.Errorf("texts not sent as a JSON array: %q", user)
+	}
+}
+
+func TestHTTPClient_Translate_CountMismatch(t *testing.T) {
+	server := translationServer(t, `{"source_language":"es","translations":["one"]}`, nil)
+	client := &HTTPClient{baseURL: server.URL, httpClient: &http.Client{Timeout: 5 * time.Second}}
+
+	_, err := client.Translate(context.Background(), TranslationRequest{TargetLanguage: "en", Texts: []string{"uno", "dos"}})
+	if err == nil {
+		t.Fatal("expected error when the reply has fewer translations than texts")
+	}
+}
+
+func TestHTTPClient_Translate_NoTexts(t *testing.T) {
+	client := &HTTPClient{baseURL: "http://127.0.0.1:0", httpClient: &http.Client{Timeout: time.Second}}
+
+	result, err := client.Translate(context.Background(), TranslationRequest{TargetLanguage: "en", SourceLanguage: "ar"})
+	if err != nil {
+		t.Fatalf("Translate with no texts should not call the API: %v", err)
+	}
+	if len(result.Texts) != 0 || result.SourceLanguage != "ar" {
+		t.Errorf("result = %+v", result)
+	}
+	if _, err := client.Translate(context.Background(), TranslationRequest{Texts: []string{"hola"}}); err == nil {
+		t.Error("expected error without a target language")
+	}
+}
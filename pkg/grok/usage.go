@@ -0,0 +1,62 @@
+package grok
+
+import (
+	"context"
+	"strings"
+)
+
+// Usage is the token usage of one successful chat request.
+type Usage struct {
+	Provider         string
+	Model            string
+	Task             string // config.AITask*
+	PromptTokens     int
+	CompletionTokens int
+}
+
+// UsageRecorder receives the usage of every successful chat request. The
+// request context is passed through so recorders can read caller metadata.
+type UsageRecorder interface {
+	RecordAIUsage(ctx context.Context, usage Usage)
+}
+
+// modelPrices are list prices in USD per million input and output tokens,
+// matched by model name prefix. Longer prefixes must come first.
+var modelPrices = []struct {
+	prefix        string
+	input, output float64
+}{
+	{"grok-3-mini", 0.30, 0.50},
+	{"grok-2-vision", 2.00, 10.00},
+	{"grok-", 3.00, 15.00},
+	{"gpt-4o-mini", 0.15, 0.60},
+	{"gpt-4o", 2.50, 10.00},
+}
+
+// EstimateCost estimates the cost of a chat request in USD. Unknown models,
+// such as those served locally, are assumed to be free.
+func EstimateCost(model string, promptTokens, completionTokens int) float64 {
+	model = strings.ToLower(model)
+	for _, p := range modelPrices {
+		if strings.HasPrefix(model, p.prefix) {
+			return (float64(promptTokens)*p.input + float64(completionTokens)*p.output) / 1e6
+		}
+	}
+	return 0
+}
+
+// SetUsageRecorder reports the token usage of each successful request to r.
+func (c *HTTPClient) SetUsageRecorder(r UsageRecorder) {
+	c.usage = r
+}
+
+// SetUsageRecorder reports usage from every routed provider that supports it.
+func (r *Router) SetUsageRecorder(rec UsageRecorder) {
+	for _, providers := range r.routes {
+		for _, p := range providers {
+			if c, ok := p.Client.(interface{ SetUsageRecorder(UsageRecorder) }); ok {
+				c.SetUsageRecorder(rec)
+			}
+		}
+	}
+}
@@ -0,0 +1,88 @@
+// Package ocr extracts text from images using a pluggable engine.
+package ocr
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+// Engine extracts text from an image on disk.
+type Engine interface {
+	// ExtractText returns the text found in the image at imagePath.
+	// An image with no text returns an empty string and no error.
+	ExtractText(ctx context.Context, imagePath string) (string, error)
+	// Name identifies the engine (e.g. "tesseract", "vision").
+	Name() string
+}
+
+// Engine names accepted by Config.Engine.
+const (
+	EngineAuto      = "auto"
+	EngineTesseract = "tesseract"
+	EngineVision    = "vision"
+	EngineNone      = "none"
+)
+
+// Config for creating an OCR engine.
+type Config struct {
+	Engine        string        // auto, tesseract, vision or none
+	TesseractPath string        // Optional, defaults to "tesseract" on PATH
+	Languages     string        // Tesseract languages, e.g. "eng+deu"
+	APIKey        string        // Vision endpoint API key
+	BaseURL       string        // OpenAI-compatible base URL
+	Model         string        // Vision model name
+	Timeout       time.Duration // Per-image timeout
+}
+
+// New builds the engine selected by cfg. With EngineAuto it prefers a local
+// tesseract binary and falls back to the vision endpoint when an API key is set.
+// It returns a nil Engine (and no error) when OCR is disabled or unavailable.
+func New(cfg Config) (Engine, error) {
+	switch strings.ToLower(strings.TrimSpace(cfg.Engine)) {
+	case "", EngineAuto:
+		if TesseractAvailable(cfg.TesseractPath) {
+			return NewTesseractEngine(cfg)
+		}
+		if cfg.APIKey != "" {
+			return NewVisionEngine(cfg), nil
+		}
+		return nil, nil
+	case EngineTesseract:
+		return NewTesseractEngine(cfg)
+	case EngineVision:
+		if cfg.APIKey == "" {
+			return nil, fmt.Errorf("vision OCR requires an API key")
+		}
+		return NewVisionEngine(cfg), nil
+	case EngineNone:
+		return nil, nil
+	default:
+		return nil, fmt.Errorf("unknown OCR engine %q", cfg.Engine)
+	}
+}
+
+// TesseractAvailable reports whether the tesseract binary can be found.
+func TesseractAvailable(path string) bool {
+	if path == "" {
+		path = "tesseract"
+	}
+	_, err := exec.LookPath(path)
+	return err == nil
+}
+
+// CleanText normalizes OCR output: trims lines, drops blank lines and
+// collapses runs of whitespace.
+func CleanText(s string) string {
+	lines := strings.Split(s, "\n")
+	out := make([]string, 0, len(lines))
+	for _, line := range lines {
+		line = strings.Join(strings.Fields(line), " ")
+		if line != "" {
+			out = append(out, line)
+		}
+	}
+	return strings.Join(out, "\n")
+}
@@ -0,0 +1,121 @@
+package ocr
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestCleanText(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{"empty", "", ""},
+		{"blank lines dropped", "\n\n  hello  \n\n", "hello"},
+		{"collapses spaces", "a   b\tc\nd", "a b c\nd"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := CleanText(tt.in); got != tt.want {
+				t.Errorf("CleanText(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNew(t *testing.T) {
+	if e, err := New(Config{Engine: EngineNone}); err != nil || e != nil {
+		t.Errorf("none: got (%v, %v), want (nil, nil)", e, err)
+	}
+	if _, err := New(Config{Engine: "bogus"}); err == nil {
+		t.Error("unknown engine should error")
+	}
+	if _, err := New(Config{Engine: EngineVision}); err == nil {
+		t.Error("vision without API key should error")
+	}
+	if _, err := New(Config{Engine: EngineTesseract, TesseractPath: "/nonexistent/tesseract"}); err == nil {
+		t.Error("missing tesseract should error")
+	}
+
+	e, err := New(Config{Engine: EngineAuto, TesseractPath: "/nonexistent/tesseract", APIKey: "k"})
+	if err != nil {
+		t.Fatalf("auto: %v", err)
+	}
+	if e == nil || e.Name() != EngineVision {
+		t.Errorf("auto should fall back to vision, got %v", e)
+	}
+}
+
+func TestVisionEngine_ExtractText(t *testing.T) {
+	dir := t.TempDir()
+	imgPath := filepath.Join(dir, "shot.png")
+	if err := os.WriteFile(imgPath, []byte("fake-png"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	reply := "  BREAKING   NEWS \n\n second line "
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/chat/completions" {
+			t.Errorf("path = %s", r.URL.Path)
+		}
+		if r.Header.Get("Authorization") != "Bearer test-key" {
+			t.Errorf("missing auth header")
+		}
+		var req visionRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Fatalf("decode: %v", err)
+		}
+		if len(req.Messages) != 1 || len(req.Messages[0].Content) != 2 {
+			t.Fatalf("unexpected request shape: %+v", req)
+		}
+		if !strings.HasPrefix(req.Messages[0].Content[1].ImageURL.URL, "data:image/png;base64,") {
+			t.Errorf("image not sent as png data URL")
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"choices": []map[string]interface{}{
+				{"message": map[string]string{"content": reply}},
+			},
+		})
+	}))
+	defer server.Close()
+
+	e := NewVisionEngine(Config{APIKey: "test-key", BaseURL: server.URL + "/"})
+	got, err := e.ExtractText(context.Background(), imgPath)
+	if err != nil {
+		t.Fatalf("ExtractText: %v", err)
+	}
+	if got != "BREAKING NEWS\nsecond line" {
+		t.Errorf("got %q", got)
+	}
+
+	reply = "NO_TEXT"
+	got, err = e.ExtractText(context.Background(), imgPath)
+	if err != nil || got != "" {
+		t.Errorf("NO_TEXT: got (%q, %v)", got, err)
+	}
+}
+
+func TestVisionEngine_APIError(t *testing.T) {
+	dir := t.TempDir()
+	imgPath := filepath.Join(dir, "shot.jpg")
+	os.WriteFile(imgPath, []byte("x"), 0644)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+		w.Write([]byte(`{"error":{"message":"bad key"}}`))
+	}))
+	defer server.Close()
+
+	e := NewVisionEngine(Config{APIKey: "k", BaseURL: server.URL})
+	if _, err := e.ExtractText(context.Background(), imgPath); err == nil {
+		t.Error("expected error on 401")
+	}
+}
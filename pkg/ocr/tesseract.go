@@ -0,0 +1,60 @@
+package ocr
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+// TesseractEngine runs a local tesseract binary.
+type TesseractEngine struct {
+	binPath   string
+	languages string
+	timeout   time.Duration
+}
+
+// NewTesseractEngine creates an engine backed by the tesseract CLI.
+func NewTesseractEngine(cfg Config) (*TesseractEngine, error) {
+	bin := cfg.TesseractPath
+	if bin == "" {
+		bin = "tesseract"
+	}
+	resolved, err := exec.LookPath(bin)
+	if err != nil {
+		return nil, fmt.Errorf("tesseract not found: %w", err)
+	}
+	lang := cfg.Languages
+	if lang == "" {
+		lang = "eng"
+	}
+	timeout := cfg.Timeout
+	if timeout == 0 {
+		timeout = 60 * time.Second
+	}
+	return &TesseractEngine{binPath: resolved, languages: lang, timeout: timeout}, nil
+}
+
+// Name implements Engine.
+func (e *TesseractEngine) Name() string {
+	return EngineTesseract
+}
+
+// ExtractText implements Engine.
+func (e *TesseractEngine) ExtractText(ctx context.Context, imagePath string) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, e.timeout)
+	defer cancel()
+
+	// "stdout" as the output base makes tesseract print the text instead of writing a file
+	cmd := exec.CommandContext(ctx, e.binPath, imagePath, "stdout", "-l", e.languages)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("tesseract failed: %w: %s", err, strings.TrimSpace(stderr.String()))
+	}
+	return CleanText(stdout.String()), nil
+}
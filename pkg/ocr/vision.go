@@ -0,0 +1,165 @@
+package ocr
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// visionPrompt asks the model for a verbatim transcription only.
+const visionPrompt = `Transcribe ALL text visible in this image exactly as written, preserving line breaks.
+Do not describe the image, translate, summarize or add commentary.
+If there is no readable text, reply with exactly: NO_TEXT`
+
+// VisionEngine uses an OpenAI-compatible chat completions endpoint with image input.
+type VisionEngine struct {
+	apiKey     string
+	baseURL    string
+	model      string
+	httpClient *http.Client
+}
+
+// NewVisionEngine creates an engine backed by a vision-capable chat model.
+func NewVisionEngine(cfg Config) *VisionEngine {
+	if cfg.BaseURL == "" {
+		cfg.BaseURL = "https://api.openai.com/v1"
+	}
+	if cfg.Model == "" {
+		cfg.Model = "gpt-4o-mini"
+	}
+	if cfg.Timeout == 0 {
+		cfg.Timeout = 60 * time.Second
+	}
+	return &VisionEngine{
+		apiKey:  cfg.APIKey,
+		baseURL: strings.TrimSuffix(cfg.BaseURL, "/"),
+		model:   cfg.Model,
+		httpClient: &http.Client{
+			Timeout: cfg.Timeout,
+		},
+	}
+}
+
+// Name implements Engine.
+func (e *VisionEngine) Name() string {
+	return EngineVision
+}
+
+type visionRequest struct {
+	Model       string          `json:"model"`
+	Messages    []visionMessage `json:"messages"`
+	Temperature float64         `json:"temperature"`
+}
+
+type visionMessage struct {
+	Role    string       `json:"role"`
+	Content []visionPart `json:"content"`
+}
+
+type visionPart struct {
+	Type     string          `json:"type"`
+	Text     string          `json:"text,omitempty"`
+	ImageURL *visionImageURL `json:"image_url,omitempty"`
+}
+
+type visionImageURL struct {
+	URL    string `json:"url"`
+	Detail string `json:"detail,omitempty"`
+}
+
+type visionResponse struct {
+	Choices []struct {
+		Message struct {
+			Content string `json:"content"`
+		} `json:"message"`
+	} `json:"choices"`
+	Error *struct {
+		Message string `json:"message"`
+	} `json:"error,omitempty"`
+}
+
+// ExtractText implements Engine.
+func (e *VisionEngine) ExtractText(ctx context.Context, imagePath string) (string, error) {
+	data, err := os.ReadFile(imagePath)
+	if err != nil {
+		return "", fmt.Errorf("read image: %w", err)
+	}
+
+	dataURL := fmt.Sprintf("data:%s;base64,%s", imageMIMEType(imagePath), base64.StdEncoding.EncodeToString(data))
+	reqBody := visionRequest{
+		Model: e.model,
+		Messages: []visionMessage{{
+			Role: "user",
+			Content: []visionPart{
+				{Type: "text", Text: visionPrompt},
+				{Type: "image_url", ImageURL: &visionImageURL{URL: dataURL, Detail: "high"}},
+			},
+		}},
+	}
+
+	body, err := json.Marshal(reqBody)
+	if err != nil {
+		return "", fmt.Errorf("marshal request: %w", err)
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, "POST", e.baseURL+"/chat/completions", bytes.NewReader(body))
+	if err != nil {
+		return "", fmt.Errorf("create request: %w", err)
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	if e.apiKey != "" {
+		httpReq.Header.Set("Authorization", "Bearer "+e.apiKey)
+	}
+
+	resp, err := e.httpClient.Do(httpReq)
+	if err != nil {
+		return "", fmt.Errorf("send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", fmt.Errorf("read response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("API error (status %d): %s", resp.StatusCode, string(respBody))
+	}
+
+	var visionResp visionResponse
+	if err := json.Unmarshal(respBody, &visionResp); err != nil {
+		return "", fmt.Errorf("unmarshal response: %w", err)
+	}
+	if visionResp.Error != nil {
+		return "", fmt.Errorf("API error: %s", visionResp.Error.Message)
+	}
+	if len(visionResp.Choices) == 0 {
+		return "", fmt.Errorf("no response from vision model")
+	}
+
+	text := strings.TrimSpace(visionResp.Choices[0].Message.Content)
+	if text == "NO_TEXT" {
+		return "", nil
+	}
+	return CleanText(text), nil
+}
+
+func imageMIMEType(path string) string {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".png":
+		return "image/png"
+	case ".gif":
+		return "image/gif"
+	case ".webp":
+		return "image/webp"
+	default:
+		return "image/jpeg"
+	}
+}
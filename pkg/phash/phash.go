@@ -0,0 +1,187 @@
+// Package phash computes perceptual image hashes for near-duplicate detection.
+//
+// Two 64-bit hashes are provided:
+//   - DHash (difference hash): fast, robust to scaling and compression.
+//   - PHash (DCT hash): more robust to small edits, brightness and contrast changes.
+//
+// Hashes are compared by Hamming distance; small distances (<= 10 of 64 bits)
+// indicate visually similar images.
+package phash
+
+import (
+	"fmt"
+	"image"
+	_ "image/gif"  // Register GIF decoder
+	_ "image/jpeg" // Register JPEG decoder
+	_ "image/png"  // Register PNG decoder
+	"math"
+	"math/bits"
+	"os"
+	"sort"
+	"strconv"
+)
+
+// Hashes holds both perceptual hashes for one image.
+type Hashes struct {
+	PHash uint64
+	DHash uint64
+}
+
+// FromFile decodes an image file and computes its hashes.
+// Formats without a registered decoder (e.g. WebP) return an error.
+func FromFile(path string) (Hashes, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return Hashes{}, fmt.Errorf("open image: %w", err)
+	}
+	defer f.Close()
+
+	img, _, err := image.Decode(f)
+	if err != nil {
+		return Hashes{}, fmt.Errorf("decode image: %w", err)
+	}
+	return Hashes{PHash: PHash(img), DHash: DHash(img)}, nil
+}
+
+// DHash computes a 64-bit difference hash: the image is reduced to 9x8
+// grayscale and each bit records whether a pixel is brighter than its right neighbour.
+func DHash(img image.Image) uint64 {
+	px := grayscale(img, 9, 8)
+	var hash uint64
+	for y := 0; y < 8; y++ {
+		for x := 0; x < 8; x++ {
+			hash <<= 1
+			if px[y*9+x] > px[y*9+x+1] {
+				hash |= 1
+			}
+		}
+	}
+	return hash
+}
+
+// PHash computes a 64-bit DCT hash: the image is reduced to 32x32 grayscale,
+// transformed with a 2D DCT, and the 8x8 low-frequency block is thresholded
+// against its median (excluding the DC term).
+func PHash(img image.Image) uint64 {
+	const size = 32
+	const block = 8
+
+	px := grayscale(img, size, size)
+	coeffs := dct2D(px, size)
+
+	low := make([]float64, 0, block*block)
+	for v := 0; v < block; v++ {
+		for u := 0; u < block; u++ {
+			low = append(low, coeffs[v*size+u])
+		}
+	}
+
+	sorted := append([]float64(nil), low[1:]...)
+	sort.Float64s(sorted)
+	median := sorted[len(sorted)/2]
+
+	var hash uint64
+	for _, c := range low {
+		hash <<= 1
+		if c > median {
+			hash |= 1
+		}
+	}
+	return hash
+}
+
+// Distance returns the Hamming distance between two hashes (0-64).
+func Distance(a, b uint64) int {
+	return bits.OnesCount64(a ^ b)
+}
+
+// SequenceDistance compares two keyframe hash sequences frame by frame and
+// returns the mean Hamming distance over the shorter sequence.
+// ok is false when either sequence is empty.
+func SequenceDistance(a, b []uint64) (dist float64, ok bool) {
+	n := len(a)
+	if len(b) < n {
+		n = len(b)
+	}
+	if n == 0 {
+		return 0, false
+	}
+	total := 0
+	for i := 0; i < n; i++ {
+		total += Distance(a[i], b[i])
+	}
+	return float64(total) / float64(n), true
+}
+
+// Format renders a hash as 16 lowercase hex digits.
+func Format(h uint64) string {
+	return fmt.Sprintf("%016x", h)
+}
+
+// Parse reads a hash produced by Format.
+func Parse(s string) (uint64, error) {
+	return strconv.ParseUint(s, 16, 64)
+}
+
+// grayscale downsamples img to w x h luminance values using box averaging.
+func grayscale(img image.Image, w, h int) []float64 {
+	b := img.Bounds()
+	sums := make([]float64, w*h)
+	counts := make([]int, w*h)
+	bw, bh := b.Dx(), b.Dy()
+	if bw == 0 || bh == 0 {
+		return sums
+	}
+
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		ty := (y - b.Min.Y) * h / bh
+		for x := b.Min.X; x < b.Max.X; x++ {
+			tx := (x - b.Min.X) * w / bw
+			r, g, bl, _ := img.At(x, y).RGBA()
+			// ITU-R BT.601 luma on 16-bit channels
+			lum := 0.299*float64(r) + 0.587*float64(g) + 0.114*float64(bl)
+			sums[ty*w+tx] += lum
+			counts[ty*w+tx]++
+		}
+	}
+
+	for i := range sums {
+		if counts[i] > 0 {
+			sums[i] /= float64(counts[i])
+		}
+	}
+	return sums
+}
+
+// dct2D computes an unnormalized 2D DCT-II of an n x n block.
+func dct2D(px []float64, n int) []float64 {
+	cos := make([]float64, n*n)
+	for k := 0; k < n; k++ {
+		for i := 0; i < n; i++ {
+			cos[k*n+i] = math.Cos(math.Pi / float64(n) * (float64(i) + 0.5) * float64(k))
+		}
+	}
+
+	// Rows then columns
+	tmp := make([]float64, n*n)
+	for y := 0; y < n; y++ {
+		for u := 0; u < n; u++ {
+			var sum float64
+			for x := 0; x < n; x++ {
+				sum += px[y*n+x] * cos[u*n+x]
+			}
+			tmp[y*n+u] = sum
+		}
+	}
+	out := make([]float64, n*n)
+	for u := 0; u < n; u++ {
+		for v := 0; v < n; v++ {
+			var sum float64
+			for y := 0; y < n; y++ {
+				sum += tmp[y*n+u] * cos[v*n+y]
+			}
+			out[v*n+u] = sum
+		}
+	}
+	return out
+}
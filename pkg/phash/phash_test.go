@@ -0,0 +1,111 @@
+package phash
+
+import (
+	"image"
+	"image/color"
+	"image/png"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// gradient draws a horizontal gradient with a bright square and a dark
+// bar, scaled to w x h.
+func gradient(w, h int, invert bool) image.Image {
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			v := uint8(x * 200 / w)
+			if x > w/8 && x < w/2 && y > h/8 && y < h/2 {
+				v = 250
+			}
+			if y > h*5/8 && y < h*7/8 && x > w/3 {
+				v = 10
+			}
+			if invert {
+				v = 255 - v
+			}
+			img.Set(x, y, color.RGBA{v, v, v, 255})
+		}
+	}
+	return img
+}
+
+func TestHashesStableAcrossScale(t *testing.T) {
+	small := gradient(64, 48, false)
+	large := gradient(640, 480, false)
+	other := gradient(640, 480, true)
+
+	if d := Distance(DHash(small), DHash(large)); d > 6 {
+		t.Errorf("dhash distance across scale = %d, want <= 6", d)
+	}
+	if d := Distance(PHash(small), PHash(large)); d > 6 {
+		t.Errorf("phash distance across scale = %d, want <= 6", d)
+	}
+	if d := Distance(PHash(large), PHash(other)); d < 20 {
+		t.Errorf("phash distance for different image = %d, want >= 20", d)
+	}
+}
+
+func TestDistance(t *testing.T) {
+	tests := []struct {
+		a, b uint64
+		want int
+	}{
+		{0, 0, 0},
+		{0, 1, 1},
+		{0xff, 0, 8},
+		{^uint64(0), 0, 64},
+	}
+	for _, tt := range tests {
+		if got := Distance(tt.a, tt.b); got != tt.want {
+			t.Errorf("Distance(%x, %x) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestSequenceDistance(t *testing.T) {
+	if _, ok := SequenceDistance(nil, []uint64{1}); ok {
+		t.Error("empty sequence should not be comparable")
+	}
+	d, ok := SequenceDistance([]uint64{0, 0, 0xff}, []uint64{1, 0})
+	if !ok || d != 0.5 {
+		t.Errorf("SequenceDistance = %v, %v; want 0.5, true", d, ok)
+	}
+}
+
+func TestFormatParse(t *testing.T) {
+	h := uint64(0x0123456789abcdef)
+	s := Format(h)
+	if s != "0123456789abcdef" {
+		t.Errorf("Format = %s", s)
+	}
+	got, err := Parse(s)
+	if err != nil || got != h {
+		t.Errorf("Parse = %x, %v", got, err)
+	}
+}
+
+func TestFromFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "img.png")
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := png.Encode(f, gradient(100, 80, false)); err != nil {
+		t.Fatal(err)
+	}
+	f.Close()
+
+	h, err := FromFile(path)
+	if err != nil {
+		t.Fatalf("FromFile: %v", err)
+	}
+	if h.PHash != PHash(gradient(100, 80, false)) {
+		t.Error("file hash should match in-memory hash")
+	}
+
+	if _, err := FromFile(filepath.Join(t.TempDir(), "missing.png")); err == nil {
+		t.Error("missing file should error")
+	}
+}
@@ -11,18 +11,6 @@ import (
 	"time"
 )
 
-// RateLimitError indicates the request hit a rate limit and includes a reset time if known.
-type RateLimitError struct {
-	Reset time.Time
-}
-
-func (e *RateLimitError) Error() string {
-	if !e.Reset.IsZero() {
-		return fmt.Sprintf("rate limited until %s", e.Reset.Format(time.RFC3339))
-	}
-	return "rate limited"
-}
-
 // BookmarksClient fetches bookmark lists from X API v2.
 type BookmarksClient struct {
 	httpClient  *http.Client
@@ -137,13 +125,7 @@ func (c *BookmarksClient) ListBookmarks(ctx context.Context, userID string, maxR
 	}
 
 	if resp.StatusCode == http.StatusTooManyRequests {
-		rl := &RateLimitError{}
-		if reset := resp.Header.Get("x-rate-limit-reset"); reset != "" {
-			if sec, err := strconv.ParseInt(reset, 10, 64); err == nil {
-				rl.Reset = time.Unix(sec, 0)
-			}
-		}
-		return nil, "", rl
+		return nil, "", newRateLimitError(resp.Header)
 	}
 	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
 		// Avoid huge reads; just return status.
@@ -168,7 +168,7 @@ func (b *GraphQLBookmarksClient) listBookmarksWithRetry(ctx context.Context, use
 	}
 	if resp.StatusCode == http.StatusForbidden || resp.StatusCode == http.StatusUnauthorized {
 		body, _ := io.ReadAll(resp.Body)
-		return nil, "", fmt.Errorf("bookmarks graphql unauthorized: %s", strings.TrimSpace(string(body)))
+		return nil, "", fmt.Errorf("bookmarks graphql unauthorized: %s: %w", strings.TrimSpace(string(body)), ErrAuthExpired)
 	}
 	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
 		body, _ := io.ReadAll(resp.Body)
@@ -0,0 +1,163 @@
+package twitter
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/iconidentify/xgrabba/internal/domain"
+)
+
+// cardBindingValue is one typed value in a card's binding_values.
+type cardBindingValue struct {
+	StringValue  string `json:"string_value"`
+	BooleanValue bool   `json:"boolean_value"`
+	ImageValue   *struct {
+		URL    string `json:"url"`
+		Width  int    `json:"width"`
+		Height int    `json:"height"`
+	} `json:"image_value,omitempty"`
+}
+
+// syndicationCard is a card as returned by the syndication API, where
+// binding_values is an object keyed by name.
+type syndicationCard struct {
+	Name          string                      `json:"name"`
+	URL           string                      `json:"url"`
+	BindingValues map[string]cardBindingValue `json:"binding_values"`
+}
+
+// graphQLCard is a card as returned by GraphQL, where binding_values is a
+// list of key/value pairs.
+type graphQLCard struct {
+	Legacy struct {
+		Name          string `json:"name"`
+		URL           string `json:"url"`
+		BindingValues []struct {
+			Key   string           `json:"key"`
+			Value cardBindingValue `json:"value"`
+		} `json:"binding_values"`
+	} `json:"legacy"`
+}
+
+// birdwatchPivot is the Community Notes banner attached to a post.
+type birdwatchPivot struct {
+	DestinationURL string `json:"destinationUrl"`
+	Note           struct {
+		RestID string `json:"rest_id"`
+	} `json:"note"`
+	Subtitle struct {
+		Text string `json:"text"`
+	} `json:"subtitle"`
+}
+
+// entityURL maps a t.co link in the tweet text to its destination.
+type entityURL struct {
+	URL         string `json:"url"`
+	ExpandedURL string `json:"expanded_url"`
+}
+
+// cardImageKeys are the binding values holding a card's preview image, best first.
+var cardImageKeys = []string{
+	"photo_image_full_size_original",
+	"summary_photo_image_original",
+	"thumbnail_image_original",
+	"player_image_original",
+	"photo_image_full_size",
+	"summary_photo_image",
+	"thumbnail_image",
+}
+
+// parseCard converts a card into a poll or a link preview. Either may be nil;
+// unified cards (app installs, carousels) are not modelled.
+func parseCard(name, cardURL string, values map[string]cardBindingValue, urls []entityURL) (*domain.Poll, *domain.LinkCard) {
+	if len(values) == 0 {
+		return nil, nil
+	}
+	if strings.HasPrefix(name, "poll") {
+		return parsePoll(values), nil
+	}
+	if name == "unified_card" {
+		return nil, nil
+	}
+
+	card := &domain.LinkCard{
+		URL:         expandURL(firstNonEmpty(values["card_url"].StringValue, cardURL), urls),
+		Domain:      firstNonEmpty(values["vanity_url"].StringValue, values["domain"].StringValue),
+		Title:       values["title"].StringValue,
+		Description: values["description"].StringValue,
+	}
+	for _, key := range cardImageKeys {
+		if img := values[key].ImageValue; img != nil && img.URL != "" {
+			card.ImageURL = img.URL
+			break
+		}
+	}
+	if card.Title == "" && card.ImageURL == "" {
+		return nil, nil
+	}
+	return nil, card
+}
+
+// parsePoll reads choiceN_label/choiceN_count pairs from a poll card.
+func parsePoll(values map[string]cardBindingValue) *domain.Poll {
+	poll := &domain.Poll{Final: values["counts_are_final"].BooleanValue}
+	for i := 1; ; i++ {
+		label := values["choice"+strconv.Itoa(i)+"_label"].StringValue
+		if label == "" {
+			break
+		}
+		votes, _ := strconv.Atoi(values["choice"+strconv.Itoa(i)+"_count"].StringValue)
+		poll.Options = append(poll.Options, domain.PollOption{Label: label, Votes: votes})
+	}
+	if len(poll.Options) == 0 {
+		return nil
+	}
+	if t, err := time.Parse(time.RFC3339, values["end_datetime_utc"].StringValue); err == nil {
+		poll.EndsAt = &t
+	}
+	return poll
+}
+
+// bindingValues flattens GraphQL's key/value list into a map.
+func (c *graphQLCard) bindingValues() map[string]cardBindingValue {
+	if c == nil {
+		return nil
+	}
+	values := make(map[string]cardBindingValue, len(c.Legacy.BindingValues))
+	for _, kv := range c.Legacy.BindingValues {
+		values[kv.Key] = kv.Value
+	}
+	return values
+}
+
+// communityNote converts the pivot into a note, or nil if there is none.
+func (b *birdwatchPivot) communityNote() *domain.CommunityNote {
+	if b == nil || b.Subtitle.Text == "" {
+		return nil
+	}
+	return &domain.CommunityNote{
+		ID:   b.Note.RestID,
+		Text: b.Subtitle.Text,
+		URL:  b.DestinationURL,
+	}
+}
+
+// expandURL resolves a t.co link using the tweet's URL entities.
+func expandURL(u string, urls []entityURL) string {
+	for _, e := range urls {
+		if e.URL == u && e.ExpandedURL != "" {
+			return e.ExpandedURL
+		}
+	}
+	return u
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
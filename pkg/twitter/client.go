@@ -1,8 +1,10 @@
 package twitter
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"html"
 	"io"
@@ -362,6 +364,11 @@ func (c *Client) FetchTweet(ctx context.Context, tweetURL string) (*domain.Tweet
 	// If syndication fails, try GraphQL directly
 	tweet, graphqlErr := c.fetchFromGraphQL(ctx, tweetID)
 	if graphqlErr == nil {
+		// Only a tweet syndication hid from anonymous requests needed the
+		// user's session; a transient syndication failure does not count.
+		// Syndication answers 404 for protected tweets, so a "deleted" tweet
+		// GraphQL could still read was hidden as well.
+		tweet.FetchedWithCredentials = c.HasBrowserCredentials() && (isVisibilityError(err) || errors.Is(err, ErrTweetDeleted))
 		tweet.URL = tweetURL
 		applyAuthorFromURL(tweet, tweetURL)
 		// If we still don't have an avatar URL, try a profile-page fallback.
@@ -371,7 +378,124 @@ func (c *Client) FetchTweet(ctx context.Context, tweetURL string) (*domain.Tweet
 		return tweet, nil
 	}
 
-	return nil, fmt.Errorf("failed to fetch tweet (syndication: %v, graphql: %v)", err, graphqlErr)
+	// Protected accounts and age-restricted tweets are hidden from the
+	// anonymous paths; try the user's own session before giving up
+	if c.HasBrowserCredentials() && (isVisibilityError(err) || isVisibilityError(graphqlErr)) {
+		tweet, detailErr := c.fetchFromTweetDetail(ctx, tweetID)
+		if detailErr == nil {
+			tweet.URL = tweetURL
+			applyAuthorFromURL(tweet, tweetURL)
+			c.enrichAvatarFromProfilePage(ctx, tweet)
+			tweet.Text = normalizeTweetText(tweet.Text)
+			return tweet, nil
+		}
+		return nil, fmt.Errorf("failed to fetch tweet (syndication: %w, graphql: %w, tweetdetail: %w)", err, graphqlErr, detailErr)
+	}
+
+	return nil, fmt.Errorf("failed to fetch tweet (syndication: %w, graphql: %w)", err, graphqlErr)
+}
+
+// FetchMetrics retrieves current engagement counts for a tweet from the
+// syndication endpoint. It is much cheaper than FetchTweet (one request, no
+// GraphQL) and is used for scheduled re-polls. Views are not reported by
+// syndication and are left zero.
+func (c *Client) FetchMetrics(ctx context.Context, tweetID string) (*domain.TweetMetrics, error) {
+	result, err := c.fetchFromSyndication(ctx, tweetID)
+	if err != nil {
+		return nil, err
+	}
+	return &result.Tweet.Metrics, nil
+}
+
+// CheckAvailability probes whether a tweet is still publicly visible using the
+// syndication endpoint. An error means the probe itself failed (network, rate
+// limit) and says nothing about the tweet.
+func (c *Client) CheckAvailability(ctx context.Context, tweetID string) (domain.UpstreamStatus, error) {
+	url := fmt.Sprintf("https://cdn.syndication.twimg.com/tweet-result?id=%s&token=0", tweetID)
+
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return "", fmt.Errorf("create request: %w", err)
+	}
+	req.Header.Set("User-Agent", c.userAgent)
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return "", fmt.Errorf("read response: %w", err)
+	}
+	return classifyAvailability(resp.StatusCode, body)
+}
+
+// availabilityProbe is the subset of a syndication response needed to tell
+// a live tweet from a tombstone.
+type availabilityProbe struct {
+	TypeName  string `json:"__typename"`
+	Tombstone *struct {
+		Text struct {
+			Text string `json:"text"`
+		} `json:"text"`
+	} `json:"tombstone"`
+	User struct {
+		ScreenName string `json:"screen_name"`
+	} `json:"user"`
+	WithheldInCountries []string `json:"withheld_in_countries"`
+}
+
+// classifyAvailability maps a syndication response to an upstream status.
+func classifyAvailability(statusCode int, body []byte) (domain.UpstreamStatus, error) {
+	switch statusCode {
+	case http.StatusOK:
+	case http.StatusNotFound:
+		return domain.UpstreamStatusDeleted, nil
+	default:
+		return "", fmt.Errorf("API error (status %d): %s", statusCode, truncateText(string(body), 200))
+	}
+
+	if len(bytes.TrimSpace(body)) == 0 {
+		return domain.UpstreamStatusDeleted, nil
+	}
+	var probe availabilityProbe
+	if err := json.Unmarshal(body, &probe); err != nil {
+		return "", fmt.Errorf("decode response: %w", err)
+	}
+
+	switch {
+	case probe.TypeName == "TweetTombstone" || probe.Tombstone != nil:
+		text := ""
+		if probe.Tombstone != nil {
+			text = probe.Tombstone.Text.Text
+		}
+		return classifyTombstone(text), nil
+	case len(probe.WithheldInCountries) > 0:
+		return domain.UpstreamStatusWithheld, nil
+	case probe.User.ScreenName == "":
+		// Same signal parseSyndicationResponse treats as a missing author
+		return domain.UpstreamStatusUnavailable, nil
+	}
+	return domain.UpstreamStatusAvailable, nil
+}
+
+// classifyTombstone maps X's tombstone message to an upstream status.
+func classifyTombstone(text string) domain.UpstreamStatus {
+	text = strings.ToLower(text)
+	switch {
+	case strings.Contains(text, "suspended"):
+		return domain.UpstreamStatusSuspended
+	case strings.Contains(text, "limits who can view"), strings.Contains(text, "protected"):
+		return domain.UpstreamStatusProtected
+	case strings.Contains(text, "withheld"), strings.Contains(text, "legal demand"):
+		return domain.UpstreamStatusWithheld
+	case strings.Contains(text, "deleted"), strings.Contains(text, "no longer exists"):
+		return domain.UpstreamStatusDeleted
+	}
+	return domain.UpstreamStatusUnavailable
 }
 
 // enrichAvatarFromProfilePage attempts to populate the author's avatar URL by fetching the public profile HTML.
@@ -535,6 +659,21 @@ func (c *Client) enrichFromGraphQL(ctx context.Context, tweetID string, base *do
 	if len(base.Media) == 0 && len(gqlTweet.Media) > 0 {
 		base.Media = gqlTweet.Media
 		base.MediaTotal = len(gqlTweet.Media)
+		// Media syndication withheld (sensitive) came from the user's session,
+		// which GraphQL uses whenever browser credentials are set
+		base.FetchedWithCredentials = true
+	}
+	if len(base.EditTweetIDs) == 0 && len(gqlTweet.EditTweetIDs) > 0 {
+		base.EditTweetIDs = gqlTweet.EditTweetIDs
+	}
+	if base.Poll == nil {
+		base.Poll = gqlTweet.Poll
+	}
+	if base.Card == nil {
+		base.Card = gqlTweet.Card
+	}
+	if base.CommunityNote == nil {
+		base.CommunityNote = gqlTweet.CommunityNote
 	}
 
 	// Merge article fields if this is an article
@@ -636,7 +775,13 @@ func (c *Client) fetchFromSyndication(ctx context.Context, tweetID string) (*syn
 	}
 	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotFound:
+		return nil, fmt.Errorf("syndication: %w", ErrTweetDeleted)
+	case http.StatusTooManyRequests:
+		return nil, newRateLimitError(resp.Header)
+	default:
 		body, _ := io.ReadAll(resp.Body)
 		return nil, fmt.Errorf("API error (status %d): %s", resp.StatusCode, string(body))
 	}
@@ -688,6 +833,7 @@ type syndicationResponse struct {
 				} `json:"large"`
 			} `json:"sizes"`
 		} `json:"media"`
+		URLs []entityURL `json:"urls"`
 	} `json:"entities"`
 	ExtendedEntities struct {
 		Media []struct {
@@ -748,12 +894,62 @@ type syndicationResponse struct {
 	NoteTweet *struct {
 		ID string `json:"id"`
 	} `json:"note_tweet,omitempty"`
+	EditControl *editControl `json:"edit_control,omitempty"`
+	// Card holds a poll or link preview; BirdwatchPivot a Community Note
+	Card           *syndicationCard `json:"card,omitempty"`
+	BirdwatchPivot *birdwatchPivot  `json:"birdwatch_pivot,omitempty"`
+	// TypeName and Tombstone are set instead of tweet data when the post is gone
+	TypeName  string          `json:"__typename"`
+	Tombstone *tombstoneField `json:"tombstone,omitempty"`
+}
+
+// tombstoneField is X's placeholder for a post that can no longer be shown.
+type tombstoneField struct {
+	Text struct {
+		Text string `json:"text"`
+	} `json:"text"`
+}
+
+// message returns the tombstone's user-facing explanation.
+func (t *tombstoneField) message() string {
+	if t == nil {
+		return ""
+	}
+	return t.Text.Text
+}
+
+// editControl is X's edit metadata for a post. The original version carries
+// the full history; later versions point back to it via edit_control_initial.
+type editControl struct {
+	EditTweetIDs       []string `json:"edit_tweet_ids"`
+	InitialTweetID     string   `json:"initial_tweet_id"`
+	EditControlInitial *struct {
+		EditTweetIDs []string `json:"edit_tweet_ids"`
+	} `json:"edit_control_initial,omitempty"`
+}
+
+// tweetIDs returns the edit history, oldest first.
+func (e *editControl) tweetIDs() []string {
+	if e == nil {
+		return nil
+	}
+	if len(e.EditTweetIDs) > 0 {
+		return e.EditTweetIDs
+	}
+	if e.EditControlInitial != nil {
+		return e.EditControlInitial.EditTweetIDs
+	}
+	return nil
 }
 
 func (c *Client) parseSyndicationResponse(tweetID string, resp *syndicationResponse) (*domain.Tweet, error) {
+	if resp.TypeName == "TweetTombstone" || resp.Tombstone != nil {
+		return nil, fmt.Errorf("syndication tombstone %q: %w", resp.Tombstone.message(), upstreamStatusError(classifyTombstone(resp.Tombstone.message())))
+	}
+
 	// Validate author data is present - if missing, tweet is likely deleted/suspended
 	if resp.User.ScreenName == "" {
-		return nil, fmt.Errorf("tweet author data unavailable (account may be suspended or deleted)")
+		return nil, fmt.Errorf("tweet author data unavailable (account may be suspended or deleted): %w", ErrTweetUnavailable)
 	}
 
 	// Parse created_at time
@@ -799,6 +995,11 @@ func (c *Client) parseSyndicationResponse(tweetID string, resp *syndicationRespo
 
 	// Parse media - try multiple sources
 	tweet.Media = c.parseMedia(resp)
+	tweet.EditTweetIDs = resp.EditControl.tweetIDs()
+	if resp.Card != nil {
+		tweet.Poll, tweet.Card = parseCard(resp.Card.Name, resp.Card.URL, resp.Card.BindingValues, resp.Entities.URLs)
+	}
+	tweet.CommunityNote = resp.BirdwatchPivot.communityNote()
 
 	return tweet, nil
 }
@@ -816,6 +1017,8 @@ func (c *Client) parseMedia(resp *syndicationResponse) []domain.Media {
 		width      int
 		height     int
 		mediaType  domain.MediaType
+		// proxyBitrate is set when bitrate is the width*height estimate
+		proxyBitrate bool
 	}
 	var videoCandidates []videoCandidate
 
@@ -847,6 +1050,8 @@ func (c *Client) parseMedia(resp *syndicationResponse) []domain.Media {
 					previewURL: resp.Video.Poster,
 					duration:   resp.Video.DurationMs / 1000,
 					mediaType:  domain.MediaTypeVideo,
+
+					proxyBitrate: true,
 				})
 			}
 		}
@@ -928,6 +1133,16 @@ func (c *Client) parseMedia(resp *syndicationResponse) []domain.Media {
 			return videoCandidates[i].bitrate > videoCandidates[j].bitrate
 		})
 
+		// Keep every rendition so the media quality policy can choose
+		candidates := make([]domain.MediaVariant, 0, len(videoCandidates))
+		for _, c := range videoCandidates {
+			bitrate := c.bitrate
+			if c.proxyBitrate {
+				bitrate = 0
+			}
+			candidates = append(candidates, domain.MediaVariant{URL: c.url, Bitrate: bitrate})
+		}
+
 		// Take the best one
 		best := videoCandidates[0]
 		media = append(media, domain.Media{
@@ -939,6 +1154,7 @@ func (c *Client) parseMedia(resp *syndicationResponse) []domain.Media {
 			Width:      best.width,
 			Height:     best.height,
 			Bitrate:    best.bitrate,
+			Variants:   mp4Variants(candidates),
 		})
 	}
 
@@ -1398,6 +1614,8 @@ type graphQLTweetResult struct {
 	RestID   string `json:"rest_id"`
 	// Tweet field is used when __typename is "TweetWithVisibilityResults" (age-restricted/sensitive content)
 	Tweet *graphQLTweetResult `json:"tweet,omitempty"`
+	// Tombstone explains why a "TweetTombstone" result is hidden
+	Tombstone *tombstoneField `json:"tombstone,omitempty"`
 	Core  struct {
 		UserResults struct {
 			Result struct {
@@ -1443,6 +1661,7 @@ type graphQLTweetResult struct {
 					} `json:"large"`
 				} `json:"sizes"`
 			} `json:"media"`
+			URLs []entityURL `json:"urls"`
 		} `json:"entities"`
 		ExtendedEntities struct {
 			Media []struct {
@@ -1506,6 +1725,10 @@ type graphQLTweetResult struct {
 	Views struct {
 		Count string `json:"count"`
 	} `json:"views"`
+	EditControl *editControl `json:"edit_control,omitempty"`
+	// Card holds a poll or link preview; BirdwatchPivot a Community Note
+	Card           *graphQLCard    `json:"card,omitempty"`
+	BirdwatchPivot *birdwatchPivot `json:"birdwatch_pivot,omitempty"`
 	// Article contains article data when this tweet is/contains a long-form article
 	Article *graphQLArticle `json:"article,omitempty"`
 }
@@ -1748,6 +1971,13 @@ func (c *Client) fetchFromGraphQLWithRetry(ctx context.Context, tweetID string,
 			}
 			return c.fetchFromGraphQLWithRetry(ctx, tweetID, true)
 		}
+		switch {
+		case resp.StatusCode == http.StatusTooManyRequests:
+			return nil, newRateLimitError(resp.Header)
+		case resp.StatusCode == http.StatusUnauthorized,
+			resp.StatusCode == http.StatusForbidden && source == authSourceBrowser:
+			return nil, fmt.Errorf("graphql error (status %d, auth: %s): %w", resp.StatusCode, source, ErrAuthExpired)
+		}
 		return nil, fmt.Errorf("graphql error (status %d): %s", resp.StatusCode, string(body))
 	}
 
@@ -1789,7 +2019,8 @@ func (c *Client) parseGraphQLResponse(tweetID string, resp *graphQLResponse) (*d
 
 	// Handle "TweetTombstone" (deleted) or other non-tweet types
 	if result.TypeName == "TweetTombstone" {
-		return nil, fmt.Errorf("tweet is unavailable (deleted or protected)")
+		msg := result.Tombstone.message()
+		return nil, fmt.Errorf("graphql tombstone %q: %w", msg, upstreamStatusError(classifyTombstone(msg)))
 	}
 
 	// Handle "TweetWithVisibilityResults" - age-restricted/sensitive content wrapper
@@ -1798,7 +2029,7 @@ func (c *Client) parseGraphQLResponse(tweetID string, resp *graphQLResponse) (*d
 		if result.Tweet != nil {
 			result = result.Tweet
 		} else {
-			return nil, fmt.Errorf("age-restricted tweet has no nested tweet data (authentication may be required)")
+			return nil, errAgeRestricted
 		}
 	}
 
@@ -1913,6 +2144,12 @@ func (c *Client) parseGraphQLResponse(tweetID string, resp *graphQLResponse) (*d
 		tweet.QuotedTweet = &quoted
 	}
 
+	tweet.EditTweetIDs = result.EditControl.tweetIDs()
+	if result.Card != nil {
+		tweet.Poll, tweet.Card = parseCard(result.Card.Legacy.Name, result.Card.Legacy.URL, result.Card.bindingValues(), result.Legacy.Entities.URLs)
+	}
+	tweet.CommunityNote = result.BirdwatchPivot.communityNote()
+
 	// Extract article data if present
 	c.extractArticleFromGraphQL(result, tweet)
 
@@ -2014,11 +2251,13 @@ func (c *Client) parseGraphQLMedia(result *graphQLTweetResult) []domain.Media {
 
 			bestURL := ""
 			bestBitrate := -1
+			var candidates []domain.MediaVariant
 			for _, v := range m.VideoInfo.Variants {
 				// Ignore HLS playlists; we want a direct MP4 for download.
 				if v.ContentType != "video/mp4" || v.URL == "" {
 					continue
 				}
+				candidates = append(candidates, domain.MediaVariant{URL: v.URL, Bitrate: v.Bitrate})
 				b := v.Bitrate
 				if b == 0 {
 					// Some variants don't include bitrate; infer from URL as a fallback.
@@ -2047,6 +2286,7 @@ func (c *Client) parseGraphQLMedia(result *graphQLTweetResult) []domain.Media {
 				Duration:   m.VideoInfo.DurationMillis / 1000,
 				Bitrate:    bestBitrate,
 				AltText:    m.ExtAltText,
+				Variants:   mp4Variants(candidates),
 			})
 		}
 	}
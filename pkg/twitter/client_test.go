@@ -3,11 +3,17 @@ package twitter
 import (
 	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"log/slog"
+	"net/http"
 	"os"
+	"reflect"
 	"strings"
 	"testing"
 	"time"
+
+	"github.com/iconidentify/xgrabba/internal/domain"
 )
 
 func testLogger() *slog.Logger {
@@ -111,6 +117,337 @@ func TestGetGuestToken_Caching(t *testing.T) {
 	t.Skip("Skipping - tested in integration test")
 }
 
+// =============================================================================
+// Unit Tests - Edit History
+// =============================================================================
+
+func TestParseEditControl(t *testing.T) {
+	client := NewClient(testLogger())
+
+	var synd syndicationResponse
+	if err := json.Unmarshal([]byte(`{
+		"id_str": "100",
+		"text": "first",
+		"user": {"screen_name": "someone"},
+		"edit_control": {"edit_tweet_ids": ["100", "101"], "editable_until_msecs": "1700000000000"}
+	}`), &synd); err != nil {
+		t.Fatal(err)
+	}
+	tweet, err := client.parseSyndicationResponse("100", &synd)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := strings.Join(tweet.EditTweetIDs, ","); got != "100,101" {
+		t.Errorf("syndication edit IDs = %q, want 100,101", got)
+	}
+	if tweet.LatestEditID() != "101" {
+		t.Errorf("LatestEditID = %q, want 101", tweet.LatestEditID())
+	}
+
+	// Later versions point back to the original's history
+	var gql graphQLResponse
+	if err := json.Unmarshal([]byte(`{"data": {"tweetResult": {"result": {
+		"__typename": "Tweet",
+		"rest_id": "101",
+		"core": {"user_results": {"result": {"legacy": {"screen_name": "someone"}}}},
+		"legacy": {"full_text": "second"},
+		"edit_control": {"initial_tweet_id": "100", "edit_control_initial": {"edit_tweet_ids": ["100", "101"]}}
+	}}}}`), &gql); err != nil {
+		t.Fatal(err)
+	}
+	tweet, err = client.parseGraphQLResponse("101", &gql)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := strings.Join(tweet.EditTweetIDs, ","); got != "100,101" {
+		t.Errorf("graphql edit IDs = %q, want 100,101", got)
+	}
+}
+
+func TestParseAttachments(t *testing.T) {
+	client := NewClient(testLogger())
+
+	var synd syndicationResponse
+	if err := json.Unmarshal([]byte(`{
+		"id_str": "1",
+		"text": "which one?",
+		"user": {"screen_name": "someone"},
+		"card": {"name": "poll3choice_text_only", "binding_values": {
+			"choice1_label": {"string_value": "Red"}, "choice1_count": {"string_value": "30"},
+			"choice2_label": {"string_value": "Blue"}, "choice2_count": {"string_value": "10"},
+			"choice3_label": {"string_value": "Green"}, "choice3_count": {"string_value": "0"},
+			"end_datetime_utc": {"string_value": "2024-01-02T15:04:05Z"},
+			"counts_are_final": {"boolean_value": true}
+		}}
+	}`), &synd); err != nil {
+		t.Fatal(err)
+	}
+	tweet, err := client.parseSyndicationResponse("1", &synd)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tweet.Poll == nil || len(tweet.Poll.Options) != 3 {
+		t.Fatalf("poll = %+v, want 3 options", tweet.Poll)
+	}
+	if tweet.Poll.Options[0] != (domain.PollOption{Label: "Red", Votes: 30}) || tweet.Poll.TotalVotes() != 40 {
+		t.Errorf("poll options = %+v", tweet.Poll.Options)
+	}
+	if !tweet.Poll.Final || tweet.Poll.EndsAt == nil || tweet.Poll.EndsAt.Day() != 2 {
+		t.Errorf("poll final = %v, ends = %v", tweet.Poll.Final, tweet.Poll.EndsAt)
+	}
+	if tweet.Card != nil {
+		t.Errorf("poll should not produce a link card: %+v", tweet.Card)
+	}
+
+	var gql graphQLResponse
+	if err := json.Unmarshal([]byte(`{"data": {"tweetResult": {"result": {
+		"__typename": "Tweet",
+		"rest_id": "2",
+		"core": {"user_results": {"result": {"legacy": {"screen_name": "someone"}}}},
+		"legacy": {"full_text": "read this https://t.co/abc", "entities": {"urls": [
+			{"url": "https://t.co/abc", "expanded_url": "https://example.com/story"}
+		]}},
+		"card": {"legacy": {"name": "summary_large_image", "url": "https://t.co/abc", "binding_values": [
+			{"key": "title", "value": {"string_value": "A story"}},
+			{"key": "description", "value": {"string_value": "What happened"}},
+			{"key": "vanity_url", "value": {"string_value": "example.com"}},
+			{"key": "summary_photo_image_original", "value": {"image_value": {"url": "https://pbs.twimg.com/card.jpg", "width": 1200, "height": 630}}}
+		]}},
+		"birdwatch_pivot": {"destinationUrl": "https://x.com/i/birdwatch/n/99", "note": {"rest_id": "99"}, "subtitle": {"text": "Missing context."}}
+	}}}}`), &gql); err != nil {
+		t.Fatal(err)
+	}
+	tweet, err = client.parseGraphQLResponse("2", &gql)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := domain.LinkCard{
+		URL:         "https://example.com/story",
+		Domain:      "example.com",
+		Title:       "A story",
+		Description: "What happened",
+		ImageURL:    "https://pbs.twimg.com/card.jpg",
+	}
+	if tweet.Card == nil || *tweet.Card != want {
+		t.Errorf("card = %+v, want %+v", tweet.Card, want)
+	}
+	if tweet.CommunityNote == nil || tweet.CommunityNote.ID != "99" || tweet.CommunityNote.Text != "Missing context." {
+		t.Errorf("community note = %+v", tweet.CommunityNote)
+	}
+	if tweet.Poll != nil {
+		t.Errorf("link card should not produce a poll: %+v", tweet.Poll)
+	}
+}
+
+func TestParseMedia_Variants(t *testing.T) {
+	client := NewClient(testLogger())
+
+	var synd syndicationResponse
+	if err := json.Unmarshal([]byte(`{
+		"video": {"poster": "https://pbs.twimg.com/poster.jpg", "variants": [
+			{"type": "video/mp4", "src": "https://video.twimg.com/vid/avc1/1280x720/b.mp4"},
+			{"type": "application/x-mpegURL", "src": "https://video.twimg.com/pl/x.m3u8"}
+		]},
+		"mediaDetails": [{"type": "video", "media_url_https": "https://pbs.twimg.com/poster.jpg", "video_info": {"variants": [
+			{"content_type": "video/mp4", "bitrate": 632000, "url": "https://video.twimg.com/vid/avc1/640x360/a.mp4"},
+			{"content_type": "video/mp4", "bitrate": 2176000, "url": "https://video.twimg.com/vid/avc1/1280x720/b.mp4"}
+		]}}]
+	}`), &synd); err != nil {
+		t.Fatal(err)
+	}
+	media := client.parseMedia(&synd)
+	if len(media) != 1 {
+		t.Fatalf("got %d media, want 1", len(media))
+	}
+	want := []domain.MediaVariant{
+		{URL: "https://video.twimg.com/vid/avc1/1280x720/b.mp4", Bitrate: 2176000, Width: 1280, Height: 720},
+		{URL: "https://video.twimg.com/vid/avc1/640x360/a.mp4", Bitrate: 632000, Width: 640, Height: 360},
+	}
+	if !reflect.DeepEqual(media[0].Variants, want) {
+		t.Errorf("variants = %+v, want %+v", media[0].Variants, want)
+	}
+
+	var result graphQLTweetResult
+	if err := json.Unmarshal([]byte(`{"legacy": {"extended_entities": {"media": [{
+		"type": "video", "id_str": "9", "media_url_https": "https://pbs.twimg.com/poster.jpg",
+		"video_info": {"variants": [
+			{"content_type": "video/mp4", "bitrate": 632000, "url": "https://video.twimg.com/vid/avc1/360x640/p.mp4"},
+			{"content_type": "video/mp4", "bitrate": 2176000, "url": "https://video.twimg.com/vid/avc1/720x1280/q.mp4"}
+		]}
+	}]}}}`), &result); err != nil {
+		t.Fatal(err)
+	}
+	gqlMedia := client.parseGraphQLMedia(&result)
+	if len(gqlMedia) != 1 || len(gqlMedia[0].Variants) != 2 {
+		t.Fatalf("graphql media = %+v", gqlMedia)
+	}
+	if v := gqlMedia[0].Variants[0]; v.Bitrate != 2176000 || v.Resolution() != 720 {
+		t.Errorf("best graphql variant = %+v, want 720p at 2176000", v)
+	}
+}
+
+// =============================================================================
+// Unit Tests - Upstream Availability
+// =============================================================================
+
+func TestClassifyAvailability(t *testing.T) {
+	tests := []struct {
+		name    string
+		status  int
+		body    string
+		want    domain.UpstreamStatus
+		wantErr bool
+	}{
+		{"live tweet", 200, `{"id_str":"1","user":{"screen_name":"someone"}}`, domain.UpstreamStatusAvailable, false},
+		{"not found", 404, ``, domain.UpstreamStatusDeleted, false},
+		{"empty body", 200, ``, domain.UpstreamStatusDeleted, false},
+		{"deleted tombstone", 200, `{"__typename":"TweetTombstone","tombstone":{"text":{"text":"This Post was deleted by the Post author."}}}`, domain.UpstreamStatusDeleted, false},
+		{"suspended tombstone", 200, `{"__typename":"TweetTombstone","tombstone":{"text":{"text":"This Post is from a suspended account."}}}`, domain.UpstreamStatusSuspended, false},
+		{"protected tombstone", 200, `{"__typename":"TweetTombstone","tombstone":{"text":{"text":"You're unable to view this Post because this account owner limits who can view their Posts."}}}`, domain.UpstreamStatusProtected, false},
+		{"withheld tombstone", 200, `{"__typename":"TweetTombstone","tombstone":{"text":{"text":"This Post has been withheld in response to a legal demand."}}}`, domain.UpstreamStatusWithheld, false},
+		{"withheld countries", 200, `{"id_str":"1","user":{"screen_name":"someone"},"withheld_in_countries":["DE"]}`, domain.UpstreamStatusWithheld, false},
+		{"unexplained tombstone", 200, `{"__typename":"TweetTombstone"}`, domain.UpstreamStatusUnavailable, false},
+		{"rate limited", 429, `rate limit`, "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := classifyAvailability(tt.status, []byte(tt.body))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("err = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("status = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+// =============================================================================
+// Unit Tests - Typed Errors
+// =============================================================================
+
+func TestParseSyndicationResponse_TypedErrors(t *testing.T) {
+	client := NewClient(testLogger())
+
+	tests := []struct {
+		name      string
+		body      string
+		want      error
+		wantLocal error
+	}{
+		{
+			name:      "deleted tombstone",
+			body:      `{"__typename":"TweetTombstone","tombstone":{"text":{"text":"This Post was deleted by the Post author."}}}`,
+			want:      domain.ErrTweetDeleted,
+			wantLocal: ErrTweetDeleted,
+		},
+		{
+			name:      "suspended tombstone",
+			body:      `{"__typename":"TweetTombstone","tombstone":{"text":{"text":"This Post is from a suspended account."}}}`,
+			want:      domain.ErrAuthorSuspended,
+			wantLocal: ErrAuthorSuspended,
+		},
+		{
+			name:      "missing author",
+			body:      `{"id_str":"1","text":"hello"}`,
+			want:      domain.ErrTweetUnavailable,
+			wantLocal: ErrTweetUnavailable,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var resp syndicationResponse
+			if err := json.Unmarshal([]byte(tt.body), &resp); err != nil {
+				t.Fatal(err)
+			}
+			_, err := client.parseSyndicationResponse("1", &resp)
+			if !errors.Is(err, tt.want) || !errors.Is(err, tt.wantLocal) {
+				t.Errorf("err = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestRateLimitError_MatchesErrRateLimited(t *testing.T) {
+	header := http.Header{}
+	header.Set("x-rate-limit-reset", "1700000000")
+	rl := newRateLimitError(header)
+	if !rl.Reset.Equal(time.Unix(1700000000, 0)) {
+		t.Errorf("Reset = %v", rl.Reset)
+	}
+
+	err := fmt.Errorf("failed to fetch tweet (syndication: %w, graphql: %w)", rl, errors.New("boom"))
+	if !errors.Is(err, ErrRateLimited) || !errors.Is(err, domain.ErrRateLimited) {
+		t.Errorf("wrapped RateLimitError should match ErrRateLimited: %v", err)
+	}
+	var got *RateLimitError
+	if !errors.As(err, &got) || got.Reset.IsZero() {
+		t.Errorf("errors.As should recover the reset time")
+	}
+	if reason := domain.FailureReasonFor(err); reason != domain.FailureRateLimited {
+		t.Errorf("FailureReasonFor = %q, want rate_limited", reason)
+	}
+}
+
+func TestParseTweetDetailResponse_FocalTweet(t *testing.T) {
+	client := NewClient(testLogger())
+
+	var detail tweetDetailResponse
+	if err := json.Unmarshal([]byte(`{"data": {"threaded_conversation_with_injections_v2": {"instructions": [{"entries": [
+		{"content": {"itemContent": {"tweet_results": {"result": {
+			"__typename": "Tweet", "rest_id": "1",
+			"core": {"user_results": {"result": {"legacy": {"screen_name": "parent"}}}},
+			"legacy": {"full_text": "parent tweet"}
+		}}}}},
+		{"content": {"itemContent": {"tweet_results": {"result": {
+			"__typename": "TweetWithVisibilityResults",
+			"tweet": {
+				"__typename": "Tweet", "rest_id": "2",
+				"core": {"user_results": {"result": {"legacy": {"screen_name": "private"}}}},
+				"legacy": {"full_text": "sensitive reply"}
+			}
+		}}}}}
+	]}]}}}`), &detail); err != nil {
+		t.Fatal(err)
+	}
+
+	tweet, err := client.parseTweetDetailResponse("2", &detail)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tweet.Text != "sensitive reply" || tweet.Author.Username != "private" {
+		t.Errorf("got %q by %q, want the focal tweet", tweet.Text, tweet.Author.Username)
+	}
+	if !tweet.FetchedWithCredentials {
+		t.Error("TweetDetail results should be marked FetchedWithCredentials")
+	}
+
+	if _, err := client.parseTweetDetailResponse("3", &detail); err == nil {
+		t.Error("expected error for a tweet missing from the timeline")
+	}
+}
+
+func TestIsVisibilityError(t *testing.T) {
+	tests := []struct {
+		err  error
+		want bool
+	}{
+		{fmt.Errorf("syndication: %w", ErrTweetDeleted), false},
+		{fmt.Errorf("graphql: %w", ErrTweetUnavailable), true},
+		{ErrProtected, true},
+		{fmt.Errorf("graphql: %w", errAgeRestricted), true},
+		{ErrAuthorSuspended, false},
+		{newRateLimitError(http.Header{}), false},
+		{errors.New("graphql error (status 500)"), false},
+		{nil, false},
+	}
+	for _, tt := range tests {
+		if got := isVisibilityError(tt.err); got != tt.want {
+			t.Errorf("isVisibilityError(%v) = %v, want %v", tt.err, got, tt.want)
+		}
+	}
+}
+
 // =============================================================================
 // Unit Tests - Extract Tweet ID
 // =============================================================================
@@ -0,0 +1,64 @@
+package twitter
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/iconidentify/xgrabba/internal/domain"
+)
+
+// Typed errors returned by Client. Each wraps the matching domain error, so
+// callers can test with errors.Is against either package.
+var (
+	ErrTweetDeleted     = fmt.Errorf("x: %w", domain.ErrTweetDeleted)
+	ErrAuthorSuspended  = fmt.Errorf("x: %w", domain.ErrAuthorSuspended)
+	ErrProtected        = fmt.Errorf("x: %w", domain.ErrTweetProtected)
+	ErrWithheld         = fmt.Errorf("x: %w", domain.ErrTweetWithheld)
+	ErrTweetUnavailable = fmt.Errorf("x: %w", domain.ErrTweetUnavailable)
+	ErrRateLimited      = fmt.Errorf("x: %w", domain.ErrRateLimited)
+	ErrAuthExpired      = fmt.Errorf("x: %w", domain.ErrAuthExpired)
+)
+
+// RateLimitError indicates the request hit a rate limit and includes a reset time if known.
+// It matches ErrRateLimited.
+type RateLimitError struct {
+	Reset time.Time
+}
+
+func (e *RateLimitError) Error() string {
+	if !e.Reset.IsZero() {
+		return fmt.Sprintf("rate limited until %s", e.Reset.Format(time.RFC3339))
+	}
+	return "rate limited"
+}
+
+func (e *RateLimitError) Unwrap() error {
+	return ErrRateLimited
+}
+
+// newRateLimitError builds a RateLimitError from X's x-rate-limit-reset
+// header (unix seconds).
+func newRateLimitError(header http.Header) *RateLimitError {
+	rl := &RateLimitError{}
+	if sec, err := strconv.ParseInt(header.Get("x-rate-limit-reset"), 10, 64); err == nil && sec > 0 {
+		rl.Reset = time.Unix(sec, 0)
+	}
+	return rl
+}
+
+// upstreamStatusError returns the typed error for a tweet X reports as gone.
+func upstreamStatusError(status domain.UpstreamStatus) error {
+	switch status {
+	case domain.UpstreamStatusDeleted:
+		return ErrTweetDeleted
+	case domain.UpstreamStatusSuspended:
+		return ErrAuthorSuspended
+	case domain.UpstreamStatusProtected:
+		return ErrProtected
+	case domain.UpstreamStatusWithheld:
+		return ErrWithheld
+	}
+	return ErrTweetUnavailable
+}
@@ -0,0 +1,159 @@
+package twitter
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+
+	"github.com/iconidentify/xgrabba/internal/domain"
+)
+
+// errAgeRestricted is returned when X wraps a sensitive tweet without its
+// content, which happens for sessions that cannot view it (e.g. guest tokens).
+var errAgeRestricted = errors.New("age-restricted tweet has no nested tweet data (authentication may be required)")
+
+// tweetDetailResponse is the subset of the TweetDetail timeline needed to find
+// the focal tweet.
+type tweetDetailResponse struct {
+	Data struct {
+		Conversation struct {
+			Instructions []struct {
+				Entries []struct {
+					Content struct {
+						ItemContent struct {
+							TweetResults struct {
+								Result *graphQLTweetResult `json:"result"`
+							} `json:"tweet_results"`
+						} `json:"itemContent"`
+					} `json:"content"`
+				} `json:"entries"`
+			} `json:"instructions"`
+		} `json:"threaded_conversation_with_injections_v2"`
+	} `json:"data"`
+	Errors []struct {
+		Message string `json:"message"`
+	} `json:"errors"`
+}
+
+// focalTweet returns the timeline entry for tweetID, or nil if absent.
+func (r *tweetDetailResponse) focalTweet(tweetID string) *graphQLTweetResult {
+	for _, instr := range r.Data.Conversation.Instructions {
+		for _, entry := range instr.Entries {
+			result := entry.Content.ItemContent.TweetResults.Result
+			if result == nil {
+				continue
+			}
+			restID := result.RestID
+			if result.TypeName == "TweetWithVisibilityResults" && result.Tweet != nil {
+				restID = result.Tweet.RestID
+			}
+			if restID == tweetID {
+				return result
+			}
+		}
+	}
+	return nil
+}
+
+// isVisibilityError reports whether err means the tweet was hidden from the
+// requesting session rather than a transient failure. Deleted does not count:
+// a tweet that is gone for everyone is not worth an authenticated TweetDetail
+// request, whose own failure would then hide the tweet_deleted reason.
+func isVisibilityError(err error) bool {
+	return errors.Is(err, ErrProtected) ||
+		errors.Is(err, ErrWithheld) ||
+		errors.Is(err, ErrTweetUnavailable) ||
+		errors.Is(err, errAgeRestricted)
+}
+
+// fetchFromTweetDetail fetches a tweet through the TweetDetail timeline using
+// the user's browser session. It only runs with browser credentials, since
+// its purpose is reaching tweets the anonymous paths cannot see (protected
+// accounts the user follows, age-restricted media). The result is marked
+// FetchedWithCredentials.
+func (c *Client) fetchFromTweetDetail(ctx context.Context, tweetID string) (*domain.Tweet, error) {
+	headers := c.getBrowserHeaders()
+	if headers == nil {
+		return nil, fmt.Errorf("tweetdetail: no browser credentials: %w", ErrAuthExpired)
+	}
+
+	queryID := defaultTweetDetailQueryID
+	if qid := c.getBrowserQueryID("TweetDetail"); qid != "" {
+		queryID = qid
+	}
+	variables := fmt.Sprintf(`{"focalTweetId":"%s","with_rux_injections":false,"rankingMode":"Relevance","includePromotedContent":false,"withCommunity":true,"withQuickPromoteEligibilityTweetFields":false,"withBirdwatchNotes":true,"withVoice":true}`, tweetID)
+	features, _ := c.getGraphQLFeaturesWithSource()
+	fieldToggles := `{"withArticleRichContentState":true,"withArticlePlainText":false,"withGrokAnalyze":false,"withDisallowedReplyControls":false}`
+
+	reqURL := fmt.Sprintf("https://x.com/i/api/graphql/%s/TweetDetail?variables=%s&features=%s&fieldToggles=%s",
+		queryID,
+		url.QueryEscape(variables),
+		url.QueryEscape(features),
+		url.QueryEscape(fieldToggles))
+
+	req, err := http.NewRequestWithContext(ctx, "GET", reqURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("create TweetDetail request: %w", err)
+	}
+	for k, v := range headers {
+		req.Header[k] = v
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("TweetDetail request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusTooManyRequests:
+		return nil, newRateLimitError(resp.Header)
+	case resp.StatusCode == http.StatusUnauthorized, resp.StatusCode == http.StatusForbidden:
+		return nil, fmt.Errorf("TweetDetail error (status %d): %w", resp.StatusCode, ErrAuthExpired)
+	case resp.StatusCode != http.StatusOK:
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("TweetDetail error (status %d): %s", resp.StatusCode, truncateText(string(body), 200))
+	}
+
+	var detail tweetDetailResponse
+	if err := json.NewDecoder(resp.Body).Decode(&detail); err != nil {
+		return nil, fmt.Errorf("decode TweetDetail response: %w", err)
+	}
+
+	tweet, err := c.parseTweetDetailResponse(tweetID, &detail)
+	if err != nil {
+		return nil, err
+	}
+
+	c.logger.Info("authenticated TweetDetail fetch succeeded", "tweet_id", tweetID, "query_id", queryID)
+
+	if tweet.ContentType == domain.ContentTypeArticle {
+		c.enrichArticleContent(ctx, tweet)
+	}
+	return tweet, nil
+}
+
+// parseTweetDetailResponse extracts the focal tweet from a TweetDetail
+// timeline and marks it as fetched with credentials.
+func (c *Client) parseTweetDetailResponse(tweetID string, detail *tweetDetailResponse) (*domain.Tweet, error) {
+	result := detail.focalTweet(tweetID)
+	if result == nil {
+		if len(detail.Errors) > 0 {
+			return nil, fmt.Errorf("TweetDetail API error: %s", detail.Errors[0].Message)
+		}
+		return nil, fmt.Errorf("tweet %s not found in TweetDetail response", tweetID)
+	}
+
+	var single graphQLResponse
+	single.Data.TweetResult.Result = result
+	tweet, err := c.parseGraphQLResponse(tweetID, &single)
+	if err != nil {
+		return nil, err
+	}
+	tweet.FetchedWithCredentials = true
+	return tweet, nil
+}
@@ -0,0 +1,47 @@
+package twitter
+
+import (
+	"regexp"
+	"strconv"
+
+	"github.com/iconidentify/xgrabba/internal/domain"
+)
+
+var variantDimensionsRe = regexp.MustCompile(`/(\d+)x(\d+)/`)
+
+// variantDimensions reads the WxH path segment of a video.twimg.com URL,
+// e.g. /vid/avc1/720x1280/. Zero when the URL has none.
+func variantDimensions(urlStr string) (width, height int) {
+	m := variantDimensionsRe.FindStringSubmatch(urlStr)
+	if len(m) < 3 {
+		return 0, 0
+	}
+	width, _ = strconv.Atoi(m[1])
+	height, _ = strconv.Atoi(m[2])
+	return width, height
+}
+
+// mp4Variants dedupes MP4 renditions by URL, keeping the highest reported
+// bitrate, fills in dimensions from the URL and orders them best first.
+func mp4Variants(candidates []domain.MediaVariant) []domain.MediaVariant {
+	index := make(map[string]int)
+	var variants []domain.MediaVariant
+	for _, c := range candidates {
+		if c.URL == "" {
+			continue
+		}
+		if c.Width == 0 || c.Height == 0 {
+			c.Width, c.Height = variantDimensions(c.URL)
+		}
+		if i, ok := index[c.URL]; ok {
+			if c.Bitrate > variants[i].Bitrate {
+				variants[i].Bitrate = c.Bitrate
+			}
+			continue
+		}
+		index[c.URL] = len(variants)
+		variants = append(variants, c)
+	}
+	domain.SortVariants(variants)
+	return variants
+}
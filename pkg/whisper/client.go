@@ -94,6 +94,11 @@ func NewClient(cfg Config) *HTTPClient {
 	}
 }
 
+// Model returns the transcription model, for cost estimates.
+func (c *HTTPClient) Model() string {
+	return c.model
+}
+
 // Transcribe sends audio to the Whisper API and returns the transcription.
 func (c *HTTPClient) Transcribe(ctx context.Context, req TranscriptionRequest) (*TranscriptionResponse, error) {
 	if req.Model == "" {